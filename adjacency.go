@@ -0,0 +1,112 @@
+package dag
+
+// AdjacencyView is a read-only view on the structure of a DAG. It gives
+// access to vertex ids and edges without copying vertex values or building
+// intermediate maps, which makes it suitable for implementing algorithms on
+// large graphs.
+//
+// An AdjacencyView is only valid within the function passed to
+// ViewAdjacency. It must not be retained or used concurrently afterwards.
+type AdjacencyView struct {
+	ids          *idTable
+	inboundEdge  map[vertexHandle]map[vertexHandle]struct{}
+	outboundEdge map[vertexHandle]map[vertexHandle]struct{}
+}
+
+// Order returns the number of vertices.
+func (v AdjacencyView) Order() int {
+	return v.ids.len()
+}
+
+// Size returns the number of edges.
+func (v AdjacencyView) Size() int {
+	count := 0
+	for _, children := range v.outboundEdge {
+		count += len(children)
+	}
+	return count
+}
+
+// Has returns true, if the vertex with the given id exists.
+func (v AdjacencyView) Has(id string) bool {
+	_, ok := v.ids.handle(id)
+	return ok
+}
+
+// EachVertex calls f for each vertex id until f returns false. Vertices are
+// visited in insertion order, if the DAG preserves it.
+func (v AdjacencyView) EachVertex(f func(id string) bool) {
+	v.ids.each(func(id string, _ vertexHandle) bool {
+		return f(id)
+	})
+}
+
+// EachEdge calls f for each edge until f returns false.
+func (v AdjacencyView) EachEdge(f func(srcID, dstID string) bool) {
+	for src, children := range v.outboundEdge {
+		srcID := v.ids.id(src)
+		for dst := range children {
+			if !f(srcID, v.ids.id(dst)) {
+				return
+			}
+		}
+	}
+}
+
+// Children returns the ids of the children of the vertex with the given id.
+// Children returns nil, if the id is unknown.
+func (v AdjacencyView) Children(id string) []string {
+	return v.relatives(id, v.outboundEdge)
+}
+
+// Parents returns the ids of the parents of the vertex with the given id.
+// Parents returns nil, if the id is unknown.
+func (v AdjacencyView) Parents(id string) []string {
+	return v.relatives(id, v.inboundEdge)
+}
+
+// OutDegree returns the number of children of the vertex with the given id.
+func (v AdjacencyView) OutDegree(id string) int {
+	k, ok := v.ids.handle(id)
+	if !ok {
+		return 0
+	}
+	return len(v.outboundEdge[k])
+}
+
+// InDegree returns the number of parents of the vertex with the given id.
+func (v AdjacencyView) InDegree(id string) int {
+	k, ok := v.ids.handle(id)
+	if !ok {
+		return 0
+	}
+	return len(v.inboundEdge[k])
+}
+
+func (v AdjacencyView) relatives(id string, edges map[vertexHandle]map[vertexHandle]struct{}) []string {
+	k, ok := v.ids.handle(id)
+	if !ok {
+		return nil
+	}
+	return relativeIDs(v.ids, edges[k])
+}
+
+// ViewAdjacency calls f with a read-only view on the structure of the
+// GenericDAG. The GenericDAG is read-locked while f runs, so f must not
+// modify the GenericDAG.
+func (d *GenericDAG[T]) ViewAdjacency(f func(view AdjacencyView)) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	f(AdjacencyView{
+		ids:          &d.ids,
+		inboundEdge:  d.inboundEdge,
+		outboundEdge: d.outboundEdge,
+	})
+}
+
+// ViewAdjacency calls f with a read-only view on the structure of the
+// TypedDAG. The TypedDAG is read-locked while f runs, so f must not modify
+// the TypedDAG.
+func (d *TypedDAG[T]) ViewAdjacency(f func(view AdjacencyView)) {
+	d.inner.ViewAdjacency(f)
+}
@@ -0,0 +1,65 @@
+package dag
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestViewAdjacency(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c", "d"} {
+		d.MustAddVertexByID(id, i)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("b", "d")
+	d.MustAddEdge("c", "d")
+
+	d.ViewAdjacency(func(view AdjacencyView) {
+		if view.Order() != 4 || view.Size() != 4 {
+			t.Errorf("Order(), Size() = %d, %d, want 4, 4", view.Order(), view.Size())
+		}
+		children := view.Children("a")
+		sort.Strings(children)
+		if len(children) != 2 || children[0] != "b" || children[1] != "c" {
+			t.Errorf("Children(a) = %v, want [b c]", children)
+		}
+		if parents := view.Parents("d"); len(parents) != 2 {
+			t.Errorf("Parents(d) = %v, want two parents", parents)
+		}
+		if view.InDegree("d") != 2 || view.OutDegree("d") != 0 {
+			t.Errorf("InDegree(d), OutDegree(d) = %d, %d, want 2, 0", view.InDegree("d"), view.OutDegree("d"))
+		}
+		if view.Has("x") || view.Children("x") != nil {
+			t.Error("unknown ids should not be reported")
+		}
+
+		edges := 0
+		view.EachEdge(func(srcID, dstID string) bool {
+			edges++
+			return true
+		})
+		if edges != 4 {
+			t.Errorf("EachEdge visited %d edges, want 4", edges)
+		}
+
+		vertices := 0
+		view.EachVertex(func(id string) bool {
+			vertices++
+			return vertices < 2
+		})
+		if vertices != 2 {
+			t.Errorf("EachVertex should stop when f returns false, visited %d", vertices)
+		}
+	})
+
+	legacy := NewDAG()
+	legacy.MustAddVertexByID("a", 1)
+	legacy.MustAddVertexByID("b", 2)
+	legacy.MustAddEdge("a", "b")
+	legacy.ViewAdjacency(func(view AdjacencyView) {
+		if parents := view.Parents("b"); len(parents) != 1 || parents[0] != "a" {
+			t.Errorf("Parents(b) = %v, want [a]", parents)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package dag
+
+import "sort"
+
+// AffectedVertices compares two versions of a graph and returns the sorted
+// ids of the vertices of newDAG that are affected by the changes, e.g. the
+// steps of a pipeline to re-run. A vertex of newDAG is changed, if it isn't
+// a vertex of oldDAG, if its parents differ between the versions, or if
+// changed returns true for its value a in oldDAG and b in newDAG. A nil
+// changed considers no values changed. The affected vertices are the changed
+// ones along with all their descendants in newDAG. Vertices only in oldDAG
+// aren't returned, but their former children are changed.
+func AffectedVertices[T any](oldDAG, newDAG *GenericDAG[T], changed func(id string, a, b T) bool) []string {
+	type version struct {
+		value   T
+		parents map[string]struct{}
+	}
+	oldDAG.muDAG.RLock()
+	old := make(map[string]version, oldDAG.getOrder())
+	oldDAG.ids.each(func(id string, h vertexHandle) bool {
+		parents := make(map[string]struct{}, len(oldDAG.inboundEdge[h]))
+		for parent := range oldDAG.inboundEdge[h] {
+			parents[oldDAG.ids.id(parent)] = struct{}{}
+		}
+		old[id] = version{oldDAG.values[h], parents}
+		return true
+	})
+	oldDAG.muDAG.RUnlock()
+
+	newDAG.muDAG.RLock()
+	defer newDAG.muDAG.RUnlock()
+
+	affected := make(map[vertexHandle]struct{})
+	newDAG.ids.each(func(id string, h vertexHandle) bool {
+		if _, exists := affected[h]; exists {
+			return true
+		}
+		prev, exists := old[id]
+		isChanged := !exists || len(prev.parents) != len(newDAG.inboundEdge[h])
+		if !isChanged {
+			for parent := range newDAG.inboundEdge[h] {
+				if _, exists := prev.parents[newDAG.ids.id(parent)]; !exists {
+					isChanged = true
+					break
+				}
+			}
+		}
+		if !isChanged && changed != nil {
+			isChanged = changed(id, prev.value, newDAG.values[h])
+		}
+		if isChanged {
+			affected[h] = struct{}{}
+			for descendant := range newDAG.getDescendants(h) {
+				affected[descendant] = struct{}{}
+			}
+		}
+		return true
+	})
+
+	ids := make([]string, 0, len(affected))
+	for h := range affected {
+		ids = append(ids, newDAG.ids.id(h))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// AffectedSince returns the sorted ids of the vertices affected by the
+// changes from the TypedDAG old to this one. See AffectedVertices for
+// details.
+func (d *TypedDAG[T]) AffectedSince(old *TypedDAG[T], changed func(id string, a, b T) bool) []string {
+	return AffectedVertices(old.inner, d.inner, changed)
+}
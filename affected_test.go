@@ -0,0 +1,41 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAffectedVertices(t *testing.T) {
+	// a -> b -> c, d -> e, f
+	build := func() *GenericDAG[int] {
+		d := NewGenericDAG[int]()
+		for i, id := range []string{"a", "b", "c", "d", "e", "f"} {
+			_ = d.AddVertexByID(id, i)
+		}
+		_ = d.AddEdge("a", "b")
+		_ = d.AddEdge("b", "c")
+		_ = d.AddEdge("d", "e")
+		return d
+	}
+	changed := func(_ string, a, b int) bool { return a != b }
+
+	old, next := build(), build()
+	if got := AffectedVertices(old, next, changed); len(got) != 0 {
+		t.Errorf("AffectedVertices() of equal graphs = %v, want []", got)
+	}
+
+	// change b's value, move f below a new vertex g and drop d
+	_ = next.DeleteVertex("b")
+	_ = next.AddVertexByID("b", 10)
+	_ = next.AddEdge("a", "b")
+	_ = next.AddEdge("b", "c")
+	_ = next.AddVertexByID("g", 11)
+	_ = next.AddEdge("g", "f")
+	_ = next.DeleteVertex("d")
+	if got, want := AffectedVertices(old, next, changed), []string{"b", "c", "e", "f", "g"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AffectedVertices() = %v, want %v", got, want)
+	}
+	if got, want := AffectedVertices(old, next, nil), []string{"e", "f", "g"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AffectedVertices() without changed = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package dag
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+)
+
+// ArgoTask is the spec of a task of an Argo Workflows DAG template. As
+// ArgoTask isn't comparable, a graph holding ArgoTask values needs
+// WithDuplicateValues or a VertexHashFunc.
+type ArgoTask struct {
+	// Template is the name of the template the task runs.
+	Template string
+
+	// Parameters are the parameters passed to the template.
+	Parameters map[string]string
+
+	// When is the condition under which the task runs. It is omitted if
+	// empty.
+	When string
+}
+
+// ArgoOptions configures the Argo Workflows DAG template written by
+// WriteArgoDAGTemplate.
+type ArgoOptions struct {
+	// Name is the name of the DAG template. If Name is empty, "main" is used.
+	Name string
+
+	// Task returns the task spec of the vertex with the given id and value.
+	// If Task is nil, the vertex values must be of type ArgoTask.
+	Task func(id string, v interface{}) (ArgoTask, error)
+}
+
+// argoTaskName matches the names Argo Workflows accepts for tasks.
+var argoTaskName = regexp.MustCompile(`^[a-zA-Z0-9][-a-zA-Z0-9]*$`)
+
+// WriteArgoDAGTemplate writes the GenericDAG to w as Argo Workflows DAG
+// template in YAML, to be added to the templates of a Workflow or
+// WorkflowTemplate. Each vertex becomes a task named by the vertex id, which
+// depends on the tasks of the vertex's parents. Tasks are written in sorted
+// order, so the output is deterministic.
+//
+// WriteArgoDAGTemplate returns an error if an id isn't a valid task name
+// (letters, digits and '-'), if the task spec of a vertex can't be determined
+// or has no template, or if writing to w fails.
+func (d *GenericDAG[T]) WriteArgoDAGTemplate(w io.Writer, opts ArgoOptions) error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	name := opts.Name
+	if name == "" {
+		name = "main"
+	}
+	ids := make([]string, 0, d.ids.len())
+	for id := range d.ids.handles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "name: %s\n", yamlQuote(name))
+	fmt.Fprintln(b, "dag:")
+	if len(ids) == 0 {
+		fmt.Fprintln(b, "  tasks: []")
+	} else {
+		fmt.Fprintln(b, "  tasks:")
+	}
+	for _, id := range ids {
+		if !argoTaskName.MatchString(id) {
+			return fmt.Errorf("'%s' is not a valid Argo task name", id)
+		}
+		task, err := argoTaskOf(id, d.value(id), opts.Task)
+		if err != nil {
+			return err
+		}
+		if task.Template == "" {
+			return fmt.Errorf("task '%s' has no template", id)
+		}
+		fmt.Fprintf(b, "  - name: %s\n", yamlQuote(id))
+		fmt.Fprintf(b, "    template: %s\n", yamlQuote(task.Template))
+		if parents := relativeIDs(&d.ids, d.inboundEdge[d.keyOf(id)]); len(parents) > 0 {
+			sort.Strings(parents)
+			fmt.Fprintln(b, "    dependencies:")
+			for _, parent := range parents {
+				fmt.Fprintf(b, "    - %s\n", yamlQuote(parent))
+			}
+		}
+		if task.When != "" {
+			fmt.Fprintf(b, "    when: %s\n", yamlQuote(task.When))
+		}
+		if len(task.Parameters) > 0 {
+			keys := make([]string, 0, len(task.Parameters))
+			for k := range task.Parameters {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			fmt.Fprintln(b, "    arguments:")
+			fmt.Fprintln(b, "      parameters:")
+			for _, k := range keys {
+				fmt.Fprintf(b, "      - name: %s\n", yamlQuote(k))
+				fmt.Fprintf(b, "        value: %s\n", yamlQuote(task.Parameters[k]))
+			}
+		}
+	}
+	return b.Flush()
+}
+
+// WriteArgoDAGTemplate writes the TypedDAG to w as Argo Workflows DAG template
+// in YAML. See GenericDAG.WriteArgoDAGTemplate for details.
+func (d *TypedDAG[T]) WriteArgoDAGTemplate(w io.Writer, opts ArgoOptions) error {
+	return d.inner.WriteArgoDAGTemplate(w, opts)
+}
+
+// argoTaskOf returns the task spec of the vertex with id and value v.
+func argoTaskOf(id string, v interface{}, task func(id string, v interface{}) (ArgoTask, error)) (ArgoTask, error) {
+	if task != nil {
+		t, err := task(id, v)
+		if err != nil {
+			return ArgoTask{}, fmt.Errorf("task '%s': %w", id, err)
+		}
+		return t, nil
+	}
+	switch t := v.(type) {
+	case ArgoTask:
+		return t, nil
+	case *ArgoTask:
+		if t != nil {
+			return *t, nil
+		}
+	}
+	return ArgoTask{}, fmt.Errorf("value of vertex '%s' (%T) is no ArgoTask", id, v)
+}
+
+// yamlQuote returns s as a double-quoted YAML string. JSON strings are valid
+// YAML strings.
+func yamlQuote(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
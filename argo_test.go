@@ -0,0 +1,83 @@
+package dag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteArgoDAGTemplate(t *testing.T) {
+	d := New[ArgoTask](WithDuplicateValues())
+	d.MustAddVertexByID("build", ArgoTask{Template: "make", Parameters: map[string]string{"target": "all", "jobs": "4"}})
+	d.MustAddVertexByID("test", ArgoTask{Template: "make", Parameters: map[string]string{"target": "test"}})
+	d.MustAddVertexByID("lint", ArgoTask{Template: "lint"})
+	d.MustAddVertexByID("deploy", ArgoTask{Template: "deploy", When: `"{{workflow.parameters.env}}" == "prod"`})
+	d.MustAddEdge("build", "test")
+	d.MustAddEdge("test", "deploy")
+	d.MustAddEdge("lint", "deploy")
+
+	var b strings.Builder
+	if err := d.WriteArgoDAGTemplate(&b, ArgoOptions{Name: "pipeline"}); err != nil {
+		t.Fatalf("WriteArgoDAGTemplate() unexpected error: %v", err)
+	}
+	want := `name: "pipeline"
+dag:
+  tasks:
+  - name: "build"
+    template: "make"
+    arguments:
+      parameters:
+      - name: "jobs"
+        value: "4"
+      - name: "target"
+        value: "all"
+  - name: "deploy"
+    template: "deploy"
+    dependencies:
+    - "lint"
+    - "test"
+    when: "\"{{workflow.parameters.env}}\" == \"prod\""
+  - name: "lint"
+    template: "lint"
+  - name: "test"
+    template: "make"
+    dependencies:
+    - "build"
+    arguments:
+      parameters:
+      - name: "target"
+        value: "test"
+`
+	if b.String() != want {
+		t.Errorf("WriteArgoDAGTemplate() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	// task specs from other values
+	steps := NewDAG()
+	_ = steps.AddVertexByID("a", "echo")
+	b.Reset()
+	err := steps.WriteArgoDAGTemplate(&b, ArgoOptions{Task: func(id string, v interface{}) (ArgoTask, error) {
+		return ArgoTask{Template: v.(string)}, nil
+	}})
+	if err != nil || !strings.HasPrefix(b.String(), "name: \"main\"\n") || !strings.Contains(b.String(), `template: "echo"`) {
+		t.Errorf("WriteArgoDAGTemplate() = %s, %v", b.String(), err)
+	}
+
+	failing := errors.New("no spec")
+	errorTests := map[string]ArgoOptions{
+		"no ArgoTask": {},
+		"failing":     {Task: func(string, interface{}) (ArgoTask, error) { return ArgoTask{}, failing }},
+		"no template": {Task: func(string, interface{}) (ArgoTask, error) { return ArgoTask{}, nil }},
+	}
+	for name, opts := range errorTests {
+		if err := steps.WriteArgoDAGTemplate(&b, opts); err == nil {
+			t.Errorf("%s: WriteArgoDAGTemplate() should fail", name)
+		}
+	}
+	_ = steps.AddVertexByID("not a name", "x")
+	if err := steps.WriteArgoDAGTemplate(&b, ArgoOptions{Task: func(string, interface{}) (ArgoTask, error) {
+		return ArgoTask{Template: "x"}, nil
+	}}); err == nil {
+		t.Error("WriteArgoDAGTemplate() with an invalid task name should fail")
+	}
+}
@@ -0,0 +1,158 @@
+package dag
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AutoSnapshotOption configures an AutoSnapshot.
+type AutoSnapshotOption func(*autoSnapshotConfig)
+
+type autoSnapshotConfig struct {
+	mutationThreshold int
+	onError           func(error)
+}
+
+// SnapshotAfterMutations makes an AutoSnapshot serialize the graph as soon as
+// n modifications happened since the last snapshot, without waiting for the
+// next tick.
+func SnapshotAfterMutations(n int) AutoSnapshotOption {
+	return func(c *autoSnapshotConfig) {
+		c.mutationThreshold = n
+	}
+}
+
+// SnapshotErrorHandler sets a function that is called with every error
+// returned while serializing the graph or by the sink.
+func SnapshotErrorHandler(f func(error)) AutoSnapshotOption {
+	return func(c *autoSnapshotConfig) {
+		c.onError = f
+	}
+}
+
+// AutoSnapshot periodically persists a GenericDAG in the background. It is
+// created by StartAutoSnapshot.
+type AutoSnapshot struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	snapshot func() error
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// Stop stops the background persister and writes a final snapshot, if the
+// graph has changed since the last one. Stop returns the error of this final
+// snapshot. Subsequent calls to Stop do nothing and return the same error.
+func (s *AutoSnapshot) Stop() error {
+	s.stopOnce.Do(func() {
+		s.cancel()
+		<-s.done
+		s.stopErr = s.snapshot()
+	})
+	return s.stopErr
+}
+
+// StartAutoSnapshot starts a background goroutine that serializes the
+// GenericDAG to JSON and passes the data to sink every interval. Unchanged
+// graphs are not serialized again. The goroutine stops when ctx is done or
+// Stop is called on the returned AutoSnapshot.
+//
+// Note, sink is called from the background goroutine without holding any
+// lock of the GenericDAG. Like time.NewTicker, StartAutoSnapshot panics if
+// interval isn't positive.
+func (d *GenericDAG[T]) StartAutoSnapshot(ctx context.Context, interval time.Duration, sink func([]byte) error, opts ...AutoSnapshotOption) *AutoSnapshot {
+	if interval <= 0 {
+		panic("dag: non-positive interval for StartAutoSnapshot")
+	}
+	var config autoSnapshotConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	signal := make(chan struct{}, 1)
+	d.muDAG.Lock()
+	d.mutationSignals = append(d.mutationSignals, signal)
+	d.muDAG.Unlock()
+
+	// the version of the graph that has been passed to the sink last; the
+	// graph initially counts as dirty, so the first tick always persists it
+	var mu sync.Mutex
+	persisted, hasPersisted := uint64(0), false
+	snapshot := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		d.muDAG.RLock()
+		version := d.mutations
+		if hasPersisted && version == persisted {
+			d.muDAG.RUnlock()
+			return nil
+		}
+		data, err := d.marshalJSON()
+		d.muDAG.RUnlock()
+		if err != nil {
+			return err
+		}
+		if err := sink(data); err != nil {
+			return err
+		}
+		persisted, hasPersisted = version, true
+		return nil
+	}
+	pending := func() uint64 {
+		mu.Lock()
+		defer mu.Unlock()
+		d.muDAG.RLock()
+		defer d.muDAG.RUnlock()
+		return d.mutations - persisted
+	}
+	report := func(err error) {
+		if err != nil && config.onError != nil {
+			config.onError(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &AutoSnapshot{
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		snapshot: snapshot,
+	}
+	go func() {
+		defer close(s.done)
+		defer d.removeMutationSignal(signal)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report(snapshot())
+			case <-signal:
+				if config.mutationThreshold > 0 && pending() >= uint64(config.mutationThreshold) {
+					report(snapshot())
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// StartAutoSnapshot starts a background goroutine that serializes the
+// TypedDAG to JSON and passes the data to sink every interval. See
+// GenericDAG.StartAutoSnapshot for details.
+func (d *TypedDAG[T]) StartAutoSnapshot(ctx context.Context, interval time.Duration, sink func([]byte) error, opts ...AutoSnapshotOption) *AutoSnapshot {
+	return d.inner.StartAutoSnapshot(ctx, interval, sink, opts...)
+}
+
+func (d *GenericDAG[T]) removeMutationSignal(signal chan struct{}) {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	for i, c := range d.mutationSignals {
+		if c == signal {
+			d.mutationSignals = append(d.mutationSignals[:i], d.mutationSignals[i+1:]...)
+			return
+		}
+	}
+}
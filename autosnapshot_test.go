@@ -0,0 +1,134 @@
+package dag
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type snapshotRecorder struct {
+	mu        sync.Mutex
+	snapshots [][]byte
+}
+
+func (r *snapshotRecorder) sink(data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshots = append(r.snapshots, data)
+	return nil
+}
+
+func (r *snapshotRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.snapshots)
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAutoSnapshotInterval(t *testing.T) {
+	d := New[string]()
+	d.MustAddVertexByID("a", "A")
+
+	var r snapshotRecorder
+	s := d.StartAutoSnapshot(context.Background(), 5*time.Millisecond, r.sink)
+	waitFor(t, "the first snapshot", func() bool { return r.count() == 1 })
+
+	// an unchanged graph is not serialized again
+	time.Sleep(30 * time.Millisecond)
+	if r.count() != 1 {
+		t.Errorf("unchanged graph was persisted %d times, want 1", r.count())
+	}
+
+	d.MustAddVertexByID("b", "B")
+	waitFor(t, "the second snapshot", func() bool { return r.count() == 2 })
+
+	if err := s.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	restored, err := UnmarshalJSON[string](r.snapshots[1], Options{})
+	if err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if restored.GetOrder() != 2 {
+		t.Errorf("restored snapshot has %d vertices, want 2", restored.GetOrder())
+	}
+}
+
+func TestAutoSnapshotMutationThreshold(t *testing.T) {
+	d := NewGenericDAG[int]()
+	var r snapshotRecorder
+	s := d.StartAutoSnapshot(context.Background(), time.Hour, r.sink, SnapshotAfterMutations(3))
+
+	d.MustAddVertexByID("a", 1)
+	d.MustAddVertexByID("b", 2)
+	time.Sleep(10 * time.Millisecond)
+	if r.count() != 0 {
+		t.Errorf("snapshot taken before reaching the threshold")
+	}
+	d.MustAddEdge("a", "b")
+	waitFor(t, "the threshold snapshot", func() bool { return r.count() == 1 })
+
+	// Stop persists pending changes
+	d.MustAddVertexByID("c", 3)
+	if err := s.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if r.count() != 2 {
+		t.Errorf("Stop should persist pending changes, got %d snapshots", r.count())
+	}
+	if err := s.Stop(); err != nil || r.count() != 2 {
+		t.Errorf("second Stop should do nothing")
+	}
+	if len(d.mutationSignals) != 0 {
+		t.Errorf("stopped snapshotter is still registered")
+	}
+}
+
+func TestAutoSnapshotErrors(t *testing.T) {
+	d := NewGenericDAG[int]()
+	errSink := errors.New("sink failed")
+	var mu sync.Mutex
+	var reported []error
+	ctx, cancel := context.WithCancel(context.Background())
+	s := d.StartAutoSnapshot(ctx, time.Millisecond,
+		func([]byte) error { return errSink },
+		SnapshotErrorHandler(func(err error) {
+			mu.Lock()
+			reported = append(reported, err)
+			mu.Unlock()
+		}))
+	waitFor(t, "a reported error", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(reported) > 0
+	})
+	cancel()
+	if err := s.Stop(); !errors.Is(err, errSink) {
+		t.Errorf("Stop() = %v, want %v", err, errSink)
+	}
+}
+
+func TestAutoSnapshotInvalidInterval(t *testing.T) {
+	d := New[string]()
+	var r snapshotRecorder
+	for _, interval := range []time.Duration{0, -time.Second} {
+		expectPanic(t, "StartAutoSnapshot with interval "+interval.String(), func() {
+			d.StartAutoSnapshot(context.Background(), interval, r.sink)
+		})
+	}
+	if n := len(d.inner.mutationSignals); n != 0 {
+		t.Errorf("StartAutoSnapshot left %d mutation signals, want 0", n)
+	}
+}
@@ -0,0 +1,91 @@
+package dag
+
+import "sync/atomic"
+
+// CacheStats describes the use of the ancestors- and descendants-caches of a
+// DAG since it was created.
+type CacheStats struct {
+	// Ancestors describes the ancestors-cache.
+	Ancestors CacheStat
+
+	// Descendants describes the descendants-cache.
+	Descendants CacheStat
+}
+
+// CacheStat describes the use of a cache of the relatives of vertices.
+type CacheStat struct {
+	// Hits is the number of lookups answered from the cache.
+	Hits uint64
+
+	// Misses is the number of lookups, that had to collect the relatives.
+	Misses uint64
+
+	// Evictions is the number of entries dropped from the cache, as the graph
+	// changed or the cache was flushed.
+	Evictions uint64
+
+	// Entries is the number of vertices, whose relatives are cached.
+	Entries int
+
+	// Size is the total number of relatives cached for all entries.
+	Size int
+}
+
+// HitRatio returns the share of lookups answered from the cache, or 0 if
+// there were no lookups.
+func (s CacheStat) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// cacheCounters counts the use of a cache. Lookups happen concurrently under
+// the read lock, so the counters are atomic.
+type cacheCounters struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// evict deletes the entry of h from cache, if there is one.
+func (c *cacheCounters) evict(cache map[vertexHandle]map[vertexHandle]struct{}, h vertexHandle) {
+	if _, exists := cache[h]; exists {
+		delete(cache, h)
+		c.evictions.Add(1)
+	}
+}
+
+// stat returns the counters along with the current extent of cache.
+func (c *cacheCounters) stat(cache map[vertexHandle]map[vertexHandle]struct{}) CacheStat {
+	s := CacheStat{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   len(cache),
+	}
+	for _, relatives := range cache {
+		s.Size += len(relatives)
+	}
+	return s
+}
+
+// CacheStats returns the hits, misses and evictions of the ancestors- and
+// descendants-caches along with their current extent, e.g. to tune how often
+// to call FlushCaches. With Options.DisableCache, all numbers are 0.
+func (d *GenericDAG[T]) CacheStats() CacheStats {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	d.muCache.RLock()
+	defer d.muCache.RUnlock()
+	return CacheStats{
+		Ancestors:   d.ancestorsStats.stat(d.ancestorsCache),
+		Descendants: d.descendantsStats.stat(d.descendantsCache),
+	}
+}
+
+// CacheStats returns the use of the ancestors- and descendants-caches. See
+// GenericDAG.CacheStats for details.
+func (d *TypedDAG[T]) CacheStats() CacheStats {
+	return d.inner.CacheStats()
+}
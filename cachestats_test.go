@@ -0,0 +1,49 @@
+package dag
+
+import "testing"
+
+func TestCacheStats(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, i)
+	}
+	_ = d.AddEdge("a", "b")
+	_ = d.AddEdge("b", "c")
+	// maintaining the caches while adding edges uses them, too
+	d.FlushCaches()
+	base := d.CacheStats()
+
+	_, _ = d.GetDescendants("a")
+	_, _ = d.GetDescendants("a")
+	s := d.CacheStats().since(base)
+	// a misses, b and c miss while collecting, then a hits
+	if s.Descendants.Hits != 1 || s.Descendants.Misses != 3 {
+		t.Errorf("Descendants hits, misses = %d, %d, want 1, 3", s.Descendants.Hits, s.Descendants.Misses)
+	}
+	if s.Descendants.Entries != 3 || s.Descendants.Size != 3 {
+		t.Errorf("Descendants entries, size = %d, %d, want 3, 3", s.Descendants.Entries, s.Descendants.Size)
+	}
+	if r := s.Descendants.HitRatio(); r != 0.25 {
+		t.Errorf("HitRatio() = %v, want 0.25", r)
+	}
+
+	_ = d.DeleteEdge("a", "b")
+	s = d.CacheStats().since(base)
+	if s.Descendants.Evictions != 2 || s.Descendants.Entries != 1 {
+		t.Errorf("Descendants evictions, entries = %d, %d, want 2, 1", s.Descendants.Evictions, s.Descendants.Entries)
+	}
+	d.FlushCaches()
+	if s = d.CacheStats().since(base); s.Descendants.Evictions != 3 || s.Descendants.Entries != 0 {
+		t.Errorf("Descendants evictions, entries = %d, %d, want 3, 0", s.Descendants.Evictions, s.Descendants.Entries)
+	}
+}
+
+// since returns the counters of s minus those of base.
+func (s CacheStats) since(base CacheStats) CacheStats {
+	for _, c := range []struct{ s, base *CacheStat }{{&s.Ancestors, &base.Ancestors}, {&s.Descendants, &base.Descendants}} {
+		c.s.Hits -= c.base.Hits
+		c.s.Misses -= c.base.Misses
+		c.s.Evictions -= c.base.Evictions
+	}
+	return s
+}
@@ -0,0 +1,169 @@
+package dag
+
+// EdgeCheckResult describes the outcome of adding an edge, as determined by
+// CheckEdge.
+type EdgeCheckResult int
+
+const (
+	// EdgeOK indicates that the edge can be added.
+	EdgeOK EdgeCheckResult = iota
+
+	// EdgeDuplicate indicates that the edge already exists.
+	EdgeDuplicate
+
+	// EdgeCycle indicates that the edge would create a loop.
+	EdgeCycle
+)
+
+// EdgeCheck is the outcome of CheckEdge.
+type EdgeCheck struct {
+	// Result tells whether the edge can be added.
+	Result EdgeCheckResult
+
+	// Path lists the ids of the vertices on a shortest path from dst to src,
+	// if Result is EdgeCycle, i.e. the loop the edge would close. If src and
+	// dst are equal, Path holds only their id.
+	Path []string
+}
+
+// CheckEdge reports whether an edge between srcID and dstID could be added,
+// without adding it. An existing edge is reported as EdgeDuplicate, and an
+// edge that would create a loop as EdgeCycle along with the loop's path.
+// CheckEdge returns an error if srcID or dstID are empty strings or unknown.
+func (d *GenericDAG[T]) CheckEdge(srcID, dstID string) (EdgeCheck, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(srcID); err != nil {
+		return EdgeCheck{}, err
+	}
+	if err := d.saneID(dstID); err != nil {
+		return EdgeCheck{}, err
+	}
+	if srcID == dstID {
+		return EdgeCheck{Result: EdgeCycle, Path: []string{srcID}}, nil
+	}
+
+	srcHash := d.keyOf(srcID)
+	dstHash := d.keyOf(dstID)
+	if d.isEdge(srcHash, dstHash) {
+		return EdgeCheck{Result: EdgeDuplicate}, nil
+	}
+	if path := d.shortestPath(dstHash, srcHash); path != nil {
+		return EdgeCheck{Result: EdgeCycle, Path: path}, nil
+	}
+	return EdgeCheck{Result: EdgeOK}, nil
+}
+
+// shortestPath returns the ids of the vertices on a shortest path from
+// fromHash to toHash, or nil if toHash is no descendant of fromHash.
+func (d *GenericDAG[T]) shortestPath(fromHash, toHash vertexHandle) []string {
+	// previous maps each visited vertex to its predecessor on the path
+	previous := map[vertexHandle]vertexHandle{fromHash: fromHash}
+	fifo := []vertexHandle{fromHash}
+	found := false
+	for len(fifo) > 0 && !found {
+		top := fifo[0]
+		fifo = fifo[1:]
+		d.eachChild(top, func(child vertexHandle) {
+			if _, exists := previous[child]; exists || found {
+				return
+			}
+			previous[child] = top
+			fifo = append(fifo, child)
+			found = child == toHash
+		})
+	}
+	if !found {
+		return nil
+	}
+
+	var path []string
+	for h := toHash; h != fromHash; h = previous[h] {
+		path = append(path, d.ids.id(h))
+	}
+	path = append(path, d.ids.id(fromHash))
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// CheckEdge reports whether an edge between srcID and dstID could be added,
+// without adding it. See GenericDAG.CheckEdge for details.
+func (d *TypedDAG[T]) CheckEdge(srcID, dstID string) (EdgeCheck, error) {
+	return d.inner.CheckEdge(srcID, dstID)
+}
+
+// AuditAcyclicity verifies that the GenericDAG is acyclic by a full pass of
+// Kahn's algorithm, independent of the loop detection applied when adding
+// edges and of any caches. It is meant as a health check after bulk loads
+// and after Replay, which trust the loop detection of the operations they
+// apply. If a cycle is found, AuditAcyclicity returns a CycleError listing
+// the ids of the vertices of one cycle in the order of its edges, starting
+// at the smallest id.
+func (d *GenericDAG[T]) AuditAcyclicity() error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	_, remaining := d.topologicalOrder(false)
+	if len(remaining) == 0 {
+		return nil
+	}
+	return CycleError{d.findCycle(remaining)}
+}
+
+// findCycle returns the ids of the vertices of a cycle among the vertices
+// Kahn's algorithm couldn't remove, each of which has a parent among them.
+// Following the parents with the smallest ids from the smallest id
+// eventually repeats a vertex, which closes the cycle.
+func (d *GenericDAG[T]) findCycle(remaining map[string]int) []string {
+	start := ""
+	for id := range remaining {
+		if start == "" || id < start {
+			start = id
+		}
+	}
+
+	position := make(map[vertexHandle]int)
+	var path []vertexHandle
+	h := d.keyOf(start)
+	for {
+		if _, exists := position[h]; exists {
+			break
+		}
+		position[h] = len(path)
+		path = append(path, h)
+		next, nextID := h, ""
+		for parent := range d.inboundEdge[h] {
+			id := d.ids.id(parent)
+			if _, exists := remaining[id]; !exists {
+				continue
+			}
+			if nextID == "" || id < nextID {
+				next, nextID = parent, id
+			}
+		}
+		h = next
+	}
+
+	// path follows the edges backwards, so the cycle is its reversed tail
+	cycle := path[position[h]:]
+	ids := make([]string, len(cycle))
+	for i, c := range cycle {
+		ids[len(cycle)-1-i] = d.ids.id(c)
+	}
+	minPos := 0
+	for i, id := range ids {
+		if id < ids[minPos] {
+			minPos = i
+		}
+	}
+	return append(ids[minPos:], ids[:minPos]...)
+}
+
+// AuditAcyclicity verifies that the TypedDAG is acyclic. See
+// GenericDAG.AuditAcyclicity for details.
+func (d *TypedDAG[T]) AuditAcyclicity() error {
+	return d.inner.AuditAcyclicity()
+}
@@ -0,0 +1,83 @@
+package dag
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckEdge(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"1", "2", "3", "4", "5"} {
+		_ = d.AddVertexByID(id, i)
+	}
+	// 1 -> 2 -> 3 -> 4, 1 -> 4
+	_ = d.AddEdge("1", "2")
+	_ = d.AddEdge("2", "3")
+	_ = d.AddEdge("3", "4")
+	_ = d.AddEdge("1", "4")
+
+	tests := []struct {
+		src, dst string
+		want     EdgeCheck
+	}{
+		{"1", "5", EdgeCheck{Result: EdgeOK}},
+		{"1", "3", EdgeCheck{Result: EdgeOK}},
+		{"2", "3", EdgeCheck{Result: EdgeDuplicate}},
+		{"3", "1", EdgeCheck{Result: EdgeCycle, Path: []string{"1", "2", "3"}}},
+		{"4", "1", EdgeCheck{Result: EdgeCycle, Path: []string{"1", "4"}}},
+		{"2", "2", EdgeCheck{Result: EdgeCycle, Path: []string{"2"}}},
+	}
+	for _, tt := range tests {
+		got, err := d.CheckEdge(tt.src, tt.dst)
+		if err != nil {
+			t.Errorf("CheckEdge(%s, %s) unexpected error: %v", tt.src, tt.dst, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CheckEdge(%s, %s) = %+v, want %+v", tt.src, tt.dst, got, tt.want)
+		}
+	}
+	if d.GetSize() != 4 {
+		t.Errorf("GetSize() = %d, want 4", d.GetSize())
+	}
+
+	if _, err := d.CheckEdge("1", "6"); err == nil {
+		t.Error("CheckEdge() with unknown id expected error")
+	}
+	if _, err := d.CheckEdge("", "1"); err == nil {
+		t.Error("CheckEdge() with empty id expected error")
+	}
+}
+
+func TestAuditAcyclicity(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, i)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("b", "c")
+	d.MustAddEdge("c", "d")
+	d.MustAddEdge("a", "e")
+	if err := d.AuditAcyclicity(); err != nil {
+		t.Fatalf("AuditAcyclicity() = %v, want nil", err)
+	}
+
+	// link d -> b bypassing the loop detection, e.g. like a corrupted load
+	g := d.inner
+	g.linkEdge(g.keyOf("d"), g.keyOf("b"))
+	var cycle CycleError
+	if err := d.AuditAcyclicity(); !errors.As(err, &cycle) {
+		t.Fatalf("AuditAcyclicity() = %v, want CycleError", err)
+	}
+	if want := []string{"b", "c", "d"}; !reflect.DeepEqual(cycle.IDs(), want) {
+		t.Errorf("CycleError.IDs() = %v, want %v", cycle.IDs(), want)
+	}
+
+	g.linkEdge(g.keyOf("e"), g.keyOf("e"))
+	if err := d.AuditAcyclicity(); !errors.As(err, &cycle) {
+		t.Fatalf("AuditAcyclicity() = %v, want CycleError", err)
+	}
+	if want := []string{"b", "c", "d"}; !reflect.DeepEqual(cycle.IDs(), want) {
+		t.Errorf("CycleError.IDs() = %v, want %v", cycle.IDs(), want)
+	}
+}
@@ -0,0 +1,148 @@
+package dag
+
+import "sort"
+
+// linkEdge adds the edge from srcKey to dstKey to the adjacency maps and the
+// root and leaf sets of the GenericDAG without any checks or cache
+// maintenance.
+func (d *GenericDAG[T]) linkEdge(srcKey, dstKey vertexHandle) {
+	if _, exists := d.outboundEdge[srcKey]; !exists {
+		d.outboundEdge[srcKey] = make(map[vertexHandle]struct{})
+	}
+	d.outboundEdge[srcKey][dstKey] = struct{}{}
+	if _, exists := d.inboundEdge[dstKey]; !exists {
+		d.inboundEdge[dstKey] = make(map[vertexHandle]struct{})
+	}
+	d.inboundEdge[dstKey][srcKey] = struct{}{}
+	d.insertChild(srcKey, dstKey)
+	d.setLeaf(srcKey, false)
+	d.setRoot(dstKey, false)
+}
+
+// unlinkEdge removes the edge from srcKey to dstKey from the adjacency maps
+// and updates the root and leaf sets of the GenericDAG without any checks or
+// cache maintenance.
+func (d *GenericDAG[T]) unlinkEdge(srcKey, dstKey vertexHandle) {
+	delete(d.outboundEdge[srcKey], dstKey)
+	delete(d.inboundEdge[dstKey], srcKey)
+	delete(d.edgeValues, [2]vertexHandle{srcKey, dstKey})
+	d.removeChild(srcKey, dstKey)
+	if len(d.outboundEdge[srcKey]) == 0 {
+		d.setLeaf(srcKey, true)
+	}
+	if len(d.inboundEdge[dstKey]) == 0 {
+		d.setRoot(dstKey, true)
+	}
+}
+
+// setRoot adds h to or removes it from the roots, counting changes of the
+// roots in rootsVersion.
+func (d *GenericDAG[T]) setRoot(h vertexHandle, root bool) {
+	if setMember(d.roots, h, root) {
+		d.rootsVersion++
+	}
+}
+
+// setLeaf adds h to or removes it from the leaves, counting changes of the
+// leaves in leavesVersion.
+func (d *GenericDAG[T]) setLeaf(h vertexHandle, leaf bool) {
+	if setMember(d.leaves, h, leaf) {
+		d.leavesVersion++
+	}
+}
+
+// setMember adds h to or removes it from set and returns whether set has
+// changed.
+func setMember(set map[vertexHandle]struct{}, h vertexHandle, member bool) bool {
+	if _, exists := set[h]; exists == member {
+		return false
+	}
+	if member {
+		set[h] = struct{}{}
+	} else {
+		delete(set, h)
+	}
+	return true
+}
+
+// insertChild adds child to the sorted children of parent, iff the
+// GenericDAG keeps sorted children.
+func (d *GenericDAG[T]) insertChild(parent, child vertexHandle) {
+	if d.childLists == nil {
+		return
+	}
+	list := d.childLists[parent]
+	i := d.searchChild(list, child)
+	list = append(list, 0)
+	copy(list[i+1:], list[i:])
+	list[i] = child
+	d.childLists[parent] = list
+}
+
+// removeChild removes child from the sorted children of parent, iff the
+// GenericDAG keeps sorted children. The id of child must still be known.
+func (d *GenericDAG[T]) removeChild(parent, child vertexHandle) {
+	if d.childLists == nil {
+		return
+	}
+	list := d.childLists[parent]
+	i := d.searchChild(list, child)
+	if i == len(list) || list[i] != child {
+		return
+	}
+	if len(list) == 1 {
+		delete(d.childLists, parent)
+		return
+	}
+	d.childLists[parent] = append(list[:i], list[i+1:]...)
+}
+
+// searchChild returns the index at which child is (or belongs) in the sorted
+// list of children.
+func (d *GenericDAG[T]) searchChild(list []vertexHandle, child vertexHandle) int {
+	id := d.ids.id(child)
+	return sort.Search(len(list), func(i int) bool {
+		return d.ids.id(list[i]) >= id
+	})
+}
+
+// eachChild calls f for each child of the vertex with the handle h. If the
+// GenericDAG keeps sorted children, they are visited in the order of their
+// ids, otherwise as described for eachRelative.
+func (d *GenericDAG[T]) eachChild(h vertexHandle, f func(child vertexHandle)) {
+	if d.childLists != nil {
+		for _, child := range d.childLists[h] {
+			f(child)
+		}
+		return
+	}
+	d.eachRelative(d.outboundEdge[h], f)
+}
+
+// childIDs returns the ids of the children of the known vertex with id in the
+// order walks visit them.
+func (d *GenericDAG[T]) childIDs(id string) []string {
+	if d.childLists != nil {
+		list := d.childLists[d.keyOf(id)]
+		ids := make([]string, len(list))
+		for i, child := range list {
+			ids[i] = d.ids.id(child)
+		}
+		return ids
+	}
+	return d.orderedRelativeIDs(d.outboundEdge[d.keyOf(id)])
+}
+
+// orderedRelativeIDs returns the ids of the given set of relatives in
+// insertion order, if the GenericDAG preserves it, and in sorted order
+// otherwise.
+func (d *GenericDAG[T]) orderedRelativeIDs(relatives map[vertexHandle]struct{}) []string {
+	ids := make([]string, 0, len(relatives))
+	d.eachRelative(relatives, func(h vertexHandle) {
+		ids = append(ids, d.ids.id(h))
+	})
+	if !d.ids.ordered {
+		sort.Strings(ids)
+	}
+	return ids
+}
@@ -0,0 +1,45 @@
+package dag
+
+import "fmt"
+
+// Cloner is implemented by vertex values that can create deep copies of
+// themselves. DeepCopy uses it, if no clone function is given.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// DeepCopy returns a copy of the GenericDAG whose vertex values are copied
+// with clone, so that e.g. pointer values are not shared between the original
+// and the copy. If clone is nil, values must implement Cloner. Ids and edges
+// are kept. DeepCopy returns an error if clone is nil and a value does not
+// implement Cloner.
+//
+// clone is called without holding any lock of the GenericDAG.
+func (d *GenericDAG[T]) DeepCopy(clone func(T) T) (*GenericDAG[T], error) {
+	d.muDAG.RLock()
+	s := d.snapshot()
+	d.muDAG.RUnlock()
+
+	for i, v := range s.values {
+		if clone != nil {
+			s.values[i] = clone(v)
+			continue
+		}
+		c, ok := any(v).(Cloner[T])
+		if !ok {
+			return nil, fmt.Errorf("value of vertex '%s' (%T) does not implement Cloner", s.ids[i], v)
+		}
+		s.values[i] = c.Clone()
+	}
+	return materializeGeneric(s)
+}
+
+// DeepCopy returns a copy of the TypedDAG whose vertex values are copied with
+// clone. See GenericDAG.DeepCopy for details.
+func (d *TypedDAG[T]) DeepCopy(clone func(T) T) (*TypedDAG[T], error) {
+	inner, err := d.inner.DeepCopy(clone)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDAG[T]{inner: inner}, nil
+}
@@ -0,0 +1,53 @@
+package dag
+
+import "testing"
+
+type clonePerson struct {
+	Name string
+	Tags []string
+}
+
+func (p *clonePerson) Clone() *clonePerson {
+	c := *p
+	c.Tags = append([]string(nil), p.Tags...)
+	return &c
+}
+
+func TestDeepCopy(t *testing.T) {
+	d := NewGenericDAG[*clonePerson](WithDuplicateValues())
+	d.MustAddVertexByID("alice", &clonePerson{Name: "Alice", Tags: []string{"a"}})
+	d.MustAddVertexByID("bob", &clonePerson{Name: "Bob"})
+	d.MustAddEdge("alice", "bob")
+
+	for name, clone := range map[string]func(*clonePerson) *clonePerson{
+		"Cloner":   nil,
+		"function": func(p *clonePerson) *clonePerson { return p.Clone() },
+	} {
+		c, err := d.DeepCopy(clone)
+		if err != nil {
+			t.Fatalf("%s: DeepCopy failed: %v", name, err)
+		}
+		if ok, _ := c.IsEdge("alice", "bob"); !ok || c.GetOrder() != 2 {
+			t.Errorf("%s: DeepCopy did not keep the structure", name)
+		}
+		orig, _ := d.GetVertex("alice")
+		copied, _ := c.GetVertex("alice")
+		if orig == copied {
+			t.Errorf("%s: DeepCopy shares the value of alice", name)
+		}
+		copied.Tags[0] = "changed"
+		if orig.Tags[0] != "a" {
+			t.Errorf("%s: modifying the copy changed the original", name)
+		}
+	}
+
+	ints := NewGenericDAG[int]()
+	ints.MustAddVertexByID("one", 1)
+	if _, err := ints.DeepCopy(nil); err == nil {
+		t.Errorf("expected an error for values that don't implement Cloner")
+	}
+	c, err := ints.DeepCopy(func(v int) int { return v })
+	if err != nil || c.GetOrder() != 1 {
+		t.Errorf("DeepCopy with a clone function failed: %v", err)
+	}
+}
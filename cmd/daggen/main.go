@@ -0,0 +1,58 @@
+// Command daggen reads a graph serialized as JSON and writes Go source code
+// building it, see dag.GenerateGoCode.
+//
+// Usage:
+//
+//	daggen [-package name] [-func name] [-type type] [-o file] [graph.json]
+//
+// The graph is read from stdin if no file is given, and the code is written
+// to stdout if -o isn't set.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/JodeZer/dag"
+)
+
+func main() {
+	var opts dag.GoCodeOptions
+	flag.StringVar(&opts.Package, "package", "main", "package of the generated file")
+	flag.StringVar(&opts.Func, "func", "NewGraph", "name of the generated function")
+	flag.StringVar(&opts.ConstPrefix, "prefix", "Vertex", "prefix of the constants of the vertex ids")
+	flag.StringVar(&opts.ValueType, "type", "", "Go type of the vertex values (inferred if empty)")
+	output := flag.String("o", "", "output file (stdout if empty)")
+	flag.Parse()
+
+	if err := run(opts, flag.Arg(0), *output); err != nil {
+		fmt.Fprintln(os.Stderr, "daggen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(opts dag.GoCodeOptions, input, output string) error {
+	var data []byte
+	var err error
+	if input == "" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return err
+	}
+
+	var src bytes.Buffer
+	if err := dag.GenerateGoCode(&src, data, opts); err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(src.Bytes())
+		return err
+	}
+	return os.WriteFile(output, src.Bytes(), 0o644)
+}
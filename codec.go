@@ -0,0 +1,188 @@
+package dag
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Codec converts vertex values of a single type to and from text. Values with
+// a codec are serialized as the string returned by Encode instead of their
+// regular encoding.
+type Codec struct {
+	Encode func(v interface{}) (string, error)
+	Decode func(s string) (interface{}, error)
+}
+
+// CodecRegistry holds codecs for vertex value types that don't serialize well
+// on their own, e.g. time.Time (which loses its monotonic clock and location
+// name), net.IP or interface types. A CodecRegistry is passed to a DAG with
+// WithCodecs and used by all of its marshal and unmarshal functions, the
+// operation log and auto-snapshots.
+//
+// The codec for the value type of a graph takes precedence. Otherwise, the
+// codec for the dynamic type of a value is used, e.g. for a time.Time in a
+// DAG, and its encoding is tagged with the name of the type, so decoding
+// picks the same codec.
+//
+// A CodecRegistry is safe for concurrent use.
+type CodecRegistry struct {
+	mu     sync.RWMutex
+	codecs map[reflect.Type]Codec
+	types  map[string]reflect.Type
+}
+
+// NewCodecRegistry creates an empty CodecRegistry.
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{
+		codecs: make(map[reflect.Type]Codec),
+		types:  make(map[string]reflect.Type),
+	}
+}
+
+// Register sets the codec for values of type t. It replaces a codec that has
+// been registered for t before.
+func (r *CodecRegistry) Register(t reflect.Type, c Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codecs[t] = c
+	r.types[codecTypeName(t)] = t
+}
+
+// RegisterCodec sets the codec for values of type V. V may be an interface
+// type, in which case the codec is used for all values of a DAG with V as
+// value type:
+//
+//	dag.RegisterCodec(registry,
+//		func(ip net.IP) (string, error) { return ip.String(), nil },
+//		func(s string) (net.IP, error) { return net.ParseIP(s), nil })
+func RegisterCodec[V any](r *CodecRegistry, encode func(V) (string, error), decode func(string) (V, error)) {
+	r.Register(reflect.TypeOf((*V)(nil)).Elem(), Codec{
+		Encode: func(v interface{}) (string, error) {
+			typed, ok := v.(V)
+			if !ok {
+				return "", fmt.Errorf("codec for %T got a value of type %T", typed, v)
+			}
+			return encode(typed)
+		},
+		Decode: func(s string) (interface{}, error) {
+			return decode(s)
+		},
+	})
+}
+
+func (r *CodecRegistry) lookup(t reflect.Type) (Codec, bool) {
+	if r == nil || t == nil {
+		return Codec{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.codecs[t]
+	return c, ok
+}
+
+// lookupName returns the codec registered for the type named name (see
+// codecTypeName).
+func (r *CodecRegistry) lookupName(name string) (Codec, bool) {
+	if r == nil {
+		return Codec{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[name]
+	if !ok {
+		return Codec{}, false
+	}
+	c, ok := r.codecs[t]
+	return c, ok
+}
+
+// codecTypeName returns the name of t written to tag the values encoded by
+// its codec, e.g. "time.Time".
+func codecTypeName(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+// taggedValue is the serialization of a value encoded by the codec of its
+// dynamic type, if that isn't T, e.g. of a time.Time in a DAG. The tag names
+// the type, so that decodeValue can pick the same codec.
+type taggedValue struct {
+	Codec string `json:"$codec"`
+	Value string `json:"$value"`
+}
+
+// encodeValue returns what is to be serialized for v: the text produced by
+// the codec for T, else by the codec for the dynamic type of v, or v itself
+// if there is no such codec. Text produced by the codec for the dynamic type
+// is wrapped in a taggedValue, unless the dynamic type is T.
+func encodeValue[T any](r *CodecRegistry, v T) (interface{}, error) {
+	static := reflect.TypeOf((*T)(nil)).Elem()
+	dynamic := reflect.TypeOf(v)
+	c, ok := r.lookup(static)
+	tagged := false
+	if !ok {
+		c, ok = r.lookup(dynamic)
+		tagged = dynamic != static
+	}
+	if !ok {
+		return v, nil
+	}
+	s, err := c.Encode(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %T: %w", v, err)
+	}
+	if tagged {
+		return taggedValue{codecTypeName(dynamic), s}, nil
+	}
+	return s, nil
+}
+
+// decodeValue parses the serialized value data, using the codec named by its
+// tag if it is a taggedValue, else the codec for T if there is one.
+func decodeValue[T any](r *CodecRegistry, data json.RawMessage) (T, error) {
+	var v T
+	c, ok := r.lookup(reflect.TypeOf((*T)(nil)).Elem())
+	var s string
+	if tag, isTagged := decodeTaggedValue(data); isTagged {
+		if c, ok = r.lookupName(tag.Codec); !ok {
+			return v, fmt.Errorf("decoding %T: no codec for %s", v, tag.Codec)
+		}
+		s = tag.Value
+	} else if !ok {
+		err := json.Unmarshal(data, &v)
+		return v, err
+	} else if err := json.Unmarshal(data, &s); err != nil {
+		return v, fmt.Errorf("decoding %T: %w", v, err)
+	}
+	decoded, err := c.Decode(s)
+	if err != nil {
+		return v, fmt.Errorf("decoding %T: %w", v, err)
+	}
+	typed, ok := decoded.(T)
+	if !ok && decoded != nil {
+		return v, fmt.Errorf("codec for %T returned a value of type %T", v, decoded)
+	}
+	return typed, nil
+}
+
+// decodeTaggedValue returns the taggedValue encoded as data, if data is an
+// object of exactly its fields.
+func decodeTaggedValue(data json.RawMessage) (taggedValue, bool) {
+	var tag taggedValue
+	if len(data) == 0 || data[0] != '{' {
+		return tag, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || len(fields) != 2 {
+		return tag, false
+	}
+	if err := json.Unmarshal(data, &tag); err != nil || tag.Codec == "" {
+		return tag, false
+	}
+	_, hasValue := fields["$value"]
+	return tag, hasValue
+}
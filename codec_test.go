@@ -0,0 +1,200 @@
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type codecShape interface {
+	Area() int
+}
+
+type codecSquare struct{ Side int }
+
+func (s codecSquare) Area() int { return s.Side * s.Side }
+
+func newTestCodecs() *CodecRegistry {
+	r := NewCodecRegistry()
+	RegisterCodec(r,
+		func(ip net.IP) (string, error) { return ip.String(), nil },
+		func(s string) (net.IP, error) {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip %q", s)
+			}
+			return ip, nil
+		})
+	RegisterCodec(r,
+		func(s codecShape) (string, error) { return fmt.Sprintf("square:%d", s.(codecSquare).Side), nil },
+		func(s string) (codecShape, error) {
+			var side int
+			_, err := fmt.Sscanf(s, "square:%d", &side)
+			return codecSquare{side}, err
+		})
+	return r
+}
+
+func TestCodecGenericJSON(t *testing.T) {
+	codecs := newTestCodecs()
+	d := NewGenericDAG[net.IP](WithCodecs(codecs), WithDuplicateValues())
+	d.MustAddVertexByID("a", net.ParseIP("10.0.0.1"))
+	d.MustAddVertexByID("b", net.ParseIP("::1"))
+	d.MustAddEdge("a", "b")
+
+	data, err := d.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"10.0.0.1"`) {
+		t.Errorf("MarshalJSON() = %s, want the codec's encoding", data)
+	}
+	restored, err := UnmarshalGenericJSON[net.IP](data, Options{Codecs: codecs, AllowDuplicateValues: true})
+	if err != nil {
+		t.Fatalf("UnmarshalGenericJSON failed: %v", err)
+	}
+	if ip, _ := restored.GetVertex("b"); !ip.Equal(net.IPv6loopback) {
+		t.Errorf("restored b = %v, want ::1", ip)
+	}
+	if ok, _ := restored.IsEdge("a", "b"); !ok {
+		t.Errorf("restored graph lacks the edge a -> b")
+	}
+
+	// decoding errors are reported
+	bad := strings.Replace(string(data), "10.0.0.1", "no ip", 1)
+	if _, err := UnmarshalGenericJSON[net.IP]([]byte(bad), Options{Codecs: codecs}); err == nil {
+		t.Errorf("expected an error for an invalid encoding")
+	}
+}
+
+func TestCodecInterfaceValues(t *testing.T) {
+	codecs := newTestCodecs()
+	d := New[codecShape](WithCodecs(codecs))
+	d.MustAddVertexByID("s", codecSquare{3})
+
+	data, err := d.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	restored, err := UnmarshalJSON[codecShape](data, Options{Codecs: codecs})
+	if err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if s, _ := restored.GetVertex("s"); s == nil || s.Area() != 9 {
+		t.Errorf("restored s = %v, want a square with side 3", s)
+	}
+
+	// without a codec, interface values can't be decoded
+	if _, err := UnmarshalJSON[codecShape](data, Options{}); err == nil {
+		t.Errorf("expected an error without codecs")
+	}
+}
+
+func TestCodecDAG(t *testing.T) {
+	codecs := NewCodecRegistry()
+	RegisterCodec(codecs,
+		func(t time.Time) (string, error) { return t.Format(time.RFC3339Nano), nil },
+		func(s string) (time.Time, error) { return time.Parse(time.RFC3339Nano, s) })
+	start := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	d := NewDAG(WithCodecs(codecs))
+	d.MustAddVertexByID("start", start)
+	d.MustAddVertexByID("end", start.Add(time.Hour))
+	d.MustAddEdge("start", "end")
+
+	for name, marshal := range map[string]func(*DAG) ([]byte, error){
+		"MarshalJSON":    (*DAG).MarshalJSON,
+		"MarshalGeneric": MarshalGeneric[time.Time],
+	} {
+		data, err := marshal(d)
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		if !strings.Contains(string(data), `"2024-01-02T03:04:05.000000006Z"`) {
+			t.Errorf("%s() = %s, want the codec's encoding", name, data)
+		}
+		restored, err := UnmarshalJSONGeneric[time.Time](data, Options{Codecs: codecs})
+		if err != nil {
+			t.Fatalf("UnmarshalJSONGeneric failed: %v", err)
+		}
+		if v, _ := restored.GetVertex("start"); v != start {
+			t.Errorf("%s: restored start = %v, want %v", name, v, start)
+		}
+	}
+}
+
+func TestCodecOpLog(t *testing.T) {
+	codecs := newTestCodecs()
+	d := NewGenericDAG[net.IP](WithCodecs(codecs), WithDuplicateValues())
+	var log OpLog
+	r := d.Record(&log)
+	d.MustAddVertexByID("a", net.ParseIP("10.0.0.1"))
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if string(log[0].Value) != `"10.0.0.1"` {
+		t.Errorf("recorded value %s, want the codec's encoding", log[0].Value)
+	}
+	replayed := NewGenericDAG[net.IP](WithCodecs(codecs), WithDuplicateValues())
+	if err := replayed.Replay(log); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if ip, _ := replayed.GetVertex("a"); !ip.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("replayed a = %v", ip)
+	}
+}
+
+func TestMarshalGenericConversionError(t *testing.T) {
+	d := NewDAG()
+	d.MustAddVertexByID("a", "not a number")
+	if _, err := MarshalGeneric[int](d); err == nil {
+		t.Errorf("expected an error for a value that can't be converted")
+	}
+
+	codecs := NewCodecRegistry()
+	errEncode := errors.New("cannot encode")
+	RegisterCodec(codecs,
+		func(string) (string, error) { return "", errEncode },
+		func(s string) (string, error) { return s, nil })
+	d = NewDAG(WithCodecs(codecs))
+	d.MustAddVertexByID("a", "value")
+	if _, err := d.MarshalJSON(); !errors.Is(err, errEncode) {
+		t.Errorf("MarshalJSON() = %v, want %v", err, errEncode)
+	}
+}
+
+func TestCodecDAGRoundTrip(t *testing.T) {
+	codecs := NewCodecRegistry()
+	RegisterCodec(codecs,
+		func(t time.Time) (string, error) { return t.Format(time.RFC3339Nano), nil },
+		func(s string) (time.Time, error) { return time.Parse(time.RFC3339Nano, s) })
+	start := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	d := NewDAG(WithCodecs(codecs))
+	d.MustAddVertexByID("start", start)
+	d.MustAddVertexByID("name", "2024-01-02T03:04:05.000000006Z")
+	d.MustAddEdge("start", "name")
+
+	data, err := d.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	restored, err := UnmarshalJSONGeneric[interface{}](data, Options{Codecs: codecs})
+	if err != nil {
+		t.Fatalf("UnmarshalJSONGeneric failed: %v", err)
+	}
+	if v, _ := restored.GetVertex("start"); v != start {
+		t.Errorf("restored start = %#v, want %v", v, start)
+	}
+	if v, _ := restored.GetVertex("name"); v != "2024-01-02T03:04:05.000000006Z" {
+		t.Errorf("restored name = %#v, want the string", v)
+	}
+
+	// a tag without a codec is an error rather than a string
+	if _, err := UnmarshalJSONGeneric[interface{}](data, Options{Codecs: NewCodecRegistry()}); err == nil {
+		t.Error("expected an error for a tagged value without its codec")
+	}
+}
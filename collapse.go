@@ -0,0 +1,240 @@
+package dag
+
+import (
+	"sort"
+	"sync"
+)
+
+// CollapsedView is a view of a GenericDAG, in which groups of vertices appear
+// as single vertices, e.g. to render graphs too large to show every vertex.
+// The edges of a group are those of its members to vertices outside of the
+// group. Groups may contain other groups, so graphs can be collapsed
+// hierarchically and expanded level by level.
+//
+// The view doesn't copy the graph: Graph applies the groups to the graph as
+// it is at the time of the call. Members deleted from the graph in the
+// meantime are skipped. A CollapsedView is safe for concurrent use.
+type CollapsedView[T any] struct {
+	d      *GenericDAG[T]
+	mu     sync.Mutex
+	groups map[string]*vertexGroup[T]
+	owners map[string]string
+}
+
+// vertexGroup is a group of vertices and groups collapsed into a single
+// vertex with the given value.
+type vertexGroup[T any] struct {
+	value   T
+	members []string
+}
+
+// CollapsedView returns a new view of the GenericDAG without any groups. See
+// CollapsedView for details.
+func (d *GenericDAG[T]) CollapsedView() *CollapsedView[T] {
+	return &CollapsedView[T]{
+		d:      d,
+		groups: make(map[string]*vertexGroup[T]),
+		owners: make(map[string]string),
+	}
+}
+
+// CollapseGroup collapses the vertices and groups with the given ids into a
+// single vertex with the id groupID and the value groupValue.
+// CollapseGroup returns an error if groupID is empty or already the id of a
+// vertex or group, if an id is unknown, if an id already belongs to a group,
+// or if the collapsed graph would contain a cycle (a CycleError), e.g.
+// because a path between two members leaves the group. The view is unchanged
+// in this case.
+func (v *CollapsedView[T]) CollapseGroup(ids []string, groupID string, groupValue T) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if groupID == "" {
+		return IDEmptyError{}
+	}
+	if _, exists := v.groups[groupID]; exists || v.isVertex(groupID) {
+		return IDDuplicateError{groupID}
+	}
+	members := make([]string, 0, len(ids))
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, exists := seen[id]; exists {
+			continue
+		}
+		seen[id] = struct{}{}
+		if id == "" {
+			return IDEmptyError{}
+		}
+		if _, exists := v.groups[id]; !exists && !v.isVertex(id) {
+			return IDUnknownError{id}
+		}
+		if owner, grouped := v.owners[id]; grouped {
+			return GroupMemberError{id, owner}
+		}
+		members = append(members, id)
+	}
+	sort.Strings(members)
+
+	v.groups[groupID] = &vertexGroup[T]{value: groupValue, members: members}
+	for _, id := range members {
+		v.owners[id] = groupID
+	}
+	if _, err := v.graph(); err != nil {
+		for _, id := range members {
+			delete(v.owners, id)
+		}
+		delete(v.groups, groupID)
+		return err
+	}
+	return nil
+}
+
+// isVertex returns true, if id is the id of a vertex of the graph.
+func (v *CollapsedView[T]) isVertex(id string) bool {
+	v.d.muDAG.RLock()
+	defer v.d.muDAG.RUnlock()
+	_, exists := v.d.ids.handle(id)
+	return exists
+}
+
+// Expand dissolves the group with the given id, so that its members appear in
+// its place, or in the group it belongs to.
+// Expand returns an error if groupID is empty or unknown, or if the expanded
+// graph would contain a cycle (a CycleError), which happens only if the graph
+// changed since the group was collapsed. The view is unchanged in this case.
+func (v *CollapsedView[T]) Expand(groupID string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if groupID == "" {
+		return IDEmptyError{}
+	}
+	g, exists := v.groups[groupID]
+	if !exists {
+		return IDUnknownError{groupID}
+	}
+	owner, grouped := v.owners[groupID]
+
+	delete(v.groups, groupID)
+	delete(v.owners, groupID)
+	for _, id := range g.members {
+		if grouped {
+			v.owners[id] = owner
+		} else {
+			delete(v.owners, id)
+		}
+	}
+	if _, err := v.graph(); err != nil {
+		v.groups[groupID] = g
+		if grouped {
+			v.owners[groupID] = owner
+		}
+		for _, id := range g.members {
+			v.owners[id] = groupID
+		}
+		return err
+	}
+	return nil
+}
+
+// Members returns the ids of the vertices and groups directly collapsed into
+// the group with the given id, in sorted order.
+// Members returns an error if groupID is empty or unknown.
+func (v *CollapsedView[T]) Members(groupID string) ([]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if groupID == "" {
+		return nil, IDEmptyError{}
+	}
+	g, exists := v.groups[groupID]
+	if !exists {
+		return nil, IDUnknownError{groupID}
+	}
+	return append([]string(nil), g.members...), nil
+}
+
+// Graph returns a new GenericDAG, in which each outermost group replaces its
+// members. As groups may hold the values of their members, the new GenericDAG
+// allows duplicate values. Edges between ungrouped vertices keep their
+// values, while edges to and from groups have none. Graph returns a CycleError, if the graph changed
+// since the groups were collapsed in a way that the collapsed graph would
+// contain a cycle, and an IDDuplicateError, if a vertex with the id of a
+// group has been added.
+func (v *CollapsedView[T]) Graph() (*GenericDAG[T], error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.graph()
+}
+
+// graph is Graph, which must be called with v.mu held.
+func (v *CollapsedView[T]) graph() (*GenericDAG[T], error) {
+	v.d.muDAG.RLock()
+	s := v.d.snapshot()
+	v.d.muDAG.RUnlock()
+
+	// outermost returns the id of the outermost group id belongs to, or id
+	// itself
+	outermost := func(id string) string {
+		for {
+			owner, grouped := v.owners[id]
+			if !grouped {
+				return id
+			}
+			id = owner
+		}
+	}
+
+	collapsed := graphSnapshot[T]{options: s.options}
+	collapsed.options.AllowDuplicateValues = true
+	for i, id := range s.ids {
+		if _, grouped := v.owners[id]; !grouped {
+			collapsed.ids = append(collapsed.ids, id)
+			collapsed.values = append(collapsed.values, s.values[i])
+		}
+	}
+	groupIDs := make([]string, 0, len(v.groups))
+	for id := range v.groups {
+		if _, grouped := v.owners[id]; !grouped {
+			groupIDs = append(groupIDs, id)
+		}
+	}
+	sort.Strings(groupIDs)
+	for _, id := range groupIDs {
+		collapsed.ids = append(collapsed.ids, id)
+		collapsed.values = append(collapsed.values, v.groups[id].value)
+	}
+
+	edges := make(map[[2]string]struct{}, len(s.edges))
+	for _, e := range s.edges {
+		src, dst := outermost(e[0]), outermost(e[1])
+		if src == dst {
+			continue
+		}
+		if _, exists := edges[[2]string{src, dst}]; !exists {
+			edges[[2]string{src, dst}] = struct{}{}
+			collapsed.edges = append(collapsed.edges, [2]string{src, dst})
+		}
+	}
+
+	for _, e := range s.edgeValues {
+		if outermost(e.SrcID) == e.SrcID && outermost(e.DstID) == e.DstID {
+			collapsed.edgeValues = append(collapsed.edgeValues, e)
+		}
+	}
+
+	g, err := materializeGeneric(collapsed)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.AuditAcyclicity(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// CollapsedView returns a new view of the TypedDAG without any groups. See
+// GenericDAG.CollapsedView for details.
+func (d *TypedDAG[T]) CollapsedView() *CollapsedView[T] {
+	return d.inner.CollapsedView()
+}
@@ -0,0 +1,105 @@
+package dag
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCollapsedView(t *testing.T) {
+	// a -> b -> c -> e, b -> d -> e
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, i)
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "e"}, {"b", "d"}, {"d", "e"}} {
+		_ = d.AddEdge(e[0], e[1])
+	}
+	edgesOf := func(v *CollapsedView[int]) []string {
+		g, err := v.Graph()
+		if err != nil {
+			t.Fatalf("Graph() unexpected error: %v", err)
+		}
+		var edges []string
+		for _, e := range g.GetEdges().Edges {
+			edges = append(edges, e.SrcID+e.DstID)
+		}
+		sort.Strings(edges)
+		return edges
+	}
+
+	v := d.CollapsedView()
+	if err := v.CollapseGroup([]string{"c", "d"}, "cd", 9); err != nil {
+		t.Fatalf("CollapseGroup(c, d) unexpected error: %v", err)
+	}
+	if got, want := edgesOf(v), []string{"ab", "bcd", "cde"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+	if err := v.CollapseGroup([]string{"b", "cd"}, "bcd", 8); err != nil {
+		t.Fatalf("CollapseGroup(b, cd) unexpected error: %v", err)
+	}
+	g, _ := v.Graph()
+	if g.GetOrder() != 3 {
+		t.Errorf("GetOrder() = %d, want 3", g.GetOrder())
+	}
+	if val, _ := g.GetVertex("bcd"); val != 8 {
+		t.Errorf("GetVertex(bcd) = %d, want 8", val)
+	}
+
+	if err := v.CollapseGroup([]string{"a", "e"}, "ae", 7); !errors.As(err, &CycleError{}) {
+		t.Errorf("CollapseGroup(a, e) = %v, want CycleError", err)
+	}
+	if err := v.CollapseGroup([]string{"c"}, "x", 7); err != (GroupMemberError{"c", "cd"}) {
+		t.Errorf("CollapseGroup(c) = %v, want GroupMemberError", err)
+	}
+	if err := v.CollapseGroup([]string{"a"}, "e", 7); err != (IDDuplicateError{"e"}) {
+		t.Errorf("CollapseGroup(a) into e = %v, want IDDuplicateError", err)
+	}
+	if err := v.CollapseGroup([]string{"z"}, "x", 7); err != (IDUnknownError{"z"}) {
+		t.Errorf("CollapseGroup(z) = %v, want IDUnknownError", err)
+	}
+
+	if err := v.Expand("bcd"); err != nil {
+		t.Fatalf("Expand(bcd) unexpected error: %v", err)
+	}
+	if got, want := edgesOf(v), []string{"ab", "bcd", "cde"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("edges after Expand(bcd) = %v, want %v", got, want)
+	}
+	if members, _ := v.Members("cd"); !reflect.DeepEqual(members, []string{"c", "d"}) {
+		t.Errorf("Members(cd) = %v, want [c d]", members)
+	}
+	if err := v.Expand("bcd"); err != (IDUnknownError{"bcd"}) {
+		t.Errorf("Expand(bcd) twice = %v, want IDUnknownError", err)
+	}
+	_ = v.Expand("cd")
+	if got := edgesOf(v); len(got) != d.GetSize() {
+		t.Errorf("edges after expanding all = %v, want those of the graph", got)
+	}
+}
+
+func TestCollapsedView_EdgeValues(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, i)
+	}
+	_ = d.AddEdge("a", "b")
+	_ = d.AddEdge("b", "c")
+	_ = d.SetEdgeValue("a", "b", "ab")
+	_ = d.SetEdgeValue("b", "c", "bc")
+
+	v := d.CollapsedView()
+	if err := v.CollapseGroup([]string{"c"}, "g", 9); err != nil {
+		t.Fatalf("CollapseGroup(c) unexpected error: %v", err)
+	}
+	g, err := v.Graph()
+	if err != nil {
+		t.Fatalf("Graph() unexpected error: %v", err)
+	}
+	if value, _ := g.GetEdgeValue("a", "b"); value != "ab" {
+		t.Errorf("GetEdgeValue(a, b) = %v, want ab", value)
+	}
+	if value, _ := g.GetEdgeValue("b", "g"); value != nil {
+		t.Errorf("GetEdgeValue(b, g) = %v, want nil", value)
+	}
+}
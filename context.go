@@ -0,0 +1,198 @@
+package dag
+
+import "context"
+
+// cancelCheckInterval is the number of steps after which a long-running
+// operation checks whether its context is done.
+const cancelCheckInterval = 1024
+
+// cancelChecker checks a context for cancellation every cancelCheckInterval
+// steps, as checking on every step would slow down the operation noticeably.
+// A nil cancelChecker never reports a cancellation.
+type cancelChecker struct {
+	ctx   context.Context
+	steps int
+}
+
+func newCancelChecker(ctx context.Context) *cancelChecker {
+	return &cancelChecker{ctx: ctx}
+}
+
+// err counts a step and returns the error of the context if it is done.
+func (c *cancelChecker) err() error {
+	if c == nil {
+		return nil
+	}
+	c.steps++
+	if c.steps%cancelCheckInterval != 1 {
+		return nil
+	}
+	return c.ctx.Err()
+}
+
+// GetDescendantsContext is like GetDescendants, but returns the error of ctx
+// if ctx is done before all descendants have been collected. Descendants
+// already cached are returned right away.
+func (d *GenericDAG[T]) GetDescendantsContext(ctx context.Context, id string) (map[string]T, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.collectDescendants(id, nil, newCancelChecker(ctx))
+}
+
+// collectDescendants returns the descendants of the vertex with id from the
+// cache, or else collects and caches them, counting the vertices visited and
+// descendants found against budget and checking c on each step. Descendants
+// already cached only count as results. collectDescendants must be called
+// with the graph read-locked.
+func (d *GenericDAG[T]) collectDescendants(id string, budget *traversalBudget, c *cancelChecker) (map[string]T, error) {
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	vHash := d.keyOf(id)
+
+	d.muCache.RLock()
+	cache, exists := d.descendantsCache[vHash]
+	d.muCache.RUnlock()
+	if exists {
+		d.descendantsStats.hits.Add(1)
+		if budget != nil && budget.limits.MaxResults > 0 && len(cache) > budget.limits.MaxResults {
+			return nil, LimitExceededError{"results", budget.limits.MaxResults}
+		}
+	} else {
+		d.descendantsStats.misses.Add(1)
+		cache = make(map[vertexHandle]struct{})
+		fifo := []vertexHandle{vHash}
+		for len(fifo) > 0 {
+			if err := c.err(); err != nil {
+				return nil, err
+			}
+			if err := budget.visit(); err != nil {
+				return nil, err
+			}
+			top := fifo[0]
+			fifo = fifo[1:]
+			for child := range d.outboundEdge[top] {
+				if _, seen := cache[child]; !seen {
+					if err := budget.result(); err != nil {
+						return nil, err
+					}
+					cache[child] = struct{}{}
+					fifo = append(fifo, child)
+				}
+			}
+		}
+		if !d.options.DisableCache {
+			d.muCache.Lock()
+			d.descendantsCache[vHash] = cache
+			d.muCache.Unlock()
+		}
+	}
+
+	descendants := make(map[string]T, len(cache))
+	for dv := range cache {
+		descendants[d.ids.id(dv)] = d.values[dv]
+	}
+	return descendants, nil
+}
+
+// ReduceTransitivelyContext is like ReduceTransitively, but returns the error
+// of ctx if ctx is done before the reduction has been computed. The graph is
+// then left unchanged.
+func (d *GenericDAG[T]) ReduceTransitivelyContext(ctx context.Context) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	return d.reduceTransitively(newCancelChecker(ctx))
+}
+
+// CopyContext is like Copy, but returns the error of ctx if ctx is done
+// before the copy has been built.
+func (d *GenericDAG[T]) CopyContext(ctx context.Context) (*GenericDAG[T], error) {
+	d.muDAG.RLock()
+	s := d.snapshot()
+	d.muDAG.RUnlock()
+	return materializeChecked(s, newCancelChecker(ctx))
+}
+
+// GetDescendantsGraphContext is like GetDescendantsGraph, but returns the
+// error of ctx if ctx is done before the subgraph has been built.
+func (d *GenericDAG[T]) GetDescendantsGraphContext(ctx context.Context, id string) (*GenericDAG[T], string, error) {
+	return d.getRelativesGraph(id, nil, false, newCancelChecker(ctx), nil)
+}
+
+// GetAncestorsGraphContext is like GetAncestorsGraph, but returns the error
+// of ctx if ctx is done before the subgraph has been built.
+func (d *GenericDAG[T]) GetAncestorsGraphContext(ctx context.Context, id string) (*GenericDAG[T], string, error) {
+	return d.getRelativesGraph(id, nil, true, newCancelChecker(ctx), nil)
+}
+
+// CopyContext is like Copy, but returns the error of ctx if ctx is done
+// before the copy has been built.
+func (d *DAG) CopyContext(ctx context.Context) (*DAG, error) {
+	core, err := d.dagCore.CopyContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &DAG{core}, nil
+}
+
+// GetDescendantsGraphContext is like GetDescendantsGraph, but returns the
+// error of ctx if ctx is done before the subgraph has been built.
+func (d *DAG) GetDescendantsGraphContext(ctx context.Context, id string) (*DAG, string, error) {
+	core, newId, err := d.dagCore.GetDescendantsGraphContext(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return &DAG{core}, newId, nil
+}
+
+// GetAncestorsGraphContext is like GetAncestorsGraph, but returns the error
+// of ctx if ctx is done before the subgraph has been built.
+func (d *DAG) GetAncestorsGraphContext(ctx context.Context, id string) (*DAG, string, error) {
+	core, newId, err := d.dagCore.GetAncestorsGraphContext(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return &DAG{core}, newId, nil
+}
+
+// GetDescendantsContext is like GetDescendants, but returns the error of ctx
+// if ctx is done before all descendants have been collected.
+func (d *TypedDAG[T]) GetDescendantsContext(ctx context.Context, id string) (map[string]T, error) {
+	return d.inner.GetDescendantsContext(ctx, id)
+}
+
+// ReduceTransitivelyContext is like ReduceTransitively, but returns the error
+// of ctx if ctx is done before the reduction has been computed.
+func (d *TypedDAG[T]) ReduceTransitivelyContext(ctx context.Context) error {
+	return d.inner.ReduceTransitivelyContext(ctx)
+}
+
+// CopyContext is like Copy, but returns the error of ctx if ctx is done
+// before the copy has been built.
+func (d *TypedDAG[T]) CopyContext(ctx context.Context) (*TypedDAG[T], error) {
+	inner, err := d.inner.CopyContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDAG[T]{inner: inner}, nil
+}
+
+// GetDescendantsGraphContext is like GetDescendantsGraph, but returns the
+// error of ctx if ctx is done before the subgraph has been built.
+func (d *TypedDAG[T]) GetDescendantsGraphContext(ctx context.Context, id string) (*TypedDAG[T], string, error) {
+	inner, newId, err := d.inner.GetDescendantsGraphContext(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return &TypedDAG[T]{inner: inner}, newId, nil
+}
+
+// GetAncestorsGraphContext is like GetAncestorsGraph, but returns the error
+// of ctx if ctx is done before the subgraph has been built.
+func (d *TypedDAG[T]) GetAncestorsGraphContext(ctx context.Context, id string) (*TypedDAG[T], string, error) {
+	inner, newId, err := d.inner.GetAncestorsGraphContext(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return &TypedDAG[T]{inner: inner}, newId, nil
+}
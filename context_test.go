@@ -0,0 +1,82 @@
+package dag
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestContextVariants(t *testing.T) {
+	newGraph := func() *GenericDAG[int] {
+		d, err := GenerateRandomDAG(7, 200, 1200, func(i int) int { return i })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	d := newGraph()
+	ctx := context.Background()
+
+	want, _ := d.GetDescendants("node_0")
+	got, err := newGraph().GetDescendantsContext(ctx, "node_0")
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDescendantsContext() = %d descendants, %v, want %d", len(got), err, len(want))
+	}
+	if got, _ := d.GetDescendantsContext(ctx, "node_0"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDescendantsContext() from cache = %d descendants, want %d", len(got), len(want))
+	}
+
+	sameGraph := func(a, b *GenericDAG[int]) bool {
+		return a.GetOrder() == b.GetOrder() && reflect.DeepEqual(sortedEdges(a.GetEdges()), sortedEdges(b.GetEdges()))
+	}
+	copied, err := d.CopyContext(ctx)
+	if err != nil || !sameGraph(copied, d) {
+		t.Errorf("CopyContext() = %v, %v, want a copy", copied, err)
+	}
+	sub, _, err := d.GetAncestorsGraphContext(ctx, "node_99")
+	if want, _, _ := d.GetAncestorsGraph("node_99"); err != nil || !sameGraph(sub, want) {
+		t.Errorf("GetAncestorsGraphContext() = %v, %v, want %v", sub, err, want)
+	}
+
+	reduced := newGraph()
+	if err := reduced.ReduceTransitivelyContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	d.ReduceTransitively()
+	if !sameGraph(reduced, d) {
+		t.Error("ReduceTransitivelyContext() differs from ReduceTransitively()")
+	}
+}
+
+func TestContextVariantsCancelled(t *testing.T) {
+	d := NewGenericDAG[int]()
+	for i := 0; i < 3; i++ {
+		_ = d.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = d.AddEdge("0", "1")
+	_ = d.AddEdge("1", "2")
+	_ = d.AddEdge("0", "2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.GetDescendantsContext(ctx, "0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetDescendantsContext() = %v, want context.Canceled", err)
+	}
+	if _, err := d.CopyContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("CopyContext() = %v, want context.Canceled", err)
+	}
+	if _, _, err := d.GetDescendantsGraphContext(ctx, "0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetDescendantsGraphContext() = %v, want context.Canceled", err)
+	}
+	if err := d.ReduceTransitivelyContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReduceTransitivelyContext() = %v, want context.Canceled", err)
+	}
+	if d.GetSize() != 3 {
+		t.Errorf("cancelled ReduceTransitivelyContext() modified the graph: %v", d)
+	}
+	if _, err := d.GetDescendantsContext(ctx, "x"); err != (IDUnknownError{"x"}) {
+		t.Errorf("GetDescendantsContext(x) = %v, want IDUnknownError", err)
+	}
+}
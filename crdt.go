@@ -0,0 +1,393 @@
+package dag
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// MergeableDAG is a graph that several writers build independently, e.g.
+// offline or in different processes, each on its own replica, and that is
+// assembled by merging the replicas (a conflict-free replicated data type).
+// Merging is commutative, associative and idempotent: replicas that have
+// merged the same changes in any order, any number of times, are equal.
+//
+// Vertices and edges are added and deleted by writers like on a DAG, but
+// without any checks that depend on the state of other replicas:
+//
+//   - A deletion only deletes the additions its replica has seen (observed
+//     remove), which are kept as tombstones, so a concurrent addition of the
+//     same vertex or edge survives the merge.
+//   - If a vertex has been added with different values concurrently, the
+//     value of the last addition wins, which is determined by a logical clock
+//     and the name of the replica.
+//   - Edges may be added before their vertices. They are part of the graph
+//     once both vertices are.
+//   - The value of an edge is the value set last, like the value of a vertex.
+//     It is kept while the edge is deleted and added again.
+//   - Loops can't be detected when edges are added, thus Graph quarantines
+//     the edges that close loops.
+//
+// A MergeableDAG is safe for concurrent use.
+type MergeableDAG[T any] struct {
+	mu       sync.Mutex
+	replica  string
+	clock    uint64
+	options  []Option
+	vertices map[string]*crdtVertex[T]
+	edges    map[GenericEdge]*crdtEdge
+}
+
+// crdtTag identifies a single addition of a vertex or edge.
+type crdtTag struct {
+	Replica string `json:"r"`
+	Clock   uint64 `json:"c"`
+}
+
+// after reports whether t happened after o, or wins over o if they are
+// concurrent.
+func (t crdtTag) after(o crdtTag) bool {
+	if t.Clock != o.Clock {
+		return t.Clock > o.Clock
+	}
+	return t.Replica > o.Replica
+}
+
+// crdtVertex holds the additions of a vertex that haven't been deleted, and
+// the tombstones of those that have.
+type crdtVertex[T any] struct {
+	adds    map[crdtTag]T
+	removed map[crdtTag]struct{}
+}
+
+// crdtEdge holds the additions of an edge that haven't been deleted, and the
+// tombstones of those that have, along with the value set last and the tag
+// of this setting, which is zero if no value has been set.
+type crdtEdge struct {
+	adds     map[crdtTag]struct{}
+	removed  map[crdtTag]struct{}
+	value    interface{}
+	valueTag crdtTag
+}
+
+// NewMergeableDAG creates an empty replica of a MergeableDAG. The name of the
+// replica must be unique among all replicas merged with each other. The
+// options configure the graphs returned by Graph.
+func NewMergeableDAG[T any](replica string, opts ...Option) *MergeableDAG[T] {
+	return &MergeableDAG[T]{
+		replica:  replica,
+		options:  opts,
+		vertices: make(map[string]*crdtVertex[T]),
+		edges:    make(map[GenericEdge]*crdtEdge),
+	}
+}
+
+// tick advances the logical clock and returns the tag of a new addition.
+func (m *MergeableDAG[T]) tick() crdtTag {
+	m.clock++
+	return crdtTag{Replica: m.replica, Clock: m.clock}
+}
+
+func (m *MergeableDAG[T]) vertex(id string) *crdtVertex[T] {
+	v, ok := m.vertices[id]
+	if !ok {
+		v = &crdtVertex[T]{adds: make(map[crdtTag]T), removed: make(map[crdtTag]struct{})}
+		m.vertices[id] = v
+	}
+	return v
+}
+
+func (m *MergeableDAG[T]) edge(e GenericEdge) *crdtEdge {
+	edge, ok := m.edges[e]
+	if !ok {
+		edge = &crdtEdge{adds: make(map[crdtTag]struct{}), removed: make(map[crdtTag]struct{})}
+		m.edges[e] = edge
+	}
+	return edge
+}
+
+// AddVertex adds the vertex with id and value v, or replaces the value of the
+// vertex if it has been added already. AddVertex returns an error if id is
+// empty.
+func (m *MergeableDAG[T]) AddVertex(id string, v T) error {
+	if id == "" {
+		return IDEmptyError{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	vertex := m.vertex(id)
+	for tag := range vertex.adds {
+		delete(vertex.adds, tag)
+		vertex.removed[tag] = struct{}{}
+	}
+	vertex.adds[m.tick()] = v
+	return nil
+}
+
+// DeleteVertex deletes the vertex with id, as far as it has been added on or
+// merged into this replica. Its edges are kept, but aren't part of the graph
+// as long as the vertex is deleted. DeleteVertex returns an error if id is
+// empty or unknown.
+func (m *MergeableDAG[T]) DeleteVertex(id string) error {
+	if id == "" {
+		return IDEmptyError{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	vertex, ok := m.vertices[id]
+	if !ok || len(vertex.adds) == 0 {
+		return IDUnknownError{id}
+	}
+	for tag := range vertex.adds {
+		delete(vertex.adds, tag)
+		vertex.removed[tag] = struct{}{}
+	}
+	return nil
+}
+
+// AddEdge adds an edge from srcID to dstID, whose vertices may be added
+// later on. AddEdge returns an error if srcID or dstID are empty or equal.
+func (m *MergeableDAG[T]) AddEdge(srcID, dstID string) error {
+	if srcID == "" || dstID == "" {
+		return IDEmptyError{}
+	}
+	if srcID == dstID {
+		return SrcDstEqualError{srcID, dstID}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.edge(GenericEdge{SrcID: srcID, DstID: dstID}).adds[m.tick()] = struct{}{}
+	return nil
+}
+
+// DeleteEdge deletes the edge from srcID to dstID, as far as it has been
+// added on or merged into this replica. DeleteEdge returns an error if there
+// is no such edge.
+func (m *MergeableDAG[T]) DeleteEdge(srcID, dstID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	edge, ok := m.edges[GenericEdge{SrcID: srcID, DstID: dstID}]
+	if !ok || len(edge.adds) == 0 {
+		return EdgeUnknownError{srcID, dstID}
+	}
+	for tag := range edge.adds {
+		delete(edge.adds, tag)
+		edge.removed[tag] = struct{}{}
+	}
+	return nil
+}
+
+// SetEdgeValue sets the value of the edge from srcID to dstID, or removes it
+// if value is nil. SetEdgeValue returns an error if there is no such edge.
+func (m *MergeableDAG[T]) SetEdgeValue(srcID, dstID string, value interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	edge, ok := m.edges[GenericEdge{SrcID: srcID, DstID: dstID}]
+	if !ok || len(edge.adds) == 0 {
+		return EdgeUnknownError{srcID, dstID}
+	}
+	edge.value, edge.valueTag = value, m.tick()
+	return nil
+}
+
+// Merge merges the additions and deletions of other into this replica.
+func (m *MergeableDAG[T]) Merge(other *MergeableDAG[T]) {
+	// capture other first, so that two replicas merging each other
+	// concurrently don't deadlock
+	state := other.state()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mergeState(state)
+}
+
+// Graph returns the graph the replica currently describes. Edges which would
+// close a loop are not added to the graph, but returned as quarantined, so
+// they can be deleted or reported. The edges are added sorted by id, so that
+// equal replicas quarantine the same edges. Graph returns an error if a vertex
+// can't be added, e.g. as its value is a duplicate.
+func (m *MergeableDAG[T]) Graph() (*GenericDAG[T], []GenericEdge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d := NewGenericDAG[T](m.options...)
+	ids := make([]string, 0, len(m.vertices))
+	for id, vertex := range m.vertices {
+		if len(vertex.adds) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		var last crdtTag
+		var value T
+		for tag, v := range m.vertices[id].adds {
+			if tag.after(last) {
+				last, value = tag, v
+			}
+		}
+		if err := d.addVertexByID(id, value); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	edges := make([]GenericEdge, 0, len(m.edges))
+	for e, edge := range m.edges {
+		if len(edge.adds) > 0 {
+			edges = append(edges, e)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].SrcID != edges[j].SrcID {
+			return edges[i].SrcID < edges[j].SrcID
+		}
+		return edges[i].DstID < edges[j].DstID
+	})
+	var quarantined []GenericEdge
+	for _, e := range edges {
+		if _, ok := d.ids.handle(e.SrcID); !ok {
+			continue
+		}
+		if _, ok := d.ids.handle(e.DstID); !ok {
+			continue
+		}
+		err := d.addEdge(e.SrcID, e.DstID)
+		if errors.As(err, new(EdgeLoopError)) {
+			quarantined = append(quarantined, e)
+		} else if err != nil {
+			return nil, nil, err
+		} else if value := m.edges[e].value; value != nil {
+			d.setEdgeValue(d.keyOf(e.SrcID), d.keyOf(e.DstID), value)
+		}
+	}
+	return d, quarantined, nil
+}
+
+// crdtState is the serializable state of a MergeableDAG.
+type crdtState[T any] struct {
+	Replica  string               `json:"replica"`
+	Clock    uint64               `json:"clock"`
+	Vertices []crdtVertexState[T] `json:"vertices"`
+	Edges    []crdtEdgeState      `json:"edges"`
+}
+
+type crdtVertexState[T any] struct {
+	ID      string         `json:"id"`
+	Adds    []crdtValue[T] `json:"adds,omitempty"`
+	Removed []crdtTag      `json:"removed,omitempty"`
+}
+
+type crdtValue[T any] struct {
+	crdtTag
+	Value T `json:"value"`
+}
+
+type crdtEdgeState struct {
+	GenericEdge
+	Adds     []crdtTag   `json:"adds,omitempty"`
+	Removed  []crdtTag   `json:"removed,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+	ValueTag *crdtTag    `json:"valueTag,omitempty"`
+}
+
+// state returns a copy of the state of the replica, sorted so that equal
+// replicas have equal states.
+func (m *MergeableDAG[T]) state() crdtState[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := crdtState[T]{Replica: m.replica, Clock: m.clock}
+	for id, vertex := range m.vertices {
+		vs := crdtVertexState[T]{ID: id, Removed: sortedTags(vertex.removed)}
+		for tag, v := range vertex.adds {
+			vs.Adds = append(vs.Adds, crdtValue[T]{tag, v})
+		}
+		sort.Slice(vs.Adds, func(i, j int) bool { return vs.Adds[j].after(vs.Adds[i].crdtTag) })
+		s.Vertices = append(s.Vertices, vs)
+	}
+	sort.Slice(s.Vertices, func(i, j int) bool { return s.Vertices[i].ID < s.Vertices[j].ID })
+	for e, edge := range m.edges {
+		es := crdtEdgeState{GenericEdge: e, Adds: sortedTags(edge.adds), Removed: sortedTags(edge.removed)}
+		if edge.valueTag != (crdtTag{}) {
+			tag := edge.valueTag
+			es.Value, es.ValueTag = edge.value, &tag
+		}
+		s.Edges = append(s.Edges, es)
+	}
+	sort.Slice(s.Edges, func(i, j int) bool {
+		if s.Edges[i].SrcID != s.Edges[j].SrcID {
+			return s.Edges[i].SrcID < s.Edges[j].SrcID
+		}
+		return s.Edges[i].DstID < s.Edges[j].DstID
+	})
+	return s
+}
+
+func sortedTags(tags map[crdtTag]struct{}) []crdtTag {
+	sorted := make([]crdtTag, 0, len(tags))
+	for tag := range tags {
+		sorted = append(sorted, tag)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[j].after(sorted[i]) })
+	return sorted
+}
+
+// mergeState merges s into the replica. It must be called with the lock of
+// the replica held.
+func (m *MergeableDAG[T]) mergeState(s crdtState[T]) {
+	if s.Clock > m.clock {
+		m.clock = s.Clock
+	}
+	for _, vs := range s.Vertices {
+		vertex := m.vertex(vs.ID)
+		for _, tag := range vs.Removed {
+			delete(vertex.adds, tag)
+			vertex.removed[tag] = struct{}{}
+		}
+		for _, add := range vs.Adds {
+			if _, removed := vertex.removed[add.crdtTag]; !removed {
+				vertex.adds[add.crdtTag] = add.Value
+			}
+		}
+	}
+	for _, es := range s.Edges {
+		edge := m.edge(es.GenericEdge)
+		for _, tag := range es.Removed {
+			delete(edge.adds, tag)
+			edge.removed[tag] = struct{}{}
+		}
+		for _, tag := range es.Adds {
+			if _, removed := edge.removed[tag]; !removed {
+				edge.adds[tag] = struct{}{}
+			}
+		}
+		if es.ValueTag != nil && es.ValueTag.after(edge.valueTag) {
+			edge.value, edge.valueTag = es.Value, *es.ValueTag
+		}
+	}
+}
+
+// MarshalJSON returns the JSON encoding of the replica including its
+// tombstones, e.g. to ship it to another process to be merged there with
+// UnmarshalJSON and Merge.
+// Values are encoded with encoding/json.
+func (m *MergeableDAG[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.state())
+}
+
+// UnmarshalJSON restores a replica from its JSON encoding. The options of the
+// replica are kept.
+func (m *MergeableDAG[T]) UnmarshalJSON(data []byte) error {
+	var s crdtState[T]
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replica, m.clock = s.Replica, 0
+	m.vertices = make(map[string]*crdtVertex[T])
+	m.edges = make(map[GenericEdge]*crdtEdge)
+	m.mergeState(s)
+	return nil
+}
@@ -0,0 +1,144 @@
+package dag
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMergeableDAG(t *testing.T) {
+	a := NewMergeableDAG[string]("a")
+	b := NewMergeableDAG[string]("b")
+	for _, id := range []string{"x", "y"} {
+		_ = a.AddVertex(id, id)
+	}
+	_ = a.AddEdge("x", "y")
+	b.Merge(a)
+
+	// concurrent modifications: a deletes y and adds z, while b adds an edge
+	// to y, changes the value of x and adds an edge closing a loop
+	_ = a.DeleteVertex("y")
+	_ = a.AddVertex("z", "z")
+	_ = a.AddEdge("z", "x")
+	_ = b.AddVertex("w", "w")
+	_ = b.AddEdge("w", "y")
+	_ = b.AddVertex("x", "x2")
+	_ = b.AddEdge("x", "z")
+
+	// merging is commutative and idempotent
+	ab := NewMergeableDAG[string]("ab")
+	ab.Merge(a)
+	ab.Merge(b)
+	ba := NewMergeableDAG[string]("ba")
+	ba.Merge(b)
+	ba.Merge(a)
+	ba.Merge(b)
+	for _, m := range []*MergeableDAG[string]{ab, ba} {
+		g, quarantined, err := m.Graph()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[string]string{"w": "w", "x": "x2", "z": "z"}; !reflect.DeepEqual(g.GetVertices(), want) {
+			t.Errorf("vertices = %v, want %v", g.GetVertices(), want)
+		}
+		if want := []GenericEdge{{"x", "z"}}; !reflect.DeepEqual(sortedEdges(g.GetEdges()), want) {
+			t.Errorf("edges = %v, want %v", sortedEdges(g.GetEdges()), want)
+		}
+		if want := []GenericEdge{{"z", "x"}}; !reflect.DeepEqual(quarantined, want) {
+			t.Errorf("quarantined = %v, want %v", quarantined, want)
+		}
+	}
+
+	// re-adding a deleted vertex revives its edges
+	_ = ab.AddVertex("y", "y")
+	g, _, _ := ab.Graph()
+	if size := g.GetSize(); size != 3 {
+		t.Errorf("GetSize() = %d after re-adding y, want 3", size)
+	}
+
+	if err := a.DeleteVertex("y"); err != (IDUnknownError{"y"}) {
+		t.Errorf("DeleteVertex(y) = %v, want IDUnknownError", err)
+	}
+	if err := a.AddEdge("x", "x"); err == nil {
+		t.Error("AddEdge(x, x) = nil, want error")
+	}
+}
+
+func TestMergeableDAGEdgeValues(t *testing.T) {
+	a := NewMergeableDAG[string]("a")
+	_ = a.AddVertex("x", "x")
+	_ = a.AddVertex("y", "y")
+	_ = a.AddEdge("x", "y")
+	if err := a.SetEdgeValue("x", "y", "first"); err != nil {
+		t.Fatal(err)
+	}
+	b := NewMergeableDAG[string]("b")
+	b.Merge(a)
+
+	// the value set last wins, whichever replica merges first
+	if err := b.SetEdgeValue("x", "y", "second"); err != nil {
+		t.Fatal(err)
+	}
+	ab := NewMergeableDAG[string]("ab")
+	ab.Merge(a)
+	ab.Merge(b)
+	ba := NewMergeableDAG[string]("ba")
+	ba.Merge(b)
+	ba.Merge(a)
+
+	// the value survives the encoding of the replica
+	data, err := json.Marshal(ab)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := NewMergeableDAG[string]("decoded")
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []*MergeableDAG[string]{ab, ba, decoded} {
+		g, _, err := m.Graph()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := g.GetEdgeValue("x", "y"); v != "second" {
+			t.Errorf("GetEdgeValue(x, y) = %v, want second", v)
+		}
+	}
+
+	if err := a.SetEdgeValue("y", "x", 1); err != (EdgeUnknownError{"y", "x"}) {
+		t.Errorf("SetEdgeValue(y, x) = %v, want EdgeUnknownError", err)
+	}
+}
+
+func TestMergeableDAGJSON(t *testing.T) {
+	a := NewMergeableDAG[int]("a")
+	_ = a.AddVertex("1", 1)
+	_ = a.AddVertex("2", 2)
+	_ = a.AddEdge("1", "2")
+	_ = a.DeleteEdge("1", "2")
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored MergeableDAG[int]
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := json.Marshal(&restored); string(again) != string(data) {
+		t.Errorf("round trip = %s, want %s", again, data)
+	}
+
+	// the tombstone of the edge survives the round trip
+	b := NewMergeableDAG[int]("b")
+	b.Merge(&restored)
+	_ = b.AddEdge("2", "1")
+	b.Merge(a)
+	g, _, err := b.Graph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []GenericEdge{{"2", "1"}}; !reflect.DeepEqual(sortedEdges(g.GetEdges()), want) {
+		t.Errorf("edges = %v, want %v", sortedEdges(g.GetEdges()), want)
+	}
+}
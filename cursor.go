@@ -0,0 +1,105 @@
+package dag
+
+import (
+	"container/heap"
+	"encoding/base64"
+	"strings"
+)
+
+// cursorPrefix versions the encoding of cursor tokens.
+const cursorPrefix = "v1:"
+
+// Cursor returns the ids of at most limit vertices following the position
+// the token points to, in id order, along with the token of the position
+// after the last returned id. An empty token starts at the first vertex, and
+// the returned token is empty once all vertices have been returned.
+//
+// Unlike VertexPager, cursors don't pin the graph: the token only holds the
+// last id returned, so iteration can be resumed in later calls or even by
+// another process, and tolerates modifications in between. Deleted vertices
+// are skipped, and vertices added meanwhile are returned if their ids follow
+// the position of the cursor. A page costs O(n log limit) for a graph of
+// order n, without sorting all ids.
+//
+// If limit isn't positive, Cursor returns no ids and the token unchanged.
+// Cursor returns a CursorInvalidError if the token hasn't been returned by
+// Cursor.
+func (d *GenericDAG[T]) Cursor(token string, limit int) ([]string, string, error) {
+	after, err := decodeCursor(token)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		return []string{}, token, nil
+	}
+
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	// keep the limit smallest ids following the cursor in a max-heap
+	page := &idMaxHeap{}
+	remaining := 0
+	for id := range d.ids.handles {
+		if token != "" && id <= after {
+			continue
+		}
+		remaining++
+		if page.Len() < limit {
+			heap.Push(page, id)
+		} else if id < (*page)[0] {
+			(*page)[0] = id
+			heap.Fix(page, 0)
+		}
+	}
+
+	ids := make([]string, page.Len())
+	for i := len(ids) - 1; i >= 0; i-- {
+		ids[i] = heap.Pop(page).(string)
+	}
+	if remaining <= limit {
+		return ids, "", nil
+	}
+	return ids, encodeCursor(ids[len(ids)-1]), nil
+}
+
+// Cursor returns the ids of at most limit vertices following the position
+// the token points to. See GenericDAG.Cursor for details.
+func (d *TypedDAG[T]) Cursor(token string, limit int) ([]string, string, error) {
+	return d.inner.Cursor(token, limit)
+}
+
+// encodeCursor returns the token of the position after id.
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + id))
+}
+
+// decodeCursor returns the id the token has been created for, or an empty id
+// for an empty token.
+func decodeCursor(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return "", CursorInvalidError{token}
+	}
+	return strings.TrimPrefix(string(decoded), cursorPrefix), nil
+}
+
+// idMaxHeap is a max-heap of ids implementing heap.Interface.
+type idMaxHeap []string
+
+func (h idMaxHeap) Len() int           { return len(h) }
+func (h idMaxHeap) Less(i, j int) bool { return h[i] > h[j] }
+func (h idMaxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *idMaxHeap) Push(x interface{}) {
+	*h = append(*h, x.(string))
+}
+
+func (h *idMaxHeap) Pop() interface{} {
+	old := *h
+	id := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return id
+}
@@ -0,0 +1,93 @@
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	d := New[int]()
+	for i := 9; i >= 0; i-- {
+		d.MustAddVertexByID(fmt.Sprintf("v%d", i), i)
+	}
+
+	var ids []string
+	token := ""
+	for pages := 0; ; pages++ {
+		page, next, err := d.Cursor(token, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, page...)
+		if next == "" {
+			if pages != 3 {
+				t.Errorf("Cursor() returned %d pages, want 4", pages+1)
+			}
+			break
+		}
+		token = next
+	}
+	want := []string{"v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7", "v8", "v9"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("Cursor() = %v, want %v", ids, want)
+	}
+
+	// a page ending exactly at the last vertex has no next token
+	page, next, err := d.Cursor("", 10)
+	if err != nil || len(page) != 10 || next != "" {
+		t.Errorf("Cursor(\"\", 10) = %v, %q, %v, want all ids and no token", page, next, err)
+	}
+
+	page, next, err = d.Cursor("", 0)
+	if err != nil || len(page) != 0 || next != "" {
+		t.Errorf("Cursor(\"\", 0) = %v, %q, %v", page, next, err)
+	}
+}
+
+func TestCursorModification(t *testing.T) {
+	d := New[string]()
+	for _, id := range []string{"a", "c", "e", "g"} {
+		d.MustAddVertexByID(id, id)
+	}
+
+	page, token, err := d.Cursor("", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(page, want) {
+		t.Fatalf("Cursor() = %v, want %v", page, want)
+	}
+
+	// deleting the last returned vertex keeps the position of the cursor,
+	// vertices added before it are missed and those after it are returned
+	if err := d.DeleteVertex("c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DeleteVertex("e"); err != nil {
+		t.Fatal(err)
+	}
+	d.MustAddVertexByID("b", "b")
+	d.MustAddVertexByID("f", "f")
+
+	page, token, err = d.Cursor(token, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"f", "g"}; !reflect.DeepEqual(page, want) || token != "" {
+		t.Errorf("Cursor() = %v, %q, want %v and no token", page, token, want)
+	}
+}
+
+func TestCursorInvalid(t *testing.T) {
+	d := New[string]()
+	d.MustAddVertexByID("a", "a")
+
+	for _, token := range []string{"a", "!!", encodeCursor("a")[1:]} {
+		var cursorErr CursorInvalidError
+		if _, _, err := d.Cursor(token, 1); !errors.As(err, &cursorErr) {
+			t.Errorf("Cursor(%q) = %v, want CursorInvalidError", token, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package dag
 
 import (
 	"fmt"
+	"runtime"
 	"strconv"
 	"testing"
 )
@@ -47,6 +48,27 @@ func BenchmarkAddEdge(b *testing.B) {
 	}
 }
 
+func BenchmarkAddEdgeLoop(b *testing.B) {
+	d := generateWideTreeDAG(4, 10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = d.AddEdge("node_3_999", "root_0")
+	}
+}
+
+func BenchmarkAddEdgeLoopCached(b *testing.B) {
+	d := generateWideTreeDAG(4, 10)
+
+	// Populate cache
+	_, _ = d.GetDescendants("root_0")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = d.AddEdge("node_3_999", "root_0")
+	}
+}
+
 func BenchmarkDeleteVertex(b *testing.B) {
 	b.StopTimer()
 
@@ -1048,6 +1070,58 @@ func BenchmarkAddEdgeAllocs(b *testing.B) {
 	}
 }
 
+func BenchmarkAddEdgeLoopAllocs(b *testing.B) {
+	d := NewDAG(WithoutCache())
+	for i := 0; i < 1000; i++ {
+		_ = d.AddVertexByID(strconv.Itoa(i), i)
+		if i > 0 {
+			_ = d.AddEdge(strconv.Itoa(i-1), strconv.Itoa(i))
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = d.AddEdge("999", "0")
+	}
+}
+
+func BenchmarkGetOrderedDescendantsAllocs(b *testing.B) {
+	d := generateWideTreeDAG(4, 10)
+	rootID := "root_0"
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = d.GetOrderedDescendants(rootID)
+	}
+}
+
+func BenchmarkBFSWalkAllocs(b *testing.B) {
+	d := generateWideTreeDAG(4, 10)
+	visitor := &benchmarkVisitor{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.BFSWalk(visitor)
+	}
+}
+
+func BenchmarkDescendantsFlowAllocs(b *testing.B) {
+	d := generateWideTreeDAG(4, 10)
+	rootID := "root_0"
+	callback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		return nil, nil
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = d.DescendantsFlow(rootID, nil, callback)
+	}
+}
+
 // ============================================================================
 // Type Conversion Benchmarks
 // ============================================================================
@@ -1400,4 +1474,69 @@ func BenchmarkGenericDAG_GetDescendantsCached(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_, _ = dag.GetDescendants(rootID)
 	}
-}
\ No newline at end of file
+}
+
+// BenchmarkGenericDAG_Memory_1M reports the heap retained by a GenericDAG
+// with 1M vertices and edges.
+func BenchmarkGenericDAG_Memory_1M(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		d, err := GenerateRandomDAG(42, 1000000, 1000000, func(i int) string {
+			return "value_" + strconv.Itoa(i)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/(1<<20), "heap-MB")
+		runtime.KeepAlive(d)
+	}
+}
+
+func BenchmarkBFSWalk_100k(b *testing.B) {
+	d := generateBalancedTreeDAG(100000, 4)
+	visitor := visitorFunc[interface{}](func(interface{}, string) {})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.GenericBFSWalk(visitor)
+	}
+}
+
+func BenchmarkParallelBFSWalk_100k(b *testing.B) {
+	d := generateBalancedTreeDAG(100000, 4)
+	visitor := visitorFunc[interface{}](func(interface{}, string) {})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.ParallelBFSWalk(visitor, ParallelOptions{})
+	}
+}
+
+func BenchmarkDFSWalk_100k(b *testing.B) {
+	d := generateBalancedTreeDAG(100000, 4)
+	visitor := visitorFunc[interface{}](func(interface{}, string) {})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.GenericDFSWalk(visitor)
+	}
+}
+
+func BenchmarkParallelDFSWalk_100k(b *testing.B) {
+	d := generateBalancedTreeDAG(100000, 4)
+	visitor := visitorFunc[interface{}](func(interface{}, string) {})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.ParallelDFSWalk(visitor, ParallelOptions{})
+	}
+}
+
+func BenchmarkReachabilityIndex(b *testing.B) {
+	d, _ := GenerateRandomDAG(42, 10000, 30000, strconv.Itoa)
+	index := d.BuildReachabilityIndex()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = index.IsReachable("node_"+strconv.Itoa(i%100), "node_"+strconv.Itoa(9999-i%100))
+	}
+}
@@ -2,8 +2,11 @@ package dag
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 	"testing"
+	"time"
 )
 
 // TestEmptyGraph tests operations on an empty graph.
@@ -524,6 +527,49 @@ func TestDescendantsFlowParentAggregation(t *testing.T) {
 	}
 }
 
+// TestDescendantsFlowMetadata tests the timing and parent metadata of results.
+func TestDescendantsFlowMetadata(t *testing.T) {
+	d := generateDiamondDAG()
+
+	inputs := []FlowResult{{ID: "input", Result: 10}}
+	callback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		return parentResults, nil
+	}
+
+	before := time.Now()
+	results, err := d.DescendantsFlow("A", inputs, callback)
+	if err != nil {
+		t.Fatalf("DescendantsFlow() unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("DescendantsFlow() = %d results, want 1", len(results))
+	}
+
+	r := results[0]
+	if r.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", r.Attempts)
+	}
+	if r.StartedAt.Before(before) || r.FinishedAt.Before(r.StartedAt) {
+		t.Errorf("StartedAt = %v, FinishedAt = %v, want %v <= StartedAt <= FinishedAt", r.StartedAt, r.FinishedAt, before)
+	}
+	parentIDs := append([]string(nil), r.ParentIDs...)
+	sort.Strings(parentIDs)
+	if !reflect.DeepEqual(parentIDs, []string{"B", "C"}) {
+		t.Errorf("ParentIDs = %v, want [B C]", r.ParentIDs)
+	}
+	for i, pr := range r.Result.([]FlowResult) {
+		if pr.ID != r.ParentIDs[i] {
+			t.Errorf("ParentIDs[%d] = %s, want %s", i, r.ParentIDs[i], pr.ID)
+		}
+		if !reflect.DeepEqual(pr.ParentIDs, []string{"A"}) {
+			t.Errorf("ParentIDs of %s = %v, want [A]", pr.ID, pr.ParentIDs)
+		}
+		if pr.FinishedAt.After(r.StartedAt) {
+			t.Errorf("%s finished at %v, after D started at %v", pr.ID, pr.FinishedAt, r.StartedAt)
+		}
+	}
+}
+
 // TestDescendantsFlowErrorHandling tests error handling in callback.
 func TestDescendantsFlowErrorHandling(t *testing.T) {
 	d := NewDAG()
@@ -1,8 +1,10 @@
 package dag
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-test/deep"
+	"reflect"
 	"sort"
 	"strconv"
 	"testing"
@@ -425,6 +427,25 @@ func TestDAG_AddEdge(t *testing.T) {
 	}
 }
 
+func TestDAG_EnsureEdge(t *testing.T) {
+	dag := NewDAG()
+	v1, _ := dag.AddVertex("1")
+	v2, _ := dag.AddVertex("2")
+
+	if added, err := dag.EnsureEdge(v1, v2); err != nil || !added {
+		t.Fatalf("EnsureEdge(v1, v2) = %v, %v, want true, nil", added, err)
+	}
+	if added, err := dag.EnsureEdge(v1, v2); err != nil || added {
+		t.Errorf("EnsureEdge(v1, v2) = %v, %v, want false, nil", added, err)
+	}
+	if _, err := dag.EnsureEdge(v2, v1); err == nil {
+		t.Errorf("EnsureEdge(v2, v1) = nil, want %T", EdgeLoopError{})
+	}
+	if dag.GetSize() != 1 {
+		t.Errorf("GetSize() = %d, want 1", dag.GetSize())
+	}
+}
+
 func TestDAG_DeleteEdge(t *testing.T) {
 	dag := NewDAG()
 	v0, _ := dag.AddVertex(iVertex{0})
@@ -1237,6 +1258,7 @@ func TestErrors(t *testing.T) {
 		{"edge between '1' and '2' is already known", EdgeDuplicateError{"1", "2"}},
 		{"edge between '1' and '2' is unknown", EdgeUnknownError{"1", "2"}},
 		{"edge between '1' and '2' would create a loop", EdgeLoopError{"1", "2"}},
+		{"2 errors: '1' is unknown; don't know what to do with \"\"", MultiError{[]error{IDUnknownError{"1"}, IDEmptyError{}}}},
 	}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("%T", tt.err), func(t *testing.T) {
@@ -1247,6 +1269,39 @@ func TestErrors(t *testing.T) {
 	}
 }
 
+func TestMultiError(t *testing.T) {
+	var multi MultiError
+	multi.add(nil)
+	multi.add(IDUnknownError{"1"})
+	multi.add(EdgeLoopError{"1", "2"})
+	var err error = multi
+
+	if got := len(multi.Errors()); got != 2 {
+		t.Errorf("Errors() = %d errors, want 2", got)
+	}
+	var loopErr EdgeLoopError
+	if !errors.As(err, &loopErr) || loopErr != (EdgeLoopError{"1", "2"}) {
+		t.Errorf("errors.As(EdgeLoopError) = %v, want %v", loopErr, EdgeLoopError{"1", "2"})
+	}
+	if !errors.Is(err, IDUnknownError{"1"}) {
+		t.Error("errors.Is(IDUnknownError) = false, want true")
+	}
+
+	// a ValidationError holds the errors of the invalid vertices
+	err = validateVertices(2, func(i int) (string, interface{}) {
+		return fmt.Sprint(i), i
+	}, func(id string, v interface{}) error {
+		return EdgeUnknownError{id, id}
+	})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors()) != 2 {
+		t.Errorf("errors.As(ValidationError) = %v, want 2 errors", err)
+	}
+	if !errors.Is(err, EdgeUnknownError{"1", "1"}) {
+		t.Error("errors.Is(EdgeUnknownError) = false, want true")
+	}
+}
+
 func ExampleDAG_AncestorsWalker() {
 	dag := NewDAG()
 
@@ -1343,6 +1398,87 @@ func TestDAG_DescendantsFlowOneNode(t *testing.T) {
 	}
 }
 
+func TestDAG_DescendantsFlowParentOrder(t *testing.T) {
+	byIDDesc := func(a, b FlowResult) bool { return a.ID > b.ID }
+	for _, tc := range []struct {
+		opt  Option
+		want []string
+	}{
+		{WithSortedFlowParents(), []string{"p0", "p1", "p2", "p3", "p4", "p5", "p6", "p7"}},
+		{WithFlowParentOrder(byIDDesc), []string{"p7", "p6", "p5", "p4", "p3", "p2", "p1", "p0"}},
+	} {
+		d := NewDAG(tc.opt)
+		d.MustAddVertexByID("root", "root")
+		d.MustAddVertexByID("sink", "sink")
+		for i := 7; i >= 0; i-- {
+			id := "p" + strconv.Itoa(i)
+			d.MustAddVertexByID(id, id)
+			d.MustAddEdge("root", id)
+			d.MustAddEdge(id, "sink")
+		}
+
+		callback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+			return id, nil
+		}
+		results, err := d.DescendantsFlow("root", nil, callback)
+		if err != nil || len(results) != 1 {
+			t.Fatalf("DescendantsFlow() = %v, %v, want the result of sink", results, err)
+		}
+		if !reflect.DeepEqual(results[0].ParentIDs, tc.want) {
+			t.Errorf("DescendantsFlow() passed parents %v, want %v", results[0].ParentIDs, tc.want)
+		}
+
+		results, err = d.WorkStealingFlow("root", nil, callback, WorkStealingOptions{Workers: 4})
+		if err != nil || len(results) != 1 {
+			t.Fatalf("WorkStealingFlow() = %v, %v, want the result of sink", results, err)
+		}
+		if !reflect.DeepEqual(results[0].ParentIDs, tc.want) {
+			t.Errorf("WorkStealingFlow() passed parents %v, want %v", results[0].ParentIDs, tc.want)
+		}
+	}
+}
+
+func TestDAG_SharedEngine(t *testing.T) {
+	d := NewDAG()
+	g := NewGenericDAG[interface{}]()
+	for _, id := range []string{"c", "a", "b", "d"} {
+		d.MustAddVertexByID(id, id)
+		g.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "d"}, {"a", "b"}, {"c", "d"}} {
+		d.MustAddEdge(e[0], e[1])
+		g.MustAddEdge(e[0], e[1])
+	}
+
+	// both walk in the same, sorted order
+	var dagOrder testVisitor
+	var genericOrder []string
+	d.DFSWalk(&dagOrder)
+	g.GenericDFSWalk(visitorFunc[interface{}](func(_ interface{}, id string) {
+		genericOrder = append(genericOrder, id)
+	}))
+	want := []string{"a", "b", "d", "c"}
+	if deep.Equal(dagOrder.Values, want) != nil || deep.Equal(genericOrder, want) != nil {
+		t.Errorf("DFSWalk() = %v, GenericDFSWalk() = %v, want %v", dagOrder.Values, genericOrder, want)
+	}
+
+	// nil values are rejected by DAG and its copies only
+	if _, err := g.AddVertex(nil); err != nil {
+		t.Errorf("GenericDAG rejected nil: %v", err)
+	}
+	c, _ := d.Copy()
+	sub, _, _ := d.GetDescendantsGraph("a")
+	for name, dag := range map[string]*DAG{"DAG": d, "Copy": c, "GetDescendantsGraph": sub} {
+		if _, err := dag.AddVertex(nil); err != (VertexNilError{}) {
+			t.Errorf("%s: AddVertex(nil) = %v, want VertexNilError", name, err)
+		}
+	}
+	d.Options(Options{})
+	if _, err := d.AddVertex(nil); err != (VertexNilError{}) {
+		t.Errorf("AddVertex(nil) after Options() = %v, want VertexNilError", err)
+	}
+}
+
 func largeAux(d *DAG, level int, branches int, parent iVertex) (int, int) {
 	var vertexCount int
 	var edgeCount int
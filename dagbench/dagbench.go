@@ -0,0 +1,230 @@
+// Package dagbench measures the performance of DAGs with different options on
+// graphs of different shapes, e.g. to quantify the impact of disabling the
+// cache on the graphs of an application:
+//
+//	configs := []dagbench.Config{
+//		{Name: "default"},
+//		{Name: "no-cache", Options: []dag.Option{dag.WithoutCache()}},
+//	}
+//	results, err := dagbench.RunAll(
+//		[]dagbench.Shape{dagbench.Tree(6, 5), dagbench.Layers(20, 20)},
+//		[]dagbench.Workload{{Name: "read-mostly", Mix: dagbench.ReadMostly, Ops: 10000}},
+//		configs,
+//	)
+//	...
+//	dagbench.Compare(os.Stdout, results, "default")
+package dagbench
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/JodeZer/dag"
+)
+
+// Mix holds the relative frequencies of the operations of a workload.
+type Mix struct {
+	AddVertex      int
+	AddEdge        int
+	DeleteEdge     int
+	IsEdge         int
+	GetDescendants int
+	GetAncestors   int
+}
+
+// Predefined mixes of operations.
+var (
+	// ReadMostly mostly queries relatives and rarely modifies the graph.
+	ReadMostly = Mix{AddVertex: 1, AddEdge: 2, DeleteEdge: 1, IsEdge: 16, GetDescendants: 40, GetAncestors: 40}
+
+	// WriteHeavy modifies the graph as often as it queries it.
+	WriteHeavy = Mix{AddVertex: 15, AddEdge: 25, DeleteEdge: 10, IsEdge: 10, GetDescendants: 20, GetAncestors: 20}
+)
+
+// Workload is a random sequence of operations run on a graph.
+type Workload struct {
+	Name string
+	Mix  Mix
+	Ops  int
+
+	// Seed seeds the choice of the operations and of the vertices they
+	// apply to, so that runs with the same Seed are comparable.
+	Seed int64
+}
+
+// Config is a named set of options of the DAGs to measure.
+type Config struct {
+	Name    string
+	Options []dag.Option
+}
+
+// Result holds the measurements of a workload run on a shape with a config.
+type Result struct {
+	Shape    string
+	Workload string
+	Config   string
+
+	// Build is the time it took to build the graph.
+	Build time.Duration
+
+	// Ops is the number of operations and Run the time they took.
+	Ops int
+	Run time.Duration
+
+	// Allocs and Bytes are the number of heap allocations and the number of
+	// bytes allocated while running the operations.
+	Allocs uint64
+	Bytes  uint64
+
+	// Memory is the memory used by the graph after running the operations,
+	// as estimated by EstimateMemory.
+	Memory int64
+}
+
+// NsPerOp returns the average time per operation in nanoseconds.
+func (r Result) NsPerOp() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+	return float64(r.Run.Nanoseconds()) / float64(r.Ops)
+}
+
+// AllocsPerOp returns the average number of allocations per operation.
+func (r Result) AllocsPerOp() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+	return float64(r.Allocs) / float64(r.Ops)
+}
+
+// Run builds a graph of the shape with the options of config and runs the
+// workload on it. Operations failing as they would create a loop or
+// duplicate edge are part of the workload and not reported. Run returns an
+// error if the graph can't be built or the mix of the workload is empty.
+func Run(shape Shape, workload Workload, config Config) (Result, error) {
+	weights := []int{
+		workload.Mix.AddVertex,
+		workload.Mix.AddEdge,
+		workload.Mix.DeleteEdge,
+		workload.Mix.IsEdge,
+		workload.Mix.GetDescendants,
+		workload.Mix.GetAncestors,
+	}
+	total := 0
+	for _, w := range weights {
+		if w < 0 {
+			return Result{}, errors.New("dagbench: negative frequency in mix")
+		}
+		total += w
+	}
+	if total == 0 {
+		return Result{}, errors.New("dagbench: empty mix")
+	}
+
+	start := time.Now()
+	d, err := shape.Build(config.Options...)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{
+		Shape:    shape.Name,
+		Workload: workload.Name,
+		Config:   config.Name,
+		Build:    time.Since(start),
+		Ops:      workload.Ops,
+	}
+
+	// run the operations on vertices chosen from a sorted list of ids, as
+	// the order of VertexIDs isn't reproducible
+	ids := d.VertexIDs()
+	sort.Strings(ids)
+	r := rand.New(rand.NewSource(workload.Seed))
+	vertex := func() string {
+		return ids[r.Intn(len(ids))]
+	}
+	added := 0
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start = time.Now()
+	for i := 0; i < workload.Ops; i++ {
+		op := pick(weights, r.Intn(total))
+		if op == opAddVertex {
+			id := "added_" + strconv.Itoa(added)
+			if err := d.AddVertexByID(id, -1-added); err == nil {
+				ids = append(ids, id)
+			}
+			added++
+			continue
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		switch op {
+		case opAddEdge:
+			_ = d.AddEdge(vertex(), vertex())
+		case opDeleteEdge:
+			src := vertex()
+			if children, _ := d.ChildrenIDs(src); len(children) > 0 {
+				_ = d.DeleteEdge(src, children[r.Intn(len(children))])
+			}
+		case opIsEdge:
+			_, _ = d.IsEdge(vertex(), vertex())
+		case opGetDescendants:
+			_, _ = d.GetDescendants(vertex())
+		case opGetAncestors:
+			_, _ = d.GetAncestors(vertex())
+		}
+	}
+	result.Run = time.Since(start)
+	runtime.ReadMemStats(&after)
+	result.Allocs = after.Mallocs - before.Mallocs
+	result.Bytes = after.TotalAlloc - before.TotalAlloc
+	result.Memory = d.EstimateMemory().Total
+	return result, nil
+}
+
+// The operations of a Mix in the order of its fields.
+const (
+	opAddVertex = iota
+	opAddEdge
+	opDeleteEdge
+	opIsEdge
+	opGetDescendants
+	opGetAncestors
+)
+
+// pick returns the index of the weight n falls into, for n between 0 and the
+// sum of weights.
+func pick(weights []int, n int) int {
+	for i, w := range weights {
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+	return len(weights) - 1
+}
+
+// RunAll runs each workload on each shape with each config and returns the
+// results in this order. RunAll stops at the first error.
+func RunAll(shapes []Shape, workloads []Workload, configs []Config) ([]Result, error) {
+	results := make([]Result, 0, len(shapes)*len(workloads)*len(configs))
+	for _, shape := range shapes {
+		for _, workload := range workloads {
+			for _, config := range configs {
+				result, err := Run(shape, workload, config)
+				if err != nil {
+					return results, err
+				}
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
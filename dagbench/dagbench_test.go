@@ -0,0 +1,70 @@
+package dagbench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JodeZer/dag"
+)
+
+func TestShapes(t *testing.T) {
+	tests := []struct {
+		shape         Shape
+		order, size   int
+		roots, leaves int
+	}{
+		{Random(1, 50, 100), 50, 100, -1, -1},
+		{Chain(10), 10, 9, 1, 1},
+		{Tree(3, 4), 21, 20, 1, 16},
+		{Layers(3, 4), 12, 32, 4, 4},
+	}
+	for _, tt := range tests {
+		d, err := tt.shape.Build()
+		if err != nil {
+			t.Fatalf("%s: Build() unexpected error: %v", tt.shape.Name, err)
+		}
+		if d.GetOrder() != tt.order || d.GetSize() != tt.size {
+			t.Errorf("%s: order, size = %d, %d, want %d, %d", tt.shape.Name, d.GetOrder(), d.GetSize(), tt.order, tt.size)
+		}
+		if tt.roots >= 0 && (len(d.GetRoots()) != tt.roots || len(d.GetLeaves()) != tt.leaves) {
+			t.Errorf("%s: roots, leaves = %d, %d, want %d, %d", tt.shape.Name, len(d.GetRoots()), len(d.GetLeaves()), tt.roots, tt.leaves)
+		}
+	}
+}
+
+func TestRunAllAndCompare(t *testing.T) {
+	configs := []Config{
+		{Name: "default"},
+		{Name: "no-cache", Options: []dag.Option{dag.WithoutCache()}},
+	}
+	workloads := []Workload{
+		{Name: "read", Mix: ReadMostly, Ops: 200, Seed: 1},
+		{Name: "write", Mix: WriteHeavy, Ops: 200, Seed: 1},
+	}
+	results, err := RunAll([]Shape{Tree(4, 3), Layers(4, 4), Chain(0)}, workloads, configs)
+	if err != nil {
+		t.Fatalf("RunAll() unexpected error: %v", err)
+	}
+	if len(results) != 12 {
+		t.Fatalf("RunAll() returned %d results, want 12", len(results))
+	}
+	for _, r := range results {
+		if r.Ops != 200 || r.Run <= 0 || r.NsPerOp() <= 0 {
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Compare(&buf, results, "default"); err != nil {
+		t.Fatalf("Compare() unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 13 || !strings.Contains(lines[2], "no-cache") || !strings.Contains(lines[2], "%") {
+		t.Errorf("Compare() =\n%s", buf.String())
+	}
+
+	if _, err := Run(Chain(3), Workload{Ops: 1}, configs[0]); err == nil {
+		t.Error("Run() with an empty mix should fail")
+	}
+}
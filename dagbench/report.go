@@ -0,0 +1,41 @@
+package dagbench
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Compare writes a table of the results to w. Each result is compared to the
+// result of the same shape and workload with the baseline config, showing the
+// change of the time per operation and the memory in percent.
+func Compare(w io.Writer, results []Result, baseline string) error {
+	type key struct{ shape, workload string }
+	base := make(map[key]Result)
+	for _, r := range results {
+		if r.Config == baseline {
+			base[key{r.Shape, r.Workload}] = r
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "shape\tworkload\tconfig\tbuild\tns/op\tallocs/op\tmemory\tΔ ns/op\tΔ memory\t")
+	for _, r := range results {
+		deltaTime, deltaMemory := "", ""
+		if b, ok := base[key{r.Shape, r.Workload}]; ok && r.Config != baseline {
+			deltaTime = percent(r.NsPerOp(), b.NsPerOp())
+			deltaMemory = percent(float64(r.Memory), float64(b.Memory))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%.0f\t%.1f\t%d\t%s\t%s\t\n",
+			r.Shape, r.Workload, r.Config, r.Build, r.NsPerOp(), r.AllocsPerOp(), r.Memory, deltaTime, deltaMemory)
+	}
+	return tw.Flush()
+}
+
+// percent formats the change from base to v in percent.
+func percent(v, base float64) string {
+	if base == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (v-base)/base*100)
+}
@@ -0,0 +1,112 @@
+package dagbench
+
+import (
+	"strconv"
+
+	"github.com/JodeZer/dag"
+)
+
+// Shape builds the graph a workload runs on. The value of each vertex is its
+// index, and its id is "v<index>" (or "node_<index>" for Random).
+type Shape struct {
+	Name  string
+	Build func(opts ...dag.Option) (*dag.GenericDAG[int], error)
+}
+
+// Random returns the shape of a random graph with the given number of vertices
+// and edges, see dag.GenerateRandomDAG.
+func Random(seed int64, vertices, edges int) Shape {
+	return Shape{
+		Name: "random-" + strconv.Itoa(vertices) + "-" + strconv.Itoa(edges),
+		Build: func(opts ...dag.Option) (*dag.GenericDAG[int], error) {
+			return dag.GenerateRandomDAG(seed, vertices, edges, func(i int) int { return i }, opts...)
+		},
+	}
+}
+
+// Chain returns the shape of a single path of n vertices.
+func Chain(n int) Shape {
+	return Shape{
+		Name: "chain-" + strconv.Itoa(n),
+		Build: func(opts ...dag.Option) (*dag.GenericDAG[int], error) {
+			d := dag.NewGenericDAG[int](append([]dag.Option{dag.WithCapacity(n, n)}, opts...)...)
+			for i := 0; i < n; i++ {
+				if err := d.AddVertexByID(id(i), i); err != nil {
+					return nil, err
+				}
+				if i > 0 {
+					if err := d.AddEdge(id(i-1), id(i)); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return d, nil
+		},
+	}
+}
+
+// Tree returns the shape of a tree of the given depth, in which each inner
+// vertex has the given number of children.
+func Tree(depth, branches int) Shape {
+	return Shape{
+		Name: "tree-" + strconv.Itoa(depth) + "x" + strconv.Itoa(branches),
+		Build: func(opts ...dag.Option) (*dag.GenericDAG[int], error) {
+			d := dag.NewGenericDAG[int](opts...)
+			if err := d.AddVertexByID(id(0), 0); err != nil {
+				return nil, err
+			}
+			level := []int{0}
+			next := 1
+			for l := 1; l < depth; l++ {
+				var children []int
+				for _, parent := range level {
+					for b := 0; b < branches; b++ {
+						if err := d.AddVertexByID(id(next), next); err != nil {
+							return nil, err
+						}
+						if err := d.AddEdge(id(parent), id(next)); err != nil {
+							return nil, err
+						}
+						children = append(children, next)
+						next++
+					}
+				}
+				level = children
+			}
+			return d, nil
+		},
+	}
+}
+
+// Layers returns the shape of the given number of layers of width vertices
+// each, in which each vertex is connected to all vertices of the next layer.
+// This shape has many paths between its vertices, as e.g. build pipelines.
+func Layers(layers, width int) Shape {
+	return Shape{
+		Name: "layers-" + strconv.Itoa(layers) + "x" + strconv.Itoa(width),
+		Build: func(opts ...dag.Option) (*dag.GenericDAG[int], error) {
+			d := dag.NewGenericDAG[int](append([]dag.Option{dag.WithCapacity(layers*width, layers*width*width)}, opts...)...)
+			for l := 0; l < layers; l++ {
+				for w := 0; w < width; w++ {
+					i := l*width + w
+					if err := d.AddVertexByID(id(i), i); err != nil {
+						return nil, err
+					}
+					if l == 0 {
+						continue
+					}
+					for p := (l - 1) * width; p < l*width; p++ {
+						if err := d.AddEdge(id(p), id(i)); err != nil {
+							return nil, err
+						}
+					}
+				}
+			}
+			return d, nil
+		},
+	}
+}
+
+func id(i int) string {
+	return "v" + strconv.Itoa(i)
+}
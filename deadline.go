@@ -0,0 +1,74 @@
+package dag
+
+import "time"
+
+// DurationInterface describes the interface a vertex value must implement to
+// provide its estimated duration, see ComputeLatestStartTimes. Vertices whose
+// values don't implement DurationInterface take no time.
+type DurationInterface interface {
+	EstimatedDuration() time.Duration
+}
+
+// LatestStart is the latest time a vertex may start for the graph to finish
+// by a deadline.
+type LatestStart struct {
+	// Start is the latest start time of the vertex.
+	Start time.Time
+
+	// Infeasible is true if Start has already passed, i.e. the deadline can't
+	// be met anymore unless the vertex and its descendants run faster than
+	// estimated.
+	Infeasible bool
+}
+
+// ComputeLatestStartTimes returns the latest start time of each vertex by its
+// id, such that all vertices finish by the deadline when each vertex starts
+// after all its parents have finished: a vertex must finish by the latest
+// start of each of its children, and leaves by the deadline. Durations are
+// taken from vertex values implementing DurationInterface.
+func (d *GenericDAG[T]) ComputeLatestStartTimes(deadline time.Time) map[string]LatestStart {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	now := time.Now()
+	latest := make(map[vertexHandle]time.Time, d.ids.len())
+	pending := make(map[vertexHandle]int, d.ids.len())
+	var ready []vertexHandle
+	d.ids.each(func(_ string, h vertexHandle) bool {
+		if pending[h] = len(d.outboundEdge[h]); pending[h] == 0 {
+			ready = append(ready, h)
+		}
+		return true
+	})
+
+	// visit the vertices from the leaves upwards, so that the latest start
+	// times of all children are known
+	starts := make(map[string]LatestStart, d.ids.len())
+	for len(ready) > 0 {
+		h := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		finish := deadline
+		for child := range d.outboundEdge[h] {
+			if latest[child].Before(finish) {
+				finish = latest[child]
+			}
+		}
+		if v, ok := any(d.values[h]).(DurationInterface); ok {
+			finish = finish.Add(-v.EstimatedDuration())
+		}
+		latest[h] = finish
+		starts[d.ids.id(h)] = LatestStart{Start: finish, Infeasible: finish.Before(now)}
+		for parent := range d.inboundEdge[h] {
+			if pending[parent]--; pending[parent] == 0 {
+				ready = append(ready, parent)
+			}
+		}
+	}
+	return starts
+}
+
+// ComputeLatestStartTimes returns the latest start time of each vertex by its
+// id. See GenericDAG.ComputeLatestStartTimes for details.
+func (d *TypedDAG[T]) ComputeLatestStartTimes(deadline time.Time) map[string]LatestStart {
+	return d.inner.ComputeLatestStartTimes(deadline)
+}
@@ -0,0 +1,49 @@
+package dag
+
+import (
+	"testing"
+	"time"
+)
+
+type estimatedStep struct {
+	name     string
+	duration time.Duration
+}
+
+func (s estimatedStep) EstimatedDuration() time.Duration {
+	return s.duration
+}
+
+func TestComputeLatestStartTimes(t *testing.T) {
+	/*   a
+	 *  / \
+	 * b   c
+	 *  \ /
+	 *   d   e
+	 */
+	d := New[estimatedStep]()
+	for _, s := range []estimatedStep{{"a", time.Hour}, {"b", 2 * time.Hour}, {"c", 30 * time.Minute}, {"d", time.Hour}, {"e", 5 * time.Hour}} {
+		d.MustAddVertexByID(s.name, s)
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	deadline := time.Now().Add(4*time.Hour + 30*time.Minute)
+	starts := d.ComputeLatestStartTimes(deadline)
+	want := map[string]LatestStart{
+		"a": {deadline.Add(-4 * time.Hour), false},
+		"b": {deadline.Add(-3 * time.Hour), false},
+		"c": {deadline.Add(-90 * time.Minute), false},
+		"d": {deadline.Add(-time.Hour), false},
+		"e": {deadline.Add(-5 * time.Hour), true},
+	}
+	if len(starts) != len(want) {
+		t.Fatalf("ComputeLatestStartTimes() returned %d vertices, want %d", len(starts), len(want))
+	}
+	for id, w := range want {
+		if got := starts[id]; !got.Start.Equal(w.Start) || got.Infeasible != w.Infeasible {
+			t.Errorf("latest start of %s = %v, want %v", id, got, w)
+		}
+	}
+}
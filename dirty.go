@@ -0,0 +1,144 @@
+package dag
+
+// Vertices are marked dirty and clean by numbering the marks in the order
+// they are made: a vertex is dirty if it or one of its ancestors has been
+// marked dirty after the vertex has been marked clean the last time. Thus,
+// marking a vertex dirty doesn't need to visit its descendants.
+
+// MarkDirty marks the vertex with id and all its descendants as dirty, e.g.
+// as the vertex has changed and it and everything depending on it needs to be
+// rebuilt. The descendants are marked lazily, which makes MarkDirty O(1).
+// Marks are removed along with their vertex. MarkDirty returns an error if id
+// is empty or unknown.
+func (d *GenericDAG[T]) MarkDirty(id string) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	if err := d.saneID(id); err != nil {
+		return err
+	}
+	if d.dirtyMarks == nil {
+		d.dirtyMarks = make(map[vertexHandle]uint64)
+	}
+	d.marks++
+	d.dirtyMarks[d.keyOf(id)] = d.marks
+	return nil
+}
+
+// MarkClean marks the vertex with id as clean, e.g. as it has been rebuilt.
+// Its descendants stay dirty. MarkClean returns an error if id is empty or
+// unknown.
+func (d *GenericDAG[T]) MarkClean(id string) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	if err := d.saneID(id); err != nil {
+		return err
+	}
+	if len(d.dirtyMarks) == 0 {
+		return nil
+	}
+	if d.cleanMarks == nil {
+		d.cleanMarks = make(map[vertexHandle]uint64)
+	}
+	d.marks++
+	d.cleanMarks[d.keyOf(id)] = d.marks
+	return nil
+}
+
+// IsDirty reports whether the vertex with id is dirty. IsDirty returns an
+// error if id is empty or unknown.
+func (d *GenericDAG[T]) IsDirty(id string) (bool, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return false, err
+	}
+	h := d.keyOf(id)
+	latest := d.dirtyMarks[h]
+	for ancestor := range d.getAncestors(h) {
+		if mark := d.dirtyMarks[ancestor]; mark > latest {
+			latest = mark
+		}
+	}
+	return latest > d.cleanMarks[h], nil
+}
+
+// DirtyVertices returns the ids of all dirty vertices in topological order,
+// i.e. each vertex is listed after all its dirty ancestors, which is the
+// order to rebuild them in.
+func (d *GenericDAG[T]) DirtyVertices() []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	// the candidates are the vertices marked dirty and their descendants
+	candidates := make(map[vertexHandle]struct{})
+	for h := range d.dirtyMarks {
+		candidates[h] = struct{}{}
+		for descendant := range d.getDescendants(h) {
+			candidates[descendant] = struct{}{}
+		}
+	}
+
+	// visit the candidates in topological order, passing on the latest dirty
+	// mark of each vertex to its children
+	pending := make(map[vertexHandle]int, len(candidates))
+	var ready map[vertexHandle]struct{}
+	for h := range candidates {
+		for parent := range d.inboundEdge[h] {
+			if _, ok := candidates[parent]; ok {
+				pending[h]++
+			}
+		}
+		if pending[h] == 0 {
+			if ready == nil {
+				ready = make(map[vertexHandle]struct{})
+			}
+			ready[h] = struct{}{}
+		}
+	}
+	latest := make(map[vertexHandle]uint64, len(candidates))
+	dirty := []string{}
+	for len(ready) > 0 {
+		next := make(map[vertexHandle]struct{})
+		for _, id := range d.orderedRelativeIDs(ready) {
+			h := d.keyOf(id)
+			if mark := d.dirtyMarks[h]; mark > latest[h] {
+				latest[h] = mark
+			}
+			if latest[h] > d.cleanMarks[h] {
+				dirty = append(dirty, id)
+			}
+			for child := range d.outboundEdge[h] {
+				if latest[h] > latest[child] {
+					latest[child] = latest[h]
+				}
+				if pending[child]--; pending[child] == 0 {
+					next[child] = struct{}{}
+				}
+			}
+		}
+		ready = next
+	}
+	return dirty
+}
+
+// MarkDirty marks the vertex with id and all its descendants as dirty. See
+// GenericDAG.MarkDirty for details.
+func (d *TypedDAG[T]) MarkDirty(id string) error {
+	return d.inner.MarkDirty(id)
+}
+
+// MarkClean marks the vertex with id as clean. See GenericDAG.MarkClean for
+// details.
+func (d *TypedDAG[T]) MarkClean(id string) error {
+	return d.inner.MarkClean(id)
+}
+
+// IsDirty reports whether the vertex with id is dirty.
+func (d *TypedDAG[T]) IsDirty(id string) (bool, error) {
+	return d.inner.IsDirty(id)
+}
+
+// DirtyVertices returns the ids of all dirty vertices in topological order.
+func (d *TypedDAG[T]) DirtyVertices() []string {
+	return d.inner.DirtyVertices()
+}
@@ -0,0 +1,83 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarkDirty(t *testing.T) {
+	/*   a   b
+	 *   |\ /
+	 *   c d
+	 *   |/
+	 *   e
+	 */
+	d := New[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "c"}, {"a", "d"}, {"b", "d"}, {"c", "e"}, {"d", "e"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	if got := d.DirtyVertices(); len(got) != 0 {
+		t.Errorf("DirtyVertices() = %v, want none", got)
+	}
+	if err := d.MarkDirty("a"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.DirtyVertices(), []string{"a", "c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DirtyVertices() = %v, want %v", got, want)
+	}
+
+	// rebuilding a vertex leaves its descendants dirty
+	for _, id := range []string{"a", "c"} {
+		if err := d.MarkClean(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := d.DirtyVertices(), []string{"d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DirtyVertices() = %v, want %v", got, want)
+	}
+	if dirty, _ := d.IsDirty("c"); dirty {
+		t.Error("IsDirty(c) = true, want false")
+	}
+	if dirty, _ := d.IsDirty("e"); !dirty {
+		t.Error("IsDirty(e) = false, want true")
+	}
+
+	// marking an ancestor dirty again overrides clean marks
+	if err := d.MarkDirty("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.MarkClean("e"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.DirtyVertices(), []string{"b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DirtyVertices() = %v, want %v", got, want)
+	}
+	if err := d.MarkDirty("c"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.DirtyVertices(), []string{"b", "c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DirtyVertices() = %v, want %v", got, want)
+	}
+
+	// marks are removed along with their vertex
+	if err := d.DeleteVertex("b"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.DirtyVertices(), []string{"c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DirtyVertices() = %v, want %v", got, want)
+	}
+
+	if err := d.MarkDirty("x"); err != (IDUnknownError{"x"}) {
+		t.Errorf("MarkDirty(x) = %v, want IDUnknownError", err)
+	}
+	if err := d.MarkClean(""); err != (IDEmptyError{}) {
+		t.Errorf("MarkClean('') = %v, want IDEmptyError", err)
+	}
+	if _, err := d.IsDirty("x"); err != (IDUnknownError{"x"}) {
+		t.Errorf("IsDirty(x) = %v, want IDUnknownError", err)
+	}
+}
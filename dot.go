@@ -0,0 +1,243 @@
+package dag
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DOTOptions configures the Graphviz DOT output written by WriteDOT.
+type DOTOptions struct {
+	// Name is the name of the digraph. It is omitted if empty.
+	Name string
+
+	// VertexStyle returns the style of the vertex with the given id and
+	// value, e.g. to color-code the state of a pipeline step. If VertexStyle
+	// is nil, vertices are written without attributes.
+	VertexStyle func(id string, v interface{}) NodeStyle
+
+	// EdgeStyle returns the style of the edge between srcID and dstID. If
+	// EdgeStyle is nil, edges are written without attributes.
+	EdgeStyle func(srcID, dstID string) EdgeStyle
+
+	// VertexLabel returns the label of the vertex with the given id and
+	// value. It takes precedence over the label of VertexStyle. If
+	// VertexLabel is nil, the label of VertexStyle is used.
+	VertexLabel func(id string, v interface{}) string
+
+	// EdgeLabel returns the label of the edge between srcID and dstID. It
+	// takes precedence over the label of EdgeStyle. If EdgeLabel is nil, the
+	// label of EdgeStyle is used.
+	EdgeLabel func(srcID, dstID string) string
+}
+
+// NodeStyle holds the Graphviz attributes of a vertex. Empty attributes are
+// omitted.
+type NodeStyle struct {
+	// Label replaces the id as the text of the vertex.
+	Label string
+
+	// Shape is the shape of the vertex, e.g. "box" or "ellipse".
+	Shape string
+
+	// Style is the style of the vertex, e.g. "filled" or "dashed".
+	Style string
+
+	// Color is the color of the outline, e.g. "red" or "#ff0000".
+	Color string
+
+	// FillColor is the color of the background, if Style is "filled".
+	FillColor string
+}
+
+// EdgeStyle holds the Graphviz attributes of an edge. Empty attributes are
+// omitted.
+type EdgeStyle struct {
+	// Label is the text of the edge.
+	Label string
+
+	// Style is the style of the edge, e.g. "dashed" or "bold".
+	Style string
+
+	// Color is the color of the edge, e.g. "red" or "#ff0000".
+	Color string
+}
+
+func (s NodeStyle) attributes() string {
+	return dotAttributes("label", s.Label, "shape", s.Shape, "style", s.Style,
+		"color", s.Color, "fillcolor", s.FillColor)
+}
+
+func (s EdgeStyle) attributes() string {
+	return dotAttributes("label", s.Label, "style", s.Style, "color", s.Color)
+}
+
+// dotAttributes formats the given pairs of names and values as a DOT
+// attribute list, skipping empty values. It returns "" if all values are
+// empty.
+func dotAttributes(pairs ...string) string {
+	var attrs []string
+	for i := 0; i < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			attrs = append(attrs, pairs[i]+"="+dotQuote(pairs[i+1]))
+		}
+	}
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
+
+// WriteDOT writes the GenericDAG in the Graphviz DOT language to w. Vertices
+// are identified by their ids and written in sorted order, so the output is
+// deterministic.
+func (d *GenericDAG[T]) WriteDOT(w io.Writer, opts DOTOptions) error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	ids := make([]string, 0, d.ids.len())
+	for id := range d.ids.handles {
+		ids = append(ids, id)
+	}
+	return writeDOT(w, ids, func(id string) []string {
+		return relativeIDs(&d.ids, d.outboundEdge[d.keyOf(id)])
+	}, func(id string) interface{} {
+		return d.value(id)
+	}, opts)
+}
+
+// WriteDOT writes the TypedDAG in the Graphviz DOT language to w.
+func (d *TypedDAG[T]) WriteDOT(w io.Writer, opts DOTOptions) error {
+	return d.inner.WriteDOT(w, opts)
+}
+
+// MarshalDOT returns the GenericDAG in the Graphviz DOT language. See WriteDOT
+// for details.
+func (d *GenericDAG[T]) MarshalDOT(opts DOTOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.WriteDOT(&buf, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalDOT returns the TypedDAG in the Graphviz DOT language.
+func (d *TypedDAG[T]) MarshalDOT(opts DOTOptions) ([]byte, error) {
+	return d.inner.MarshalDOT(opts)
+}
+
+// relativeIDs maps a set of vertex keys to the ids of these vertices.
+func relativeIDs(t *idTable, keys map[vertexHandle]struct{}) []string {
+	ids := make([]string, 0, len(keys))
+	for k := range keys {
+		ids = append(ids, t.id(k))
+	}
+	return ids
+}
+
+func writeDOT(w io.Writer, ids []string, children func(id string) []string, value func(id string) interface{}, opts DOTOptions) error {
+	sort.Strings(ids)
+	b := bufio.NewWriter(w)
+	if opts.Name != "" {
+		fmt.Fprintf(b, "digraph %s {\n", dotQuote(opts.Name))
+	} else {
+		fmt.Fprintln(b, "digraph {")
+	}
+	for _, id := range ids {
+		var style NodeStyle
+		if opts.VertexStyle != nil {
+			style = opts.VertexStyle(id, value(id))
+		}
+		if opts.VertexLabel != nil {
+			style.Label = opts.VertexLabel(id, value(id))
+		}
+		fmt.Fprintf(b, "\t%s%s;\n", dotQuote(id), style.attributes())
+	}
+	for _, id := range ids {
+		childIDs := children(id)
+		sort.Strings(childIDs)
+		for _, child := range childIDs {
+			var style EdgeStyle
+			if opts.EdgeStyle != nil {
+				style = opts.EdgeStyle(id, child)
+			}
+			if opts.EdgeLabel != nil {
+				style.Label = opts.EdgeLabel(id, child)
+			}
+			fmt.Fprintf(b, "\t%s -> %s%s;\n", dotQuote(id), dotQuote(child), style.attributes())
+		}
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// GraphvizRenderer renders the DOT document read from dot into the given
+// output format (e.g. "svg" or "png") and writes the result to w.
+type GraphvizRenderer func(ctx context.Context, format string, dot io.Reader, w io.Writer) error
+
+// graphvizFormat restricts output formats to what Graphviz accepts after -T
+// (e.g. "svg", "png", "png:cairo"), so a format can't inject other flags.
+var graphvizFormat = regexp.MustCompile(`^[a-z0-9_]+(:[a-z0-9_]+)*$`)
+
+// ExecGraphvizRenderer returns a GraphvizRenderer that runs the Graphviz
+// binary at path (e.g. "dot"). Relative names are looked up in PATH.
+func ExecGraphvizRenderer(path string) GraphvizRenderer {
+	return func(ctx context.Context, format string, dot io.Reader, w io.Writer) error {
+		if !graphvizFormat.MatchString(format) {
+			return fmt.Errorf("invalid graphviz output format %q", format)
+		}
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, path, "-T"+format)
+		cmd.Stdin = dot
+		cmd.Stdout = w
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("%s: %w: %s", path, err, msg)
+			}
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+// renderGraphviz renders the DOT document written by writeTo. If renderer is
+// nil, the locally installed dot binary is used.
+func renderGraphviz(ctx context.Context, format string, w io.Writer, renderer GraphvizRenderer, writeTo func(io.Writer) error) error {
+	if renderer == nil {
+		renderer = ExecGraphvizRenderer("dot")
+	}
+	var dot bytes.Buffer
+	if err := writeTo(&dot); err != nil {
+		return err
+	}
+	return renderer(ctx, format, &dot, w)
+}
+
+// RenderGraphviz renders the GenericDAG into the given Graphviz output format
+// (e.g. "svg" or "png") and writes the result to w. If renderer is nil, the
+// locally installed dot binary is used.
+func (d *GenericDAG[T]) RenderGraphviz(ctx context.Context, format string, w io.Writer, renderer GraphvizRenderer) error {
+	return renderGraphviz(ctx, format, w, renderer, func(dot io.Writer) error {
+		return d.WriteDOT(dot, DOTOptions{})
+	})
+}
+
+// RenderGraphviz renders the TypedDAG into the given Graphviz output format
+// (e.g. "svg" or "png") and writes the result to w. If renderer is nil, the
+// locally installed dot binary is used.
+func (d *TypedDAG[T]) RenderGraphviz(ctx context.Context, format string, w io.Writer, renderer GraphvizRenderer) error {
+	return d.inner.RenderGraphviz(ctx, format, w, renderer)
+}
@@ -0,0 +1,172 @@
+package dag
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	d := New[string]()
+	d.MustAddVertexByID("b", "B")
+	d.MustAddVertexByID("a", "A")
+	d.MustAddVertexByID(`c "quoted"`, "C")
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("a", `c "quoted"`)
+
+	var b strings.Builder
+	if err := d.WriteDOT(&b, DOTOptions{Name: "g"}); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	want := "digraph \"g\" {\n\t\"a\";\n\t\"b\";\n\t\"c \\\"quoted\\\"\";\n\t\"a\" -> \"b\";\n\t\"a\" -> \"c \\\"quoted\\\"\";\n}\n"
+	if b.String() != want {
+		t.Errorf("WriteDOT() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestWriteDOT_Styles(t *testing.T) {
+	d := New[string]()
+	d.MustAddVertexByID("build", "succeeded")
+	d.MustAddVertexByID("test", "failed")
+	d.MustAddVertexByID("deploy", "")
+	d.MustAddEdge("build", "test")
+	d.MustAddEdge("test", "deploy")
+
+	colors := map[string]string{"succeeded": "green", "failed": "red"}
+	opts := DOTOptions{
+		VertexStyle: func(id string, v interface{}) NodeStyle {
+			if color, ok := colors[v.(string)]; ok {
+				return NodeStyle{Style: "filled", FillColor: color}
+			}
+			return NodeStyle{}
+		},
+		EdgeStyle: func(srcID, dstID string) EdgeStyle {
+			if srcID == "test" {
+				return EdgeStyle{Style: "dashed", Label: "skipped"}
+			}
+			return EdgeStyle{}
+		},
+	}
+	var b strings.Builder
+	if err := d.WriteDOT(&b, opts); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	want := "digraph {\n" +
+		"\t\"build\" [style=\"filled\", fillcolor=\"green\"];\n" +
+		"\t\"deploy\";\n" +
+		"\t\"test\" [style=\"filled\", fillcolor=\"red\"];\n" +
+		"\t\"build\" -> \"test\";\n" +
+		"\t\"test\" -> \"deploy\" [label=\"skipped\", style=\"dashed\"];\n" +
+		"}\n"
+	if b.String() != want {
+		t.Errorf("WriteDOT() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestMarshalDOT_RoundTrip(t *testing.T) {
+	d := NewDAG()
+	d.MustAddVertexByID("build", 1)
+	d.MustAddVertexByID("test", 2)
+	d.MustAddVertexByID("deploy", 3)
+	d.MustAddEdge("build", "test")
+	d.MustAddEdge("test", "deploy")
+
+	data, err := d.MarshalDOT(DOTOptions{
+		VertexLabel: func(id string, v interface{}) string { return strings.ToUpper(id) },
+		EdgeLabel:   func(srcID, dstID string) string { return srcID + " then " + dstID },
+		EdgeStyle:   func(srcID, dstID string) EdgeStyle { return EdgeStyle{Label: "ignored", Style: "bold"} },
+	})
+	if err != nil {
+		t.Fatalf("MarshalDOT failed: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"test" -> "deploy" [label="test then deploy", style="bold"];`)) {
+		t.Errorf("MarshalDOT() = %s, want labeled edges", data)
+	}
+
+	g, err := UnmarshalDOT(data, DOTImportOptions{})
+	if err != nil {
+		t.Fatalf("UnmarshalDOT failed: %v", err)
+	}
+	buildTest, _ := g.IsEdge("build", "test")
+	testDeploy, _ := g.IsEdge("test", "deploy")
+	if g.GetOrder() != 3 || g.GetSize() != 2 || !buildTest || !testDeploy {
+		t.Errorf("UnmarshalDOT() = %v, want the marshaled graph", g)
+	}
+	if v, _ := g.GetVertex("deploy"); v != "DEPLOY" {
+		t.Errorf("UnmarshalDOT() labeled deploy %q, want DEPLOY", v)
+	}
+}
+
+func TestMarshalDOT_RoundTripEscapes(t *testing.T) {
+	ids := []string{`C:\tmp`, `say "hi"`, "multi\nline", `back\\slashes\"`}
+	d := NewDAG()
+	for i, id := range ids {
+		d.MustAddVertexByID(id, i)
+		if i > 0 {
+			d.MustAddEdge(ids[i-1], id)
+		}
+	}
+
+	data, err := d.MarshalDOT(DOTOptions{VertexLabel: func(id string, v interface{}) string { return id }})
+	if err != nil {
+		t.Fatalf("MarshalDOT failed: %v", err)
+	}
+	g, err := UnmarshalDOT(data, DOTImportOptions{})
+	if err != nil {
+		t.Fatalf("UnmarshalDOT failed: %v", err)
+	}
+	if g.GetOrder() != len(ids) || g.GetSize() != len(ids)-1 {
+		t.Errorf("UnmarshalDOT() = %v, want the marshaled graph", g)
+	}
+	for i, id := range ids {
+		if v, err := g.GetVertex(id); err != nil || v != id {
+			t.Errorf("UnmarshalDOT() vertex %q = %q, %v, want its id as label", id, v, err)
+		}
+		if i > 0 {
+			if ok, _ := g.IsEdge(ids[i-1], id); !ok {
+				t.Errorf("UnmarshalDOT() lost the edge %q -> %q", ids[i-1], id)
+			}
+		}
+	}
+}
+
+func TestRenderGraphviz(t *testing.T) {
+	d := NewGenericDAG[int]()
+	d.MustAddVertexByID("a", 1)
+	d.MustAddVertexByID("b", 2)
+	d.MustAddEdge("a", "b")
+
+	var gotFormat, gotDOT string
+	renderer := func(ctx context.Context, format string, dot io.Reader, w io.Writer) error {
+		gotFormat = format
+		data, _ := io.ReadAll(dot)
+		gotDOT = string(data)
+		_, err := io.WriteString(w, "rendered")
+		return err
+	}
+	var out bytes.Buffer
+	if err := d.RenderGraphviz(context.Background(), "svg", &out, renderer); err != nil {
+		t.Fatalf("RenderGraphviz failed: %v", err)
+	}
+	if gotFormat != "svg" || !strings.Contains(gotDOT, `"a" -> "b";`) || out.String() != "rendered" {
+		t.Errorf("renderer got format %q and DOT %q, wrote %q", gotFormat, gotDOT, out.String())
+	}
+
+	if err := d.RenderGraphviz(context.Background(), "-o/tmp/x", &out, ExecGraphvizRenderer("dot")); err == nil {
+		t.Error("RenderGraphviz should reject invalid formats")
+	}
+
+	if _, err := exec.LookPath("dot"); err != nil {
+		t.Skip("graphviz is not installed")
+	}
+	out.Reset()
+	if err := d.RenderGraphviz(context.Background(), "svg", &out, nil); err != nil {
+		t.Fatalf("RenderGraphviz with dot failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "<svg") {
+		t.Errorf("RenderGraphviz() did not produce SVG: %q", out.String())
+	}
+}
@@ -0,0 +1,500 @@
+package dag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// DOTImportOptions configures ReadDOT.
+type DOTImportOptions struct {
+	// Reverse reverses all edges, e.g. to turn "depends on" edges into "is
+	// required by" edges, so that the descendants of a vertex are the
+	// vertices affected by changing it.
+	Reverse bool
+
+	// CutCycles drops edges that would close a cycle (in the order they
+	// appear in the input) instead of failing. Self-loops are dropped too.
+	CutCycles bool
+
+	// OnCut is called for each edge dropped by CutCycles.
+	OnCut func(srcID, dstID string)
+
+	// MapID maps the id of a node of the input to the id of its vertex, or
+	// returns false to drop the node along with its edges. Nodes mapped to
+	// the same id are merged. If MapID is nil, the ids of the input are used.
+	MapID func(id string) (string, bool)
+}
+
+// ReadDOT reads a graph in the Graphviz DOT language from r and returns it as
+// GenericDAG. The value of each vertex is the label of its node, or its id if
+// the node has no label. Vertices are keyed by id only, so labels may repeat.
+// Subgraphs are flattened, and ports and all other attributes are ignored.
+//
+// ReadDOT returns an error if the input can't be parsed, uses edges to or
+// from subgraphs, or contains a cycle and CutCycles isn't set.
+func ReadDOT(r io.Reader, opts DOTImportOptions) (*GenericDAG[string], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &dotParser{lexer: dotLexer{input: string(data)}, labels: make(map[string]string)}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+
+	d := NewGenericDAG[string](WithDuplicateValues(), WithCapacity(len(p.nodes), len(p.edges)))
+	mapID := func(id string) (string, bool) {
+		if opts.MapID == nil {
+			return id, true
+		}
+		return opts.MapID(id)
+	}
+	for _, node := range p.nodes {
+		id, ok := mapID(node)
+		if !ok {
+			continue
+		}
+		if _, exists := d.ids.handle(id); exists {
+			continue
+		}
+		label, ok := p.labels[node]
+		if !ok {
+			label = id
+		}
+		if err := d.AddVertexByID(id, label); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range p.edges {
+		src, srcOK := mapID(e[0])
+		dst, dstOK := mapID(e[1])
+		if !srcOK || !dstOK {
+			continue
+		}
+		if opts.Reverse {
+			src, dst = dst, src
+		}
+		err := d.AddEdge(src, dst)
+		switch err.(type) {
+		case nil, EdgeDuplicateError:
+		case SrcDstEqualError:
+			// merged nodes may yield self-loops, which are no cycles of
+			// the input
+			if e[0] != e[1] {
+				continue
+			}
+			if !opts.CutCycles {
+				return nil, err
+			}
+			if opts.OnCut != nil {
+				opts.OnCut(src, dst)
+			}
+		case EdgeLoopError:
+			if !opts.CutCycles {
+				return nil, err
+			}
+			if opts.OnCut != nil {
+				opts.OnCut(src, dst)
+			}
+		default:
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// UnmarshalDOT parses a graph in the Graphviz DOT language, e.g. as returned
+// by MarshalDOT. See ReadDOT for details.
+func UnmarshalDOT(data []byte, opts DOTImportOptions) (*GenericDAG[string], error) {
+	return ReadDOT(bytes.NewReader(data), opts)
+}
+
+// ReadTerraformGraph reads the output of "terraform graph" from r. The
+// vertices are named like the resources, e.g. "aws_instance.web", without the
+// module path prefix "[root] " and suffixes like " (expand)" of older versions
+// of Terraform, whose synthetic root, meta and "(close)" nodes are dropped.
+//
+// In the output of Terraform, and so by default in the returned graph, an
+// edge points from a resource to a resource it depends on. Set Reverse in
+// opts to get the resources affected by a change as its descendants.
+func ReadTerraformGraph(r io.Reader, opts DOTImportOptions) (*GenericDAG[string], error) {
+	mapID := opts.MapID
+	opts.MapID = func(id string) (string, bool) {
+		id, ok := terraformID(id)
+		if ok && mapID != nil {
+			return mapID(id)
+		}
+		return id, ok
+	}
+	return ReadDOT(r, opts)
+}
+
+// terraformID returns the resource name of the node with id of the output of
+// terraform graph, or false for synthetic nodes.
+func terraformID(id string) (string, bool) {
+	id = strings.TrimPrefix(id, "[root] ")
+	if id == "root" || strings.HasPrefix(id, "meta.") || strings.HasSuffix(id, " (close)") {
+		return "", false
+	}
+	for _, suffix := range []string{" (expand)", " (prepare state)"} {
+		id = strings.TrimSuffix(id, suffix)
+	}
+	return id, true
+}
+
+// dotParser collects the nodes, node labels and edges of a DOT document.
+type dotParser struct {
+	lexer  dotLexer
+	token  string
+	quoted bool
+	nodes  []string
+	labels map[string]string
+	edges  [][2]string
+	known  map[string]struct{}
+}
+
+var errDOTSyntax = errors.New("dot: syntax error")
+
+func (p *dotParser) next() error {
+	var err error
+	p.token, p.quoted, err = p.lexer.next()
+	return err
+}
+
+func (p *dotParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w at offset %d: %s", errDOTSyntax, p.lexer.pos, fmt.Sprintf(format, args...))
+}
+
+// keyword reports whether the current token is the unquoted keyword kw. DOT
+// keywords are case-insensitive.
+func (p *dotParser) keyword(kw string) bool {
+	return !p.quoted && strings.EqualFold(p.token, kw)
+}
+
+func (p *dotParser) punct(s string) bool {
+	return !p.quoted && p.token == s
+}
+
+func (p *dotParser) parse() error {
+	p.known = make(map[string]struct{})
+	if err := p.next(); err != nil {
+		return err
+	}
+	if p.keyword("strict") {
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+	if !p.keyword("digraph") && !p.keyword("graph") {
+		return p.errorf("expected graph or digraph, got %q", p.token)
+	}
+	if err := p.next(); err != nil {
+		return err
+	}
+	if !p.punct("{") {
+		// the optional name of the graph
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+	if !p.punct("{") {
+		return p.errorf("expected '{', got %q", p.token)
+	}
+	return p.statements()
+}
+
+// statements parses the statements of a graph or subgraph up to and
+// including the closing brace.
+func (p *dotParser) statements() error {
+	if err := p.next(); err != nil {
+		return err
+	}
+	for !p.punct("}") {
+		if p.token == "" && !p.quoted {
+			return p.errorf("unexpected end of input")
+		}
+		if err := p.statement(); err != nil {
+			return err
+		}
+		if p.punct(";") {
+			if err := p.next(); err != nil {
+				return err
+			}
+		}
+	}
+	return p.next()
+}
+
+func (p *dotParser) statement() error {
+	switch {
+	case p.keyword("subgraph") || p.punct("{"):
+		if p.keyword("subgraph") {
+			if err := p.next(); err != nil {
+				return err
+			}
+			if !p.punct("{") {
+				// the optional name of the subgraph
+				if err := p.next(); err != nil {
+					return err
+				}
+			}
+		}
+		if !p.punct("{") {
+			return p.errorf("expected '{', got %q", p.token)
+		}
+		if err := p.statements(); err != nil {
+			return err
+		}
+		if p.punct("->") || p.punct("--") {
+			return p.errorf("edges to subgraphs are not supported")
+		}
+		return nil
+	case p.keyword("graph") || p.keyword("node") || p.keyword("edge"):
+		if err := p.next(); err != nil {
+			return err
+		}
+		_, err := p.attributes()
+		return err
+	}
+
+	id, err := p.nodeID()
+	if err != nil {
+		return err
+	}
+	if p.punct("=") {
+		// an attribute of the graph
+		if err := p.next(); err != nil {
+			return err
+		}
+		return p.next()
+	}
+	ids := []string{id}
+	for p.punct("->") || p.punct("--") {
+		if err := p.next(); err != nil {
+			return err
+		}
+		if p.punct("{") || p.keyword("subgraph") {
+			return p.errorf("edges to subgraphs are not supported")
+		}
+		id, err := p.nodeID()
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	attrs, err := p.attributes()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, known := p.known[id]; !known {
+			p.known[id] = struct{}{}
+			p.nodes = append(p.nodes, id)
+		}
+	}
+	if len(ids) == 1 {
+		if label, ok := attrs["label"]; ok {
+			p.labels[id] = label
+		}
+		return nil
+	}
+	for i := 1; i < len(ids); i++ {
+		p.edges = append(p.edges, [2]string{ids[i-1], ids[i]})
+	}
+	return nil
+}
+
+// nodeID parses a node id, skipping its port if any.
+func (p *dotParser) nodeID() (string, error) {
+	if !p.quoted && !isDOTID(p.token) {
+		return "", p.errorf("expected node id, got %q", p.token)
+	}
+	id := p.token
+	if err := p.next(); err != nil {
+		return "", err
+	}
+	for i := 0; i < 2 && p.punct(":"); i++ {
+		if err := p.next(); err != nil {
+			return "", err
+		}
+		if err := p.next(); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+// attributes parses any number of attribute lists.
+func (p *dotParser) attributes() (map[string]string, error) {
+	attrs := make(map[string]string)
+	for p.punct("[") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		for !p.punct("]") {
+			if p.token == "" && !p.quoted {
+				return nil, p.errorf("unexpected end of input")
+			}
+			key := p.token
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if p.punct("=") {
+				if err := p.next(); err != nil {
+					return nil, err
+				}
+				attrs[key] = p.token
+				if err := p.next(); err != nil {
+					return nil, err
+				}
+			}
+			if p.punct(",") || p.punct(";") {
+				if err := p.next(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
+
+// isDOTID reports whether the unquoted token is an id, i.e. no punctuation.
+func isDOTID(token string) bool {
+	switch token {
+	case "", "{", "}", "[", "]", "=", ";", ",", ":", "->", "--":
+		return false
+	}
+	return true
+}
+
+// dotLexer splits a DOT document into tokens.
+type dotLexer struct {
+	input string
+	pos   int
+}
+
+// next returns the next token and whether it was quoted, or "" and false at
+// the end of the input.
+func (l *dotLexer) next() (string, bool, error) {
+	l.skipSpaceAndComments()
+	if l.pos >= len(l.input) {
+		return "", false, nil
+	}
+	c := l.input[l.pos]
+	switch {
+	case c == '"':
+		return l.quoted()
+	case c == '<':
+		return l.html()
+	case strings.HasPrefix(l.input[l.pos:], "->") || strings.HasPrefix(l.input[l.pos:], "--"):
+		l.pos += 2
+		return l.input[l.pos-2 : l.pos], false, nil
+	case strings.ContainsRune("{}[]=;,:", rune(c)):
+		l.pos++
+		return string(c), false, nil
+	}
+	start := l.pos
+	if c == '-' {
+		// a negative number
+		l.pos++
+	}
+	for l.pos < len(l.input) && isDOTIDByte(l.input[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start || l.input[start:l.pos] == "-" {
+		return "", false, fmt.Errorf("%w at offset %d: unexpected %q", errDOTSyntax, start, c)
+	}
+	return l.input[start:l.pos], false, nil
+}
+
+// isDOTIDByte reports whether b may be part of an unquoted id or number.
+func isDOTIDByte(b byte) bool {
+	return b == '_' || b == '.' || b >= 0x80 ||
+		'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9'
+}
+
+func (l *dotLexer) skipSpaceAndComments() {
+	for l.pos < len(l.input) {
+		rest := l.input[l.pos:]
+		switch {
+		case unicode.IsSpace(rune(rest[0])):
+			l.pos++
+		case strings.HasPrefix(rest, "//") || rest[0] == '#':
+			if i := strings.IndexByte(rest, '\n'); i >= 0 {
+				l.pos += i + 1
+			} else {
+				l.pos = len(l.input)
+			}
+		case strings.HasPrefix(rest, "/*"):
+			if i := strings.Index(rest[2:], "*/"); i >= 0 {
+				l.pos += i + 4
+			} else {
+				l.pos = len(l.input)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// quoted returns the content of the double-quoted string at the current
+// position. Escaped quotes, backslashes and newlines (\n) are unescaped like
+// dotQuote escapes them, and escaped line breaks are removed. Other escape
+// sequences of Graphviz, e.g. \l, are kept as they are.
+func (l *dotLexer) quoted() (string, bool, error) {
+	var b strings.Builder
+	for i := l.pos + 1; i < len(l.input); i++ {
+		switch c := l.input[i]; c {
+		case '"':
+			l.pos = i + 1
+			return b.String(), true, nil
+		case '\\':
+			if i+1 < len(l.input) {
+				switch next := l.input[i+1]; next {
+				case '"', '\\':
+					b.WriteByte(next)
+					i++
+					continue
+				case 'n':
+					b.WriteByte('\n')
+					i++
+					continue
+				case '\n':
+					i++
+					continue
+				}
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false, fmt.Errorf("%w at offset %d: unterminated string", errDOTSyntax, l.pos)
+}
+
+// html returns the HTML string at the current position, without the
+// outermost angle brackets.
+func (l *dotLexer) html() (string, bool, error) {
+	depth := 0
+	for i := l.pos; i < len(l.input); i++ {
+		switch l.input[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				s := l.input[l.pos+1 : i]
+				l.pos = i + 1
+				return s, true, nil
+			}
+		}
+	}
+	return "", false, fmt.Errorf("%w at offset %d: unterminated HTML string", errDOTSyntax, l.pos)
+}
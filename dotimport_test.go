@@ -0,0 +1,97 @@
+package dag
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadDOT(t *testing.T) {
+	input := `/* a comment */
+strict digraph "deps" {
+	graph [rankdir = LR];
+	node [shape = box]
+	label = "ignored"
+	a [label = "Task A", color = red]
+	"b c" [label = <<b>B</b>>]
+	a -> "b c" -> d:port:n [style = dashed]
+	# another comment
+	subgraph cluster_x {
+		d -> e; a -> e
+		{ f }
+	}
+	e -> f // trailing comment
+	"b c" -> d
+}`
+	d, err := ReadDOT(strings.NewReader(input), DOTImportOptions{})
+	if err != nil {
+		t.Fatalf("ReadDOT() unexpected error: %v", err)
+	}
+	wantVertices := map[string]string{"a": "Task A", "b c": "<b>B</b>", "d": "d", "e": "e", "f": "f"}
+	if got := d.GetVertices(); !reflect.DeepEqual(got, wantVertices) {
+		t.Errorf("ReadDOT() vertices = %v, want %v", got, wantVertices)
+	}
+	for _, e := range [][2]string{{"a", "b c"}, {"b c", "d"}, {"d", "e"}, {"a", "e"}, {"e", "f"}} {
+		if isEdge, _ := d.IsEdge(e[0], e[1]); !isEdge {
+			t.Errorf("ReadDOT() misses edge %s -> %s", e[0], e[1])
+		}
+	}
+	if d.GetSize() != 5 {
+		t.Errorf("ReadDOT() has %d edges, want 5", d.GetSize())
+	}
+
+	reversed, _ := ReadDOT(strings.NewReader(input), DOTImportOptions{Reverse: true})
+	if isEdge, _ := reversed.IsEdge("f", "e"); !isEdge {
+		t.Error("ReadDOT() with Reverse misses edge f -> e")
+	}
+
+	cyclic := `digraph { a -> b -> c -> a; c -> c; c -> d }`
+	if _, err := ReadDOT(strings.NewReader(cyclic), DOTImportOptions{}); err == nil {
+		t.Error("ReadDOT() of a cyclic graph should fail")
+	}
+	var cut [][2]string
+	d, err = ReadDOT(strings.NewReader(cyclic), DOTImportOptions{CutCycles: true, OnCut: func(src, dst string) {
+		cut = append(cut, [2]string{src, dst})
+	}})
+	if err != nil {
+		t.Fatalf("ReadDOT() with CutCycles unexpected error: %v", err)
+	}
+	if want := [][2]string{{"c", "a"}, {"c", "c"}}; !reflect.DeepEqual(cut, want) || d.GetSize() != 3 {
+		t.Errorf("ReadDOT() with CutCycles cut %v and kept %d edges, want %v and 3", cut, d.GetSize(), want)
+	}
+
+	for _, invalid := range []string{``, `digraph {`, `digraph { a -> }`, `tree { }`, `digraph { a -> { b } }`, `digraph { "a }`} {
+		if _, err := ReadDOT(strings.NewReader(invalid), DOTImportOptions{}); err == nil {
+			t.Errorf("ReadDOT(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestReadTerraformGraph(t *testing.T) {
+	input := `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] aws_instance.web (expand)" [label = "aws_instance.web", shape = "box"]
+		"[root] aws_security_group.sg (expand)" [label = "aws_security_group.sg", shape = "box"]
+		"[root] provider[\"registry.terraform.io/hashicorp/aws\"]" [label = "provider[\"registry.terraform.io/hashicorp/aws\"]", shape = "diamond"]
+		"[root] aws_instance.web (expand)" -> "[root] aws_security_group.sg (expand)"
+		"[root] aws_security_group.sg (expand)" -> "[root] provider[\"registry.terraform.io/hashicorp/aws\"]"
+		"[root] meta.count-boundary (EachMode fixup)" -> "[root] aws_instance.web (expand)"
+		"[root] provider[\"registry.terraform.io/hashicorp/aws\"] (close)" -> "[root] aws_instance.web (expand)"
+		"[root] root" -> "[root] provider[\"registry.terraform.io/hashicorp/aws\"] (close)"
+	}
+}`
+	d, err := ReadTerraformGraph(strings.NewReader(input), DOTImportOptions{Reverse: true})
+	if err != nil {
+		t.Fatalf("ReadTerraformGraph() unexpected error: %v", err)
+	}
+	provider := `provider["registry.terraform.io/hashicorp/aws"]`
+	affected, err := d.GetOrderedDescendants(provider)
+	if want := []string{"aws_security_group.sg", "aws_instance.web"}; err != nil || !reflect.DeepEqual(affected, want) {
+		t.Errorf("descendants of the provider = %v, %v, want %v", affected, err, want)
+	}
+	if d.GetOrder() != 3 {
+		t.Errorf("ReadTerraformGraph() has %d vertices, want 3: %v", d.GetOrder(), d.GetVertices())
+	}
+}
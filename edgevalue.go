@@ -0,0 +1,171 @@
+package dag
+
+import "sort"
+
+// GenericEdgeValue represents the value of an edge for serialization.
+type GenericEdgeValue struct {
+	SrcID string      `json:"s"`
+	DstID string      `json:"d"`
+	Value interface{} `json:"v"`
+}
+
+// AddEdgeWithValue adds an edge between srcID and dstID like AddEdge and
+// attaches value to it, e.g. a label or an arbitrary payload. Edge values
+// are kept by copies of the graph, serialized along with it and recorded as
+// OpSetEdgeValue, see Record.
+// AddEdgeWithValue returns the errors of AddEdge.
+func (d *GenericDAG[T]) AddEdgeWithValue(srcID, dstID string, value interface{}) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	if err := d.addEdge(srcID, dstID); err != nil {
+		return err
+	}
+	if value != nil {
+		d.changeEdgeValue(srcID, dstID, value)
+	}
+	return nil
+}
+
+// AddWeightedEdge adds an edge between srcID and dstID with the given weight
+// as its value. See AddEdgeWithValue for details.
+func (d *GenericDAG[T]) AddWeightedEdge(srcID, dstID string, weight float64) error {
+	return d.AddEdgeWithValue(srcID, dstID, weight)
+}
+
+// GetEdgeValue returns the value of the edge between srcID and dstID, or nil
+// if the edge has no value.
+// GetEdgeValue returns an error if srcID or dstID are empty or unknown, or if
+// there is no such edge.
+func (d *GenericDAG[T]) GetEdgeValue(srcID, dstID string) (interface{}, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	srcHash, dstHash, err := d.saneEdge(srcID, dstID)
+	if err != nil {
+		return nil, err
+	}
+	return d.edgeValues[[2]vertexHandle{srcHash, dstHash}], nil
+}
+
+// SetEdgeValue replaces the value of the edge between srcID and dstID. A nil
+// value removes the value of the edge.
+// SetEdgeValue returns an error if srcID or dstID are empty or unknown, or if
+// there is no such edge.
+func (d *GenericDAG[T]) SetEdgeValue(srcID, dstID string, value interface{}) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	if _, _, err := d.saneEdge(srcID, dstID); err != nil {
+		return err
+	}
+	d.changeEdgeValue(srcID, dstID, value)
+	return nil
+}
+
+// changeEdgeValue sets the value of the existing edge between srcID and dstID
+// like setEdgeValue, and records the modification. It must be called with
+// the write lock held.
+func (d *GenericDAG[T]) changeEdgeValue(srcID, dstID string, value interface{}) {
+	d.setEdgeValue(d.keyOf(srcID), d.keyOf(dstID), value)
+	d.recordEdgeValueOp(srcID, dstID, value)
+	d.mutated()
+}
+
+// saneEdge returns the handles of srcID and dstID, or an error if they are
+// empty or unknown, or if there is no edge between them.
+func (d *GenericDAG[T]) saneEdge(srcID, dstID string) (vertexHandle, vertexHandle, error) {
+	if err := d.saneID(srcID); err != nil {
+		return 0, 0, err
+	}
+	if err := d.saneID(dstID); err != nil {
+		return 0, 0, err
+	}
+	srcHash, dstHash := d.keyOf(srcID), d.keyOf(dstID)
+	if !d.isEdge(srcHash, dstHash) {
+		return 0, 0, EdgeUnknownError{srcID, dstID}
+	}
+	return srcHash, dstHash, nil
+}
+
+// setEdgeValue sets the value of the existing edge from srcHash to dstHash,
+// or removes it, if value is nil.
+func (d *GenericDAG[T]) setEdgeValue(srcHash, dstHash vertexHandle, value interface{}) {
+	key := [2]vertexHandle{srcHash, dstHash}
+	if value == nil {
+		delete(d.edgeValues, key)
+		return
+	}
+	if d.edgeValues == nil {
+		d.edgeValues = make(map[[2]vertexHandle]interface{})
+	}
+	d.edgeValues[key] = value
+}
+
+// edgeValueList returns the values of all edges with a value, sorted by the
+// ids of their vertices, or nil if there are none. edgeValueList must be
+// called with the read lock held.
+func (d *GenericDAG[T]) edgeValueList() []GenericEdgeValue {
+	if len(d.edgeValues) == 0 {
+		return nil
+	}
+	values := make([]GenericEdgeValue, 0, len(d.edgeValues))
+	for key, value := range d.edgeValues {
+		values = append(values, GenericEdgeValue{SrcID: d.ids.id(key[0]), DstID: d.ids.id(key[1]), Value: value})
+	}
+	sortEdgeValues(values)
+	return values
+}
+
+// getEdgeValueList is edgeValueList, which takes the read lock.
+func (d *GenericDAG[T]) getEdgeValueList() []GenericEdgeValue {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.edgeValueList()
+}
+
+// setEdgeValues sets the given values of existing edges, e.g. when
+// unmarshaling. setEdgeValues returns an error if an edge is unknown.
+func (d *GenericDAG[T]) setEdgeValues(values []GenericEdgeValue) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	for _, v := range values {
+		srcHash, dstHash, err := d.saneEdge(v.SrcID, v.DstID)
+		if err != nil {
+			return err
+		}
+		d.setEdgeValue(srcHash, dstHash, v.Value)
+	}
+	return nil
+}
+
+// sortEdgeValues sorts values by the ids of their vertices.
+func sortEdgeValues(values []GenericEdgeValue) {
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].SrcID != values[j].SrcID {
+			return values[i].SrcID < values[j].SrcID
+		}
+		return values[i].DstID < values[j].DstID
+	})
+}
+
+// AddEdgeWithValue adds an edge between srcID and dstID with the given
+// value. See GenericDAG.AddEdgeWithValue for details.
+func (d *TypedDAG[T]) AddEdgeWithValue(srcID, dstID string, value interface{}) error {
+	return d.inner.AddEdgeWithValue(srcID, dstID, value)
+}
+
+// AddWeightedEdge adds an edge between srcID and dstID with the given weight
+// as its value.
+func (d *TypedDAG[T]) AddWeightedEdge(srcID, dstID string, weight float64) error {
+	return d.inner.AddWeightedEdge(srcID, dstID, weight)
+}
+
+// GetEdgeValue returns the value of the edge between srcID and dstID. See
+// GenericDAG.GetEdgeValue for details.
+func (d *TypedDAG[T]) GetEdgeValue(srcID, dstID string) (interface{}, error) {
+	return d.inner.GetEdgeValue(srcID, dstID)
+}
+
+// SetEdgeValue replaces the value of the edge between srcID and dstID. See
+// GenericDAG.SetEdgeValue for details.
+func (d *TypedDAG[T]) SetEdgeValue(srcID, dstID string, value interface{}) error {
+	return d.inner.SetEdgeValue(srcID, dstID, value)
+}
@@ -0,0 +1,116 @@
+package dag
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEdgeValues(t *testing.T) {
+	d := NewGenericDAG[string]()
+	for _, id := range []string{"a", "b", "c"} {
+		_ = d.AddVertexByID(id, id)
+	}
+	if err := d.AddWeightedEdge("a", "b", 2.5); err != nil {
+		t.Fatalf("AddWeightedEdge(a, b) unexpected error: %v", err)
+	}
+	if err := d.AddEdgeWithValue("b", "c", "label"); err != nil {
+		t.Fatalf("AddEdgeWithValue(b, c) unexpected error: %v", err)
+	}
+	if err := d.AddEdgeWithValue("c", "a", 1); err != (EdgeLoopError{"c", "a"}) {
+		t.Errorf("AddEdgeWithValue(c, a) = %v, want EdgeLoopError", err)
+	}
+	if v, _ := d.GetEdgeValue("a", "b"); v != 2.5 {
+		t.Errorf("GetEdgeValue(a, b) = %v, want 2.5", v)
+	}
+	if _, err := d.GetEdgeValue("a", "c"); err != (EdgeUnknownError{"a", "c"}) {
+		t.Errorf("GetEdgeValue(a, c) = %v, want EdgeUnknownError", err)
+	}
+	if err := d.SetEdgeValue("b", "c", "relabeled"); err != nil {
+		t.Fatalf("SetEdgeValue(b, c) unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal unexpected error: %v", err)
+	}
+	restored, err := UnmarshalGenericJSON[string](data, Options{})
+	if err != nil {
+		t.Fatalf("UnmarshalGenericJSON unexpected error: %v", err)
+	}
+	if v, _ := restored.GetEdgeValue("a", "b"); v != 2.5 {
+		t.Errorf("restored GetEdgeValue(a, b) = %v, want 2.5", v)
+	}
+	if v, _ := restored.GetEdgeValue("b", "c"); v != "relabeled" {
+		t.Errorf("restored GetEdgeValue(b, c) = %v, want relabeled", v)
+	}
+
+	copied, _ := d.Copy()
+	if v, _ := copied.GetEdgeValue("b", "c"); v != "relabeled" {
+		t.Errorf("copied GetEdgeValue(b, c) = %v, want relabeled", v)
+	}
+
+	_ = d.SoftDeleteVertex("b")
+	_ = d.RestoreVertex("b")
+	if v, _ := d.GetEdgeValue("a", "b"); v != 2.5 {
+		t.Errorf("GetEdgeValue(a, b) after RestoreVertex = %v, want 2.5", v)
+	}
+
+	_ = d.DeleteEdge("a", "b")
+	_ = d.AddEdge("a", "b")
+	if v, _ := d.GetEdgeValue("a", "b"); v != nil {
+		t.Errorf("GetEdgeValue(a, b) of re-added edge = %v, want nil", v)
+	}
+}
+
+func TestEdgeValuesDAG(t *testing.T) {
+	d := NewDAG()
+	_ = d.AddVertexByID("a", "a")
+	_ = d.AddVertexByID("b", "b")
+	_ = d.AddWeightedEdge("a", "b", 3)
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal unexpected error: %v", err)
+	}
+	restored, err := UnmarshalJSONGeneric[string](data, Options{})
+	if err != nil {
+		t.Fatalf("UnmarshalJSONGeneric unexpected error: %v", err)
+	}
+	if v, _ := restored.GetEdgeValue("a", "b"); v != 3.0 {
+		t.Errorf("restored GetEdgeValue(a, b) = %v, want 3", v)
+	}
+}
+
+func TestEdgeValueModifications(t *testing.T) {
+	d := NewGenericDAG[string]()
+	for _, id := range []string{"a", "b"} {
+		_ = d.AddVertexByID(id, id)
+	}
+	_ = d.AddEdge("a", "b")
+	modCount, hash := d.ModCount(), d.Hash()
+	merkle, _ := d.GetMerkleHash("b")
+
+	if err := d.SetEdgeValue("a", "b", 7); err != nil {
+		t.Fatal(err)
+	}
+	if d.ModCount() == modCount {
+		t.Error("ModCount() unchanged after SetEdgeValue")
+	}
+	if d.Hash() == hash {
+		t.Error("Hash() unchanged after SetEdgeValue")
+	}
+	if m, _ := d.GetMerkleHash("b"); m == merkle {
+		t.Error("GetMerkleHash(b) unchanged after SetEdgeValue")
+	}
+
+	// removing the value restores the digests
+	if err := d.SetEdgeValue("a", "b", nil); err != nil {
+		t.Fatal(err)
+	}
+	if d.Hash() != hash {
+		t.Error("Hash() differs after removing the edge value")
+	}
+	if m, _ := d.GetMerkleHash("b"); m != merkle {
+		t.Error("GetMerkleHash(b) differs after removing the edge value")
+	}
+}
@@ -1,7 +1,8 @@
 package dag_test
 
 import (
-	"fmt"
+	"os"
+
 	"github.com/JodeZer/dag"
 )
 
@@ -24,8 +25,8 @@ func Example() {
 	_ = d.AddEdge(v1, v2)
 	_ = d.AddEdge(v1, v3)
 
-	// describe the graph
-	fmt.Print(d.String())
+	// describe the graph, labeling the vertices by their values
+	_ = d.Format(os.Stdout, dag.FormatOptions{Label: dag.LabelValue})
 
 	// Unordered output:
 	// DAG Vertices: 3 - Edges: 2
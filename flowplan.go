@@ -0,0 +1,168 @@
+package dag
+
+import "encoding/json"
+
+// FlowPlan is a frozen copy of a vertex and all its descendants, on which a
+// flow can be run independently of the DAG it has been planned from.
+//
+// DescendantsFlow read-locks the DAG for the whole run, so topology edits
+// block until the flow is done, and a FlowCallback modifying the DAG
+// deadlocks. A FlowPlan instead captures the subgraph once, when it is
+// created: the DAG may be modified while the plan runs, also from within the
+// callbacks, and these modifications neither affect the running flow nor any
+// later run of the same plan.
+type FlowPlan struct {
+	graph   *DAG
+	startID string
+}
+
+// PlanFlow returns a FlowPlan of the vertex with the ID startID and all its
+// descendants as they are now. PlanFlow returns an error if startID is empty
+// or unknown.
+func (d *DAG) PlanFlow(startID string) (*FlowPlan, error) {
+	graph, newID, err := d.GetDescendantsGraph(startID)
+	if err != nil {
+		return nil, err
+	}
+	return &FlowPlan{graph: graph, startID: newID}, nil
+}
+
+// PlanFlow returns a FlowPlan of the vertex with the ID startID and all its
+// descendants as they are now. PlanFlow returns an error if startID is empty
+// or unknown.
+func (d *TypedDAG[T]) PlanFlow(startID string) (*FlowPlan, error) {
+	// capture the subgraph directly instead of boxing a typed copy of it
+	d.inner.muDAG.RLock()
+	if err := d.inner.saneID(startID); err != nil {
+		d.inner.muDAG.RUnlock()
+		return nil, err
+	}
+	s := d.inner.descendantsSnapshot(d.inner.keyOf(startID))
+	d.inner.muDAG.RUnlock()
+
+	graph, err := materializeDAG(boxSnapshot(s))
+	if err != nil {
+		return nil, err
+	}
+	return &FlowPlan{graph: graph, startID: startID}, nil
+}
+
+// StartID returns the ID of the vertex the flow starts at.
+func (p *FlowPlan) StartID() string {
+	return p.startID
+}
+
+// Order returns the number of vertices in the plan.
+func (p *FlowPlan) Order() int {
+	return p.graph.GetOrder()
+}
+
+// Run executes the given callback for the start vertex and each of its
+// descendants like DescendantsFlow, and returns the results of the leaves.
+// The callback is passed the frozen copy instead of the original DAG and
+// must not modify it. A plan can be run any number of times, also
+// concurrently.
+func (p *FlowPlan) Run(inputs []FlowResult, callback FlowCallback) ([]FlowResult, error) {
+	return p.graph.DescendantsFlow(p.startID, inputs, callback)
+}
+
+// ExportJSON returns the plan as JSON document for external runners. See
+// GenericDAG.ExportPlanJSON for details.
+func (p *FlowPlan) ExportJSON() ([]byte, error) {
+	return p.graph.ExportPlanJSON(p.startID)
+}
+
+// ExportedPlan is the JSON document written by ExportPlanJSON. It allows
+// runners not written in Go to execute a flow without scheduling it
+// themselves: all steps of a wave may run concurrently once all steps of the
+// previous waves are done.
+type ExportedPlan struct {
+	StartID string           `json:"start"`
+	Waves   [][]ExportedStep `json:"waves"`
+}
+
+// ExportedStep is a vertex to be run as part of an ExportedPlan, along with
+// the ids of its parents within the plan, whose results it takes as input.
+type ExportedStep struct {
+	ID      string      `json:"id"`
+	Value   interface{} `json:"value"`
+	Parents []string    `json:"parents"`
+}
+
+// ExportPlanJSON returns the vertex with startID and all its descendants as
+// JSON encoded ExportedPlan. Each vertex is placed in the wave after the last
+// of its parents, i.e. the first wave holds the start vertex only. The
+// vertices of a wave are sorted by id or in insertion order if the GenericDAG
+// preserves it. Values are encoded like by MarshalJSON. ExportPlanJSON
+// returns an error if startID is empty or unknown, or if a value can't be
+// encoded.
+func (d *GenericDAG[T]) ExportPlanJSON(startID string) ([]byte, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(startID); err != nil {
+		return nil, err
+	}
+	start := d.keyOf(startID)
+	plan := copyMap(d.getDescendants(start))
+	plan[start] = struct{}{}
+
+	// assign the waves in topological order by counting down the number of
+	// parents within the plan, which haven't been assigned a wave yet
+	parentsOf := make(map[vertexHandle]map[vertexHandle]struct{}, len(plan))
+	pending := make(map[vertexHandle]int, len(plan))
+	for h := range plan {
+		parents := make(map[vertexHandle]struct{}, len(d.inboundEdge[h]))
+		for parent := range d.inboundEdge[h] {
+			if _, inPlan := plan[parent]; inPlan {
+				parents[parent] = struct{}{}
+			}
+		}
+		parentsOf[h] = parents
+		pending[h] = len(parents)
+	}
+	waveOf := map[vertexHandle]int{start: 0}
+	var waves []map[vertexHandle]struct{}
+	queue := []vertexHandle{start}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		wave := waveOf[h]
+		if wave == len(waves) {
+			waves = append(waves, make(map[vertexHandle]struct{}))
+		}
+		waves[wave][h] = struct{}{}
+		for child := range d.outboundEdge[h] {
+			if waveOf[child] < wave+1 {
+				waveOf[child] = wave + 1
+			}
+			if pending[child]--; pending[child] == 0 {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	exported := ExportedPlan{StartID: startID, Waves: make([][]ExportedStep, len(waves))}
+	for i, wave := range waves {
+		exported.Waves[i] = make([]ExportedStep, 0, len(wave))
+		for _, id := range d.orderedRelativeIDs(wave) {
+			h := d.keyOf(id)
+			value, err := encodeValue(d.options.Codecs, d.values[h])
+			if err != nil {
+				return nil, err
+			}
+			exported.Waves[i] = append(exported.Waves[i], ExportedStep{
+				ID:      id,
+				Value:   value,
+				Parents: d.orderedRelativeIDs(parentsOf[h]),
+			})
+		}
+	}
+	return json.Marshal(exported)
+}
+
+// ExportPlanJSON returns the vertex with startID and all its descendants as
+// JSON encoded ExportedPlan. See GenericDAG.ExportPlanJSON for details.
+func (d *TypedDAG[T]) ExportPlanJSON(startID string) ([]byte, error) {
+	return d.inner.ExportPlanJSON(startID)
+}
@@ -0,0 +1,166 @@
+package dag
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestFlowPlan(t *testing.T) {
+	d := generateDiamondDAG()
+
+	plan, err := d.PlanFlow("A")
+	if err != nil {
+		t.Fatalf("PlanFlow() unexpected error: %v", err)
+	}
+	if plan.StartID() != "A" || plan.Order() != 4 {
+		t.Errorf("StartID() = %s, Order() = %d, want A, 4", plan.StartID(), plan.Order())
+	}
+
+	// modify the DAG while the plan runs
+	var mu sync.Mutex
+	var visited []string
+	results, err := plan.Run(nil, func(g *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		if id == "A" {
+			_ = d.DeleteVertex("D")
+			_ = d.AddVertexByID("E", TestVertex{VertexID: "E"})
+			_ = d.AddEdge("B", "E")
+		}
+		mu.Lock()
+		visited = append(visited, id)
+		mu.Unlock()
+		return len(parentResults), nil
+	})
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "D" || results[0].Result.(int) != 2 {
+		t.Errorf("Run() = %+v, want a single result of D with 2 parents", results)
+	}
+	sort.Strings(visited)
+	if len(visited) != 4 || visited[3] != "D" {
+		t.Errorf("Run() visited %v, want [A B C D]", visited)
+	}
+
+	// the DAG has been modified, but not the plan
+	if _, err := d.GetVertex("D"); err == nil {
+		t.Error("DeleteVertex(D) had no effect on the DAG")
+	}
+	if plan.Order() != 4 {
+		t.Errorf("Order() = %d after modifying the DAG, want 4", plan.Order())
+	}
+
+	if _, err := d.PlanFlow("unknown"); err == nil {
+		t.Error("PlanFlow() with unknown id expected error")
+	}
+}
+
+func TestTypedDAGFlowPlan(t *testing.T) {
+	d := New[int]()
+	d.MustAddVertexByID("a", 1)
+	d.MustAddVertexByID("b", 2)
+	d.MustAddVertexByID("c", 3)
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("c", "a")
+
+	plan, err := d.PlanFlow("a")
+	if err != nil {
+		t.Fatalf("PlanFlow() unexpected error: %v", err)
+	}
+	if plan.Order() != 2 || plan.StartID() != "a" {
+		t.Errorf("Order(), StartID() = %d, %q, want 2, a", plan.Order(), plan.StartID())
+	}
+	if _, err := d.PlanFlow("unknown"); err == nil {
+		t.Error("PlanFlow() with unknown id expected error")
+	}
+	results, err := plan.Run(nil, func(g *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		v, err := g.GetVertex(id)
+		if err != nil {
+			return nil, err
+		}
+		sum := v.(int)
+		for _, r := range parentResults {
+			sum += r.Result.(int)
+		}
+		return sum, nil
+	})
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Result.(int) != 3 {
+		t.Errorf("Run() = %+v, want a single result 3", results)
+	}
+}
+
+func TestExportPlanJSON(t *testing.T) {
+	/*     x
+	 *     |
+	 *     a
+	 *    / \
+	 *   b   c
+	 *   |\  |
+	 *   | d |
+	 *    \|/
+	 *     e
+	 */
+	d := New[int]()
+	for i, id := range []string{"x", "a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, i)
+	}
+	for _, e := range [][2]string{{"x", "a"}, {"a", "b"}, {"a", "c"}, {"b", "d"}, {"b", "e"}, {"c", "e"}, {"d", "e"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	data, err := d.ExportPlanJSON("a")
+	if err != nil {
+		t.Fatalf("ExportPlanJSON() unexpected error: %v", err)
+	}
+	want := `{"start":"a","waves":[` +
+		`[{"id":"a","value":1,"parents":[]}],` +
+		`[{"id":"b","value":2,"parents":["a"]},{"id":"c","value":3,"parents":["a"]}],` +
+		`[{"id":"d","value":4,"parents":["b"]}],` +
+		`[{"id":"e","value":5,"parents":["b","c","d"]}]]}`
+	if string(data) != want {
+		t.Errorf("ExportPlanJSON() =\n%s\nwant\n%s", data, want)
+	}
+
+	var plan ExportedPlan
+	if err := json.Unmarshal(data, &plan); err != nil || len(plan.Waves) != 4 {
+		t.Errorf("ExportPlanJSON() isn't a valid ExportedPlan: %v", err)
+	}
+
+	flowPlan, _ := d.PlanFlow("d")
+	if data, err := flowPlan.ExportJSON(); err != nil || string(data) != `{"start":"d","waves":[[{"id":"d","value":4,"parents":[]}],[{"id":"e","value":5,"parents":["d"]}]]}` {
+		t.Errorf("ExportJSON() = %s, %v", data, err)
+	}
+	if _, err := d.ExportPlanJSON("z"); err == nil {
+		t.Error("ExportPlanJSON() with an unknown id should fail")
+	}
+}
+
+func TestTypedDAGFlowPlanNilValues(t *testing.T) {
+	d := New[*int](WithDuplicateValues())
+	d.MustAddVertexByID("a", nil)
+	d.MustAddVertexByID("b", nil)
+	d.MustAddEdge("a", "b")
+
+	// nil values are copied as they are, but can't be added to the copies
+	for _, g := range []*DAG{d.ToLegacyDAG(), mustPlan(t, d, "a").graph} {
+		if g.GetOrder() != 2 || g.GetSize() != 1 {
+			t.Errorf("order, size = %d, %d, want 2, 1", g.GetOrder(), g.GetSize())
+		}
+		if err := g.AddVertexByID("c", nil); err == nil {
+			t.Error("AddVertexByID(c, nil) expected error")
+		}
+	}
+}
+
+func mustPlan[T any](t *testing.T, d *TypedDAG[T], startID string) *FlowPlan {
+	t.Helper()
+	plan, err := d.PlanFlow(startID)
+	if err != nil {
+		t.Fatalf("PlanFlow() unexpected error: %v", err)
+	}
+	return plan
+}
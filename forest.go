@@ -0,0 +1,92 @@
+package dag
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ParentChooser selects the parent that a vertex keeps in a spanning forest.
+// It is called with the id of a vertex and the ids of all its parents in
+// sorted order, and must return one of these parents.
+type ParentChooser func(id string, parentIDs []string) string
+
+// GetSpanningForest returns a copy of the GenericDAG that keeps exactly one
+// inbound edge per non-root vertex, i.e. every connected part of the result
+// is a tree rooted at a root of the GenericDAG. Among multiple parents, the
+// one with the smallest id is kept, so the result is deterministic. Use
+// GetSpanningForestBy to choose parents differently.
+func (d *GenericDAG[T]) GetSpanningForest() (*GenericDAG[T], error) {
+	return d.GetSpanningForestBy(func(_ string, parentIDs []string) string {
+		return parentIDs[0]
+	})
+}
+
+// GetSpanningForestBy returns a copy of the GenericDAG that keeps exactly one
+// inbound edge per non-root vertex. For vertices with more than one parent,
+// choose selects the parent to keep. GetSpanningForestBy returns an error if
+// choose returns an id that is not a parent of the vertex.
+func (d *GenericDAG[T]) GetSpanningForestBy(choose ParentChooser) (*GenericDAG[T], error) {
+	d.muDAG.RLock()
+	s := d.snapshot()
+	d.muDAG.RUnlock()
+
+	parents := make(map[string][]string)
+	for _, e := range s.edges {
+		parents[e[1]] = append(parents[e[1]], e[0])
+	}
+	ids := make([]string, 0, len(parents))
+	for id := range parents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	s.edges = s.edges[:0]
+	for _, id := range ids {
+		parentIDs := parents[id]
+		parent := parentIDs[0]
+		if len(parentIDs) > 1 {
+			sort.Strings(parentIDs)
+			parent = choose(id, parentIDs)
+			if i := sort.SearchStrings(parentIDs, parent); i == len(parentIDs) || parentIDs[i] != parent {
+				return nil, fmt.Errorf("'%s' is not a parent of '%s'", parent, id)
+			}
+		}
+		s.edges = append(s.edges, [2]string{parent, id})
+	}
+
+	// keep the values of the kept edges only
+	kept := make(map[[2]string]struct{}, len(s.edges))
+	for _, e := range s.edges {
+		kept[e] = struct{}{}
+	}
+	values := s.edgeValues[:0]
+	for _, v := range s.edgeValues {
+		if _, ok := kept[[2]string{v.SrcID, v.DstID}]; ok {
+			values = append(values, v)
+		}
+	}
+	s.edgeValues = values
+	return materializeGeneric(s)
+}
+
+// GetSpanningForest returns a copy of the TypedDAG that keeps exactly one
+// inbound edge per non-root vertex. See GenericDAG.GetSpanningForest for
+// details.
+func (d *TypedDAG[T]) GetSpanningForest() (*TypedDAG[T], error) {
+	inner, err := d.inner.GetSpanningForest()
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDAG[T]{inner: inner}, nil
+}
+
+// GetSpanningForestBy returns a copy of the TypedDAG that keeps exactly one
+// inbound edge per non-root vertex, chosen by choose. See
+// GenericDAG.GetSpanningForestBy for details.
+func (d *TypedDAG[T]) GetSpanningForestBy(choose ParentChooser) (*TypedDAG[T], error) {
+	inner, err := d.inner.GetSpanningForestBy(choose)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDAG[T]{inner: inner}, nil
+}
@@ -0,0 +1,77 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetSpanningForest(t *testing.T) {
+	d := NewGenericDAG[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e", "x"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("b", "c")
+	d.MustAddEdge("c", "d")
+	d.MustAddEdge("b", "d")
+	d.MustAddEdge("a", "e")
+
+	f, err := d.GetSpanningForest()
+	if err != nil {
+		t.Fatalf("GetSpanningForest failed: %v", err)
+	}
+	want := []GenericEdge{{"a", "c"}, {"a", "e"}, {"b", "d"}}
+	if got := sortedEdges(f.GetEdges()); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSpanningForest() edges = %v, want %v", got, want)
+	}
+	if f.GetOrder() != d.GetOrder() {
+		t.Errorf("forest has %d vertices, want %d", f.GetOrder(), d.GetOrder())
+	}
+	if d.GetSize() != 5 {
+		t.Errorf("GetSpanningForest modified the original")
+	}
+
+	// prefer the parent with the largest id
+	f, err = d.GetSpanningForestBy(func(_ string, parentIDs []string) string {
+		return parentIDs[len(parentIDs)-1]
+	})
+	if err != nil {
+		t.Fatalf("GetSpanningForestBy failed: %v", err)
+	}
+	want = []GenericEdge{{"a", "e"}, {"b", "c"}, {"c", "d"}}
+	if got := sortedEdges(f.GetEdges()); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSpanningForestBy() edges = %v, want %v", got, want)
+	}
+
+	if _, err := d.GetSpanningForestBy(func(string, []string) string { return "x" }); err == nil {
+		t.Errorf("expected an error for a chosen vertex that is no parent")
+	}
+}
+
+func TestGetSpanningForest_EdgeValues(t *testing.T) {
+	d := NewGenericDAG[string]()
+	for _, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, id)
+	}
+	if err := d.AddEdgeWithValue("a", "c", "kept"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddEdgeWithValue("b", "c", "pruned"); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := d.GetSpanningForest()
+	if err != nil {
+		t.Fatalf("GetSpanningForest failed: %v", err)
+	}
+	if v, _ := f.GetEdgeValue("a", "c"); v != "kept" {
+		t.Errorf("GetEdgeValue(a, c) = %v, want kept", v)
+	}
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnmarshalGenericJSON[string](data, Options{}); err != nil {
+		t.Errorf("UnmarshalGenericJSON of the forest failed: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package dag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// LabelMode selects how vertices are labeled by Format.
+type LabelMode int
+
+const (
+	// LabelID labels vertices by their id.
+	LabelID LabelMode = iota
+
+	// LabelValue labels vertices by their value.
+	LabelValue
+
+	// LabelIDAndValue labels vertices by their id followed by their value.
+	LabelIDAndValue
+)
+
+// FormatOptions configures the textual representation written by Format.
+// The zero value prints all vertices and edges labeled by id in no
+// particular order.
+type FormatOptions struct {
+	// Label selects whether vertices are labeled by id, value or both.
+	Label LabelMode
+
+	// ValueFormatter turns a vertex value into text. If ValueFormatter is nil,
+	// values are formatted with fmt's %v verb.
+	ValueFormatter func(v interface{}) string
+
+	// Sorted prints vertices and edges ordered by id.
+	Sorted bool
+
+	// MaxVertices limits the number of vertices printed. Only edges between
+	// printed vertices are listed. Zero or a negative value means no limit.
+	MaxVertices int
+}
+
+// Format writes a textual representation of the DAG to w.
+func (d *DAG) Format(w io.Writer, opts FormatOptions) error {
+	return d.format(w, "DAG", opts)
+}
+
+// Format writes a textual representation of the GenericDAG to w.
+func (d *GenericDAG[T]) Format(w io.Writer, opts FormatOptions) error {
+	return d.format(w, "GenericDAG", opts)
+}
+
+func (d *GenericDAG[T]) format(w io.Writer, name string, opts FormatOptions) error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	ids := make([]string, 0, d.ids.len())
+	for id := range d.ids.handles {
+		ids = append(ids, id)
+	}
+	children := func(id string) []string {
+		return relativeIDs(&d.ids, d.outboundEdge[d.keyOf(id)])
+	}
+	value := func(id string) interface{} {
+		return d.value(id)
+	}
+	return formatGraph(w, name, ids, d.getSize(), value, children, opts)
+}
+
+// Format writes a textual representation of the TypedDAG to w.
+func (d *TypedDAG[T]) Format(w io.Writer, opts FormatOptions) error {
+	return d.inner.Format(w, opts)
+}
+
+// String returns a textual representation of the TypedDAG.
+func (d *TypedDAG[T]) String() string {
+	return d.inner.String()
+}
+
+func formatGraph(w io.Writer, name string, ids []string, size int,
+	value func(id string) interface{}, children func(id string) []string, opts FormatOptions) error {
+
+	if opts.Sorted {
+		sort.Strings(ids)
+	}
+	order := len(ids)
+	omitted := 0
+	if opts.MaxVertices > 0 && order > opts.MaxVertices {
+		omitted = order - opts.MaxVertices
+		ids = ids[:opts.MaxVertices]
+	}
+	printed := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		printed[id] = struct{}{}
+	}
+
+	label := func(id string) string {
+		var v string
+		if opts.Label != LabelID {
+			if opts.ValueFormatter != nil {
+				v = opts.ValueFormatter(value(id))
+			} else {
+				v = fmt.Sprintf("%v", value(id))
+			}
+		}
+		switch opts.Label {
+		case LabelValue:
+			return v
+		case LabelIDAndValue:
+			return id + " (" + v + ")"
+		default:
+			return id
+		}
+	}
+
+	// bufio.Writer remembers the first write error, so it suffices to check
+	// the final Flush
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "%s Vertices: %d - Edges: %d\n", name, order, size)
+	fmt.Fprintln(b, "Vertices:")
+	for _, id := range ids {
+		fmt.Fprintf(b, "  %s\n", label(id))
+	}
+	if omitted > 0 {
+		fmt.Fprintf(b, "  ... %d more\n", omitted)
+	}
+	fmt.Fprintln(b, "Edges:")
+	for _, id := range ids {
+		childIDs := children(id)
+		if opts.Sorted {
+			sort.Strings(childIDs)
+		}
+		for _, child := range childIDs {
+			if _, ok := printed[child]; ok {
+				fmt.Fprintf(b, "  %s -> %s\n", label(id), label(child))
+			}
+		}
+	}
+	return b.Flush()
+}
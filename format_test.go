@@ -0,0 +1,71 @@
+package dag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	d := NewGenericDAG[int]()
+	for i, id := range []string{"c", "a", "b"} {
+		if err := d.AddVertexByID(id, i); err != nil {
+			t.Fatalf("AddVertexByID failed: %v", err)
+		}
+	}
+	_ = d.AddEdge("a", "b")
+	_ = d.AddEdge("a", "c")
+	_ = d.AddEdge("b", "c")
+
+	tests := []struct {
+		name string
+		opts FormatOptions
+		want string
+	}{
+		{
+			name: "ids",
+			opts: FormatOptions{Sorted: true},
+			want: "GenericDAG Vertices: 3 - Edges: 3\nVertices:\n  a\n  b\n  c\nEdges:\n  a -> b\n  a -> c\n  b -> c\n",
+		},
+		{
+			name: "values",
+			opts: FormatOptions{Sorted: true, Label: LabelValue},
+			want: "GenericDAG Vertices: 3 - Edges: 3\nVertices:\n  1\n  2\n  0\nEdges:\n  1 -> 2\n  1 -> 0\n  2 -> 0\n",
+		},
+		{
+			name: "ids and formatted values",
+			opts: FormatOptions{
+				Sorted:         true,
+				Label:          LabelIDAndValue,
+				ValueFormatter: func(v interface{}) string { return strings.Repeat("*", v.(int)) },
+				MaxVertices:    2,
+			},
+			want: "GenericDAG Vertices: 3 - Edges: 3\nVertices:\n  a (*)\n  b (**)\n  ... 1 more\nEdges:\n  a (*) -> b (**)\n",
+		},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := d.Format(&b, tt.opts); err != nil {
+			t.Fatalf("%s: Format failed: %v", tt.name, err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("%s: Format() = %q, want %q", tt.name, b.String(), tt.want)
+		}
+	}
+}
+
+func TestStringPrintsIDs(t *testing.T) {
+	d := NewDAG()
+	_ = d.AddVertexByID("first", 1)
+	_ = d.AddVertexByID("second", 2)
+	_ = d.AddEdge("first", "second")
+	s := d.String()
+	if !strings.Contains(s, "  first -> second\n") {
+		t.Errorf("String() = %q, want edges labeled by id", s)
+	}
+
+	typed := New[string]()
+	_ = typed.AddVertexByID("x", "value")
+	if s := typed.String(); !strings.Contains(s, "  x\n") {
+		t.Errorf("String() = %q, want vertices labeled by id", s)
+	}
+}
@@ -0,0 +1,95 @@
+package dag
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+// GenerateRandomDAG creates a random GenericDAG with the given number of
+// vertices and edges, e.g. for load testing and simulation. The value of the
+// i-th vertex is valueFactory(i) and its id is "node_<i>".
+//
+// The graph depends only on seed, vertices and edges: identical arguments
+// yield identical graphs on all platforms. GenerateRandomDAG returns an error,
+// if vertices or edges are negative, if more edges are requested than an
+// acyclic graph with the given number of vertices can hold, or if the values
+// returned by valueFactory are not accepted by the DAG (e.g. duplicates).
+func GenerateRandomDAG[T any](seed int64, vertices, edges int, valueFactory func(i int) T, opts ...Option) (*GenericDAG[T], error) {
+	if vertices < 0 || edges < 0 {
+		return nil, fmt.Errorf("vertices (%d) and edges (%d) must be >= 0", vertices, edges)
+	}
+	maxEdges := vertices * (vertices - 1) / 2
+	if edges > maxEdges {
+		return nil, fmt.Errorf("a DAG with %d vertices holds at most %d edges, got %d", vertices, maxEdges, edges)
+	}
+
+	// math/rand's sources are specified to produce the same sequence for the
+	// same seed on every platform
+	r := rand.New(rand.NewSource(seed))
+
+	d := NewGenericDAG[T](append([]Option{WithCapacity(vertices, edges)}, opts...)...)
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	ids := make([]string, vertices)
+	for i := 0; i < vertices; i++ {
+		ids[i] = "node_" + strconv.Itoa(i)
+		if err := d.addVertexByID(ids[i], valueFactory(i)); err != nil {
+			return nil, err
+		}
+	}
+
+	// edges only point forward in a random topological order, which
+	// guarantees the graph to be acyclic without any loop detection
+	order := r.Perm(vertices)
+	keys := make([]vertexHandle, vertices)
+	for pos, i := range order {
+		keys[pos] = d.keyOf(ids[i])
+	}
+
+	// all ids are of the global namespace, so edges never cross namespaces
+	// and saneNamespaces can be skipped
+	addEdge := func(src, dst int) bool {
+		srcHash, dstHash := keys[src], keys[dst]
+		if d.isEdge(srcHash, dstHash) {
+			return false
+		}
+		d.linkEdge(srcHash, dstHash)
+		d.recordEdgeOp(OpAddEdge, d.ids.id(srcHash), d.ids.id(dstHash))
+		d.mutated()
+		return true
+	}
+
+	if edges > maxEdges/2 {
+		// dense graphs: shuffle all possible edges and take the first ones
+		pairs := make([][2]int, 0, maxEdges)
+		for src := 0; src < vertices; src++ {
+			for dst := src + 1; dst < vertices; dst++ {
+				pairs = append(pairs, [2]int{src, dst})
+			}
+		}
+		r.Shuffle(len(pairs), func(i, j int) {
+			pairs[i], pairs[j] = pairs[j], pairs[i]
+		})
+		for _, p := range pairs[:edges] {
+			addEdge(p[0], p[1])
+		}
+		return d, nil
+	}
+
+	// sparse graphs: draw random pairs until enough distinct edges exist
+	for added := 0; added < edges; {
+		a, b := r.Intn(vertices), r.Intn(vertices)
+		if a == b {
+			continue
+		}
+		if a > b {
+			a, b = b, a
+		}
+		if addEdge(a, b) {
+			added++
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,62 @@
+package dag
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func sortedEdges(el EdgeList) []GenericEdge {
+	edges := append([]GenericEdge(nil), el.Edges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].SrcID != edges[j].SrcID {
+			return edges[i].SrcID < edges[j].SrcID
+		}
+		return edges[i].DstID < edges[j].DstID
+	})
+	return edges
+}
+
+func TestGenerateRandomDAG(t *testing.T) {
+	value := func(i int) int { return i }
+	for _, tt := range []struct{ vertices, edges int }{{0, 0}, {10, 5}, {50, 200}, {20, 190}} {
+		d1, err := GenerateRandomDAG(42, tt.vertices, tt.edges, value)
+		if err != nil {
+			t.Fatalf("GenerateRandomDAG(%d, %d) failed: %v", tt.vertices, tt.edges, err)
+		}
+		if d1.GetOrder() != tt.vertices || d1.GetSize() != tt.edges {
+			t.Errorf("got %d vertices and %d edges, want %d and %d", d1.GetOrder(), d1.GetSize(), tt.vertices, tt.edges)
+		}
+		d2, _ := GenerateRandomDAG(42, tt.vertices, tt.edges, value)
+		if !reflect.DeepEqual(sortedEdges(d1.GetEdges()), sortedEdges(d2.GetEdges())) {
+			t.Errorf("same seed should generate the same graph (%d, %d)", tt.vertices, tt.edges)
+		}
+	}
+
+	// the graph is a valid DAG: a topological walk visits every vertex
+	d, _ := GenerateRandomDAG(7, 30, 100, value)
+	count := 0
+	d.GenericOrderedWalk(visitorFunc[int](func(int, string) { count++ }))
+	if count != 30 {
+		t.Errorf("ordered walk visited %d vertices, want 30", count)
+	}
+
+	a, _ := GenerateRandomDAG(1, 30, 60, value)
+	b, _ := GenerateRandomDAG(2, 30, 60, value)
+	if reflect.DeepEqual(sortedEdges(a.GetEdges()), sortedEdges(b.GetEdges())) {
+		t.Error("different seeds should generate different graphs")
+	}
+
+	if _, err := GenerateRandomDAG(1, 3, 4, value); err == nil {
+		t.Error("GenerateRandomDAG should reject more edges than possible")
+	}
+	if _, err := GenerateRandomDAG(1, 3, 1, func(int) int { return 0 }); err == nil {
+		t.Error("GenerateRandomDAG should report duplicate values")
+	}
+}
+
+type visitorFunc[T any] func(value T, id string)
+
+func (f visitorFunc[T]) Visit(value T, id string) {
+	f(value, id)
+}
@@ -2,9 +2,9 @@ package dag
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
-
-	"github.com/google/uuid"
 )
 
 // GenericDAG implements the data structure of the DAG with typed vertex values.
@@ -27,29 +27,66 @@ import (
 //	personDAG.AddVertex(Person{Name: "Alice", Age: 30})
 type GenericDAG[T any] struct {
 	muDAG            sync.RWMutex
-	vertices         map[interface{}]string
-	vertexValues     map[string]T
-	inboundEdge      map[interface{}]map[interface{}]struct{}
-	outboundEdge     map[interface{}]map[interface{}]struct{}
+	ids              idTable
+	values           []T
+	hashes           map[interface{}]vertexHandle
+	collisions       map[interface{}][]vertexHandle
+	inboundEdge      map[vertexHandle]map[vertexHandle]struct{}
+	outboundEdge     map[vertexHandle]map[vertexHandle]struct{}
 	muCache          sync.RWMutex
 	verticesLocked   *dMutex
-	ancestorsCache   map[interface{}]map[interface{}]struct{}
-	descendantsCache map[interface{}]map[interface{}]struct{}
+	vertexLocks      *dMutex
+	ancestorsCache   map[vertexHandle]map[vertexHandle]struct{}
+	descendantsCache map[vertexHandle]map[vertexHandle]struct{}
+	ancestorsStats   cacheCounters
+	descendantsStats cacheCounters
+	childLists       map[vertexHandle][]vertexHandle
+	merkleHashes     map[vertexHandle][32]byte
+	merkleMutations  uint64
+	roots            map[vertexHandle]struct{}
+	leaves           map[vertexHandle]struct{}
+	rootsVersion     uint64
+	leavesVersion    uint64
+	dirtyMarks       map[vertexHandle]uint64
+	cleanMarks       map[vertexHandle]uint64
+	marks            uint64
+	statuses         *statusTable
 	options          Options
+	mutations        uint64
+	mutationSignals  []chan struct{}
+	recorders        []*OpRecorder
+	appliedSeq       uint64
+	tombstones       map[string]*tombstone[T]
+	edgeValues       map[[2]vertexHandle]interface{}
 }
 
-// NewGenericDAG creates / initializes a new generic DAG.
-func NewGenericDAG[T any]() *GenericDAG[T] {
-	return &GenericDAG[T]{
-		vertices:         make(map[interface{}]string),
-		vertexValues:     make(map[string]T),
-		inboundEdge:      make(map[interface{}]map[interface{}]struct{}),
-		outboundEdge:     make(map[interface{}]map[interface{}]struct{}),
+// NewGenericDAG creates / initializes a new generic DAG. The given options
+// configure the DAG for its whole lifetime, e.g.:
+//
+//	d := NewGenericDAG[string](WithIDGenerator(gen), WithCapacity(1000, 2000))
+func NewGenericDAG[T any](opts ...Option) *GenericDAG[T] {
+	options := buildOptions(opts)
+	d := &GenericDAG[T]{
+		ids:              newIDTable(options.VertexCapacity, options.PreserveInsertionOrder),
+		values:           make([]T, 0, options.VertexCapacity),
+		hashes:           make(map[interface{}]vertexHandle, options.VertexCapacity),
+		inboundEdge:      make(map[vertexHandle]map[vertexHandle]struct{}, options.adjacencyCapacity()),
+		outboundEdge:     make(map[vertexHandle]map[vertexHandle]struct{}, options.adjacencyCapacity()),
 		verticesLocked:   newDMutex(),
-		ancestorsCache:   make(map[interface{}]map[interface{}]struct{}),
-		descendantsCache: make(map[interface{}]map[interface{}]struct{}),
-		options:          defaultOptions(),
+		vertexLocks:      newDMutex(),
+		ancestorsCache:   make(map[vertexHandle]map[vertexHandle]struct{}),
+		descendantsCache: make(map[vertexHandle]map[vertexHandle]struct{}),
+		roots:            make(map[vertexHandle]struct{}),
+		leaves:           make(map[vertexHandle]struct{}),
+		options:          options,
+	}
+	if options.SortedChildren {
+		d.childLists = make(map[vertexHandle][]vertexHandle, options.adjacencyCapacity())
+	}
+	if options.TrackStatus {
+		d.statuses = &statusTable{}
 	}
+	return d
 }
 
 // AddVertex adds the vertex v to the DAG.
@@ -61,12 +98,16 @@ func (d *GenericDAG[T]) AddVertex(v T) (string, error) {
 }
 
 func (d *GenericDAG[T]) addVertex(v T) (string, error) {
+	// reject before asking a nil IDInterface for its id
+	if d.options.rejects(v) {
+		return "", VertexNilError{}
+	}
 	var id string
 	// Use interface{} for IDInterface check
 	if i, ok := any(v).(IDInterface); ok {
 		id = i.ID()
 	} else {
-		id = uuid.New().String()
+		id = d.options.IDGenerator()
 	}
 
 	err := d.addVertexByID(id, v)
@@ -74,29 +115,101 @@ func (d *GenericDAG[T]) addVertex(v T) (string, error) {
 }
 
 // AddVertexByID adds the vertex v and the specified id to the DAG.
-// AddVertexByID returns an error if v is already part of the graph,
-// or the specified id is already part of the graph.
+// AddVertexByID returns an error if the id is empty, if v is already part of
+// the graph, or the specified id is already part of the graph, unless
+// Options.ExistingIDs says otherwise.
 func (d *GenericDAG[T]) AddVertexByID(id string, v T) error {
 	d.muDAG.Lock()
 	defer d.muDAG.Unlock()
 	return d.addVertexByID(id, v)
 }
 
-func (d *GenericDAG[T]) addVertexByID(id string, v T) error {
-	vHash := d.hashVertex(v)
+// AddVerticesBatch adds the vertices by their ids under a single acquisition
+// of the write lock, e.g. to build large graphs. Vertices are added in sorted
+// order of their ids. A vertex that can't be added (see AddVertexByID) is
+// skipped; all others are added. AddVerticesBatch returns a MultiError of the
+// errors of all skipped vertices, or nil.
+func (d *GenericDAG[T]) AddVerticesBatch(vertices map[string]T) error {
+	ids := make([]string, 0, len(vertices))
+	for id := range vertices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	var errs MultiError
+	for _, id := range ids {
+		errs.add(d.addVertexByID(id, vertices[id]))
+	}
+	if len(errs.errs) > 0 {
+		return errs
+	}
+	return nil
+}
 
+func (d *GenericDAG[T]) addVertexByID(id string, v T) error {
+	if id == "" {
+		return IDEmptyError{}
+	}
+	if d.options.rejects(v) {
+		return VertexNilError{}
+	}
+	if d.options.ExistingIDs != ExistingIDFail {
+		if h, exists := d.ids.handle(id); exists {
+			return d.resolveExistingID(id, h, v)
+		}
+	}
 	// Check for duplicate vertex
-	if _, exists := d.vertices[vHash]; exists {
-		return VertexDuplicateError{v}
+	vHash, indexed := d.hashVertex(v)
+	if indexed {
+		if _, exists := d.findValue(v, vHash); exists {
+			return VertexDuplicateError{v}
+		}
 	}
 
-	// Check for duplicate ID
-	if _, exists := d.vertexValues[id]; exists {
+	// Check for duplicate ID, including the ids of soft deleted vertices
+	if d.idTaken(id) {
 		return IDDuplicateError{id}
 	}
 
-	d.vertices[vHash] = id
-	d.vertexValues[id] = v
+	h := d.ids.add(id)
+	if int(h) == len(d.values) {
+		d.values = append(d.values, v)
+	} else {
+		d.values[h] = v
+	}
+	if indexed {
+		d.indexValue(vHash, h)
+	}
+	d.setRoot(h, true)
+	d.setLeaf(h, true)
+	d.recordVertexOp(OpAddVertex, id, &v)
+	d.mutated()
+	return nil
+}
+
+// resolveExistingID handles adding the value v with the id of the existing
+// vertex with handle h according to Options.ExistingIDs.
+func (d *GenericDAG[T]) resolveExistingID(id string, h vertexHandle, v T) error {
+	if d.options.ExistingIDs == ExistingIDIgnore {
+		return nil
+	}
+	vHash, indexed := d.hashVertex(v)
+	if indexed {
+		if other, exists := d.findValue(v, vHash); exists && other != h {
+			return VertexDuplicateError{v}
+		}
+	}
+	if oldHash, oldIndexed := d.hashVertex(d.values[h]); oldIndexed {
+		d.unindexValue(oldHash, h)
+	}
+	d.values[h] = v
+	if indexed {
+		d.indexValue(vHash, h)
+	}
+	d.recordVertexOp(OpAddVertex, id, &v)
+	d.mutated()
 	return nil
 }
 
@@ -111,76 +224,192 @@ func (d *GenericDAG[T]) GetVertex(id string) (T, error) {
 		return zero, IDEmptyError{}
 	}
 
-	v, exists := d.vertexValues[id]
+	h, exists := d.ids.handle(id)
 	if !exists {
 		var zero T
 		return zero, IDUnknownError{id}
 	}
-	return v, nil
+	return d.values[h], nil
+}
+
+// GetVerticesByIDs returns the values of the vertices with the given ids by
+// their id, and the ids that are empty or unknown, in the order given. All
+// vertices are looked up under a single lock, which makes GetVerticesByIDs
+// much faster than calling GetVertex for each id.
+func (d *GenericDAG[T]) GetVerticesByIDs(ids []string) (map[string]T, []string) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	found := make(map[string]T, len(ids))
+	var missing []string
+	for _, id := range ids {
+		if h, exists := d.ids.handle(id); exists {
+			found[id] = d.values[h]
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return found, missing
+}
+
+// Rehash recomputes the hash of the value of the vertex with id, which is
+// needed after the data the value points to has been modified, e.g. through a
+// pointer, so that detecting duplicate values stays correct. Rehash returns an
+// error if id is empty or unknown, or if the value now equals the value of
+// another vertex, in which case the old hash is kept. Rehash is a no-op for
+// values which aren't hashed, e.g. for graphs allowing duplicate values.
+// Either way, Rehash invalidates the Merkle hashes, see GetMerkleHash.
+func (d *GenericDAG[T]) Rehash(id string) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	if err := d.saneID(id); err != nil {
+		return err
+	}
+	h := d.keyOf(id)
+	v := d.values[h]
+	d.merkleHashes = nil
+	vHash, indexed := d.hashVertex(v)
+	if !indexed {
+		return nil
+	}
+
+	// the old hash can't be computed anymore, thus look it up by handle
+	oldHash, wasIndexed := d.indexedHash(h)
+	if wasIndexed {
+		d.unindexValue(oldHash, h)
+	}
+	if _, exists := d.findValue(v, vHash); exists {
+		if wasIndexed {
+			d.indexValue(oldHash, h)
+		}
+		return VertexDuplicateError{v}
+	}
+	d.indexValue(vHash, h)
+	return nil
 }
 
 // DeleteVertex deletes the vertex with the given id.
 // DeleteVertex also deletes all attached edges (inbound and outbound).
-// DeleteVertex returns an error if id is empty or unknown.
+// DeleteVertex returns an error if id is empty or unknown. Deleting a soft
+// deleted vertex drops it for good. Either way, soft deleted vertices forget
+// their edges to the deleted vertex, so they aren't restored to a new vertex
+// with the same id.
 func (d *GenericDAG[T]) DeleteVertex(id string) error {
 	d.muDAG.Lock()
 	defer d.muDAG.Unlock()
+	if _, exists := d.tombstones[id]; exists {
+		delete(d.tombstones, id)
+		d.forgetTombstoneEdges(id)
+		return nil
+	}
+	if err := d.deleteVertex(id); err != nil {
+		return err
+	}
+	d.forgetTombstoneEdges(id)
+	return nil
+}
 
+func (d *GenericDAG[T]) deleteVertex(id string) error {
 	if err := d.saneID(id); err != nil {
 		return err
 	}
 
-	v := d.vertexValues[id]
-	vHash := d.hashVertex(v)
+	vHash := d.keyOf(id)
 
-	// get descendants and ancestors as they are now
-	descendants := copyMap(d.getDescendants(vHash))
-	ancestors := copyMap(d.getAncestors(vHash))
+	// get descendants and ancestors as they are now (only needed to maintain
+	// the caches)
+	var descendants, ancestors map[vertexHandle]struct{}
+	if !d.options.DisableCache {
+		descendants = copyMap(d.getDescendants(vHash))
+		ancestors = copyMap(d.getAncestors(vHash))
+	}
 
-	// delete v in outbound edges of parents
-	if _, exists := d.inboundEdge[vHash]; exists {
-		for parent := range d.inboundEdge[vHash] {
-			delete(d.outboundEdge[parent], vHash)
+	// delete v in outbound edges of parents, which may become leaves
+	for parent := range d.inboundEdge[vHash] {
+		delete(d.outboundEdge[parent], vHash)
+		delete(d.edgeValues, [2]vertexHandle{parent, vHash})
+		d.removeChild(parent, vHash)
+		if len(d.outboundEdge[parent]) == 0 {
+			d.setLeaf(parent, true)
 		}
 	}
 
-	// delete v in inbound edges of children
-	if _, exists := d.outboundEdge[vHash]; exists {
-		for child := range d.outboundEdge[vHash] {
-			delete(d.inboundEdge[child], vHash)
+	// delete v in inbound edges of children, which may become roots
+	for child := range d.outboundEdge[vHash] {
+		delete(d.inboundEdge[child], vHash)
+		delete(d.edgeValues, [2]vertexHandle{vHash, child})
+		if len(d.inboundEdge[child]) == 0 {
+			d.setRoot(child, true)
 		}
 	}
 
 	// delete in- and outbound of v itself
 	delete(d.inboundEdge, vHash)
 	delete(d.outboundEdge, vHash)
+	if d.childLists != nil {
+		delete(d.childLists, vHash)
+	}
+	delete(d.dirtyMarks, vHash)
+	delete(d.cleanMarks, vHash)
+	if d.statuses != nil {
+		d.statuses.forget(id)
+	}
+	d.setRoot(vHash, false)
+	d.setLeaf(vHash, false)
 
 	// for v and all its descendants delete cached ancestors
 	for descendant := range descendants {
-		delete(d.ancestorsCache, descendant)
+		d.ancestorsStats.evict(d.ancestorsCache, descendant)
 	}
-	delete(d.ancestorsCache, vHash)
+	d.ancestorsStats.evict(d.ancestorsCache, vHash)
 
 	// for v and all its ancestors delete cached descendants
 	for ancestor := range ancestors {
-		delete(d.descendantsCache, ancestor)
+		d.descendantsStats.evict(d.descendantsCache, ancestor)
 	}
-	delete(d.descendantsCache, vHash)
+	d.descendantsStats.evict(d.descendantsCache, vHash)
 
 	// delete v itself
-	delete(d.vertices, vHash)
-	delete(d.vertexValues, id)
+	if hash, indexed := d.hashVertex(d.values[vHash]); indexed {
+		d.unindexValue(hash, vHash)
+	}
+	var zero T
+	d.values[vHash] = zero
+	d.ids.remove(id)
 
+	d.recordVertexOp(OpDeleteVertex, id, nil)
+	d.mutated()
 	return nil
 }
 
 // AddEdge adds an edge between srcID and dstID.
 // AddEdge returns an error if srcID or dstID are empty strings or unknown,
-// if the edge already exists, or if the new edge would create a loop.
+// if the edge already exists, or if the new edge would create a loop. With
+// Options.DisallowCrossNamespaceEdges, AddEdge also returns an error if srcID
+// and dstID are in different namespaces.
 func (d *GenericDAG[T]) AddEdge(srcID, dstID string) error {
 	d.muDAG.Lock()
 	defer d.muDAG.Unlock()
+	return d.addEdge(srcID, dstID)
+}
 
+// EnsureEdge adds an edge between srcID and dstID, unless it already exists.
+// EnsureEdge returns true if the edge has been added, and false if it already
+// existed. Unlike AddEdge, an existing edge is not an error. EnsureEdge returns
+// an error if srcID or dstID are empty strings or unknown, or if the new edge
+// would create a loop.
+func (d *GenericDAG[T]) EnsureEdge(srcID, dstID string) (bool, error) {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	err := d.addEdge(srcID, dstID)
+	if _, ok := err.(EdgeDuplicateError); ok {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (d *GenericDAG[T]) addEdge(srcID, dstID string) error {
 	if err := d.saneID(srcID); err != nil {
 		return err
 	}
@@ -190,11 +419,12 @@ func (d *GenericDAG[T]) AddEdge(srcID, dstID string) error {
 	if srcID == dstID {
 		return SrcDstEqualError{srcID, dstID}
 	}
+	if err := d.saneNamespaces(srcID, dstID); err != nil {
+		return err
+	}
 
-	src := d.vertexValues[srcID]
-	srcHash := d.hashVertex(src)
-	dst := d.vertexValues[dstID]
-	dstHash := d.hashVertex(dst)
+	srcHash := d.keyOf(srcID)
+	dstHash := d.keyOf(dstID)
 
 	// if the edge is already known, there is nothing else to do
 	if d.isEdge(srcHash, dstHash) {
@@ -206,61 +436,133 @@ func (d *GenericDAG[T]) AddEdge(srcID, dstID string) error {
 		return EdgeLoopError{srcID, dstID}
 	}
 
-	// get descendants and ancestors as they are now
-	descendants := copyMap(d.getDescendants(dstHash))
-	ancestors := copyMap(d.getAncestors(srcHash))
-
-	// prepare d.outbound[src], iff needed
-	if _, exists := d.outboundEdge[srcHash]; !exists {
-		d.outboundEdge[srcHash] = make(map[interface{}]struct{})
-	}
-
-	// dst is a child of src
-	d.outboundEdge[srcHash][dstHash] = struct{}{}
-
-	// prepare d.inboundEdge[dst], iff needed
-	if _, exists := d.inboundEdge[dstHash]; !exists {
-		d.inboundEdge[dstHash] = make(map[interface{}]struct{})
+	// get descendants and ancestors as they are now (only needed to maintain
+	// the caches)
+	var descendants, ancestors map[vertexHandle]struct{}
+	if !d.options.DisableCache {
+		descendants = copyMap(d.getDescendants(dstHash))
+		ancestors = copyMap(d.getAncestors(srcHash))
 	}
 
-	// src is a parent of dst
-	d.inboundEdge[dstHash][srcHash] = struct{}{}
+	// dst is a child of src and src is a parent of dst
+	d.linkEdge(srcHash, dstHash)
 
 	// for dst and all its descendants delete cached ancestors
 	for descendant := range descendants {
-		delete(d.ancestorsCache, descendant)
+		d.ancestorsStats.evict(d.ancestorsCache, descendant)
 	}
-	delete(d.ancestorsCache, dstHash)
+	d.ancestorsStats.evict(d.ancestorsCache, dstHash)
 
 	// for src and all its ancestors delete cached descendants
 	for ancestor := range ancestors {
-		delete(d.descendantsCache, ancestor)
+		d.descendantsStats.evict(d.descendantsCache, ancestor)
 	}
-	delete(d.descendantsCache, srcHash)
+	d.descendantsStats.evict(d.descendantsCache, srcHash)
 
+	d.recordEdgeOp(OpAddEdge, srcID, dstID)
+	d.mutated()
 	return nil
 }
 
+// addEdgesBatch adds the n edges returned by edge in a single lock
+// acquisition. This is an internal method used for performance optimization
+// during deserialization.
+// It differs from AddEdge by:
+// 1. Taking the lock once for all edges
+// 2. Skipping cache invalidation (caches are empty during deserialization)
+// 3. Performing loop detection efficiently with BFS search
+// 4. Handling duplicate edges according to Options.DuplicateEdges
+//
+// The returned error is a DuplicateEdgesWarning, if duplicates have been
+// skipped under DuplicateEdgesWarn, in which case all edges have been added.
+func (d *GenericDAG[T]) addEdgesBatch(n int, edge func(i int) (srcID, dstID string)) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	var duplicates []GenericEdge
+
+	// Validate all edges and build adjacency
+	for i := 0; i < n; i++ {
+		srcID, dstID := edge(i)
+
+		// Validate IDs
+		if err := d.saneID(srcID); err != nil {
+			return err
+		}
+		if err := d.saneID(dstID); err != nil {
+			return err
+		}
+		if srcID == dstID {
+			return SrcDstEqualError{srcID, dstID}
+		}
+		if err := d.saneNamespaces(srcID, dstID); err != nil {
+			return err
+		}
+
+		srcHash := d.keyOf(srcID)
+		dstHash := d.keyOf(dstID)
+
+		// Check for duplicate edge
+		if d.isEdge(srcHash, dstHash) {
+			if d.options.DuplicateEdges == DuplicateEdgesFail {
+				return EdgeDuplicateError{srcID, dstID}
+			}
+			duplicates = append(duplicates, GenericEdge{SrcID: srcID, DstID: dstID})
+			continue
+		}
+
+		// Check if adding this edge would create a loop
+		if d.wouldCreateLoop(srcHash, dstHash) {
+			return EdgeLoopError{srcID, dstID}
+		}
+
+		// Build adjacency structure
+		d.linkEdge(srcHash, dstHash)
+	}
+
+	// No need to clear caches during deserialization
+	// Caches are empty and will be built on-demand later
+
+	return d.duplicatesWarning(duplicates)
+}
+
+// duplicatesWarning returns a DuplicateEdgesWarning of the given skipped
+// duplicates under DuplicateEdgesWarn, and nil otherwise.
+func (d *GenericDAG[T]) duplicatesWarning(duplicates []GenericEdge) error {
+	if len(duplicates) == 0 || d.options.DuplicateEdges != DuplicateEdgesWarn {
+		return nil
+	}
+	return DuplicateEdgesWarning{duplicates}
+}
+
 // wouldCreateLoop checks if adding an edge from srcHash to dstHash would create a loop.
-func (d *GenericDAG[T]) wouldCreateLoop(srcHash, dstHash interface{}) bool {
-	// Use a BFS queue and visited map to search from dstHash
-	var fifo []interface{}
-	visited := make(map[interface{}]struct{})
+func (d *GenericDAG[T]) wouldCreateLoop(srcHash, dstHash vertexHandle) bool {
+	// if the descendants of dstHash or the ancestors of srcHash are cached,
+	// a lookup is enough
+	d.muCache.RLock()
+	descendants, dstCached := d.descendantsCache[dstHash]
+	ancestors, srcCached := d.ancestorsCache[srcHash]
+	d.muCache.RUnlock()
+	if dstCached {
+		_, exists := descendants[srcHash]
+		return exists
+	}
+	if srcCached {
+		_, exists := ancestors[dstHash]
+		return exists
+	}
+
+	// Use a BFS queue and visited set to search from dstHash
+	s := getScratch()
+	defer s.release()
 
 	// Start with all children of dstHash
 	for child := range d.outboundEdge[dstHash] {
-		visited[child] = struct{}{}
-		fifo = append(fifo, child)
+		s.visit(child)
 	}
 
 	// BFS traversal
-	for {
-		if len(fifo) == 0 {
-			break
-		}
-		top := fifo[0]
-		fifo = fifo[1:]
-
+	for top, ok := s.next(); ok; top, ok = s.next() {
 		// If we reached srcHash, adding src->dst would create a loop
 		if top == srcHash {
 			return true
@@ -268,10 +570,7 @@ func (d *GenericDAG[T]) wouldCreateLoop(srcHash, dstHash interface{}) bool {
 
 		// Add all unvisited children to the queue
 		for child := range d.outboundEdge[top] {
-			if _, exists := visited[child]; !exists {
-				visited[child] = struct{}{}
-				fifo = append(fifo, child)
-			}
+			s.visit(child)
 		}
 	}
 
@@ -295,12 +594,10 @@ func (d *GenericDAG[T]) IsEdge(srcID, dstID string) (bool, error) {
 		return false, SrcDstEqualError{srcID, dstID}
 	}
 
-	src := d.vertexValues[srcID]
-	dst := d.vertexValues[dstID]
-	return d.isEdge(d.hashVertex(src), d.hashVertex(dst)), nil
+	return d.isEdge(d.keyOf(srcID), d.keyOf(dstID)), nil
 }
 
-func (d *GenericDAG[T]) isEdge(srcHash, dstHash interface{}) bool {
+func (d *GenericDAG[T]) isEdge(srcHash, dstHash vertexHandle) bool {
 	if _, exists := d.outboundEdge[srcHash]; !exists {
 		return false
 	}
@@ -322,7 +619,10 @@ func (d *GenericDAG[T]) isEdge(srcHash, dstHash interface{}) bool {
 func (d *GenericDAG[T]) DeleteEdge(srcID, dstID string) error {
 	d.muDAG.Lock()
 	defer d.muDAG.Unlock()
+	return d.deleteEdge(srcID, dstID)
+}
 
+func (d *GenericDAG[T]) deleteEdge(srcID, dstID string) error {
 	if err := d.saneID(srcID); err != nil {
 		return err
 	}
@@ -333,35 +633,38 @@ func (d *GenericDAG[T]) DeleteEdge(srcID, dstID string) error {
 		return SrcDstEqualError{srcID, dstID}
 	}
 
-	src := d.vertexValues[srcID]
-	srcHash := d.hashVertex(src)
-	dst := d.vertexValues[dstID]
-	dstHash := d.hashVertex(dst)
+	srcHash := d.keyOf(srcID)
+	dstHash := d.keyOf(dstID)
 
 	if !d.isEdge(srcHash, dstHash) {
 		return EdgeUnknownError{srcID, dstID}
 	}
 
-	// get descendants and ancestors as they are now
-	descendants := copyMap(d.getDescendants(srcHash))
-	ancestors := copyMap(d.getAncestors(dstHash))
+	// get descendants and ancestors as they are now (only needed to maintain
+	// the caches)
+	var descendants, ancestors map[vertexHandle]struct{}
+	if !d.options.DisableCache {
+		descendants = copyMap(d.getDescendants(srcHash))
+		ancestors = copyMap(d.getAncestors(dstHash))
+	}
 
 	// delete outbound and inbound
-	delete(d.outboundEdge[srcHash], dstHash)
-	delete(d.inboundEdge[dstHash], srcHash)
+	d.unlinkEdge(srcHash, dstHash)
 
 	// for src and all its descendants delete cached ancestors
 	for descendant := range descendants {
-		delete(d.ancestorsCache, descendant)
+		d.ancestorsStats.evict(d.ancestorsCache, descendant)
 	}
-	delete(d.ancestorsCache, srcHash)
+	d.ancestorsStats.evict(d.ancestorsCache, srcHash)
 
 	// for dst and all its ancestors delete cached descendants
 	for ancestor := range ancestors {
-		delete(d.descendantsCache, ancestor)
+		d.descendantsStats.evict(d.descendantsCache, ancestor)
 	}
-	delete(d.descendantsCache, dstHash)
+	d.descendantsStats.evict(d.descendantsCache, dstHash)
 
+	d.recordEdgeOp(OpDeleteEdge, srcID, dstID)
+	d.mutated()
 	return nil
 }
 
@@ -373,7 +676,7 @@ func (d *GenericDAG[T]) GetOrder() int {
 }
 
 func (d *GenericDAG[T]) getOrder() int {
-	return len(d.vertices)
+	return d.ids.len()
 }
 
 // GetSize returns the number of edges in the graph.
@@ -398,14 +701,25 @@ func (d *GenericDAG[T]) GetLeaves() map[string]T {
 	return d.getLeaves()
 }
 
+// LeavesVersion returns a counter that changes whenever the set of leaves
+// changes. See RootsVersion for details.
+func (d *GenericDAG[T]) LeavesVersion() uint64 {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.leavesVersion
+}
+
+// LeavesIter returns an iterator over the ids and values of all vertices
+// without children, which unlike GetLeaves doesn't copy them into a map. See
+// RootsIter for details.
+func (d *GenericDAG[T]) LeavesIter() func(yield func(id string, value T) bool) {
+	return d.iterSet(func() map[vertexHandle]struct{} { return d.leaves })
+}
+
 func (d *GenericDAG[T]) getLeaves() map[string]T {
-	leaves := make(map[string]T)
-	for vHash := range d.vertices {
-		dstIDs, ok := d.outboundEdge[vHash]
-		if !ok || len(dstIDs) == 0 {
-			id := d.vertices[vHash]
-			leaves[id] = d.vertexValues[id]
-		}
+	leaves := make(map[string]T, len(d.leaves))
+	for vHash := range d.leaves {
+		leaves[d.ids.id(vHash)] = d.values[vHash]
 	}
 	return leaves
 }
@@ -422,8 +736,7 @@ func (d *GenericDAG[T]) IsLeaf(id string) (bool, error) {
 }
 
 func (d *GenericDAG[T]) isLeaf(id string) bool {
-	v := d.vertexValues[id]
-	vHash := d.hashVertex(v)
+	vHash := d.keyOf(id)
 	dstIDs, ok := d.outboundEdge[vHash]
 	if !ok || len(dstIDs) == 0 {
 		return true
@@ -438,15 +751,50 @@ func (d *GenericDAG[T]) GetRoots() map[string]T {
 	return d.getRoots()
 }
 
-func (d *GenericDAG[T]) getRoots() map[string]T {
-	roots := make(map[string]T)
-	for vHash := range d.vertices {
-		srcIDs, ok := d.inboundEdge[vHash]
-		if !ok || len(srcIDs) == 0 {
-			id := d.vertices[vHash]
-			roots[id] = d.vertexValues[id]
+// RootsVersion returns a counter that changes whenever the set of roots
+// changes, i.e. whenever the result of GetRoots would differ from the one
+// obtained at a previous version. Pollers may use it to skip GetRoots while
+// the roots are unchanged. Changes of the values of roots are not counted.
+func (d *GenericDAG[T]) RootsVersion() uint64 {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.rootsVersion
+}
+
+// RootsIter returns an iterator over the ids and values of all vertices
+// without parents, which unlike GetRoots doesn't copy them into a map; with
+// Go 1.23 or later it can be used in a range loop:
+//
+//	for id, v := range d.RootsIter() {
+//		...
+//	}
+//
+// The vertices are visited in no particular order. The GenericDAG is
+// read-locked while iterating, so the loop body must not modify it. Breaking
+// out of the loop stops the iteration.
+func (d *GenericDAG[T]) RootsIter() func(yield func(id string, value T) bool) {
+	return d.iterSet(func() map[vertexHandle]struct{} { return d.roots })
+}
+
+// iterSet returns an iterator over the vertices of the set returned by set,
+// which is called under the read lock.
+func (d *GenericDAG[T]) iterSet(set func() map[vertexHandle]struct{}) func(yield func(id string, value T) bool) {
+	return func(yield func(id string, value T) bool) {
+		d.muDAG.RLock()
+		defer d.muDAG.RUnlock()
+		for h := range set() {
+			if !yield(d.ids.id(h), d.values[h]) {
+				return
+			}
 		}
 	}
+}
+
+func (d *GenericDAG[T]) getRoots() map[string]T {
+	roots := make(map[string]T, len(d.roots))
+	for vHash := range d.roots {
+		roots[d.ids.id(vHash)] = d.values[vHash]
+	}
 	return roots
 }
 
@@ -462,8 +810,7 @@ func (d *GenericDAG[T]) IsRoot(id string) (bool, error) {
 }
 
 func (d *GenericDAG[T]) isRoot(id string) bool {
-	v := d.vertexValues[id]
-	vHash := d.hashVertex(v)
+	vHash := d.keyOf(id)
 	srcIDs, ok := d.inboundEdge[vHash]
 	if !ok || len(srcIDs) == 0 {
 		return true
@@ -475,13 +822,61 @@ func (d *GenericDAG[T]) isRoot(id string) bool {
 func (d *GenericDAG[T]) GetVertices() map[string]T {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
-	out := make(map[string]T, len(d.vertexValues))
-	for id, value := range d.vertexValues {
-		out[id] = value
+	out := make(map[string]T, d.ids.len())
+	for id, h := range d.ids.handles {
+		out[id] = d.values[h]
 	}
 	return out
 }
 
+// GetVerticesPage returns the ids and values of at most limit vertices after
+// skipping the first offset vertices, e.g. to serve a large graph page by page
+// without copying all vertices per request. If sortByID is true, the vertices
+// are ordered by id, otherwise in an order which is the insertion order if
+// the GenericDAG preserves it. Either way, the pages are consistent with each
+// other as long as the graph isn't modified in between, see VertexPager to
+// detect modifications.
+func (d *GenericDAG[T]) GetVerticesPage(offset, limit int, sortByID bool) []GenericStorableVertex[T] {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.getVerticesPage(offset, limit, sortByID)
+}
+
+func (d *GenericDAG[T]) getVerticesPage(offset, limit int, sortByID bool) []GenericStorableVertex[T] {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || offset >= d.ids.len() {
+		return []GenericStorableVertex[T]{}
+	}
+	if limit > d.ids.len()-offset {
+		limit = d.ids.len() - offset
+	}
+	page := make([]GenericStorableVertex[T], 0, limit)
+
+	if sortByID {
+		ids := make([]string, 0, d.ids.len())
+		for id := range d.ids.handles {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids[offset : offset+limit] {
+			page = append(page, GenericStorableVertex[T]{ID: id, Value: d.value(id)})
+		}
+		return page
+	}
+
+	d.ids.each(func(id string, h vertexHandle) bool {
+		if offset > 0 {
+			offset--
+			return true
+		}
+		page = append(page, GenericStorableVertex[T]{ID: id, Value: d.values[h]})
+		return len(page) < limit
+	})
+	return page
+}
+
 // GetParents returns all parents of the vertex with the id.
 // GetParents returns an error if id is empty or unknown.
 func (d *GenericDAG[T]) GetParents(id string) (map[string]T, error) {
@@ -490,12 +885,11 @@ func (d *GenericDAG[T]) GetParents(id string) (map[string]T, error) {
 	if err := d.saneID(id); err != nil {
 		return nil, err
 	}
-	v := d.vertexValues[id]
-	vHash := d.hashVertex(v)
+	vHash := d.keyOf(id)
 	parents := make(map[string]T)
 	for pv := range d.inboundEdge[vHash] {
-		pid := d.vertices[pv]
-		parents[pid] = d.vertexValues[pid]
+		pid := d.ids.id(pv)
+		parents[pid] = d.values[pv]
 	}
 	return parents, nil
 }
@@ -512,12 +906,11 @@ func (d *GenericDAG[T]) getChildren(id string) (map[string]T, error) {
 	if err := d.saneID(id); err != nil {
 		return nil, err
 	}
-	v := d.vertexValues[id]
-	vHash := d.hashVertex(v)
+	vHash := d.keyOf(id)
 	children := make(map[string]T)
 	for cv := range d.outboundEdge[vHash] {
-		cid := d.vertices[cv]
-		children[cid] = d.vertexValues[cid]
+		cid := d.ids.id(cv)
+		children[cid] = d.values[cv]
 	}
 	return children, nil
 }
@@ -530,22 +923,27 @@ func (d *GenericDAG[T]) GetAncestors(id string) (map[string]T, error) {
 	if err := d.saneID(id); err != nil {
 		return nil, err
 	}
-	v := d.vertexValues[id]
-	vHash := d.hashVertex(v)
+	vHash := d.keyOf(id)
 	ancestors := make(map[string]T)
 	for av := range d.getAncestors(vHash) {
-		aid := d.vertices[av]
-		ancestors[aid] = d.vertexValues[aid]
+		aid := d.ids.id(av)
+		ancestors[aid] = d.values[av]
 	}
 	return ancestors, nil
 }
 
-func (d *GenericDAG[T]) getAncestors(vHash interface{}) map[interface{}]struct{} {
+func (d *GenericDAG[T]) getAncestors(vHash vertexHandle) map[vertexHandle]struct{} {
+	// without a cache, collect the relatives from scratch every time
+	if d.options.DisableCache {
+		return collectRelatives(vHash, d.inboundEdge)
+	}
+
 	// in the best case we have already a populated cache
 	d.muCache.RLock()
 	cache, exists := d.ancestorsCache[vHash]
 	d.muCache.RUnlock()
 	if exists {
+		d.ancestorsStats.hits.Add(1)
 		return cache
 	}
 
@@ -559,11 +957,13 @@ func (d *GenericDAG[T]) getAncestors(vHash interface{}) map[interface{}]struct{}
 	cache, exists = d.ancestorsCache[vHash]
 	d.muCache.RUnlock()
 	if exists {
+		d.ancestorsStats.hits.Add(1)
 		return cache
 	}
+	d.ancestorsStats.misses.Add(1)
 
 	// as there is no cache, we start from scratch and collect all ancestors locally
-	cache = make(map[interface{}]struct{})
+	cache = make(map[vertexHandle]struct{})
 	var mu sync.Mutex
 	if parents, ok := d.inboundEdge[vHash]; ok {
 		// for each parent collect its ancestors
@@ -602,56 +1002,106 @@ func (d *GenericDAG[T]) GetOrderedAncestors(id string) ([]string, error) {
 	return ancestors, nil
 }
 
+// GetOrderedAncestorsWithValues returns the ids and values of all ancestors of
+// the vertex with id in the breath-first order of GetOrderedAncestors.
+// GetOrderedAncestorsWithValues returns an error if id is empty or unknown.
+func (d *GenericDAG[T]) GetOrderedAncestorsWithValues(id string) ([]GenericStorableVertex[T], error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	return d.orderedRelatives(d.keyOf(id), func(h vertexHandle, f func(parent vertexHandle)) {
+		d.eachRelative(d.inboundEdge[h], f)
+	}), nil
+}
+
+// orderedRelatives returns the ids and values of all vertices reachable from
+// vHash via eachRelative in breath-first order.
+func (d *GenericDAG[T]) orderedRelatives(vHash vertexHandle, eachRelative func(h vertexHandle, f func(relative vertexHandle))) []GenericStorableVertex[T] {
+	relatives, _ := d.orderedRelativesLimited(vHash, eachRelative, nil)
+	return relatives
+}
+
+// orderedRelativesLimited is orderedRelatives, but returns a
+// LimitExceededError if it visits more vertices (including vHash) or finds
+// more relatives than allowed by budget.
+func (d *GenericDAG[T]) orderedRelativesLimited(vHash vertexHandle, eachRelative func(h vertexHandle, f func(relative vertexHandle)), budget *traversalBudget) ([]GenericStorableVertex[T], error) {
+	var relatives []GenericStorableVertex[T]
+	var err error
+	s := getScratch()
+	defer s.release()
+	visit := func(relative vertexHandle) {
+		if err == nil && s.visit(relative) {
+			err = budget.result()
+			relatives = append(relatives, GenericStorableVertex[T]{ID: d.ids.id(relative), Value: d.values[relative]})
+		}
+	}
+	s.visit(vHash)
+	for top, ok := s.next(); ok; top, ok = s.next() {
+		if err = budget.visit(); err != nil {
+			return nil, err
+		}
+		eachRelative(top, visit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return relatives, nil
+}
+
 // AncestorsWalker returns a channel and subsequently walks all ancestors of
 // the vertex with id in a breath first order. The second channel returned may
 // be used to stop further walking. AncestorsWalker returns an error if id is
 // empty or unknown.
+//
+// The walk operates on a snapshot of the ancestors taken by AncestorsWalker,
+// so the receiver may modify the graph while walking, which doesn't affect
+// the walk.
 func (d *GenericDAG[T]) AncestorsWalker(id string) (chan string, chan bool, error) {
+	return d.relativesWalker(id, true, nil)
+}
+
+// relativesWalker walks a snapshot of the ancestors or descendants of the
+// vertex with id, taken within budget.
+func (d *GenericDAG[T]) relativesWalker(id string, ancestors bool, budget *traversalBudget) (chan string, chan bool, error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
 	if err := d.saneID(id); err != nil {
 		return nil, nil, err
 	}
-	ids := make(chan string)
-	signal := make(chan bool, 1)
-	go func() {
-		d.muDAG.RLock()
-		v := d.vertexValues[id]
-		vHash := d.hashVertex(v)
-		d.walkAncestors(vHash, ids, signal)
-		d.muDAG.RUnlock()
-		close(ids)
-		close(signal)
-	}()
+	eachRelative := d.eachChild
+	if ancestors {
+		eachRelative = func(h vertexHandle, f func(parent vertexHandle)) {
+			d.eachRelative(d.inboundEdge[h], f)
+		}
+	}
+	relatives, err := d.orderedRelativesLimited(d.keyOf(id), eachRelative, budget)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids, signal := walkSnapshot(relatives)
 	return ids, signal, nil
 }
 
-func (d *GenericDAG[T]) walkAncestors(vHash interface{}, ids chan string, signal chan bool) {
-	var fifo []interface{}
-	visited := make(map[interface{}]struct{})
-	for parent := range d.inboundEdge[vHash] {
-		visited[parent] = struct{}{}
-		fifo = append(fifo, parent)
-	}
-	for {
-		if len(fifo) == 0 {
-			return
-		}
-		top := fifo[0]
-		fifo = fifo[1:]
-		for parent := range d.inboundEdge[top] {
-			if _, exists := visited[parent]; !exists {
-				visited[parent] = struct{}{}
-				fifo = append(fifo, parent)
+// walkSnapshot sends the ids of the relatives on the returned channel until
+// all are sent or a value is sent on the returned signal channel.
+func walkSnapshot[T any](relatives []GenericStorableVertex[T]) (chan string, chan bool) {
+	ids := make(chan string)
+	signal := make(chan bool, 1)
+	go func() {
+		defer close(signal)
+		defer close(ids)
+		for _, relative := range relatives {
+			select {
+			case <-signal:
+				return
+			default:
+				ids <- relative.ID
 			}
 		}
-		select {
-		case <-signal:
-			return
-		default:
-			ids <- d.vertices[top]
-		}
-	}
+	}()
+	return ids, signal
 }
 
 // GetDescendants returns all descendants of the vertex with the id.
@@ -663,23 +1113,28 @@ func (d *GenericDAG[T]) GetDescendants(id string) (map[string]T, error) {
 	if err := d.saneID(id); err != nil {
 		return nil, err
 	}
-	v := d.vertexValues[id]
-	vHash := d.hashVertex(v)
+	vHash := d.keyOf(id)
 
 	descendants := make(map[string]T)
 	for dv := range d.getDescendants(vHash) {
-		did := d.vertices[dv]
-		descendants[did] = d.vertexValues[did]
+		did := d.ids.id(dv)
+		descendants[did] = d.values[dv]
 	}
 	return descendants, nil
 }
 
-func (d *GenericDAG[T]) getDescendants(vHash interface{}) map[interface{}]struct{} {
+func (d *GenericDAG[T]) getDescendants(vHash vertexHandle) map[vertexHandle]struct{} {
+	// without a cache, collect the relatives from scratch every time
+	if d.options.DisableCache {
+		return collectRelatives(vHash, d.outboundEdge)
+	}
+
 	// in the best case we have already a populated cache
 	d.muCache.RLock()
 	cache, exists := d.descendantsCache[vHash]
 	d.muCache.RUnlock()
 	if exists {
+		d.descendantsStats.hits.Add(1)
 		return cache
 	}
 
@@ -693,12 +1148,14 @@ func (d *GenericDAG[T]) getDescendants(vHash interface{}) map[interface{}]struct
 	cache, exists = d.descendantsCache[vHash]
 	d.muCache.RUnlock()
 	if exists {
+		d.descendantsStats.hits.Add(1)
 		return cache
 	}
+	d.descendantsStats.misses.Add(1)
 
 	// as there is no cache, we start from scratch and collect all descendants
 	// locally
-	cache = make(map[interface{}]struct{})
+	cache = make(map[vertexHandle]struct{})
 	var mu sync.Mutex
 	if children, ok := d.outboundEdge[vHash]; ok {
 		for child := range children {
@@ -719,6 +1176,52 @@ func (d *GenericDAG[T]) getDescendants(vHash interface{}) map[interface{}]struct
 	return cache
 }
 
+// GetDescendantsVia returns all descendants of the vertex with rootID that are
+// reachable from it only through the vertex with viaID, i.e. whose every path
+// from rootID passes through viaID, e.g. the vertices affected by a failure of
+// viaID in a pipeline starting at rootID. viaID itself is not part of the
+// result, and neither are any vertices if viaID is no descendant of rootID.
+// If viaID equals rootID, all descendants are returned.
+// GetDescendantsVia returns an error if rootID or viaID are empty or unknown.
+func (d *GenericDAG[T]) GetDescendantsVia(rootID, viaID string) (map[string]T, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(rootID); err != nil {
+		return nil, err
+	}
+	if err := d.saneID(viaID); err != nil {
+		return nil, err
+	}
+	rootHash, viaHash := d.keyOf(rootID), d.keyOf(viaID)
+
+	descendants := make(map[string]T)
+	if _, exists := d.getDescendants(rootHash)[viaHash]; !exists && rootHash != viaHash {
+		return descendants, nil
+	}
+
+	// collect the descendants of root that can be reached bypassing via, by
+	// marking via as visited up front
+	bypassed := getScratch()
+	defer bypassed.release()
+	if rootHash != viaHash {
+		bypassed.visited[viaHash] = struct{}{}
+		bypassed.visit(rootHash)
+		for top, ok := bypassed.next(); ok; top, ok = bypassed.next() {
+			for child := range d.outboundEdge[top] {
+				bypassed.visit(child)
+			}
+		}
+	}
+
+	for dv := range d.getDescendants(viaHash) {
+		if _, exists := bypassed.visited[dv]; !exists {
+			descendants[d.ids.id(dv)] = d.values[dv]
+		}
+	}
+	return descendants, nil
+}
+
 // GetOrderedDescendants returns all descendants of the vertex with id
 // in a breath-first order. Only the first occurrence of each vertex is returned.
 // GetOrderedDescendants returns an error if id is empty or unknown.
@@ -736,56 +1239,29 @@ func (d *GenericDAG[T]) GetOrderedDescendants(id string) ([]string, error) {
 	return descendants, nil
 }
 
-// DescendantsWalker returns a channel and subsequently walks all descendants
-// of the vertex with id in a breath first order. The second channel returned
-// may be used to stop further walking. DescendantsWalker returns an error if
+// GetOrderedDescendantsWithValues returns the ids and values of all
+// descendants of the vertex with id in the breath-first order of
+// GetOrderedDescendants. GetOrderedDescendantsWithValues returns an error if
 // id is empty or unknown.
-func (d *GenericDAG[T]) DescendantsWalker(id string) (chan string, chan bool, error) {
+func (d *GenericDAG[T]) GetOrderedDescendantsWithValues(id string) ([]GenericStorableVertex[T], error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
 	if err := d.saneID(id); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	ids := make(chan string)
-	signal := make(chan bool, 1)
-	go func() {
-		d.muDAG.RLock()
-		v := d.vertexValues[id]
-		vHash := d.hashVertex(v)
-		d.walkDescendants(vHash, ids, signal)
-		d.muDAG.RUnlock()
-		close(ids)
-		close(signal)
-	}()
-	return ids, signal, nil
+	return d.orderedRelatives(d.keyOf(id), d.eachChild), nil
 }
 
-func (d *GenericDAG[T]) walkDescendants(vHash interface{}, ids chan string, signal chan bool) {
-	var fifo []interface{}
-	visited := make(map[interface{}]struct{})
-	for child := range d.outboundEdge[vHash] {
-		visited[child] = struct{}{}
-		fifo = append(fifo, child)
-	}
-	for {
-		if len(fifo) == 0 {
-			return
-		}
-		top := fifo[0]
-		fifo = fifo[1:]
-		for child := range d.outboundEdge[top] {
-			if _, exists := visited[child]; !exists {
-				visited[child] = struct{}{}
-				fifo = append(fifo, child)
-			}
-		}
-		select {
-		case <-signal:
-			return
-		default:
-			ids <- d.vertices[top]
-		}
-	}
+// DescendantsWalker returns a channel and subsequently walks all descendants
+// of the vertex with id in a breath first order. The second channel returned
+// may be used to stop further walking. DescendantsWalker returns an error if
+// id is empty or unknown.
+//
+// The walk operates on a snapshot of the descendants taken by
+// DescendantsWalker, so the receiver may modify the graph while walking,
+// which doesn't affect the walk.
+func (d *GenericDAG[T]) DescendantsWalker(id string) (chan string, chan bool, error) {
+	return d.relativesWalker(id, false, nil)
 }
 
 // GetDescendantsGraph returns a new GenericDAG consisting of the vertex with id
@@ -793,8 +1269,12 @@ func (d *GenericDAG[T]) walkDescendants(vHash interface{}, ids chan string, sign
 // the id of the (copy of the) given vertex within the new graph (i.e. the id of
 // the single root of the new graph). GetDescendantsGraph returns an error if id
 // is empty or unknown.
+//
+// The vertices of the new graph keep the ids they have in the original graph,
+// thus the returned id is always id. Use GetDescendantsGraphMapped to assign
+// other ids.
 func (d *GenericDAG[T]) GetDescendantsGraph(id string) (*GenericDAG[T], string, error) {
-	return d.getRelativesGraph(id, false)
+	return d.getRelativesGraph(id, nil, false, nil, nil)
 }
 
 // GetAncestorsGraph returns a new GenericDAG consisting of the vertex with id
@@ -802,45 +1282,76 @@ func (d *GenericDAG[T]) GetDescendantsGraph(id string) (*GenericDAG[T], string,
 // of the (copy of the) given vertex within the new graph (i.e. the id of the
 // single leaf of the new graph). GetAncestorsGraph returns an error if id is
 // empty or unknown.
+//
+// As for GetDescendantsGraph, the vertices of the new graph keep their ids.
+// Use GetAncestorsGraphMapped to assign other ids.
 func (d *GenericDAG[T]) GetAncestorsGraph(id string) (*GenericDAG[T], string, error) {
-	return d.getRelativesGraph(id, true)
+	return d.getRelativesGraph(id, nil, true, nil, nil)
 }
 
-func (d *GenericDAG[T]) getRelativesGraph(id string, asc bool) (*GenericDAG[T], string, error) {
-	// sanity checking
-	if id == "" {
-		return nil, "", IDEmptyError{}
-	}
-	v, exists := d.vertexValues[id]
-	if !exists {
-		return nil, "", IDUnknownError{id}
-	}
-	vHash := d.hashVertex(v)
+// GetDescendantsGraphMapped is like GetDescendantsGraph, but the copy of each
+// vertex gets the id mapID returns for the vertex's id in the original graph,
+// e.g. to prefix the ids of the subgraph before merging it into another graph.
+// The returned id is mapID(id). GetDescendantsGraphMapped returns an error if
+// id is empty or unknown, or if mapID returns an empty id or the same id for
+// distinct vertices.
+func (d *GenericDAG[T]) GetDescendantsGraphMapped(id string, mapID func(id string) string) (*GenericDAG[T], string, error) {
+	return d.getRelativesGraph(id, mapID, false, nil, nil)
+}
 
-	// create a new dag
-	newDAG := NewGenericDAG[T]()
+// GetAncestorsGraphMapped is like GetAncestorsGraph, but the copy of each
+// vertex gets the id mapID returns for the vertex's id in the original graph.
+// See GetDescendantsGraphMapped for details.
+func (d *GenericDAG[T]) GetAncestorsGraphMapped(id string, mapID func(id string) string) (*GenericDAG[T], string, error) {
+	return d.getRelativesGraph(id, mapID, true, nil, nil)
+}
 
+// getRelativesGraph copies the vertex with id and all its relatives into a new
+// graph. If info isn't nil, getRelativesGraph records the copied vertices and
+// edges there.
+func (d *GenericDAG[T]) getRelativesGraph(id string, mapID func(id string) string, asc bool, c *cancelChecker, info *SubgraphInfo) (*GenericDAG[T], string, error) {
 	// protect the graph from modification
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
 
+	// sanity checking
+	if err := d.saneID(id); err != nil {
+		return nil, "", err
+	}
+	vHash := d.keyOf(id)
+
+	// create a new dag
+	newDAG := NewGenericDAG[T](WithOptions(d.options))
+
 	// recursively add the current vertex and all its relatives
-	newId, err := d.getRelativesGraphRec(vHash, newDAG, make(map[interface{}]string), asc)
+	newId, err := d.getRelativesGraphRec(vHash, newDAG, make(map[vertexHandle]string), mapID, asc, c, info)
+	if info != nil {
+		info.RootID = newId
+	}
 	return newDAG, newId, err
 }
 
-func (d *GenericDAG[T]) getRelativesGraphRec(vHash interface{}, newDAG *GenericDAG[T], visited map[interface{}]string, asc bool) (newId string, err error) {
+func (d *GenericDAG[T]) getRelativesGraphRec(vHash vertexHandle, newDAG *GenericDAG[T], visited map[vertexHandle]string, mapID func(id string) string, asc bool, c *cancelChecker, info *SubgraphInfo) (newId string, err error) {
+	if err = c.err(); err != nil {
+		return
+	}
+
 	// copy this vertex to the new graph
-	newId = d.vertices[vHash]
-	if err = newDAG.AddVertexByID(newId, d.vertexValues[newId]); err != nil {
+	id := d.ids.id(vHash)
+	newId = id
+	if mapID != nil {
+		newId = mapID(id)
+	}
+	if err = newDAG.AddVertexByID(newId, d.value(id)); err != nil {
 		return
 	}
 
 	// mark this vertex as visited
 	visited[vHash] = newId
+	info.addVertex(id, newId)
 
 	// get the direct relatives (depending on the direction either parents or children)
-	var relatives map[interface{}]struct{}
+	var relatives map[vertexHandle]struct{}
 	var ok bool
 	if asc {
 		relatives, ok = d.inboundEdge[vHash]
@@ -855,67 +1366,182 @@ func (d *GenericDAG[T]) getRelativesGraphRec(vHash interface{}, newDAG *GenericD
 			relativeId, exists := visited[relative]
 			if !exists {
 				// recursively add this relative
-				if relativeId, err = d.getRelativesGraphRec(relative, newDAG, visited, asc); err != nil {
+				if relativeId, err = d.getRelativesGraphRec(relative, newDAG, visited, mapID, asc, c, info); err != nil {
 					return
 				}
 			}
 
 			// add edge to this relative (depending on the direction)
 			var srcID, dstID string
+			var edge [2]vertexHandle
 			if asc {
 				srcID, dstID = relativeId, newId
+				edge = [2]vertexHandle{relative, vHash}
 			} else {
 				srcID, dstID = newId, relativeId
+				edge = [2]vertexHandle{vHash, relative}
 			}
 			if err = newDAG.AddEdge(srcID, dstID); err != nil {
 				return
 			}
+			if value, exists := d.edgeValues[edge]; exists {
+				newDAG.setEdgeValue(newDAG.keyOf(srcID), newDAG.keyOf(dstID), value)
+			}
+			if asc {
+				info.addEdge(GenericEdge{SrcID: d.ids.id(relative), DstID: id}, GenericEdge{SrcID: srcID, DstID: dstID})
+			} else {
+				info.addEdge(GenericEdge{SrcID: id, DstID: d.ids.id(relative)}, GenericEdge{SrcID: srcID, DstID: dstID})
+			}
 		}
 	}
 	return
 }
 
+// GetDescendantsGraphInto copies the vertex with id and all its descendants
+// into target, merging them with the vertices already there: vertices whose id
+// is already known to target are kept as they are, and so are known edges.
+// This allows assembling a graph from several subgraphs without intermediate
+// copies. GetDescendantsGraphInto returns an error if id is empty or unknown,
+// or if a vertex or edge can't be added to target, e.g. as it would create a
+// loop there. In that case, target may have been modified partially.
+func (d *GenericDAG[T]) GetDescendantsGraphInto(id string, target *GenericDAG[T]) error {
+	return d.getRelativesGraphInto(id, target, false, nil)
+}
+
+// GetAncestorsGraphInto copies the vertex with id and all its ancestors into
+// target, merging them with the vertices already there. See
+// GetDescendantsGraphInto for details.
+func (d *GenericDAG[T]) GetAncestorsGraphInto(id string, target *GenericDAG[T]) error {
+	return d.getRelativesGraphInto(id, target, true, nil)
+}
+
+// getRelativesGraphInto copies the vertex with id and all its relatives into
+// target. If info isn't nil, getRelativesGraphInto records the copied and
+// skipped vertices and edges there.
+func (d *GenericDAG[T]) getRelativesGraphInto(id string, target *GenericDAG[T], asc bool, info *SubgraphInfo) error {
+	// capture the subgraph, so that only one graph is locked at a time
+	d.muDAG.RLock()
+	if err := d.saneID(id); err != nil {
+		d.muDAG.RUnlock()
+		return err
+	}
+	vHash := d.keyOf(id)
+	edgeMap := d.outboundEdge
+	if asc {
+		edgeMap = d.inboundEdge
+	}
+	relatives := collectRelatives(vHash, edgeMap)
+	relatives[vHash] = struct{}{}
+	vertices := make([]GenericStorableVertex[T], 0, len(relatives))
+	var edges [][2]string
+	for h := range relatives {
+		vertices = append(vertices, GenericStorableVertex[T]{ID: d.ids.id(h), Value: d.values[h]})
+		for relative := range edgeMap[h] {
+			if asc {
+				edges = append(edges, [2]string{d.ids.id(relative), d.ids.id(h)})
+			} else {
+				edges = append(edges, [2]string{d.ids.id(h), d.ids.id(relative)})
+			}
+		}
+	}
+	d.muDAG.RUnlock()
+
+	target.muDAG.Lock()
+	defer target.muDAG.Unlock()
+	if info != nil {
+		info.RootID = id
+	}
+	defer info.sortSkipped()
+	for _, v := range vertices {
+		if _, exists := target.ids.handle(v.ID); exists {
+			info.skipVertex(v.ID)
+			continue
+		}
+		if err := target.addVertexByID(v.ID, v.Value); err != nil {
+			return err
+		}
+		info.addVertex(v.ID, v.ID)
+	}
+	for _, e := range edges {
+		edge := GenericEdge{SrcID: e[0], DstID: e[1]}
+		if err := target.addEdge(e[0], e[1]); err != nil {
+			if _, ok := err.(EdgeDuplicateError); !ok {
+				return err
+			}
+			info.skipEdge(edge)
+			continue
+		}
+		info.addEdge(edge, edge)
+	}
+	return nil
+}
+
 // ReduceTransitively transitively reduces the graph.
 func (d *GenericDAG[T]) ReduceTransitively() {
 	d.muDAG.Lock()
 	defer d.muDAG.Unlock()
+	_ = d.reduceTransitively(nil)
+}
 
-	graphChanged := false
-
-	// populate the descendants cache for all roots (i.e. the whole graph)
-	for _, root := range d.getRoots() {
-		_ = d.getDescendants(d.hashVertex(root))
+// reduceTransitively removes all redundant edges, unless c reports the
+// cancellation of its context before they are known. reduceTransitively
+// works on the stored handles only and neither rehashes values nor depends on
+// the caches, so it works without them as well. reduceTransitively must be
+// called with the write lock held.
+func (d *GenericDAG[T]) reduceTransitively(c *cancelChecker) error {
+	// visit the vertices from the leaves upwards, so that the descendants of
+	// all children of a vertex are known when the vertex is visited
+	pending := make(map[vertexHandle]int, d.ids.len())
+	var ready []vertexHandle
+	for _, h := range d.ids.handles {
+		if pending[h] = len(d.outboundEdge[h]); pending[h] == 0 {
+			ready = append(ready, h)
+		}
 	}
-
-	// for each vertex
-	for vHash := range d.vertices {
-		// map of descendants of the children of v
-		descendantsOfChildrenOfV := make(map[interface{}]struct{})
-
-		// for each child of v
-		for childOfV := range d.outboundEdge[vHash] {
-			// collect child descendants
-			for descendant := range d.descendantsCache[childOfV] {
-				descendantsOfChildrenOfV[descendant] = struct{}{}
+	descendants := make(map[vertexHandle]map[vertexHandle]struct{}, d.ids.len())
+	var redundant [][2]vertexHandle
+	for len(ready) > 0 {
+		if err := c.err(); err != nil {
+			return err
+		}
+		h := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+
+		// the edge to a child is redundant, iff the child is a descendant of
+		// another child
+		descendantsOfChildren := make(map[vertexHandle]struct{})
+		for child := range d.outboundEdge[h] {
+			for descendant := range descendants[child] {
+				descendantsOfChildren[descendant] = struct{}{}
+			}
+		}
+		all := copyMap(descendantsOfChildren)
+		for child := range d.outboundEdge[h] {
+			if _, exists := descendantsOfChildren[child]; exists {
+				redundant = append(redundant, [2]vertexHandle{h, child})
 			}
+			all[child] = struct{}{}
 		}
+		descendants[h] = all
 
-		// for each child of v
-		for childOfV := range d.outboundEdge[vHash] {
-			// remove the edge between v and child, iff child is a
-			// descendant of any of the children of v
-			if _, exists := descendantsOfChildrenOfV[childOfV]; exists {
-				delete(d.outboundEdge[vHash], childOfV)
-				delete(d.inboundEdge[childOfV], vHash)
-				graphChanged = true
+		for parent := range d.inboundEdge[h] {
+			if pending[parent]--; pending[parent] == 0 {
+				ready = append(ready, parent)
 			}
 		}
 	}
 
-	// flush the descendants- and ancestor cache if the graph has changed
-	if graphChanged {
+	// remove the redundant edges only now, as the reduction can't be
+	// cancelled halfway
+	for _, e := range redundant {
+		d.unlinkEdge(e[0], e[1])
+		d.recordEdgeOp(OpDeleteEdge, d.ids.id(e[0]), d.ids.id(e[1]))
+	}
+	if len(redundant) > 0 {
 		d.flushCaches()
+		d.mutated()
 	}
+	return nil
 }
 
 // FlushCaches completely flushes the descendants- and ancestor cache.
@@ -926,49 +1552,41 @@ func (d *GenericDAG[T]) FlushCaches() {
 }
 
 func (d *GenericDAG[T]) flushCaches() {
-	d.ancestorsCache = make(map[interface{}]map[interface{}]struct{})
-	d.descendantsCache = make(map[interface{}]map[interface{}]struct{})
+	d.ancestorsStats.evictions.Add(uint64(len(d.ancestorsCache)))
+	d.descendantsStats.evictions.Add(uint64(len(d.descendantsCache)))
+	d.ancestorsCache = make(map[vertexHandle]map[vertexHandle]struct{})
+	d.descendantsCache = make(map[vertexHandle]map[vertexHandle]struct{})
 }
 
-// Copy returns a copy of the GenericDAG.
-func (d *GenericDAG[T]) Copy() (*GenericDAG[T], error) {
-	// create a new dag
-	newDAG := NewGenericDAG[T]()
-
-	// create a map of visited vertices
-	visited := make(map[interface{}]string)
-
-	// protect the graph from modification
-	d.muDAG.RLock()
-	defer d.muDAG.RUnlock()
-
-	// add all roots and their descendants to the new DAG
-	roots := d.getRoots()
-	for id := range roots {
-		root := roots[id]
-		if _, err := d.getRelativesGraphRec(d.hashVertex(root), newDAG, visited, false); err != nil {
-			return nil, err
+// mutated records a successful modification of the graph and wakes up all
+// goroutines waiting for modifications. mutated must be called with the write
+// lock held.
+func (d *GenericDAG[T]) mutated() {
+	d.mutations++
+	for _, signal := range d.mutationSignals {
+		select {
+		case signal <- struct{}{}:
+		default:
 		}
 	}
-	return newDAG, nil
 }
 
-// String returns a textual representation of the graph.
-func (d *GenericDAG[T]) String() string {
-	result := fmt.Sprintf("GenericDAG Vertices: %d - Edges: %d\n", d.GetOrder(), d.GetSize())
-	result += "Vertices:\n"
+// Copy returns a copy of the GenericDAG. The GenericDAG is only locked while
+// its structure is captured; the copy is built afterwards, so writers are
+// not blocked while large graphs are copied.
+func (d *GenericDAG[T]) Copy() (*GenericDAG[T], error) {
 	d.muDAG.RLock()
-	for k := range d.vertices {
-		result += fmt.Sprintf("  %v\n", k)
-	}
-	result += "Edges:\n"
-	for v, children := range d.outboundEdge {
-		for child := range children {
-			result += fmt.Sprintf("  %v -> %v\n", v, child)
-		}
-	}
+	s := d.snapshot()
 	d.muDAG.RUnlock()
-	return result
+	return materializeGeneric(s)
+}
+
+// String returns a textual representation of the graph listing the ids of all
+// vertices and edges. Use Format for more control over the output.
+func (d *GenericDAG[T]) String() string {
+	var b strings.Builder
+	_ = d.Format(&b, FormatOptions{})
+	return b.String()
 }
 
 func (d *GenericDAG[T]) saneID(id string) error {
@@ -976,23 +1594,61 @@ func (d *GenericDAG[T]) saneID(id string) error {
 	if id == "" {
 		return IDEmptyError{}
 	}
-	_, exists := d.vertexValues[id]
-	if !exists {
+	if _, exists := d.ids.handle(id); !exists {
 		return IDUnknownError{id}
 	}
 	return nil
 }
 
-func (d *GenericDAG[T]) hashVertex(v T) interface{} {
-	return d.options.VertexHashFunc(v)
+// hashVertex returns the hash of v and true, or false if v isn't hashed, as
+// the GenericDAG allows duplicate values or v has no identity.
+func (d *GenericDAG[T]) hashVertex(v T) (interface{}, bool) {
+	if d.options.AllowDuplicateValues {
+		return nil, false
+	}
+	if d.options.IdentityHash {
+		return identityOf(v)
+	}
+	return d.options.VertexHashFunc(v), true
+}
+
+// idTaken reports whether id is the id of a vertex or of a soft deleted
+// vertex, so that no new vertex may be added with it.
+func (d *GenericDAG[T]) idTaken(id string) bool {
+	if _, exists := d.ids.handle(id); exists {
+		return true
+	}
+	_, exists := d.tombstones[id]
+	return exists
+}
+
+// keyOf returns the handle of the known vertex with the given id.
+func (d *GenericDAG[T]) keyOf(id string) vertexHandle {
+	h, _ := d.ids.handle(id)
+	return h
+}
+
+// value returns the value of the known vertex with the given id.
+func (d *GenericDAG[T]) value(id string) T {
+	return d.values[d.keyOf(id)]
 }
 
 // Options sets the options for the GenericDAG.
 // Options must be called before any other method of the GenericDAG is called.
+//
+// Deprecated: Pass Option values to NewGenericDAG instead, which makes the
+// configuration immutable after construction.
 func (d *GenericDAG[T]) Options(options Options) {
 	d.muDAG.Lock()
 	defer d.muDAG.Unlock()
-	d.options = options
+	d.options = options.normalize()
+	d.ids.ordered = d.options.PreserveInsertionOrder
+	if d.options.SortedChildren && d.childLists == nil {
+		d.childLists = make(map[vertexHandle][]vertexHandle)
+	}
+	if d.options.TrackStatus && d.statuses == nil {
+		d.statuses = &statusTable{}
+	}
 }
 
 // GetDescendantsGraphByDepth returns a new GenericDAG consisting of the vertex
@@ -1013,113 +1669,43 @@ func (d *GenericDAG[T]) GetAncestorsGraphByDepth(leafID string, maxDepth int) (*
 
 // getRelativesGraphByDepth returns a subgraph limited by depth using BFS traversal.
 func (d *GenericDAG[T]) getRelativesGraphByDepth(startID string, maxDepth int, asc bool) (*GenericDAG[T], string, error) {
-	// sanity checking
-	if startID == "" {
-		return nil, "", IDEmptyError{}
-	}
-	v, exists := d.vertexValues[startID]
-	if !exists {
-		return nil, "", IDUnknownError{startID}
-	}
-	vHash := d.hashVertex(v)
-
-	// create a new dag
-	newDAG := NewGenericDAG[T]()
-
 	// protect the graph from modification
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
 
-	// For unlimited depth, use the existing implementation
-	if maxDepth < 0 {
-		return d.getRelativesGraph(startID, asc)
-	}
-
-	// Use BFS with depth tracking
-	visited := make(map[interface{}]string)
-	idMap := make(map[string]string) // original ID -> new ID (for reference during traversal)
-
-	// BFS queue item
-	type queueItem struct {
-		vHash interface{}
-		depth int
-	}
-
-	var queue []queueItem
-	if asc {
-		// For ancestors, start with parents
-		for parent := range d.inboundEdge[vHash] {
-			queue = append(queue, queueItem{vHash: parent, depth: 1})
-		}
-	} else {
-		// For descendants, start with children
-		for child := range d.outboundEdge[vHash] {
-			queue = append(queue, queueItem{vHash: child, depth: 1})
-		}
-	}
-
-	// Add the start node first
-	newStartID := startID
-	if err := newDAG.AddVertexByID(newStartID, d.vertexValues[startID]); err != nil {
+	// sanity checking
+	if err := d.saneID(startID); err != nil {
 		return nil, "", err
 	}
-	visited[vHash] = newStartID
-	idMap[startID] = newStartID
-
-	// BFS traversal
-	for len(queue) > 0 {
-		item := queue[0]
-		queue = queue[1:]
-
-		// Skip if we've exceeded max depth
-		if item.depth > maxDepth {
-			continue
-		}
-
-		// Get the original ID
-		origID := d.vertices[item.vHash]
-
-		// Add the vertex if not already visited
-		newID, exists := visited[item.vHash]
-		if !exists {
-			newID = origID
-			if err := newDAG.AddVertexByID(newID, d.vertexValues[origID]); err != nil {
-				return nil, "", err
-			}
-			visited[item.vHash] = newID
-			idMap[origID] = newID
-		}
 
-		// Add edge to this vertex
-		// Note: This is a placeholder - the actual edge handling is done in getRelativesGraphByDepthBFS
-		// We continue to use getRelativesGraphByDepthBFS for proper parent tracking
-		continue
+	// For unlimited depth, use the existing implementation
+	if maxDepth < 0 {
+		return d.getRelativesGraph(startID, nil, asc, nil, nil)
 	}
 
-	// Re-implement with proper parent tracking
 	return d.getRelativesGraphByDepthBFS(startID, maxDepth, asc)
 }
 
 // getRelativesGraphByDepthBFS implements BFS with parent tracking for depth-limited subgraph extraction.
 func (d *GenericDAG[T]) getRelativesGraphByDepthBFS(startID string, maxDepth int, asc bool) (*GenericDAG[T], string, error) {
 	// create a new dag
-	newDAG := NewGenericDAG[T]()
+	newDAG := NewGenericDAG[T](WithOptions(d.options))
 
 	// Track visited vertices and their new IDs
-	visited := make(map[interface{}]string)
+	visited := make(map[vertexHandle]string)
 
 	// BFS queue item with parent tracking
 	type queueItem struct {
-		vHash    interface{}
+		vHash    vertexHandle
 		depth    int
 		parentID string // new ID of the parent in the new graph
 	}
 
 	var queue []queueItem
-	startVHash := d.hashVertex(d.vertexValues[startID])
+	startVHash := d.keyOf(startID)
 
 	// Add the start node first
-	if err := newDAG.AddVertexByID(startID, d.vertexValues[startID]); err != nil {
+	if err := newDAG.AddVertexByID(startID, d.value(startID)); err != nil {
 		return nil, "", err
 	}
 	visited[startVHash] = startID
@@ -1154,13 +1740,13 @@ func (d *GenericDAG[T]) getRelativesGraphByDepthBFS(startID string, maxDepth int
 		}
 
 		// Get the original ID
-		origID := d.vertices[item.vHash]
+		origID := d.ids.id(item.vHash)
 
 		// Add the vertex if not already visited
 		newID, exists := visited[item.vHash]
 		if !exists {
 			newID = origID
-			if err := newDAG.AddVertexByID(newID, d.vertexValues[origID]); err != nil {
+			if err := newDAG.AddVertexByID(newID, d.value(origID)); err != nil {
 				return nil, "", err
 			}
 			visited[item.vHash] = newID
@@ -1181,10 +1767,14 @@ func (d *GenericDAG[T]) getRelativesGraphByDepthBFS(startID string, maxDepth int
 				return nil, "", err
 			}
 		}
+		// the vertices keep their ids in the new graph
+		if value, exists := d.edgeValues[[2]vertexHandle{d.keyOf(srcID), d.keyOf(dstID)}]; exists {
+			newDAG.setEdgeValue(newDAG.keyOf(srcID), newDAG.keyOf(dstID), value)
+		}
 
 		// Enqueue next level of relatives
 		if item.depth < maxDepth {
-			var relatives map[interface{}]struct{}
+			var relatives map[vertexHandle]struct{}
 			var ok bool
 			if asc {
 				relatives, ok = d.inboundEdge[item.vHash]
@@ -1224,13 +1814,12 @@ func (d *GenericDAG[T]) GetEdgesWithOption(option CopyOption) EdgeList {
 
 	edgeList := NewEdgeList(d.getSize())
 
-	for vHash, children := range d.outboundEdge {
-		srcID := d.vertices[vHash]
-		for childHash := range children {
-			dstID := d.vertices[childHash]
-			edgeList.AddEdge(srcID, dstID)
-		}
-	}
+	d.ids.each(func(srcID string, vHash vertexHandle) bool {
+		d.eachChild(vHash, func(childHash vertexHandle) {
+			edgeList.AddEdge(srcID, d.ids.id(childHash))
+		})
+		return true
+	})
 
 	if option == CopyData {
 		return *edgeList.Copy()
@@ -1238,6 +1827,34 @@ func (d *GenericDAG[T]) GetEdgesWithOption(option CopyOption) EdgeList {
 	return *edgeList
 }
 
+// Edges returns an iterator over all edges, which unlike GetEdges doesn't
+// collect them into a list; with Go 1.23 or later it can be used in a range
+// loop:
+//
+//	for e := range d.Edges() {
+//		...
+//	}
+//
+// The edges are visited in the order of GetEdges. The GenericDAG is
+// read-locked while iterating, so the loop body must not modify it. Breaking
+// out of the loop stops the iteration.
+func (d *GenericDAG[T]) Edges() func(yield func(e GenericEdge) bool) {
+	return func(yield func(e GenericEdge) bool) {
+		d.muDAG.RLock()
+		defer d.muDAG.RUnlock()
+
+		more := true
+		d.ids.each(func(srcID string, vHash vertexHandle) bool {
+			d.eachChild(vHash, func(childHash vertexHandle) {
+				if more {
+					more = yield(GenericEdge{SrcID: srcID, DstID: d.ids.id(childHash)})
+				}
+			})
+			return more
+		})
+	}
+}
+
 // GetVerticesList returns a list of all vertices in the DAG.
 // The returned node list shares data with the DAG for better performance.
 // Use GetVerticesListWithOption(CopyData) for a safe, independent copy.
@@ -1253,9 +1870,10 @@ func (d *GenericDAG[T]) GetVerticesListWithOption(option CopyOption) NodeList[T]
 
 	nodeList := NewNodeList[T](d.getOrder())
 
-	for id, value := range d.vertexValues {
-		nodeList.AddNode(id, value)
-	}
+	d.ids.each(func(id string, h vertexHandle) bool {
+		nodeList.AddNode(id, d.values[h])
+		return true
+	})
 
 	if option == CopyData {
 		return *nodeList.Copy()
@@ -1273,7 +1891,7 @@ func (d *GenericDAG[T]) GetEdgesByDepth(rootID string, minDepth, maxDepth int) (
 	if rootID == "" {
 		return EdgeList{}, IDEmptyError{}
 	}
-	if _, exists := d.vertexValues[rootID]; !exists {
+	if _, exists := d.ids.handle(rootID); !exists {
 		return EdgeList{}, IDUnknownError{rootID}
 	}
 	if minDepth < 0 {
@@ -1287,13 +1905,13 @@ func (d *GenericDAG[T]) GetEdgesByDepth(rootID string, minDepth, maxDepth int) (
 
 	// Use BFS to collect edges at specified depths
 	type queueItem struct {
-		vHash interface{}
+		vHash vertexHandle
 		depth int
 	}
 
-	queue := []queueItem{{vHash: d.hashVertex(d.vertexValues[rootID]), depth: 0}}
-	visited := make(map[interface{}]struct{})
-	visited[d.hashVertex(d.vertexValues[rootID])] = struct{}{}
+	queue := []queueItem{{vHash: d.keyOf(rootID), depth: 0}}
+	visited := make(map[vertexHandle]struct{})
+	visited[d.keyOf(rootID)] = struct{}{}
 
 	for len(queue) > 0 {
 		item := queue[0]
@@ -1309,8 +1927,8 @@ func (d *GenericDAG[T]) GetEdgesByDepth(rootID string, minDepth, maxDepth int) (
 		targetDepth := item.depth + 1
 		if targetDepth >= minDepth && (maxDepth < 0 || targetDepth <= maxDepth) {
 			for childHash := range d.outboundEdge[item.vHash] {
-				srcID := d.vertices[item.vHash]
-				dstID := d.vertices[childHash]
+				srcID := d.ids.id(item.vHash)
+				dstID := d.ids.id(childHash)
 				edgeList.AddEdge(srcID, dstID)
 			}
 		}
@@ -2,7 +2,12 @@ package dag
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
 	"sync"
 	"testing"
 )
@@ -165,6 +170,117 @@ func TestGenericDAG_GetVertexEmptyID(t *testing.T) {
 	}
 }
 
+// TestGenericDAG_GetVerticesByIDs tests looking up several vertices at once
+func TestGenericDAG_GetVerticesByIDs(t *testing.T) {
+	dag := NewGenericDAG[int]()
+	for i := 0; i < 3; i++ {
+		_ = dag.AddVertexByID(strconv.Itoa(i), i)
+	}
+
+	found, missing := dag.GetVerticesByIDs([]string{"2", "x", "0", "", "2"})
+	if want := map[string]int{"0": 0, "2": 2}; !reflect.DeepEqual(found, want) {
+		t.Errorf("GetVerticesByIDs() found %v, want %v", found, want)
+	}
+	if want := []string{"x", ""}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("GetVerticesByIDs() missing %v, want %v", missing, want)
+	}
+	if found, missing := dag.GetVerticesByIDs(nil); len(found) != 0 || len(missing) != 0 {
+		t.Errorf("GetVerticesByIDs(nil) = %v, %v, want none", found, missing)
+	}
+}
+
+// TestGenericDAG_GetVerticesPage tests paging through the vertices
+func TestGenericDAG_GetVerticesPage(t *testing.T) {
+	for _, ordered := range []bool{false, true} {
+		var opts []Option
+		if ordered {
+			opts = append(opts, WithInsertionOrder())
+		}
+		dag := NewGenericDAG[int](opts...)
+		for i := 9; i >= 0; i-- {
+			_ = dag.AddVertexByID(strconv.Itoa(i), i)
+		}
+		_ = dag.DeleteVertex("5")
+
+		for _, sortByID := range []bool{false, true} {
+			var all []GenericStorableVertex[int]
+			for offset := 0; ; offset += 4 {
+				page := dag.GetVerticesPage(offset, 4, sortByID)
+				if len(page) == 0 {
+					break
+				}
+				all = append(all, page...)
+			}
+			if len(all) != 9 {
+				t.Fatalf("ordered %v, sortByID %v: got %d vertices, want 9", ordered, sortByID, len(all))
+			}
+			seen := make(map[string]bool)
+			for i, v := range all {
+				if v.ID != strconv.Itoa(v.Value) || seen[v.ID] {
+					t.Errorf("ordered %v, sortByID %v: unexpected vertex %v", ordered, sortByID, v)
+				}
+				seen[v.ID] = true
+				if i == 0 {
+					continue
+				}
+				if sortByID && all[i-1].ID > v.ID {
+					t.Errorf("ordered %v: page not sorted by id: %v", ordered, all)
+				}
+				if ordered && !sortByID && all[i-1].Value < v.Value {
+					t.Errorf("page not in insertion order: %v", all)
+				}
+			}
+		}
+
+		if page := dag.GetVerticesPage(8, 4, true); len(page) != 1 || page[0].ID != "9" {
+			t.Errorf("GetVerticesPage(8, 4, true) = %v, want [9]", page)
+		}
+		if page := dag.GetVerticesPage(0, 0, false); len(page) != 0 {
+			t.Errorf("GetVerticesPage(0, 0, false) = %v, want none", page)
+		}
+		if page := dag.GetVerticesPage(-1, 2, true); len(page) != 2 || page[0].ID != "0" {
+			t.Errorf("GetVerticesPage(-1, 2, true) = %v, want the first page", page)
+		}
+	}
+}
+
+// TestGenericDAG_Rehash tests rehashing vertices with externally modified values
+func TestGenericDAG_Rehash(t *testing.T) {
+	type item struct{ name string }
+	dag := NewGenericDAG[*item](WithTypedHashFunc(func(v *item) interface{} { return v.name }))
+	a, b := &item{"a"}, &item{"b"}
+	_ = dag.AddVertexByID("a", a)
+	_ = dag.AddVertexByID("b", b)
+
+	a.name = "c"
+	if err := dag.Rehash("a"); err != nil {
+		t.Fatalf("Rehash failed: %v", err)
+	}
+	if err := dag.AddVertexByID("x", &item{"a"}); err != nil {
+		t.Errorf("adding the old value after Rehash failed: %v", err)
+	}
+	if err := dag.AddVertexByID("y", &item{"c"}); err == nil {
+		t.Error("Expected VertexDuplicateError for the new value after Rehash")
+	}
+
+	b.name = "c"
+	if _, ok := dag.Rehash("b").(VertexDuplicateError); !ok {
+		t.Errorf("Expected VertexDuplicateError when rehashing to a known value")
+	}
+	if _, ok := dag.Rehash("").(IDEmptyError); !ok {
+		t.Errorf("Expected IDEmptyError")
+	}
+	if _, ok := dag.Rehash("z").(IDUnknownError); !ok {
+		t.Errorf("Expected IDUnknownError")
+	}
+
+	// the new hash is removed along with the vertex
+	_ = dag.DeleteVertex("a")
+	if err := dag.AddVertexByID("y", &item{"c"}); err != nil {
+		t.Errorf("adding the value of a deleted vertex failed: %v", err)
+	}
+}
+
 // TestGenericDAG_DeleteVertex tests vertex deletion
 func TestGenericDAG_DeleteVertex(t *testing.T) {
 	dag := NewGenericDAG[string]()
@@ -334,6 +450,37 @@ func TestGenericDAG_AddEdgeSameSrcDst(t *testing.T) {
 	}
 }
 
+// TestGenericDAG_EnsureEdge tests idempotent edge addition
+func TestGenericDAG_EnsureEdge(t *testing.T) {
+	dag := NewGenericDAG[string]()
+	_ = dag.AddVertexByID("v1", "value1")
+	_ = dag.AddVertexByID("v2", "value2")
+
+	added, err := dag.EnsureEdge("v1", "v2")
+	if err != nil || !added {
+		t.Fatalf("EnsureEdge() = %v, %v, want true, nil", added, err)
+	}
+
+	added, err = dag.EnsureEdge("v1", "v2")
+	if err != nil || added {
+		t.Errorf("EnsureEdge() on existing edge = %v, %v, want false, nil", added, err)
+	}
+
+	if dag.GetSize() != 1 {
+		t.Errorf("GetSize() = %d, want 1", dag.GetSize())
+	}
+
+	_, err = dag.EnsureEdge("v2", "v1")
+	if _, ok := err.(EdgeLoopError); !ok {
+		t.Errorf("EnsureEdge() expected EdgeLoopError, got %T", err)
+	}
+
+	_, err = dag.EnsureEdge("v1", "unknown")
+	if _, ok := err.(IDUnknownError); !ok {
+		t.Errorf("EnsureEdge() expected IDUnknownError, got %T", err)
+	}
+}
+
 // TestGenericDAG_IsEdge tests edge existence check
 func TestGenericDAG_IsEdge(t *testing.T) {
 	dag := NewGenericDAG[string]()
@@ -604,6 +751,42 @@ func TestGenericDAG_GetChildren(t *testing.T) {
 	}
 }
 
+// TestGenericDAG_GetRelativesIDs tests getting the ids of children and parents
+func TestGenericDAG_GetRelativesIDs(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"default":         nil,
+		"insertion order": {WithInsertionOrder()},
+		"sorted children": {WithSortedChildren()},
+	} {
+		dag := NewGenericDAG[string](opts...)
+		for _, id := range []string{"m", "c", "z", "a"} {
+			_ = dag.AddVertexByID(id, id)
+		}
+		_ = dag.AddEdge("m", "z")
+		_ = dag.AddEdge("m", "a")
+		_ = dag.AddEdge("c", "a")
+
+		wantChildren, wantParents := []string{"a", "z"}, []string{"c", "m"}
+		if name == "insertion order" {
+			wantChildren, wantParents = []string{"z", "a"}, []string{"m", "c"}
+		}
+		children, err := dag.ChildrenIDs("m")
+		if err != nil || !reflect.DeepEqual(children, wantChildren) {
+			t.Errorf("%s: ChildrenIDs(m) = %v, %v, want %v", name, children, err, wantChildren)
+		}
+		parents, err := dag.ParentsIDs("a")
+		if err != nil || !reflect.DeepEqual(parents, wantParents) {
+			t.Errorf("%s: ParentsIDs(a) = %v, %v, want %v", name, parents, err, wantParents)
+		}
+		if ids, _ := dag.ChildrenIDs("a"); len(ids) != 0 {
+			t.Errorf("%s: ChildrenIDs(a) = %v, want none", name, ids)
+		}
+		if _, err := dag.ParentsIDs("x"); err == nil {
+			t.Errorf("%s: ParentsIDs with unknown id expected error", name)
+		}
+	}
+}
+
 // TestGenericDAG_GetAncestors tests getting ancestor vertices
 func TestGenericDAG_GetAncestors(t *testing.T) {
 	dag := NewGenericDAG[string]()
@@ -802,6 +985,173 @@ func TestGenericDAG_GetDescendantsGraph(t *testing.T) {
 	}
 }
 
+// TestGenericDAG_GetRelativesGraphIDs tests that subgraphs keep the ids of
+// the original graph unless they are mapped
+func TestGenericDAG_GetRelativesGraphIDs(t *testing.T) {
+	/*   a   b
+	 *    \ /
+	 *     c
+	 *    / \
+	 *   d   e
+	 */
+	dag := NewGenericDAG[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		_ = dag.AddVertexByID(id, "value "+id)
+	}
+	edges := [][2]string{{"a", "c"}, {"b", "c"}, {"c", "d"}, {"c", "e"}}
+	for _, e := range edges {
+		_ = dag.AddEdge(e[0], e[1])
+	}
+
+	descendants, _, _ := dag.GetDescendantsGraph("c")
+	ancestors, _, _ := dag.GetAncestorsGraph("c")
+	for name, sub := range map[string]*GenericDAG[string]{"descendants": descendants, "ancestors": ancestors} {
+		for id, v := range sub.GetVertices() {
+			if want, _ := dag.GetVertex(id); v != want {
+				t.Errorf("%s: vertex %s = %q, want %q", name, id, v, want)
+			}
+		}
+		for _, e := range edges {
+			_, srcErr := sub.GetVertex(e[0])
+			_, dstErr := sub.GetVertex(e[1])
+			if isEdge, _ := sub.IsEdge(e[0], e[1]); srcErr == nil && dstErr == nil && !isEdge {
+				t.Errorf("%s: missing edge %s -> %s", name, e[0], e[1])
+			}
+		}
+	}
+
+	prefix := func(id string) string { return "sub/" + id }
+	mapped, newID, err := dag.GetDescendantsGraphMapped("c", prefix)
+	if err != nil {
+		t.Fatalf("GetDescendantsGraphMapped failed: %v", err)
+	}
+	if newID != "sub/c" {
+		t.Errorf("GetDescendantsGraphMapped() id = %s, want sub/c", newID)
+	}
+	want := map[string]string{"sub/c": "value c", "sub/d": "value d", "sub/e": "value e"}
+	if got := mapped.GetVertices(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDescendantsGraphMapped() vertices = %v, want %v", got, want)
+	}
+	if isEdge, _ := mapped.IsEdge("sub/c", "sub/e"); !isEdge || mapped.GetSize() != 2 {
+		t.Errorf("GetDescendantsGraphMapped() edges = %v", mapped.GetEdges())
+	}
+
+	mapped, newID, err = dag.GetAncestorsGraphMapped("c", prefix)
+	if err != nil || newID != "sub/c" || mapped.GetOrder() != 3 {
+		t.Errorf("GetAncestorsGraphMapped() = %v, %s, %v", mapped, newID, err)
+	}
+	if isEdge, _ := mapped.IsEdge("sub/a", "sub/c"); !isEdge {
+		t.Errorf("GetAncestorsGraphMapped() misses edge sub/a -> sub/c")
+	}
+
+	_, _, err = dag.GetDescendantsGraphMapped("c", func(string) string { return "x" })
+	if _, ok := err.(IDDuplicateError); !ok {
+		t.Errorf("GetDescendantsGraphMapped() with colliding ids: err = %T, want IDDuplicateError", err)
+	}
+}
+
+// TestGenericDAG_GetRelativesGraphInto tests merging subgraphs into a target
+func TestGenericDAG_GetRelativesGraphInto(t *testing.T) {
+	/*     a   e
+	 *     |   |
+	 *     b   f
+	 *    / \ /
+	 *   c   d
+	 */
+	dag := NewGenericDAG[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		_ = dag.AddVertexByID(id, id)
+	}
+	_ = dag.AddEdge("a", "b")
+	_ = dag.AddEdge("b", "c")
+	_ = dag.AddEdge("b", "d")
+	_ = dag.AddEdge("e", "f")
+	_ = dag.AddEdge("f", "d")
+
+	target := NewGenericDAG[string]()
+	if err := dag.GetDescendantsGraphInto("b", target); err != nil {
+		t.Fatalf("GetDescendantsGraphInto failed: %v", err)
+	}
+	if target.GetOrder() != 3 || target.GetSize() != 2 {
+		t.Errorf("order = %d, size = %d, want 3, 2", target.GetOrder(), target.GetSize())
+	}
+
+	// d and b->d are already known to target
+	if err := dag.GetAncestorsGraphInto("d", target); err != nil {
+		t.Fatalf("GetAncestorsGraphInto failed: %v", err)
+	}
+	if target.GetOrder() != 6 || target.GetSize() != 5 {
+		t.Errorf("order = %d, size = %d, want 6, 5", target.GetOrder(), target.GetSize())
+	}
+	if v, _ := target.GetVertex("e"); v != "e" {
+		t.Errorf("GetVertex(e) = %q, want %q", v, "e")
+	}
+
+	// the merged edges would close a loop in the target
+	loop := NewGenericDAG[string]()
+	_ = loop.AddVertexByID("c", "c")
+	_ = loop.AddVertexByID("a", "a")
+	_ = loop.AddEdge("c", "a")
+	if err := dag.GetAncestorsGraphInto("c", loop); err == nil {
+		t.Error("GetAncestorsGraphInto expected EdgeLoopError, got nil")
+	} else if _, ok := err.(EdgeLoopError); !ok {
+		t.Errorf("GetAncestorsGraphInto = %T, want EdgeLoopError", err)
+	}
+
+	if err := dag.GetDescendantsGraphInto("x", target); err == nil {
+		t.Error("GetDescendantsGraphInto with unknown id expected error")
+	}
+}
+
+// TestGenericDAG_GetDescendantsVia tests descendants only reachable via a vertex
+func TestGenericDAG_GetDescendantsVia(t *testing.T) {
+	/*       r
+	 *      / \
+	 *     v   x
+	 *    / \ /
+	 *   a   b
+	 *   |   |
+	 *   c   d
+	 */
+	dag := NewGenericDAG[string]()
+	for _, id := range []string{"r", "v", "x", "a", "b", "c", "d"} {
+		_ = dag.AddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"r", "v"}, {"r", "x"}, {"v", "a"}, {"v", "b"}, {"x", "b"}, {"a", "c"}, {"b", "d"}} {
+		_ = dag.AddEdge(e[0], e[1])
+	}
+
+	tests := []struct {
+		root, via string
+		want      []string
+	}{
+		{"r", "v", []string{"a", "c"}},
+		{"r", "x", nil},
+		{"r", "r", []string{"a", "b", "c", "d", "v", "x"}},
+		{"v", "b", []string{"d"}},
+		{"x", "a", nil},
+	}
+	for _, tt := range tests {
+		got, err := dag.GetDescendantsVia(tt.root, tt.via)
+		if err != nil {
+			t.Errorf("GetDescendantsVia(%s, %s) unexpected error: %v", tt.root, tt.via, err)
+			continue
+		}
+		var ids []string
+		for id := range got {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("GetDescendantsVia(%s, %s) = %v, want %v", tt.root, tt.via, ids, tt.want)
+		}
+	}
+
+	if _, err := dag.GetDescendantsVia("r", "unknown"); err == nil {
+		t.Error("GetDescendantsVia with unknown id expected error")
+	}
+}
+
 // TestGenericDAG_GetAncestorsGraph tests getting ancestors subgraph
 func TestGenericDAG_GetAncestorsGraph(t *testing.T) {
 	dag := NewGenericDAG[string]()
@@ -1553,6 +1903,78 @@ func TestGenericDAG_ReduceTransitively(t *testing.T) {
 	}
 }
 
+// TestGenericDAG_ReduceTransitivelyProperties compares the reduction of
+// random graphs to the edges that aren't implied by any other path, for
+// GenericDAG with and without cache and for DAG.
+func TestGenericDAG_ReduceTransitivelyProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for round := 0; round < 50; round++ {
+		n := 2 + r.Intn(20)
+		var edges []GenericEdge
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if r.Intn(3) == 0 {
+					edges = append(edges, GenericEdge{SrcID: strconv.Itoa(i), DstID: strconv.Itoa(j)})
+				}
+			}
+		}
+
+		// hashing values while reducing would fail the test
+		frozen := false
+		hash := func(v interface{}) interface{} {
+			if frozen {
+				t.Error("ReduceTransitively() hashed a value")
+			}
+			return v
+		}
+		generic := NewGenericDAG[int](WithHashFunc(hash))
+		uncached := NewGenericDAG[int](WithHashFunc(hash), WithoutCache())
+		legacy := NewDAG(WithHashFunc(hash))
+		for i := 0; i < n; i++ {
+			generic.MustAddVertexByID(strconv.Itoa(i), i)
+			uncached.MustAddVertexByID(strconv.Itoa(i), i)
+			_ = legacy.AddVertexByID(strconv.Itoa(i), i)
+		}
+		for _, e := range edges {
+			generic.MustAddEdge(e.SrcID, e.DstID)
+			uncached.MustAddEdge(e.SrcID, e.DstID)
+			_ = legacy.AddEdge(e.SrcID, e.DstID)
+		}
+
+		// an edge is kept, iff dst isn't a descendant of another child of src
+		var want []GenericEdge
+		for _, e := range edges {
+			children, _ := generic.GetChildren(e.SrcID)
+			implied := false
+			for child := range children {
+				descendants, _ := generic.GetDescendants(child)
+				if _, exists := descendants[e.DstID]; exists {
+					implied = true
+				}
+			}
+			if !implied {
+				want = append(want, e)
+			}
+		}
+		want = sortedEdges(EdgeList{Edges: want})
+
+		frozen = true
+		generic.ReduceTransitively()
+		uncached.ReduceTransitively()
+		legacy.ReduceTransitively()
+		frozen = false
+		for name, got := range map[string][]GenericEdge{
+			"GenericDAG":         sortedEdges(generic.GetEdges()),
+			"GenericDAG (no cache)": sortedEdges(uncached.GetEdges()),
+			"DAG":                sortedEdges(legacy.GetEdges()),
+		} {
+			if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Fatalf("round %d: %s reduced to %v, want %v", round, name, got, want)
+			}
+		}
+	}
+}
+
 // TestGenericDAG_Options tests custom options
 func TestGenericDAG_Options(t *testing.T) {
 	type Person struct {
@@ -1632,4 +2054,253 @@ func TestGenericDAG_String(t *testing.T) {
 	if len(s) < 10 {
 		t.Error("String() returned too short string")
 	}
-}
\ No newline at end of file
+}
+
+// TestGenericDAG_GetOrderedRelativesWithValues tests the ordered relatives
+// that come with their values
+func TestGenericDAG_GetOrderedRelativesWithValues(t *testing.T) {
+	// keep the order of relatives stable between calls
+	dag := NewGenericDAG[int](WithInsertionOrder())
+	for i, id := range []string{"a", "b", "c", "d"} {
+		dag.MustAddVertexByID(id, i)
+	}
+	dag.MustAddEdge("a", "b")
+	dag.MustAddEdge("a", "c")
+	dag.MustAddEdge("b", "d")
+	dag.MustAddEdge("c", "d")
+
+	check := func(name string, got []GenericStorableVertex[int], ids []string) {
+		t.Helper()
+		if len(got) != len(ids) {
+			t.Fatalf("%s returned %v, want the ids %v", name, got, ids)
+		}
+		for i, v := range got {
+			value, _ := dag.GetVertex(v.ID)
+			if v.ID != ids[i] || v.Value != value {
+				t.Errorf("%s[%d] = %v, want %s with value %d", name, i, v, ids[i], value)
+			}
+		}
+	}
+
+	descendants, err := dag.GetOrderedDescendantsWithValues("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, _ := dag.GetOrderedDescendants("a")
+	if len(ids) != 3 || ids[2] != "d" {
+		t.Fatalf("GetOrderedDescendants(a) = %v, want d last", ids)
+	}
+	check("GetOrderedDescendantsWithValues(a)", descendants, ids)
+
+	ancestors, err := dag.GetOrderedAncestorsWithValues("d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, _ = dag.GetOrderedAncestors("d")
+	check("GetOrderedAncestorsWithValues(d)", ancestors, ids)
+
+	if _, err := dag.GetOrderedDescendantsWithValues("x"); err == nil {
+		t.Error("Expected error for unknown id")
+	}
+	if _, err := dag.GetOrderedAncestorsWithValues(""); err == nil {
+		t.Error("Expected error for empty id")
+	}
+}
+
+func TestGenericDAG_AddEdgeLoopCached(t *testing.T) {
+	d := NewGenericDAG[int]()
+	for i, id := range []string{"1", "2", "3", "4"} {
+		_ = d.AddVertexByID(id, i)
+	}
+	_ = d.AddEdge("1", "2")
+	_ = d.AddEdge("2", "3")
+
+	// populate the descendants cache of 1 and the ancestors cache of 3
+	_, _ = d.GetDescendants("1")
+	_, _ = d.GetAncestors("3")
+
+	if err := d.AddEdge("3", "1"); err == nil {
+		t.Error("AddEdge(3, 1) expected EdgeLoopError, got nil")
+	} else if _, ok := err.(EdgeLoopError); !ok {
+		t.Errorf("AddEdge(3, 1) = %T, want EdgeLoopError", err)
+	}
+	if err := d.AddEdge("3", "4"); err != nil {
+		t.Errorf("AddEdge(3, 4) unexpected error: %v", err)
+	}
+
+	// the caches must be up to date after adding an edge
+	_, _ = d.GetDescendants("1")
+	if err := d.AddEdge("4", "1"); err == nil {
+		t.Error("AddEdge(4, 1) expected EdgeLoopError, got nil")
+	}
+	if err := d.DeleteEdge("3", "4"); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = d.GetAncestors("4")
+	if err := d.AddEdge("4", "1"); err != nil {
+		t.Errorf("AddEdge(4, 1) unexpected error: %v", err)
+	}
+}
+
+func TestGenericDAG_RootsAndLeavesIndex(t *testing.T) {
+	d := NewGenericDAG[int](WithSortedChildren())
+	rnd := rand.New(rand.NewSource(1))
+	ids := make([]string, 30)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+
+	for step := 0; step < 2000; step++ {
+		src, dst := ids[rnd.Intn(len(ids))], ids[rnd.Intn(len(ids))]
+		switch rnd.Intn(5) {
+		case 0:
+			_ = d.AddVertexByID(src, step)
+		case 1:
+			_ = d.DeleteVertex(src)
+		case 2:
+			_ = d.DeleteEdge(src, dst)
+		case 3:
+			d.ReduceTransitively()
+		default:
+			_ = d.AddEdge(src, dst)
+		}
+
+		for id := range d.GetVertices() {
+			parents, _ := d.GetParents(id)
+			children, _ := d.GetChildren(id)
+			if _, isRoot := d.GetRoots()[id]; isRoot != (len(parents) == 0) {
+				t.Fatalf("step %d: vertex %s in roots = %v with %d parents", step, id, isRoot, len(parents))
+			}
+			if _, isLeaf := d.GetLeaves()[id]; isLeaf != (len(children) == 0) {
+				t.Fatalf("step %d: vertex %s in leaves = %v with %d children", step, id, isLeaf, len(children))
+			}
+		}
+		if len(d.GetRoots()) > d.GetOrder() || len(d.GetLeaves()) > d.GetOrder() {
+			t.Fatalf("step %d: %d roots and %d leaves of %d vertices", step, len(d.GetRoots()), len(d.GetLeaves()), d.GetOrder())
+		}
+	}
+}
+
+func TestGenericDAG_Edges(t *testing.T) {
+	d := NewGenericDAG[int](WithInsertionOrder())
+	for i := 0; i < 4; i++ {
+		_ = d.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = d.AddEdge("0", "1")
+	_ = d.AddEdge("0", "3")
+	_ = d.AddEdge("2", "1")
+
+	var edges []GenericEdge
+	d.Edges()(func(e GenericEdge) bool {
+		edges = append(edges, e)
+		return true
+	})
+	if want := d.GetEdges().Edges; !reflect.DeepEqual(edges, want) {
+		t.Errorf("Edges() = %v, want %v", edges, want)
+	}
+
+	visits := 0
+	d.Edges()(func(GenericEdge) bool {
+		visits++
+		return false
+	})
+	if visits != 1 {
+		t.Errorf("Edges() visited %d edges after stopping, want 1", visits)
+	}
+}
+
+func TestGenericDAG_AddVerticesBatch(t *testing.T) {
+	d := NewGenericDAG[int]()
+	d.MustAddVertexByID("b", 2)
+
+	err := d.AddVerticesBatch(map[string]int{"a": 1, "b": 20, "c": 3, "d": 1})
+	var multi MultiError
+	if !errors.As(err, &multi) || len(multi.Errors()) != 2 {
+		t.Fatalf("AddVerticesBatch() = %v, want a MultiError of 2 errors", err)
+	}
+	errs := multi.Errors()
+	if !errors.As(errs[0], new(IDDuplicateError)) || !errors.As(errs[1], new(VertexDuplicateError)) {
+		t.Errorf("AddVerticesBatch() = %v, want errors for b and d", err)
+	}
+	if d.GetOrder() != 3 {
+		t.Errorf("GetOrder() = %d, want 3", d.GetOrder())
+	}
+	if v, _ := d.GetVertex("b"); v != 2 {
+		t.Errorf("GetVertex(b) = %d, want 2", v)
+	}
+
+	if err := d.AddVerticesBatch(map[string]int{"e": 5, "f": 6}); err != nil {
+		t.Errorf("AddVerticesBatch() = %v, want nil", err)
+	}
+}
+
+func TestGenericDAG_RootsAndLeavesIter(t *testing.T) {
+	d := NewGenericDAG[int]()
+	for i := 0; i < 4; i++ {
+		_ = d.AddVertexByID(strconv.Itoa(i), i)
+	}
+	_ = d.AddEdge("0", "1")
+	_ = d.AddEdge("2", "1")
+
+	collect := func(seq func(yield func(string, int) bool)) map[string]int {
+		vertices := make(map[string]int)
+		seq(func(id string, v int) bool {
+			vertices[id] = v
+			return true
+		})
+		return vertices
+	}
+	if got := collect(d.RootsIter()); !reflect.DeepEqual(got, d.GetRoots()) {
+		t.Errorf("RootsIter() = %v, want %v", got, d.GetRoots())
+	}
+	if got := collect(d.LeavesIter()); !reflect.DeepEqual(got, d.GetLeaves()) {
+		t.Errorf("LeavesIter() = %v, want %v", got, d.GetLeaves())
+	}
+
+	visits := 0
+	d.RootsIter()(func(string, int) bool {
+		visits++
+		return false
+	})
+	if visits != 1 {
+		t.Errorf("RootsIter() visited %d roots after stopping, want 1", visits)
+	}
+}
+
+func TestGenericDAG_RootsAndLeavesVersion(t *testing.T) {
+	d := NewGenericDAG[int]()
+	roots, leaves := d.RootsVersion(), d.LeavesVersion()
+	check := func(step string, rootsChanged, leavesChanged bool) {
+		t.Helper()
+		if got := d.RootsVersion() != roots; got != rootsChanged {
+			t.Errorf("%s: roots changed = %v, want %v", step, got, rootsChanged)
+		}
+		if got := d.LeavesVersion() != leaves; got != leavesChanged {
+			t.Errorf("%s: leaves changed = %v, want %v", step, got, leavesChanged)
+		}
+		roots, leaves = d.RootsVersion(), d.LeavesVersion()
+	}
+
+	_ = d.AddVertexByID("a", 1)
+	check("AddVertexByID(a)", true, true)
+	_ = d.AddVertexByID("b", 2)
+	_ = d.AddVertexByID("c", 3)
+	check("AddVertexByID(b, c)", true, true)
+	_ = d.AddEdge("a", "b")
+	check("AddEdge(a, b)", true, true)
+	_ = d.AddEdge("c", "b")
+	check("AddEdge(c, b)", false, true)
+	_ = d.AddEdge("a", "c")
+	check("AddEdge(a, c)", true, false)
+	_ = d.AddEdge("a", "c")
+	check("AddEdge(a, c) again", false, false)
+	_ = d.DeleteEdge("a", "b")
+	check("DeleteEdge(a, b)", false, false)
+	_ = d.DeleteEdge("c", "b")
+	check("DeleteEdge(c, b)", true, true)
+	_, _ = d.GetDescendants("a")
+	d.ReduceTransitively()
+	check("read-only calls", false, false)
+	_ = d.DeleteVertex("a")
+	check("DeleteVertex(a)", true, false)
+}
@@ -1,6 +1,9 @@
 package dag
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // GenericStorableVertex represents a vertex for serialization.
 type GenericStorableVertex[T any] struct {
@@ -10,8 +13,9 @@ type GenericStorableVertex[T any] struct {
 
 // GenericStorableDAG represents a DAG for serialization.
 type GenericStorableDAG[T any] struct {
-	Vertices []GenericStorableVertex[T] `json:"vs"`
-	Edges    []GenericEdge              `json:"es"`
+	Vertices   []GenericStorableVertex[T] `json:"vs"`
+	Edges      []GenericEdge              `json:"es"`
+	EdgeValues []GenericEdgeValue         `json:"ev,omitempty"`
 }
 
 // GenericEdge represents an edge for serialization.
@@ -61,15 +65,17 @@ func (mv *GenericMarshalVisitor[T]) AddEdges(parentID string, children map[strin
 func (d *GenericDAG[T]) MarshalJSON() ([]byte, error) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
+	return d.marshalJSON()
+}
 
+func (d *GenericDAG[T]) marshalJSON() ([]byte, error) {
 	order := d.getOrder()
 	size := d.getSize()
 	visitor := NewGenericMarshalVisitor[T](order, size)
 
 	// DFS walk to collect vertices and edges
 	stack := make([]string, 0, size)
-	vertices := d.getRoots()
-	ids := vertexIDsGeneric(vertices)
+	ids := d.orderedRelativeIDs(d.roots)
 	for i := len(ids) - 1; i >= 0; i-- {
 		stack = append(stack, ids[i])
 	}
@@ -83,12 +89,13 @@ func (d *GenericDAG[T]) MarshalJSON() ([]byte, error) {
 
 		if !visited[id] {
 			visited[id] = true
-			visitor.Visit(d.vertexValues[id], id)
+			visitor.Visit(d.value(id), id)
 		}
 
-		children, _ := d.getChildren(id)
-		visitor.AddEdges(id, convertToInterfaceMap(children))
-		childIDs := vertexIDsGeneric(children)
+		childIDs := d.childIDs(id)
+		for _, childID := range childIDs {
+			visitor.edges = append(visitor.edges, GenericEdge{SrcID: id, DstID: childID})
+		}
 		for i := len(childIDs) - 1; i >= 0; i-- {
 			childID := childIDs[i]
 			if !visited[childID] {
@@ -97,9 +104,27 @@ func (d *GenericDAG[T]) MarshalJSON() ([]byte, error) {
 		}
 	}
 
-	dag := GenericStorableDAG[T]{
-		Vertices: visitor.vertices,
-		Edges:    visitor.edges,
+	if d.options.Codecs == nil {
+		dag := GenericStorableDAG[T]{
+			Vertices:   visitor.vertices,
+			Edges:      visitor.edges,
+			EdgeValues: d.edgeValueList(),
+		}
+		return json.Marshal(dag)
+	}
+
+	// replace values that have a codec by their encoding
+	dag := GenericStorableDAG[interface{}]{
+		Vertices:   make([]GenericStorableVertex[interface{}], len(visitor.vertices)),
+		Edges:      visitor.edges,
+		EdgeValues: d.edgeValueList(),
+	}
+	for i, v := range visitor.vertices {
+		value, err := encodeValue(d.options.Codecs, v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("vertex '%s': %w", v.ID, err)
+		}
+		dag.Vertices[i] = GenericStorableVertex[interface{}]{ID: v.ID, Value: value}
 	}
 	return json.Marshal(dag)
 }
@@ -121,40 +146,73 @@ func (d *GenericDAG[T]) MarshalJSON() ([]byte, error) {
 //	    Age  int    `json:"age"`
 //	}
 //	dag, err := dag.UnmarshalGenericJSON[Person](data, dag.Options{})
-func UnmarshalGenericJSON[T any](data []byte, options Options) (*GenericDAG[T], error) {
-	var dag GenericStorableDAG[T]
-	if err := json.Unmarshal(data, &dag); err != nil {
+//
+// The options may also be given as OptionsT[T], e.g. to validate the decoded
+// vertices with a typed VertexValidator.
+//
+// Edges listed more than once make UnmarshalGenericJSON fail, unless
+// Options.DuplicateEdges says otherwise. Under DuplicateEdgesWarn, the graph
+// is returned along with a DuplicateEdgesWarning.
+func UnmarshalGenericJSON[T any, O Options | OptionsT[T]](data []byte, opts O) (*GenericDAG[T], error) {
+	options := optionsOf[T](opts)
+	dag, err := decodeStorableDAG[T](data, options.Codecs)
+	if err != nil {
 		return nil, err
 	}
-
-	g := NewGenericDAG[T]()
-	if options.VertexHashFunc != nil {
-		g.Options(options)
+	if err := validateVertices(len(dag.Vertices), func(i int) (string, interface{}) {
+		return dag.Vertices[i].ID, dag.Vertices[i].Value
+	}, options.VertexValidator); err != nil {
+		return nil, err
 	}
 
-	// Add all vertices
+	g := NewGenericDAG[T](WithOptions(options))
+
+	// Batch add vertices
+	g.muDAG.Lock()
 	for _, v := range dag.Vertices {
-		if err := g.AddVertexByID(v.ID, v.Value); err != nil {
+		if err := g.addVertexByID(v.ID, v.Value); err != nil {
+			g.muDAG.Unlock()
 			return nil, err
 		}
 	}
-
-	// Add all edges
-	for _, e := range dag.Edges {
-		if err := g.AddEdge(e.SrcID, e.DstID); err != nil {
-			return nil, err
-		}
+	g.muDAG.Unlock()
+
+	// Batch add edges
+	edges := dag.Edges
+	errEdges := g.addEdgesBatch(len(edges), func(i int) (string, string) {
+		return edges[i].SrcID, edges[i].DstID
+	})
+	if _, warning := errEdges.(DuplicateEdgesWarning); errEdges != nil && !warning {
+		return nil, errEdges
+	}
+	if err := g.setEdgeValues(dag.EdgeValues); err != nil {
+		return nil, err
 	}
 
-	return g, nil
+	// errEdges is nil or a DuplicateEdgesWarning
+	return g, errEdges
 }
 
-// convertToInterfaceMap is a helper to convert map[string]T to map[string]interface{}
-// for compatibility with AddEdges method.
-func convertToInterfaceMap[T any](m map[string]T) map[string]interface{} {
-	result := make(map[string]interface{}, len(m))
-	for k, v := range m {
-		result[k] = v
+// decodeStorableDAG parses data, decoding vertex values with the given codecs.
+func decodeStorableDAG[T any](data []byte, codecs *CodecRegistry) (GenericStorableDAG[T], error) {
+	var dag GenericStorableDAG[T]
+	if codecs == nil {
+		err := json.Unmarshal(data, &dag)
+		return dag, err
+	}
+	var raw GenericStorableDAG[json.RawMessage]
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return dag, err
 	}
-	return result
-}
\ No newline at end of file
+	dag.Vertices = make([]GenericStorableVertex[T], len(raw.Vertices))
+	for i, v := range raw.Vertices {
+		value, err := decodeValue[T](codecs, v.Value)
+		if err != nil {
+			return dag, fmt.Errorf("vertex '%s': %w", v.ID, err)
+		}
+		dag.Vertices[i] = GenericStorableVertex[T]{ID: v.ID, Value: value}
+	}
+	dag.Edges = raw.Edges
+	dag.EdgeValues = raw.EdgeValues
+	return dag, nil
+}
@@ -1,5 +1,7 @@
 package dag
 
+import "sort"
+
 // GenericVisitor is the interface for visiting generic DAG vertices.
 type GenericVisitor[T any] interface {
 	Visit(value T, id string)
@@ -24,89 +26,102 @@ func (gv *GenericDFSVisitor[T]) Visit(v Vertexer) {
 // GenericDFSWalk implements the Depth-First-Search algorithm to traverse the entire GenericDAG.
 // The algorithm starts at the root node and explores as far as possible
 // along each branch before backtracking.
+// The GenericDAG is read-locked during the walk, so visitor must not modify it.
 func (d *GenericDAG[T]) GenericDFSWalk(visitor GenericVisitor[T]) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
+	d.genericDFSWalk(visitor, nil)
+}
 
-	// Use native slice as stack for better performance
-	stack := make([]string, 0, d.GetSize())
+// genericDFSWalk is GenericDFSWalk without locking. It returns a
+// LimitExceededError before visiting more vertices than budget allows.
+func (d *GenericDAG[T]) genericDFSWalk(visitor GenericVisitor[T], budget *traversalBudget) error {
+	// Use the pooled id slice as stack and the pooled set of visited vertices
+	// to avoid allocating them per walk
+	s := getScratch()
+	defer s.release()
 
-	vertices := d.getRoots()
 	// Push roots in reverse order to maintain consistent traversal order
-	ids := vertexIDsGeneric(vertices)
+	ids := d.orderedRelativeIDs(d.roots)
 	for i := len(ids) - 1; i >= 0; i-- {
-		id := ids[i]
-		stack = append(stack, id)
+		s.ids = append(s.ids, ids[i])
 	}
 
-	visited := make(map[string]bool, d.getSize())
-
-	for len(stack) > 0 {
+	for len(s.ids) > 0 {
 		// Pop from stack
-		idx := len(stack) - 1
-		id := stack[idx]
-		stack = stack[:idx]
+		idx := len(s.ids) - 1
+		id := s.ids[idx]
+		s.ids = s.ids[:idx]
 
-		if !visited[id] {
-			visited[id] = true
-			visitor.Visit(d.vertexValues[id], id)
+		if h := d.keyOf(id); !s.seen(h) {
+			if err := budget.visit(); err != nil {
+				return err
+			}
+			s.visited[h] = struct{}{}
+			visitor.Visit(d.values[h], id)
 		}
 
-		children, _ := d.getChildren(id)
-		childIDs := vertexIDsGeneric(children)
+		childIDs := d.childIDs(id)
 		for i := len(childIDs) - 1; i >= 0; i-- {
 			childID := childIDs[i]
-			if !visited[childID] {
-				stack = append(stack, childID)
+			if !s.seen(d.keyOf(childID)) {
+				s.ids = append(s.ids, childID)
 			}
 		}
 	}
+	return nil
 }
 
 // GenericBFSWalk implements the Breadth-First-Search algorithm to traverse the entire GenericDAG.
 // It starts at the tree root and explores all nodes at the present depth prior
 // to moving on to the nodes at the next depth level.
+// The GenericDAG is read-locked during the walk, so visitor must not modify it.
 func (d *GenericDAG[T]) GenericBFSWalk(visitor GenericVisitor[T]) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
+	d.genericBFSWalk(visitor, nil)
+}
 
-	// Use native slice as queue for better performance
-	queue := make([]string, 0, d.GetSize())
+// genericBFSWalk is GenericBFSWalk without locking. It returns a
+// LimitExceededError before visiting more vertices than budget allows.
+func (d *GenericDAG[T]) genericBFSWalk(visitor GenericVisitor[T], budget *traversalBudget) error {
+	// Use the pooled id slice as queue and the pooled set of visited vertices
+	// to avoid allocating them per walk
+	s := getScratch()
+	defer s.release()
 
-	vertices := d.getRoots()
-	ids := vertexIDsGeneric(vertices)
-	queue = append(queue, ids...)
-
-	visited := make(map[string]bool, d.getOrder())
+	s.ids = append(s.ids, d.orderedRelativeIDs(d.roots)...)
 
-	for len(queue) > 0 {
-		id := queue[0]
-		queue = queue[1:]
+	for head := 0; head < len(s.ids); head++ {
+		id := s.ids[head]
 
-		if !visited[id] {
-			visited[id] = true
-			visitor.Visit(d.vertexValues[id], id)
+		if h := d.keyOf(id); !s.seen(h) {
+			if err := budget.visit(); err != nil {
+				return err
+			}
+			s.visited[h] = struct{}{}
+			visitor.Visit(d.values[h], id)
 		}
 
-		children, _ := d.getChildren(id)
-		childIDs := vertexIDsGeneric(children)
+		childIDs := d.childIDs(id)
 		for _, childID := range childIDs {
-			if !visited[childID] {
-				queue = append(queue, childID)
+			if !s.seen(d.keyOf(childID)) {
+				s.ids = append(s.ids, childID)
 			}
 		}
 	}
+	return nil
 }
 
 // GenericOrderedWalk implements the Topological Sort algorithm to traverse the entire GenericDAG.
 // This means that for any edge a -> b, node a will be visited before node b.
+// The GenericDAG is read-locked during the walk, so visitor must not modify it.
 func (d *GenericDAG[T]) GenericOrderedWalk(visitor GenericVisitor[T]) {
 	d.muDAG.RLock()
 	defer d.muDAG.RUnlock()
 
-	queue := make([]string, 0, d.GetSize())
-	vertices := d.getRoots()
-	ids := vertexIDsGeneric(vertices)
+	queue := make([]string, 0, d.getSize())
+	ids := d.orderedRelativeIDs(d.roots)
 	queue = append(queue, ids...)
 
 	visited := make(map[string]bool, d.getOrder())
@@ -136,11 +151,10 @@ func (d *GenericDAG[T]) GenericOrderedWalk(visitor GenericVisitor[T]) {
 
 		if !visited[id] {
 			visited[id] = true
-			visitor.Visit(d.vertexValues[id], id)
+			visitor.Visit(d.value(id), id)
 		}
 
-		children, _ := d.getChildren(id)
-		childIDs := vertexIDsGeneric(children)
+		childIDs := d.childIDs(id)
 		for _, childID := range childIDs {
 			if !visited[childID] {
 				queue = append(queue, childID)
@@ -149,10 +163,21 @@ func (d *GenericDAG[T]) GenericOrderedWalk(visitor GenericVisitor[T]) {
 	}
 }
 
-func vertexIDsGeneric[T any](vertices map[string]T) []string {
-	ids := make([]string, 0, len(vertices))
-	for id := range vertices {
-		ids = append(ids, id)
+// eachRelative calls f for each vertex of the given set of relatives. The
+// vertices are visited in insertion order, if the GenericDAG preserves it.
+func (d *GenericDAG[T]) eachRelative(relatives map[vertexHandle]struct{}, f func(h vertexHandle)) {
+	if !d.ids.ordered {
+		for h := range relatives {
+			f(h)
+		}
+		return
+	}
+	handles := make([]vertexHandle, 0, len(relatives))
+	for h := range relatives {
+		handles = append(handles, h)
+	}
+	sort.Slice(handles, func(i, j int) bool { return handles[i] < handles[j] })
+	for _, h := range handles {
+		f(h)
 	}
-	return ids
 }
\ No newline at end of file
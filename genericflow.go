@@ -0,0 +1,282 @@
+package dag
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// GenericFlowResult describes the typed data to be passed between vertices in
+// a GenericDescendantsFlow. See FlowResult for details on its fields.
+type GenericFlowResult[R any] struct {
+	ID         string
+	Result     R
+	Error      error
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Attempts   int
+	ParentIDs  []string
+}
+
+// untyped returns r as FlowResult, e.g. to sort it by
+// Options.FlowParentOrder.
+func (r GenericFlowResult[R]) untyped() FlowResult {
+	return FlowResult{
+		ID:         r.ID,
+		Result:     r.Result,
+		Error:      r.Error,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+		Attempts:   r.Attempts,
+		ParentIDs:  r.ParentIDs,
+	}
+}
+
+// genericFlowResults returns results as GenericFlowResults, e.g. to pass the
+// inputs of a DescendantsFlow to GenericDescendantsFlow.
+func genericFlowResults(results []FlowResult) []GenericFlowResult[interface{}] {
+	generic := make([]GenericFlowResult[interface{}], len(results))
+	for i, r := range results {
+		generic[i] = GenericFlowResult[interface{}]{
+			ID:         r.ID,
+			Result:     r.Result,
+			Error:      r.Error,
+			StartedAt:  r.StartedAt,
+			FinishedAt: r.FinishedAt,
+			Attempts:   r.Attempts,
+			ParentIDs:  r.ParentIDs,
+		}
+	}
+	return generic
+}
+
+// untypedFlowResults returns results as FlowResults.
+func untypedFlowResults[R any](results []GenericFlowResult[R]) []FlowResult {
+	untyped := make([]FlowResult, len(results))
+	for i, r := range results {
+		untyped[i] = r.untyped()
+	}
+	return untyped
+}
+
+// GenericFlowCallback is the signature of the function executed for each
+// vertex of a GenericDescendantsFlow, like FlowCallback with typed values and
+// results.
+type GenericFlowCallback[T, R any] func(d *GenericDAG[T], id string, parentResults []GenericFlowResult[R]) (R, error)
+
+// GenericDescendantsFlow is the engine of DescendantsFlow, for GenericDAG and
+// results of type R: for the vertex with startID and each of its descendants,
+// it executes callback providing it the results of its parents, once all of
+// them have finished. The start vertex is provided the inputs. Each vertex
+// runs in its own goroutine. GenericDescendantsFlow returns the results of the
+// leaves of the flow.
+//
+// Vertices only wait for their parents within the flow, i.e. for the start
+// vertex and its descendants. The GenericDAG is read-locked during the flow,
+// so callback must not modify it. GenericDescendantsFlow returns an error if
+// startID is empty or unknown.
+func GenericDescendantsFlow[T, R any](d *GenericDAG[T], startID string, inputs []GenericFlowResult[R], callback GenericFlowCallback[T, R]) ([]GenericFlowResult[R], error) {
+	return genericDescendantsFlow(d, startID, inputs, callback, 0)
+}
+
+// genericDescendantsFlow is GenericDescendantsFlow running the callbacks on at
+// most maxConcurrency workers, or on a worker per vertex if maxConcurrency is
+// zero or negative. Workers take the vertices whose parents within the flow
+// have all finished from a shared queue.
+func genericDescendantsFlow[T, R any](d *GenericDAG[T], startID string, inputs []GenericFlowResult[R], callback GenericFlowCallback[T, R], maxConcurrency int) ([]GenericFlowResult[R], error) {
+	return runFlow(d, startID, inputs, callback, boundedQueue(maxConcurrency))
+}
+
+// legacyFlow runs callback, the FlowCallback of a flow of the DAG returned by
+// legacy, on the engine of d with the scheduler returned by newScheduler.
+func legacyFlow[T any](d *GenericDAG[T], legacy func() *DAG, startID string, inputs []FlowResult, callback FlowCallback, newScheduler func(vertices int) flowScheduler) ([]FlowResult, error) {
+	results, err := runFlow(d, startID, genericFlowResults(inputs), func(_ *GenericDAG[T], id string, parentResults []GenericFlowResult[interface{}]) (interface{}, error) {
+		return callback(legacy(), id, untypedFlowResults(parentResults))
+	}, newScheduler)
+	if err != nil {
+		return nil, err
+	}
+	return untypedFlowResults(results), nil
+}
+
+// flowScheduler decides which worker of a flow runs which ready vertex.
+type flowScheduler interface {
+	// workers returns the number of workers to start.
+	workers() int
+
+	// push queues the vertex with id, whose parents within the flow have all
+	// finished.
+	push(id string)
+
+	// next blocks until there is a vertex for worker w to run and returns its
+	// id, or returns false once all vertices are done.
+	next(w int) (string, bool)
+
+	// done counts a finished vertex, after its ready children have been
+	// pushed.
+	done()
+}
+
+// runFlow is the engine of all flows: it executes callback for the vertex with
+// startID and each of its descendants once all their parents within the flow
+// have finished, on the workers of the scheduler returned by newScheduler for
+// the number of vertices of the flow. runFlow returns the results of the
+// leaves of the flow.
+func runFlow[T, R any](d *GenericDAG[T], startID string, inputs []GenericFlowResult[R], callback GenericFlowCallback[T, R], newScheduler func(vertices int) flowScheduler) ([]GenericFlowResult[R], error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	if err := d.saneID(startID); err != nil {
+		return nil, err
+	}
+	flowIDs := map[string]interface{}{startID: nil}
+	for h := range d.getDescendants(d.keyOf(startID)) {
+		flowIDs[d.ids.id(h)] = nil
+	}
+
+	// count the parents within the flow each vertex waits for and collect
+	// the children it passes its result to
+	children := make(map[string][]string, len(flowIDs))
+	waiting := make(map[string]int, len(flowIDs))
+	parentResults := make(map[string][]GenericFlowResult[R], len(flowIDs))
+	for id := range flowIDs {
+		children[id] = d.childIDs(id)
+		if id == startID {
+			continue
+		}
+		for parent := range d.inboundEdge[d.keyOf(id)] {
+			if _, inFlow := flowIDs[d.ids.id(parent)]; inFlow {
+				waiting[id]++
+			}
+		}
+	}
+	parentResults[startID] = append([]GenericFlowResult[R](nil), inputs...)
+
+	s := newScheduler(len(flowIDs))
+	d.startFlowStatuses(flowIDs, startID)
+	s.push(startID)
+
+	var mu sync.Mutex
+	var results []GenericFlowResult[R]
+	var wg sync.WaitGroup
+	for w := 0; w < s.workers(); w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for {
+				id, ok := s.next(w)
+				if !ok {
+					return
+				}
+				mu.Lock()
+				parents := parentResults[id]
+				delete(parentResults, id)
+				mu.Unlock()
+				if less := d.options.FlowParentOrder; less != nil {
+					sort.SliceStable(parents, func(i, j int) bool {
+						return less(parents[i].untyped(), parents[j].untyped())
+					})
+				}
+				parentIDs := make([]string, len(parents))
+				for i, r := range parents {
+					parentIDs[i] = r.ID
+				}
+
+				d.setFlowStatus(id, StatusRunning)
+				startedAt := time.Now()
+				result, errWorker := callback(d, id, parents)
+				d.finishFlowStatus(id, errWorker, children[id])
+				flowResult := GenericFlowResult[R]{
+					ID:         id,
+					Result:     result,
+					Error:      errWorker,
+					StartedAt:  startedAt,
+					FinishedAt: time.Now(),
+					Attempts:   1,
+					ParentIDs:  parentIDs,
+				}
+
+				var ready []string
+				mu.Lock()
+				if len(children[id]) == 0 {
+					results = append(results, flowResult)
+				}
+				for _, child := range children[id] {
+					parentResults[child] = append(parentResults[child], flowResult)
+					if waiting[child]--; waiting[child] == 0 {
+						ready = append(ready, child)
+					}
+				}
+				mu.Unlock()
+
+				// schedulers may call user code, e.g. to estimate costs, so
+				// the children are pushed without holding mu
+				for _, child := range ready {
+					s.push(child)
+				}
+				s.done()
+			}
+		}(w)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// queueScheduler runs the vertices of a flow on a fixed number of workers
+// taking them from a shared queue.
+type queueScheduler struct {
+	n       int
+	ready   chan string
+	pending int32
+}
+
+// boundedQueue returns a function returning a queueScheduler with a worker
+// per vertex, but at most maxConcurrency workers if it is positive.
+func boundedQueue(maxConcurrency int) func(vertices int) flowScheduler {
+	return func(vertices int) flowScheduler {
+		workers := vertices
+		if maxConcurrency > 0 && maxConcurrency < workers {
+			workers = maxConcurrency
+		}
+		return newQueueScheduler(workers, vertices)
+	}
+}
+
+// newQueueScheduler returns a scheduler for a flow of the given number of
+// vertices. The queue has capacity for all vertices, so pushing never blocks.
+func newQueueScheduler(workers, vertices int) *queueScheduler {
+	return &queueScheduler{
+		n:       workers,
+		ready:   make(chan string, vertices),
+		pending: int32(vertices),
+	}
+}
+
+func (s *queueScheduler) workers() int {
+	return s.n
+}
+
+func (s *queueScheduler) push(id string) {
+	s.ready <- id
+}
+
+func (s *queueScheduler) next(int) (string, bool) {
+	id, ok := <-s.ready
+	return id, ok
+}
+
+func (s *queueScheduler) done() {
+	if atomic.AddInt32(&s.pending, -1) == 0 {
+		close(s.ready)
+	}
+}
+
+// TypedDescendantsFlow is GenericDescendantsFlow for TypedDAG: callback is
+// provided the TypedDAG instead of its GenericDAG. See GenericDescendantsFlow
+// for details.
+func TypedDescendantsFlow[T, R any](d *TypedDAG[T], startID string, inputs []GenericFlowResult[R], callback func(d *TypedDAG[T], id string, parentResults []GenericFlowResult[R]) (R, error)) ([]GenericFlowResult[R], error) {
+	return GenericDescendantsFlow(d.inner, startID, inputs, func(_ *GenericDAG[T], id string, parentResults []GenericFlowResult[R]) (R, error) {
+		return callback(d, id, parentResults)
+	})
+}
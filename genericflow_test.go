@@ -0,0 +1,101 @@
+package dag
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGenericDescendantsFlow(t *testing.T) {
+	// x -> a -> b -> d, a -> c -> d; x is outside the flow
+	d := NewGenericDAG[int](WithSortedFlowParents())
+	for i, id := range []string{"x", "a", "b", "c", "d"} {
+		d.MustAddVertexByID(id, i)
+	}
+	for _, e := range [][2]string{{"x", "a"}, {"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	// each vertex sums its value and the results of its parents
+	sum := func(d *GenericDAG[int], id string, parents []GenericFlowResult[int]) (int, error) {
+		v, _ := d.GetVertex(id)
+		for _, p := range parents {
+			v += p.Result
+		}
+		return v, nil
+	}
+	results, err := GenericDescendantsFlow(d, "a", []GenericFlowResult[int]{{ID: "in", Result: 10}}, sum)
+	if err != nil {
+		t.Fatalf("GenericDescendantsFlow failed: %v", err)
+	}
+	// a = 1 + 10, b = 2 + 11, c = 3 + 11, d = 4 + 13 + 14
+	if len(results) != 1 || results[0].ID != "d" || results[0].Result != 31 {
+		t.Fatalf("GenericDescendantsFlow() = %+v, want 31 for d", results)
+	}
+	if !reflect.DeepEqual(results[0].ParentIDs, []string{"b", "c"}) {
+		t.Errorf("GenericDescendantsFlow() passed parents %v to d, want [b c]", results[0].ParentIDs)
+	}
+
+	if _, err := GenericDescendantsFlow(d, "z", nil, sum); !errors.As(err, new(IDUnknownError)) {
+		t.Errorf("GenericDescendantsFlow(z) = %v, want IDUnknownError", err)
+	}
+}
+
+func TestTypedDescendantsFlow(t *testing.T) {
+	d := New[string]()
+	for _, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("a", "c")
+
+	// each vertex returns the path from the start vertex to itself
+	path := func(d *TypedDAG[string], id string, parents []GenericFlowResult[[]string]) ([]string, error) {
+		v, _ := d.GetVertex(id)
+		if len(parents) == 0 {
+			return []string{v}, nil
+		}
+		return append(append([]string(nil), parents[0].Result...), v), nil
+	}
+	results, err := TypedDescendantsFlow(d, "a", nil, path)
+	if err != nil {
+		t.Fatalf("TypedDescendantsFlow failed: %v", err)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	if len(results) != 2 || !reflect.DeepEqual(results[0].Result, []string{"a", "b"}) ||
+		!reflect.DeepEqual(results[1].Result, []string{"a", "c"}) {
+		t.Errorf("TypedDescendantsFlow() = %+v, want the paths to b and c", results)
+	}
+}
+
+func TestDescendantsFlow_SharedEngine(t *testing.T) {
+	// a -> b -> c, x -> c; x is a parent of c outside the flow from a
+	d := NewDAG()
+	for _, id := range []string{"a", "b", "c", "x"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("b", "c")
+	d.MustAddEdge("x", "c")
+
+	callback := func(d *DAG, id string, parents []FlowResult) (interface{}, error) {
+		ids := make([]string, len(parents))
+		for i, p := range parents {
+			ids[i] = p.ID
+		}
+		return ids, nil
+	}
+	for i := 0; i < 3; i++ {
+		results, err := d.DescendantsFlow("a", []FlowResult{{ID: "input"}}, callback)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].ID != "c" || !reflect.DeepEqual(results[0].Result, []string{"b"}) {
+			t.Errorf("run %d: DescendantsFlow() = %+v, want c based on b only", i, results)
+		}
+	}
+	if _, err := d.DescendantsFlow("z", nil, callback); err == nil {
+		t.Error("DescendantsFlow(z) returned no error")
+	}
+}
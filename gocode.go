@@ -0,0 +1,256 @@
+package dag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoCodeOptions configures GenerateGoCode.
+type GoCodeOptions struct {
+	// Package is the name of the package of the generated file. If Package
+	// is empty, "main" is used.
+	Package string
+
+	// Func is the name of the generated function returning the graph. If
+	// Func is empty, "NewGraph" is used.
+	Func string
+
+	// ConstPrefix is prepended to the names of the generated constants
+	// holding the vertex ids. If ConstPrefix is empty, "Vertex" is used.
+	ConstPrefix string
+
+	// ValueType is the Go type of the vertex values, e.g. "string" or
+	// "pipeline.Step". If ValueType is empty, it is inferred from the values,
+	// which then must all be strings, all numbers or all booleans.
+	ValueType string
+
+	// ValueLiteral returns the Go expression of the value of the vertex with
+	// id encoded as raw, e.g. a composite literal of ValueType. If
+	// ValueLiteral is nil, strings, numbers and booleans are written as Go
+	// constants; other values fail.
+	ValueLiteral func(id string, raw json.RawMessage) (string, error)
+
+	// Imports are the paths of additional packages to import, e.g. the
+	// package declaring ValueType.
+	Imports []string
+}
+
+// GenerateGoCode reads a graph serialized as JSON (see MarshalJSON) from data
+// and writes Go source code to w, which builds the graph with a function of
+// the TypedDAG API. For each vertex, an exported constant holds its id, e.g.
+// VertexBuildApp for the id "build-app", so static graphs like pipelines are
+// checked at compile time instead of being parsed at run time. Vertices and
+// edges are added in the order of data, and edge values are kept. The graph
+// allows duplicate values (see WithDuplicateValues), as serialized graphs may
+// hold equal values, e.g. graphs read by ReadDOT.
+//
+// GenerateGoCode returns an error if data can't be parsed, or if a value
+// can't be written as Go code.
+func GenerateGoCode(w io.Writer, data []byte, opts GoCodeOptions) error {
+	var raw GenericStorableDAG[json.RawMessage]
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if opts.Package == "" {
+		opts.Package = "main"
+	}
+	if opts.Func == "" {
+		opts.Func = "NewGraph"
+	}
+	if opts.ConstPrefix == "" {
+		opts.ConstPrefix = "Vertex"
+	}
+	if opts.ValueType == "" {
+		valueType, err := inferGoType(raw.Vertices)
+		if err != nil {
+			return err
+		}
+		opts.ValueType = valueType
+	}
+
+	// name the constants of the ids, numbering names that would collide
+	names := make(map[string]string, len(raw.Vertices))
+	taken := make(map[string]struct{}, len(raw.Vertices))
+	for _, v := range raw.Vertices {
+		name := opts.ConstPrefix + goIdentifier(v.ID)
+		for i := 2; ; i++ {
+			if _, exists := taken[name]; !exists {
+				break
+			}
+			name = fmt.Sprintf("%s%s%d", opts.ConstPrefix, goIdentifier(v.ID), i)
+		}
+		taken[name] = struct{}{}
+		names[v.ID] = name
+	}
+	constOf := func(id string) (string, error) {
+		name, exists := names[id]
+		if !exists {
+			return "", IDUnknownError{id}
+		}
+		return name, nil
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by daggen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", opts.Package)
+	for _, path := range opts.Imports {
+		fmt.Fprintf(&b, "%s\n", strconv.Quote(path))
+	}
+	fmt.Fprintf(&b, "\"github.com/JodeZer/dag\"\n)\n\n")
+	if len(raw.Vertices) > 0 {
+		fmt.Fprintf(&b, "// Ids of the vertices of the graph returned by %s.\nconst (\n", opts.Func)
+		for _, v := range raw.Vertices {
+			fmt.Fprintf(&b, "%s = %s\n", names[v.ID], strconv.Quote(v.ID))
+		}
+		fmt.Fprintf(&b, ")\n\n")
+	}
+
+	fmt.Fprintf(&b, "// %s returns a new graph of %d vertices and %d edges.\n", opts.Func, len(raw.Vertices), len(raw.Edges))
+	fmt.Fprintf(&b, "func %s() *dag.TypedDAG[%s] {\n", opts.Func, opts.ValueType)
+	fmt.Fprintf(&b, "d := dag.New[%s](dag.WithDuplicateValues(), dag.WithCapacity(%d, %d))\n", opts.ValueType, len(raw.Vertices), len(raw.Edges))
+	for _, v := range raw.Vertices {
+		value, err := goLiteral(opts, v.ID, v.Value)
+		if err != nil {
+			return fmt.Errorf("vertex '%s': %w", v.ID, err)
+		}
+		fmt.Fprintf(&b, "d.MustAddVertexByID(%s, %s)\n", names[v.ID], value)
+	}
+	for _, e := range raw.Edges {
+		src, err := constOf(e.SrcID)
+		if err != nil {
+			return err
+		}
+		dst, err := constOf(e.DstID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "d.MustAddEdge(%s, %s)\n", src, dst)
+	}
+	for _, e := range raw.EdgeValues {
+		src, err := constOf(e.SrcID)
+		if err != nil {
+			return err
+		}
+		dst, err := constOf(e.DstID)
+		if err != nil {
+			return err
+		}
+		value, err := goScalarLiteral(e.Value)
+		if err != nil {
+			return fmt.Errorf("edge '%s' -> '%s': %w", e.SrcID, e.DstID, err)
+		}
+		fmt.Fprintf(&b, "if err := d.SetEdgeValue(%s, %s, %s); err != nil {\npanic(err)\n}\n", src, dst, value)
+	}
+	fmt.Fprintf(&b, "return d\n}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// inferGoType returns the Go type of the values of vertices, if they are all
+// strings, all numbers, or all booleans.
+func inferGoType(vertices []GenericStorableVertex[json.RawMessage]) (string, error) {
+	valueType := ""
+	for _, v := range vertices {
+		var t string
+		switch value := decodeJSONScalar(v.Value).(type) {
+		case string:
+			t = "string"
+		case bool:
+			t = "bool"
+		case json.Number:
+			t = "int"
+			if _, err := value.Int64(); err != nil {
+				t = "float64"
+			}
+		default:
+			return "", fmt.Errorf("vertex '%s': can't infer the Go type of %s, set ValueType and ValueLiteral", v.ID, v.Value)
+		}
+		switch {
+		case valueType == "" || valueType == "int" && t == "float64":
+			valueType = t
+		case valueType == t || valueType == "float64" && t == "int":
+		default:
+			return "", fmt.Errorf("vertex '%s': values are of both type %s and %s, set ValueType", v.ID, valueType, t)
+		}
+	}
+	if valueType == "" {
+		valueType = "string"
+	}
+	return valueType, nil
+}
+
+// goLiteral returns the Go expression of the value of the vertex with id.
+func goLiteral(opts GoCodeOptions, id string, raw json.RawMessage) (string, error) {
+	if opts.ValueLiteral != nil {
+		return opts.ValueLiteral(id, raw)
+	}
+	switch value := decodeJSONScalar(raw).(type) {
+	case string, bool, json.Number:
+		return goScalarLiteral(value)
+	default:
+		return "", fmt.Errorf("can't write %s as Go code, set ValueLiteral", raw)
+	}
+}
+
+// goScalarLiteral returns the Go constant of a string, number or boolean.
+func goScalarLiteral(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case json.Number:
+		return value.String(), nil
+	case float64:
+		// keep the type of numbers decoded into interface{}
+		return "float64(" + strconv.FormatFloat(value, 'g', -1, 64) + ")", nil
+	default:
+		return "", fmt.Errorf("can't write %v as Go code", value)
+	}
+}
+
+// decodeJSONScalar returns the string, json.Number or bool encoded as raw, or
+// nil if raw encodes another kind of value.
+func decodeJSONScalar(raw json.RawMessage) interface{} {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil
+	}
+	switch value.(type) {
+	case string, bool, json.Number:
+		return value
+	default:
+		return nil
+	}
+}
+
+// goIdentifier turns id into the exported part of a Go identifier, e.g.
+// "build-app" into "BuildApp".
+func goIdentifier(id string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package dag
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGenerateGoCode(t *testing.T) {
+	d := New[string]()
+	d.MustAddVertexByID("build-app", "make build")
+	d.MustAddVertexByID("test", "make test")
+	d.MustAddVertexByID("Test", "go test")
+	d.MustAddEdge("build-app", "test")
+	d.MustAddEdge("build-app", "Test")
+	if err := d.SetEdgeValue("build-app", "test", 1.5); err != nil {
+		t.Fatal(err)
+	}
+	data, err := d.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var src bytes.Buffer
+	if err := GenerateGoCode(&src, data, GoCodeOptions{Package: "pipeline", Func: "NewPipeline"}); err != nil {
+		t.Fatalf("GenerateGoCode failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "pipeline.go", src.Bytes(), 0); err != nil {
+		t.Fatalf("GenerateGoCode() wrote invalid Go code: %v\n%s", err, src.String())
+	}
+	for _, want := range []string{
+		"package pipeline",
+		`VertexBuildApp = "build-app"`,
+		`VertexTest     = "Test"`,
+		`VertexTest2    = "test"`,
+		"func NewPipeline() *dag.TypedDAG[string] {",
+		`d.MustAddVertexByID(VertexBuildApp, "make build")`,
+		"d.MustAddEdge(VertexBuildApp, VertexTest2)",
+		"d.SetEdgeValue(VertexBuildApp, VertexTest2, float64(1.5))",
+	} {
+		if !strings.Contains(src.String(), want) {
+			t.Errorf("GenerateGoCode() = %s, want it to contain %q", src.String(), want)
+		}
+	}
+
+	numbers := []byte(`{"vs":[{"i":"a","v":1},{"i":"b","v":2.5}],"es":[{"s":"a","d":"b"}]}`)
+	src.Reset()
+	if err := GenerateGoCode(&src, numbers, GoCodeOptions{}); err != nil {
+		t.Fatalf("GenerateGoCode failed: %v", err)
+	}
+	if !strings.Contains(src.String(), "dag.New[float64]") {
+		t.Errorf("GenerateGoCode() = %s, want float64 values", src.String())
+	}
+
+	objects := []byte(`{"vs":[{"i":"a","v":{"cmd":"make"}}],"es":[]}`)
+	if err := GenerateGoCode(&src, objects, GoCodeOptions{}); err == nil {
+		t.Error("GenerateGoCode() of objects without ValueLiteral returned no error")
+	}
+	src.Reset()
+	err = GenerateGoCode(&src, objects, GoCodeOptions{
+		ValueType: "Step",
+		ValueLiteral: func(id string, raw json.RawMessage) (string, error) {
+			var step struct{ Cmd string }
+			err := json.Unmarshal(raw, &step)
+			return "Step{Cmd: " + strconv.Quote(step.Cmd) + "}", err
+		},
+	})
+	if err != nil || !strings.Contains(src.String(), `d.MustAddVertexByID(VertexA, Step{Cmd: "make"})`) {
+		t.Errorf("GenerateGoCode() = %s, %v, want a Step literal", src.String(), err)
+	}
+}
+
+func TestGenerateGoCode_DuplicateValues(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping building generated code in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	data := []byte(`{"vs":[{"i":"a","v":"x"},{"i":"b","v":"x"}],"es":[{"s":"a","d":"b"}]}`)
+	var src bytes.Buffer
+	if err := GenerateGoCode(&src, data, GoCodeOptions{}); err != nil {
+		t.Fatalf("GenerateGoCode failed: %v", err)
+	}
+	src.WriteString(`
+func main() {
+	d := NewGraph()
+	if d.GetOrder() != 2 || d.GetSize() != 1 {
+		panic("wrong graph")
+	}
+}
+`)
+
+	// build the program within this module, so it imports this package
+	dir, err := os.MkdirTemp(".", "gocode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command(goTool, "run", "./"+filepath.Base(dir)).CombinedOutput(); err != nil {
+		t.Errorf("running the generated code failed: %v\n%s\n%s", err, out, src.String())
+	}
+}
@@ -0,0 +1,94 @@
+package dag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ModuleVersion is a Go module at a version, as listed by "go mod graph". The
+// main module has no version.
+type ModuleVersion struct {
+	Path    string
+	Version string
+}
+
+// ID returns the id of the vertex of the module version in the graph returned
+// by ReadGoModGraph, which is "<path>@<version>" or the path of the main
+// module.
+func (m ModuleVersion) ID() string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + "@" + m.Version
+}
+
+// String implements the fmt.Stringer interface.
+func (m ModuleVersion) String() string {
+	return m.ID()
+}
+
+// parseModuleVersion parses a module version as printed by "go mod graph".
+func parseModuleVersion(s string) ModuleVersion {
+	if i := strings.LastIndexByte(s, '@'); i >= 0 {
+		return ModuleVersion{Path: s[:i], Version: s[i+1:]}
+	}
+	return ModuleVersion{Path: s}
+}
+
+// ReadGoModGraph reads the output of "go mod graph" from r and returns the
+// module requirement graph: each module version is a vertex with its ID as id,
+// and an edge points from a module version to each module version it
+// requires. Module versions and requirements listed repeatedly are added
+// once. The pseudo-modules "go" and "toolchain" are skipped.
+//
+// Although rare, module versions may require each other. If cutCycles is set,
+// requirements that would close a cycle are dropped in the order they appear,
+// otherwise ReadGoModGraph returns an error in that case. ReadGoModGraph also
+// returns an error if a line isn't a pair of module versions or if reading r
+// fails.
+func ReadGoModGraph(r io.Reader, cutCycles bool) (*GenericDAG[ModuleVersion], error) {
+	d := NewGenericDAG[ModuleVersion]()
+	add := func(m ModuleVersion) error {
+		if _, exists := d.ids.handle(m.ID()); exists {
+			return nil
+		}
+		_, err := d.AddVertex(m)
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("go mod graph: line %d: expected two module versions, got %d fields", line, len(fields))
+		}
+		src, dst := parseModuleVersion(fields[0]), parseModuleVersion(fields[1])
+		if dst.Path == "go" || dst.Path == "toolchain" || src.Path == "go" || src.Path == "toolchain" {
+			continue
+		}
+		if err := add(src); err != nil {
+			return nil, err
+		}
+		if err := add(dst); err != nil {
+			return nil, err
+		}
+		switch err := d.AddEdge(src.ID(), dst.ID()); err.(type) {
+		case nil, EdgeDuplicateError:
+		case EdgeLoopError, SrcDstEqualError:
+			if !cutCycles {
+				return nil, fmt.Errorf("go mod graph: line %d: %w", line, err)
+			}
+		default:
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,56 @@
+package dag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadGoModGraph(t *testing.T) {
+	input := `example.com/app go@1.21
+example.com/app github.com/google/uuid@v1.3.0
+example.com/app golang.org/x/text@v0.3.0
+example.com/app golang.org/x/tools@v0.1.0
+golang.org/x/tools@v0.1.0 golang.org/x/text@v0.3.0
+golang.org/x/tools@v0.1.0 golang.org/x/mod@v0.4.0
+golang.org/x/tools@v0.1.0 golang.org/x/mod@v0.4.0
+golang.org/x/mod@v0.4.0 toolchain@go1.21.0
+
+`
+	d, err := ReadGoModGraph(strings.NewReader(input), false)
+	if err != nil {
+		t.Fatalf("ReadGoModGraph() unexpected error: %v", err)
+	}
+	if d.GetOrder() != 5 || d.GetSize() != 5 {
+		t.Errorf("ReadGoModGraph() has %d vertices and %d edges, want 5 and 5", d.GetOrder(), d.GetSize())
+	}
+	main, err := d.GetVertex("example.com/app")
+	if err != nil || main != (ModuleVersion{Path: "example.com/app"}) {
+		t.Errorf("main module = %v, %v", main, err)
+	}
+	parents, _ := d.GetParents("golang.org/x/text@v0.3.0")
+	if len(parents) != 2 || parents["golang.org/x/tools@v0.1.0"].Version != "v0.1.0" {
+		t.Errorf("GetParents() = %v", parents)
+	}
+	descendants, _ := d.GetDescendants("golang.org/x/tools@v0.1.0")
+	want := map[string]ModuleVersion{
+		"golang.org/x/mod@v0.4.0":  {Path: "golang.org/x/mod", Version: "v0.4.0"},
+		"golang.org/x/text@v0.3.0": {Path: "golang.org/x/text", Version: "v0.3.0"},
+	}
+	if !reflect.DeepEqual(descendants, want) {
+		t.Errorf("GetDescendants() = %v, want %v", descendants, want)
+	}
+
+	cyclic := "a@v1 b@v1\nb@v1 a@v1\n"
+	var loopErr EdgeLoopError
+	if _, err := ReadGoModGraph(strings.NewReader(cyclic), false); !errors.As(err, &loopErr) {
+		t.Errorf("ReadGoModGraph() of a cycle = %v, want EdgeLoopError", err)
+	}
+	if d, err := ReadGoModGraph(strings.NewReader(cyclic), true); err != nil || d.GetSize() != 1 {
+		t.Errorf("ReadGoModGraph() cutting cycles = %v, %v", d, err)
+	}
+	if _, err := ReadGoModGraph(strings.NewReader("a b c\n"), false); err == nil {
+		t.Error("ReadGoModGraph() of an invalid line should fail")
+	}
+}
@@ -0,0 +1,117 @@
+package dag
+
+import "reflect"
+
+// The hashes of the vertex values are indexed to detect duplicate values. If
+// a VertexEqualFunc is set, vertices with unequal values may share a hash: the
+// first of them is indexed in hashes, the others in collisions. If IdentityHash
+// is set, only values with an identity are indexed.
+
+// equalValues reports whether the values a and b with equal hashes are equal.
+func (d *GenericDAG[T]) equalValues(a, b T) bool {
+	if d.options.IdentityHash || d.options.VertexEqualFunc == nil {
+		return true
+	}
+	return d.options.VertexEqualFunc(a, b)
+}
+
+// findValue returns the handle of the vertex whose value equals v, which has
+// the hash vHash, and true, or false if there is none.
+func (d *GenericDAG[T]) findValue(v T, vHash interface{}) (vertexHandle, bool) {
+	h, exists := d.hashes[vHash]
+	if !exists || d.equalValues(d.values[h], v) {
+		return h, exists
+	}
+	for _, h := range d.collisions[vHash] {
+		if d.equalValues(d.values[h], v) {
+			return h, true
+		}
+	}
+	return 0, false
+}
+
+// indexValue indexes the vertex with the handle h under the hash vHash of its
+// value.
+func (d *GenericDAG[T]) indexValue(vHash interface{}, h vertexHandle) {
+	if _, exists := d.hashes[vHash]; !exists {
+		d.hashes[vHash] = h
+		return
+	}
+	if d.collisions == nil {
+		d.collisions = make(map[interface{}][]vertexHandle)
+	}
+	d.collisions[vHash] = append(d.collisions[vHash], h)
+}
+
+// unindexValue removes the vertex with the handle h from the index of the
+// hash vHash.
+func (d *GenericDAG[T]) unindexValue(vHash interface{}, h vertexHandle) {
+	others := d.collisions[vHash]
+	if owner, exists := d.hashes[vHash]; exists && owner == h {
+		if len(others) == 0 {
+			delete(d.hashes, vHash)
+			return
+		}
+		// promote the last colliding vertex
+		d.hashes[vHash] = others[len(others)-1]
+		others = others[:len(others)-1]
+	} else {
+		for i, other := range others {
+			if other == h {
+				others = append(others[:i], others[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(others) == 0 {
+		delete(d.collisions, vHash)
+	} else {
+		d.collisions[vHash] = others
+	}
+}
+
+// indexedHash returns the hash the vertex with the handle h is indexed under
+// and true, or false if it isn't indexed. Unlike hashVertex, indexedHash
+// finds the hash even if the value has been modified since it was indexed.
+func (d *GenericDAG[T]) indexedHash(h vertexHandle) (interface{}, bool) {
+	for vHash, owner := range d.hashes {
+		if owner == h {
+			return vHash, true
+		}
+	}
+	for vHash, others := range d.collisions {
+		for _, other := range others {
+			if other == h {
+				return vHash, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// identity is the hash of a value with identity, i.e. of a pointer, map,
+// channel or slice.
+type identity struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// identityOf returns the identity of v and true, or false if v has no
+// identity, e.g. as v is a struct or nil.
+func identityOf(v interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Chan:
+		if rv.IsNil() {
+			return nil, false
+		}
+		return identity{typ: rv.Type(), ptr: rv.Pointer()}, true
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, false
+		}
+		return identity{typ: rv.Type(), ptr: rv.Pointer(), len: rv.Len()}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,84 @@
+package dag
+
+// vertexHandle is the compact key under which a vertex is referenced by the
+// edge maps and caches of a GenericDAG.
+type vertexHandle uint32
+
+// idTable assigns a vertexHandle to each vertex id. The table is the only
+// place an id string is held, while edges and caches refer to vertices by
+// handle, which keeps large graphs small. Handles of removed ids are reused,
+// unless the table is ordered.
+type idTable struct {
+	handles map[string]vertexHandle
+	ids     []string
+	free    []vertexHandle
+
+	// ordered keeps handles from being reused, so that they increase in the
+	// order the ids have been added.
+	ordered bool
+}
+
+func newIDTable(capacity int, ordered bool) idTable {
+	return idTable{
+		handles: make(map[string]vertexHandle, capacity),
+		ids:     make([]string, 0, capacity),
+		ordered: ordered,
+	}
+}
+
+// len returns the number of ids in the table.
+func (t *idTable) len() int {
+	return len(t.handles)
+}
+
+// handle returns the handle of id and true, or false if id is unknown.
+func (t *idTable) handle(id string) (vertexHandle, bool) {
+	h, ok := t.handles[id]
+	return h, ok
+}
+
+// id returns the id of the handle h, which must be in use.
+func (t *idTable) id(h vertexHandle) string {
+	return t.ids[h]
+}
+
+// add assigns a handle to the unknown, non-empty id and returns it.
+func (t *idTable) add(id string) vertexHandle {
+	var h vertexHandle
+	if n := len(t.free); n > 0 {
+		h = t.free[n-1]
+		t.free = t.free[:n-1]
+		t.ids[h] = id
+	} else {
+		h = vertexHandle(len(t.ids))
+		t.ids = append(t.ids, id)
+	}
+	t.handles[id] = h
+	return h
+}
+
+// remove releases the handle of the known id and returns it.
+func (t *idTable) remove(id string) vertexHandle {
+	h := t.handles[id]
+	delete(t.handles, id)
+	t.ids[h] = ""
+	if !t.ordered {
+		t.free = append(t.free, h)
+	}
+	return h
+}
+
+// each calls f for each id and its handle in the order of the handles until
+// f returns false. If the table is ordered, this is the order the ids have
+// been added.
+func (t *idTable) each(f func(id string, h vertexHandle) bool) {
+	for i, id := range t.ids {
+		// skip the slots of removed ids, which are emptied by remove
+		if id == "" {
+			continue
+		}
+		if !f(id, vertexHandle(i)) {
+			return
+		}
+	}
+}
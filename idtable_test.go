@@ -0,0 +1,80 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIDTable(t *testing.T) {
+	table := newIDTable(0, false)
+	a := table.add("a")
+	b := table.add("b")
+	if a == b || table.len() != 2 {
+		t.Fatalf("add() = %d and %d for two ids, len() = %d", a, b, table.len())
+	}
+	if h, ok := table.handle("b"); !ok || h != b || table.id(h) != "b" {
+		t.Errorf("handle(b) = %d, %v, want %d", h, ok, b)
+	}
+
+	table.remove("a")
+	if _, ok := table.handle("a"); ok || table.len() != 1 {
+		t.Errorf("a should be gone after remove(a), len() = %d", table.len())
+	}
+	if c := table.add("c"); c != a || table.id(c) != "c" {
+		t.Errorf("add(c) = %d, want the released handle %d", c, a)
+	}
+}
+
+func TestIDTable_Ordered(t *testing.T) {
+	table := newIDTable(0, true)
+	for _, id := range []string{"c", "a", "b"} {
+		table.add(id)
+	}
+	a, _ := table.handle("a")
+	table.remove("a")
+	if d := table.add("d"); d == a {
+		t.Errorf("add(d) reused the handle %d of a in an ordered table", a)
+	}
+	table.add("a")
+
+	var ids []string
+	table.each(func(id string, _ vertexHandle) bool {
+		ids = append(ids, id)
+		return true
+	})
+	if want := []string{"c", "b", "d", "a"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("each() visited %v, want %v", ids, want)
+	}
+}
+
+func TestGenericDAG_AddVertexByIDEmpty(t *testing.T) {
+	d := NewGenericDAG[int]()
+	if err := d.AddVertexByID("", 1); err != (IDEmptyError{}) {
+		t.Errorf("AddVertexByID(\"\") = %v, want IDEmptyError", err)
+	}
+	if d.GetOrder() != 0 {
+		t.Errorf("GetOrder() = %d after adding an empty id, want 0", d.GetOrder())
+	}
+}
+
+func TestGenericDAG_HandleReuse(t *testing.T) {
+	d := NewGenericDAG[int]()
+	d.MustAddVertexByID("a", 1)
+	d.MustAddVertexByID("b", 2)
+	d.MustAddEdge("a", "b")
+	if err := d.DeleteVertex("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// c takes over the handle of a, but none of its edges or its value
+	d.MustAddVertexByID("c", 3)
+	if parents, _ := d.GetParents("b"); len(parents) != 0 {
+		t.Errorf("GetParents(b) = %v, want none", parents)
+	}
+	if v, _ := d.GetVertex("c"); v != 3 {
+		t.Errorf("GetVertex(c) = %d, want 3", v)
+	}
+	if err := d.AddVertexByID("d", 1); err != nil {
+		t.Errorf("the value of the deleted vertex a should be free again: %v", err)
+	}
+}
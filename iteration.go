@@ -0,0 +1,70 @@
+package dag
+
+// Iterating a graph that is modified at the same time has one of two
+// well-defined outcomes:
+//
+//   - Walks taking a visitor (e.g. GenericDFSWalk), RootsIter, LeavesIter
+//     and DescendantsFlow read-lock the graph until they are done, so
+//     modifications wait for them. Modifying the graph from within the walk
+//     deadlocks.
+//   - Walkers and iterators (e.g. DescendantsWalker, TypedDAG.WalkDescendants)
+//     walk a snapshot, so they aren't affected by modifications.
+//
+// Iterations spanning several calls, like paging through the vertices, can't
+// lock the graph in between. VertexPager detects modifications using ModCount
+// and fails fast with a ConcurrentModificationError. Cursor instead resumes
+// after the last id returned, skipping deleted vertices.
+
+// ModCount returns a counter that changes whenever the graph is modified,
+// i.e. whenever a vertex or edge is added or deleted. Comparing it before and
+// after a sequence of calls tells whether their results are consistent with
+// each other.
+func (d *GenericDAG[T]) ModCount() uint64 {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.mutations
+}
+
+// VertexPager pages through the vertices of a GenericDAG like
+// GetVerticesPage, but fails as soon as the graph is modified between pages,
+// which would make pages skip or repeat vertices.
+type VertexPager[T any] struct {
+	graph    *GenericDAG[T]
+	modCount uint64
+	offset   int
+	limit    int
+	sortByID bool
+}
+
+// NewVertexPager returns a VertexPager returning pages of at most limit
+// vertices, ordered as by GetVerticesPage.
+func (d *GenericDAG[T]) NewVertexPager(limit int, sortByID bool) *VertexPager[T] {
+	return &VertexPager[T]{graph: d, modCount: d.ModCount(), limit: limit, sortByID: sortByID}
+}
+
+// Next returns the next page of vertices, which is empty once all vertices
+// have been returned. Next returns a ConcurrentModificationError if the graph
+// has been modified since the VertexPager has been created.
+func (p *VertexPager[T]) Next() ([]GenericStorableVertex[T], error) {
+	d := p.graph
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if d.mutations != p.modCount {
+		return nil, ConcurrentModificationError{p.modCount, d.mutations}
+	}
+	page := d.getVerticesPage(p.offset, p.limit, p.sortByID)
+	p.offset += len(page)
+	return page, nil
+}
+
+// ModCount returns a counter that changes whenever the graph is modified. See
+// GenericDAG.ModCount for details.
+func (d *TypedDAG[T]) ModCount() uint64 {
+	return d.inner.ModCount()
+}
+
+// NewVertexPager returns a VertexPager returning pages of at most limit
+// vertices. See GenericDAG.NewVertexPager for details.
+func (d *TypedDAG[T]) NewVertexPager(limit int, sortByID bool) *VertexPager[T] {
+	return d.inner.NewVertexPager(limit, sortByID)
+}
@@ -0,0 +1,93 @@
+package dag
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestVertexPager(t *testing.T) {
+	d := New[int]()
+	for i := 0; i < 5; i++ {
+		d.MustAddVertexByID(strconv.Itoa(i), i)
+	}
+
+	modCount := d.ModCount()
+	p := d.NewVertexPager(2, true)
+	var ids []string
+	for {
+		page, err := p.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, v := range page {
+			ids = append(ids, v.ID)
+		}
+	}
+	if want := []string{"0", "1", "2", "3", "4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("pages = %v, want %v", ids, want)
+	}
+	if d.ModCount() != modCount {
+		t.Error("ModCount() changed without modification")
+	}
+
+	p = d.NewVertexPager(2, false)
+	if _, err := p.Next(); err != nil {
+		t.Fatal(err)
+	}
+	d.MustAddEdge("0", "1")
+	if d.ModCount() == modCount {
+		t.Error("ModCount() unchanged after AddEdge")
+	}
+	var modErr ConcurrentModificationError
+	if _, err := p.Next(); !errors.As(err, &modErr) {
+		t.Errorf("Next() after AddEdge = %v, want ConcurrentModificationError", err)
+	}
+}
+
+func TestWalkersAllowModification(t *testing.T) {
+	d := New[string]()
+	for _, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("b", "c")
+
+	// modifying the graph while walking must neither deadlock nor affect the
+	// walk
+	ids, _, err := d.inner.DescendantsWalker("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var walked []string
+	for id := range ids {
+		walked = append(walked, id)
+		if id == "b" {
+			if err := d.DeleteEdge("b", "c"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if want := []string{"b", "c"}; !reflect.DeepEqual(walked, want) {
+		t.Errorf("DescendantsWalker() walked %v, want %v", walked, want)
+	}
+
+	seq, err := d.WalkAncestors("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	walked = nil
+	seq(func(id string, _ string) bool {
+		walked = append(walked, id)
+		d.MustAddVertexByID("d", "d")
+		d.MustAddEdge("d", "a")
+		return true
+	})
+	if want := []string{"a"}; !reflect.DeepEqual(walked, want) {
+		t.Errorf("WalkAncestors() walked %v, want %v", walked, want)
+	}
+}
@@ -0,0 +1,78 @@
+package dag
+
+// GenericLevelVisitor is a GenericVisitor, which is notified about the
+// boundaries of the levels of a GenericLevelWalk.
+type GenericLevelVisitor[T any] interface {
+	GenericVisitor[T]
+
+	// LevelStart is called before the first vertex of level n is visited.
+	LevelStart(n int)
+
+	// LevelEnd is called after the last vertex of level n has been visited.
+	LevelEnd(n int)
+}
+
+// LevelVisitor is a Visitor, which is notified about the boundaries of the
+// levels of a LevelWalk.
+type LevelVisitor interface {
+	Visitor
+
+	// LevelStart is called before the first vertex of level n is visited.
+	LevelStart(n int)
+
+	// LevelEnd is called after the last vertex of level n has been visited.
+	LevelEnd(n int)
+}
+
+// GenericLevelWalk traverses the entire GenericDAG level by level, where the
+// roots are on level 0 and each other vertex is one level below its lowest
+// parent. So, like for GenericOrderedWalk, for any edge a -> b, node a is
+// visited before node b, but in addition all parents of a vertex are on
+// earlier levels. This allows exporters to emit layered output, e.g. groups
+// of jobs that may run concurrently, in one pass.
+//
+// The vertices of each level are visited between LevelStart and LevelEnd of
+// the level, in id order or in insertion order if the GenericDAG preserves
+// it. The GenericDAG is read-locked during the walk, so visitor must not
+// modify it.
+func (d *GenericDAG[T]) GenericLevelWalk(visitor GenericLevelVisitor[T]) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	for n, level := range d.levels() {
+		visitor.LevelStart(n)
+		for _, id := range d.orderedRelativeIDs(level) {
+			visitor.Visit(d.value(id), id)
+		}
+		visitor.LevelEnd(n)
+	}
+}
+
+// levelVisitorAdapter passes the vertices and level boundaries of a level
+// walk of the underlying GenericDAG on to a LevelVisitor.
+type levelVisitorAdapter struct {
+	visitorAdapter
+	visitor LevelVisitor
+}
+
+func (a levelVisitorAdapter) LevelStart(n int) {
+	a.visitor.LevelStart(n)
+}
+
+func (a levelVisitorAdapter) LevelEnd(n int) {
+	a.visitor.LevelEnd(n)
+}
+
+// LevelWalk traverses the entire DAG level by level, signaling the
+// boundaries of the levels to visitor. See GenericDAG.GenericLevelWalk for
+// details.
+func (d *DAG) LevelWalk(visitor LevelVisitor) {
+	d.GenericLevelWalk(levelVisitorAdapter{visitorAdapter{visitor}, visitor})
+}
+
+// LevelWalk traverses the entire TypedDAG level by level, signaling the
+// boundaries of the levels to visitor. See GenericDAG.GenericLevelWalk for
+// details.
+func (d *TypedDAG[T]) LevelWalk(visitor GenericLevelVisitor[T]) {
+	d.inner.GenericLevelWalk(visitor)
+}
@@ -0,0 +1,71 @@
+package dag
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// levelRecorder records the level boundaries and vertices of a level walk.
+type levelRecorder struct {
+	events []string
+}
+
+func (r *levelRecorder) LevelStart(n int) {
+	r.events = append(r.events, fmt.Sprintf("start %d", n))
+}
+
+func (r *levelRecorder) LevelEnd(n int) {
+	r.events = append(r.events, fmt.Sprintf("end %d", n))
+}
+
+func (r *levelRecorder) Visit(_ string, id string) {
+	r.events = append(r.events, id)
+}
+
+// legacyLevelRecorder records a level walk of a DAG.
+type legacyLevelRecorder struct {
+	levelRecorder
+}
+
+func (r *legacyLevelRecorder) Visit(v Vertexer) {
+	id, _ := v.Vertex()
+	r.events = append(r.events, id)
+}
+
+func TestLevelWalk(t *testing.T) {
+	/*  a   b
+	 *  |\ /
+	 *  | c
+	 *  |/
+	 *  d
+	 */
+	d := New[string]()
+	legacy := NewDAG()
+	for _, id := range []string{"d", "c", "b", "a"} {
+		d.MustAddVertexByID(id, id)
+		_ = legacy.AddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "c"}, {"b", "c"}, {"a", "d"}, {"c", "d"}} {
+		d.MustAddEdge(e[0], e[1])
+		_ = legacy.AddEdge(e[0], e[1])
+	}
+
+	want := []string{"start 0", "a", "b", "end 0", "start 1", "c", "end 1", "start 2", "d", "end 2"}
+	r := &levelRecorder{}
+	d.LevelWalk(r)
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("LevelWalk() = %v, want %v", r.events, want)
+	}
+	lr := &legacyLevelRecorder{}
+	legacy.LevelWalk(lr)
+	if !reflect.DeepEqual(lr.events, want) {
+		t.Errorf("DAG.LevelWalk() = %v, want %v", lr.events, want)
+	}
+
+	r = &levelRecorder{}
+	New[string]().LevelWalk(r)
+	if len(r.events) != 0 {
+		t.Errorf("LevelWalk() of empty graph = %v, want no events", r.events)
+	}
+}
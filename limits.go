@@ -0,0 +1,126 @@
+package dag
+
+// TraversalLimits bounds the work of a single traversal, so that a single
+// pathological query on a dense graph can't exhaust the resources of a
+// service shared by many callers. A traversal exceeding a limit is aborted
+// with a LimitExceededError. Zero limits are unlimited.
+type TraversalLimits struct {
+	// MaxVisited is the maximum number of vertices the traversal visits. A
+	// vertex reached on several paths counts once per path, if the traversal
+	// follows each path, e.g. in AllPathsLimited.
+	MaxVisited int
+
+	// MaxResults is the maximum number of results the traversal returns, e.g.
+	// descendants or paths.
+	MaxResults int
+}
+
+// traversalBudget counts the vertices visited and results collected by a
+// traversal against its TraversalLimits. A nil traversalBudget is unlimited.
+type traversalBudget struct {
+	limits  TraversalLimits
+	visited int
+	results int
+}
+
+func newTraversalBudget(limits TraversalLimits) *traversalBudget {
+	return &traversalBudget{limits: limits}
+}
+
+// visit counts a visited vertex and returns a LimitExceededError if there
+// are more than MaxVisited.
+func (b *traversalBudget) visit() error {
+	if b == nil {
+		return nil
+	}
+	b.visited++
+	if b.limits.MaxVisited > 0 && b.visited > b.limits.MaxVisited {
+		return LimitExceededError{"visited vertices", b.limits.MaxVisited}
+	}
+	return nil
+}
+
+// result counts a result and returns a LimitExceededError if there are more
+// than MaxResults.
+func (b *traversalBudget) result() error {
+	if b == nil {
+		return nil
+	}
+	b.results++
+	if b.limits.MaxResults > 0 && b.results > b.limits.MaxResults {
+		return LimitExceededError{"results", b.limits.MaxResults}
+	}
+	return nil
+}
+
+// GetDescendantsLimited is like GetDescendants, but returns a
+// LimitExceededError if collecting the descendants visits more than
+// limits.MaxVisited vertices (including the vertex with id) or finds more than
+// limits.MaxResults descendants. Descendants already cached are counted
+// against limits.MaxResults only.
+func (d *GenericDAG[T]) GetDescendantsLimited(id string, limits TraversalLimits) (map[string]T, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.collectDescendants(id, newTraversalBudget(limits), nil)
+}
+
+// AncestorsWalkerLimited is like AncestorsWalker, but returns a
+// LimitExceededError instead of walking, if taking the snapshot of the
+// ancestors visits more than limits.MaxVisited vertices (including the vertex
+// with id) or finds more than limits.MaxResults ancestors.
+func (d *GenericDAG[T]) AncestorsWalkerLimited(id string, limits TraversalLimits) (chan string, chan bool, error) {
+	return d.relativesWalker(id, true, newTraversalBudget(limits))
+}
+
+// DescendantsWalkerLimited is like DescendantsWalker, but returns a
+// LimitExceededError instead of walking, if taking the snapshot of the
+// descendants visits more than limits.MaxVisited vertices (including the
+// vertex with id) or finds more than limits.MaxResults descendants.
+func (d *GenericDAG[T]) DescendantsWalkerLimited(id string, limits TraversalLimits) (chan string, chan bool, error) {
+	return d.relativesWalker(id, false, newTraversalBudget(limits))
+}
+
+// GenericDFSWalkLimited is like GenericDFSWalk, but aborts the walk with a
+// LimitExceededError instead of visiting more than limits.MaxVisited
+// vertices. limits.MaxResults doesn't apply to walks.
+func (d *GenericDAG[T]) GenericDFSWalkLimited(visitor GenericVisitor[T], limits TraversalLimits) error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.genericDFSWalk(visitor, newTraversalBudget(limits))
+}
+
+// GenericBFSWalkLimited is like GenericBFSWalk, but aborts the walk with a
+// LimitExceededError instead of visiting more than limits.MaxVisited
+// vertices. limits.MaxResults doesn't apply to walks.
+func (d *GenericDAG[T]) GenericBFSWalkLimited(visitor GenericVisitor[T], limits TraversalLimits) error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.genericBFSWalk(visitor, newTraversalBudget(limits))
+}
+
+// AllPathsLimited is like AllPaths, but returns a LimitExceededError if the
+// search visits more than limits.MaxVisited vertices or finds more than
+// limits.MaxResults paths. As the number of paths may grow exponentially
+// with the size of g, AllPathsLimited should be preferred for graphs that
+// aren't known to be small.
+func AllPathsLimited(g GraphReader, srcID, dstID string, limits TraversalLimits) ([][]string, error) {
+	return allPaths(g, srcID, dstID, newTraversalBudget(limits))
+}
+
+// GetDescendantsLimited returns all descendants of the vertex with the id
+// within limits. See GenericDAG.GetDescendantsLimited for details.
+func (d *TypedDAG[T]) GetDescendantsLimited(id string, limits TraversalLimits) (map[string]T, error) {
+	return d.inner.GetDescendantsLimited(id, limits)
+}
+
+// AncestorsWalkerLimited walks all ancestors of the vertex with id within
+// limits. See GenericDAG.AncestorsWalkerLimited for details.
+func (d *TypedDAG[T]) AncestorsWalkerLimited(id string, limits TraversalLimits) (chan string, chan bool, error) {
+	return d.inner.AncestorsWalkerLimited(id, limits)
+}
+
+// DescendantsWalkerLimited walks all descendants of the vertex with id within
+// limits. See GenericDAG.DescendantsWalkerLimited for details.
+func (d *TypedDAG[T]) DescendantsWalkerLimited(id string, limits TraversalLimits) (chan string, chan bool, error) {
+	return d.inner.DescendantsWalkerLimited(id, limits)
+}
@@ -0,0 +1,88 @@
+package dag
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTraversalLimits(t *testing.T) {
+	// a -> b -> d, a -> c -> d, a -> d, d -> e
+	d := NewGenericDAG[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"a", "d"}, {"d", "e"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+	var exceeded LimitExceededError
+
+	if _, err := d.GetDescendantsLimited("a", TraversalLimits{MaxResults: 3}); !errors.As(err, &exceeded) {
+		t.Errorf("GetDescendantsLimited(a, 3 results) = %v, want LimitExceededError", err)
+	}
+	if _, err := d.GetDescendantsLimited("a", TraversalLimits{MaxVisited: 2}); !errors.As(err, &exceeded) {
+		t.Errorf("GetDescendantsLimited(a, 2 visited) = %v, want LimitExceededError", err)
+	}
+	descendants, err := d.GetDescendantsLimited("a", TraversalLimits{MaxVisited: 5, MaxResults: 4})
+	if err != nil || len(descendants) != 4 {
+		t.Errorf("GetDescendantsLimited(a) = %v, %v, want 4 descendants", descendants, err)
+	}
+	// now cached
+	if _, err := d.GetDescendantsLimited("a", TraversalLimits{MaxResults: 3}); !errors.As(err, &exceeded) {
+		t.Errorf("GetDescendantsLimited(a, 3 results) = %v, want LimitExceededError", err)
+	}
+	if _, err := d.GetDescendantsLimited("x", TraversalLimits{}); !errors.As(err, new(IDUnknownError)) {
+		t.Errorf("GetDescendantsLimited(x) = %v, want IDUnknownError", err)
+	}
+
+	visits := 0
+	visitor := visitorFunc[string](func(string, string) { visits++ })
+	if err := d.GenericDFSWalkLimited(visitor, TraversalLimits{MaxVisited: 3}); !errors.As(err, &exceeded) || visits != 3 {
+		t.Errorf("GenericDFSWalkLimited(3) = %v after %d visits, want LimitExceededError after 3", err, visits)
+	}
+	visits = 0
+	if err := d.GenericBFSWalkLimited(visitor, TraversalLimits{MaxVisited: 5}); err != nil || visits != 5 {
+		t.Errorf("GenericBFSWalkLimited(5) = %v after %d visits, want nil after 5", err, visits)
+	}
+
+	if _, err := AllPathsLimited(d, "a", "e", TraversalLimits{MaxResults: 2}); !errors.As(err, &exceeded) {
+		t.Errorf("AllPathsLimited(a, e, 2 results) = %v, want LimitExceededError", err)
+	}
+	paths, err := AllPathsLimited(d, "a", "e", TraversalLimits{MaxResults: 3})
+	if err != nil || len(paths) != 3 {
+		t.Errorf("AllPathsLimited(a, e, 3 results) = %v, %v, want 3 paths", paths, err)
+	}
+}
+
+func TestTraversalLimits_Walkers(t *testing.T) {
+	// a -> b -> d, a -> c -> d, d -> e
+	d := New[string]()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+	var exceeded LimitExceededError
+
+	if _, _, err := d.DescendantsWalkerLimited("a", TraversalLimits{MaxResults: 3}); !errors.As(err, &exceeded) {
+		t.Errorf("DescendantsWalkerLimited(a, 3 results) = %v, want LimitExceededError", err)
+	}
+	if _, _, err := d.AncestorsWalkerLimited("e", TraversalLimits{MaxVisited: 2}); !errors.As(err, &exceeded) {
+		t.Errorf("AncestorsWalkerLimited(e, 2 visited) = %v, want LimitExceededError", err)
+	}
+	if _, _, err := d.AncestorsWalkerLimited("x", TraversalLimits{}); !errors.As(err, new(IDUnknownError)) {
+		t.Errorf("AncestorsWalkerLimited(x) = %v, want IDUnknownError", err)
+	}
+
+	ids, _, err := d.AncestorsWalkerLimited("e", TraversalLimits{MaxVisited: 5, MaxResults: 4})
+	if err != nil {
+		t.Fatalf("AncestorsWalkerLimited(e) unexpected error: %v", err)
+	}
+	var walked []string
+	for id := range ids {
+		walked = append(walked, id)
+	}
+	if len(walked) != 4 || walked[0] != "d" {
+		t.Errorf("AncestorsWalkerLimited(e) walked %v, want d, b, c, a", walked)
+	}
+}
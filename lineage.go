@@ -0,0 +1,220 @@
+package dag
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The event types of LineageEvents.
+const (
+	LineageStart    = "START"
+	LineageComplete = "COMPLETE"
+	LineageFail     = "FAIL"
+)
+
+// lineageSchemaURL is the schema of the run events of OpenLineage.
+const lineageSchemaURL = "https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/RunEvent"
+
+// LineageEvent is a run event in the format of OpenLineage, reporting that a
+// run of a job started, completed or failed, along with the datasets it
+// reads and writes.
+type LineageEvent struct {
+	EventType string           `json:"eventType"`
+	EventTime time.Time        `json:"eventTime"`
+	Run       LineageRun       `json:"run"`
+	Job       LineageJob       `json:"job"`
+	Inputs    []LineageDataset `json:"inputs"`
+	Outputs   []LineageDataset `json:"outputs"`
+	Producer  string           `json:"producer"`
+	SchemaURL string           `json:"schemaURL"`
+}
+
+// LineageRun identifies a run of a job.
+type LineageRun struct {
+	RunID  string                 `json:"runId"`
+	Facets map[string]interface{} `json:"facets,omitempty"`
+}
+
+// LineageJob identifies a job, i.e. a vertex of a flow.
+type LineageJob struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// LineageDataset identifies a dataset read or written by a job.
+type LineageDataset struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// LineageOptions configures LineageCallback.
+type LineageOptions struct {
+	// Namespace is the namespace of the jobs and datasets. If Namespace is
+	// empty, "default" is used.
+	Namespace string
+
+	// Producer is the URI identifying the producer of the events. If
+	// Producer is empty, the URI of this package is used.
+	Producer string
+
+	// RunID returns the id of the run of the vertex with id. If RunID is
+	// nil, a random UUID is used for each run.
+	RunID func(id string) string
+}
+
+// LineageCallback wraps callback, so that each vertex of a flow emits a START
+// event before callback runs, and a COMPLETE or FAIL event, depending on the
+// error returned by callback, afterwards. The job of a vertex is named by its
+// id. It reads a dataset named by the id of each of its parent results and
+// writes a dataset named by its own id, so that the graph of the flow can be
+// reconstructed from the events with ReadLineageEvents. emit must be safe for
+// concurrent use, e.g. LineageWriter.Emit.
+func LineageCallback(callback FlowCallback, emit func(LineageEvent), opts LineageOptions) FlowCallback {
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+	if opts.Producer == "" {
+		opts.Producer = "https://github.com/JodeZer/dag"
+	}
+	return func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		runID := uuid.NewString()
+		if opts.RunID != nil {
+			runID = opts.RunID(id)
+		}
+		inputs := make([]LineageDataset, len(parentResults))
+		for i, r := range parentResults {
+			inputs[i] = LineageDataset{Namespace: opts.Namespace, Name: r.ID}
+		}
+		event := func(eventType string) LineageEvent {
+			return LineageEvent{
+				EventType: eventType,
+				EventTime: time.Now().UTC(),
+				Run:       LineageRun{RunID: runID},
+				Job:       LineageJob{Namespace: opts.Namespace, Name: id},
+				Inputs:    inputs,
+				Outputs:   []LineageDataset{{Namespace: opts.Namespace, Name: id}},
+				Producer:  opts.Producer,
+				SchemaURL: lineageSchemaURL,
+			}
+		}
+
+		emit(event(LineageStart))
+		result, err := callback(d, id, parentResults)
+		if err != nil {
+			emit(event(LineageFail))
+		} else {
+			emit(event(LineageComplete))
+		}
+		return result, err
+	}
+}
+
+// LineageWriter writes LineageEvents to an io.Writer as JSON, one event per
+// line. A LineageWriter is safe for concurrent use.
+type LineageWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewLineageWriter returns a LineageWriter writing to w.
+func NewLineageWriter(w io.Writer) *LineageWriter {
+	return &LineageWriter{enc: json.NewEncoder(w)}
+}
+
+// Emit writes the event. Once writing fails, Emit drops all further events;
+// see Err.
+func (lw *LineageWriter) Emit(event LineageEvent) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if lw.err == nil {
+		lw.err = lw.enc.Encode(event)
+	}
+}
+
+// Err returns the error of the first event that couldn't be written, or nil.
+func (lw *LineageWriter) Err() error {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.err
+}
+
+// ReadLineageEvents reads a stream of LineageEvents encoded as JSON from r and
+// reconstructs the graph of the jobs: each job becomes a vertex with its name
+// as id, and an edge points from each job writing a dataset to each job
+// reading it. Jobs of different namespaces with the same name are merged.
+// Events of any type count, and repeated events of the same job are merged,
+// so the stream may hold several runs.
+//
+// ReadLineageEvents returns an error if the stream can't be parsed, if a job
+// has no name, or if the jobs depend on each other in a cycle.
+func ReadLineageEvents(r io.Reader) (*GenericDAG[LineageJob], error) {
+	var jobs []LineageJob
+	seen := make(map[string]struct{})
+	writers := make(map[LineageDataset]map[string]struct{})
+	readers := make(map[LineageDataset]map[string]struct{})
+	addTo := func(m map[LineageDataset]map[string]struct{}, ds LineageDataset, name string) {
+		if m[ds] == nil {
+			m[ds] = make(map[string]struct{})
+		}
+		m[ds][name] = struct{}{}
+	}
+
+	dec := json.NewDecoder(r)
+	for {
+		var event LineageEvent
+		if err := dec.Decode(&event); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		name := event.Job.Name
+		if name == "" {
+			return nil, IDEmptyError{}
+		}
+		if _, exists := seen[name]; !exists {
+			seen[name] = struct{}{}
+			jobs = append(jobs, event.Job)
+		}
+		for _, ds := range event.Outputs {
+			addTo(writers, ds, name)
+		}
+		for _, ds := range event.Inputs {
+			addTo(readers, ds, name)
+		}
+	}
+
+	d := NewGenericDAG[LineageJob](WithDuplicateValues(), WithCapacity(len(jobs), len(readers)))
+	for _, job := range jobs {
+		d.MustAddVertexByID(job.Name, job)
+	}
+	var edges []GenericEdge
+	for ds, dsReaders := range readers {
+		for src := range writers[ds] {
+			for dst := range dsReaders {
+				if src != dst {
+					edges = append(edges, GenericEdge{SrcID: src, DstID: dst})
+				}
+			}
+		}
+	}
+	// add the edges in a deterministic order, so that errors are too
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].SrcID != edges[j].SrcID {
+			return edges[i].SrcID < edges[j].SrcID
+		}
+		return edges[i].DstID < edges[j].DstID
+	})
+	for _, e := range edges {
+		if _, err := d.EnsureEdge(e.SrcID, e.DstID); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
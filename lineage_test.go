@@ -0,0 +1,84 @@
+package dag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineageRoundTrip(t *testing.T) {
+	d := NewDAG()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	var buf bytes.Buffer
+	w := NewLineageWriter(&buf)
+	callback := func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		if id == "c" {
+			return nil, errors.New("failed")
+		}
+		return id, nil
+	}
+	opts := LineageOptions{Namespace: "etl", RunID: func(id string) string { return "run-" + id }}
+	if _, err := d.DescendantsFlow("a", nil, LineageCallback(callback, w.Emit, opts)); err != nil {
+		t.Fatalf("DescendantsFlow failed: %v", err)
+	}
+	if err := w.Err(); err != nil {
+		t.Fatalf("LineageWriter failed: %v", err)
+	}
+
+	types := make(map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var event LineageEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid event %s: %v", line, err)
+		}
+		if event.Run.RunID != "run-"+event.Job.Name || event.Job.Namespace != "etl" {
+			t.Errorf("event %s has an unexpected run or job", line)
+		}
+		types[event.Job.Name] = append(types[event.Job.Name], event.EventType)
+	}
+	want := map[string][]string{
+		"a": {LineageStart, LineageComplete},
+		"b": {LineageStart, LineageComplete},
+		"c": {LineageStart, LineageFail},
+		"d": {LineageStart, LineageComplete},
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("emitted events %v, want %v", types, want)
+	}
+
+	g, err := ReadLineageEvents(&buf)
+	if err != nil {
+		t.Fatalf("ReadLineageEvents failed: %v", err)
+	}
+	if g.GetOrder() != 4 || g.GetSize() != 4 {
+		t.Errorf("ReadLineageEvents() has %d vertices and %d edges, want 4 and 4", g.GetOrder(), g.GetSize())
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}} {
+		if isEdge, _ := g.IsEdge(e[0], e[1]); !isEdge {
+			t.Errorf("ReadLineageEvents() lacks the edge %s -> %s", e[0], e[1])
+		}
+	}
+	if job, _ := g.GetVertex("d"); job != (LineageJob{Namespace: "etl", Name: "d"}) {
+		t.Errorf("GetVertex(d) = %+v, want job d of etl", job)
+	}
+}
+
+func TestReadLineageEvents_Cycle(t *testing.T) {
+	events := `{"job":{"name":"x"},"inputs":[{"name":"y"}],"outputs":[{"name":"x"}]}
+{"job":{"name":"y"},"inputs":[{"name":"x"}],"outputs":[{"name":"y"}]}`
+	if _, err := ReadLineageEvents(strings.NewReader(events)); !errors.As(err, new(EdgeLoopError)) {
+		t.Errorf("ReadLineageEvents() = %v, want EdgeLoopError", err)
+	}
+	if _, err := ReadLineageEvents(strings.NewReader(`{"job":{}}`)); !errors.As(err, new(IDEmptyError)) {
+		t.Errorf("ReadLineageEvents() = %v, want IDEmptyError", err)
+	}
+}
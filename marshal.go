@@ -3,12 +3,13 @@ package dag
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 )
 
 // convertToType efficiently converts an interface{} value to type T.
 // For common types (string, int, bool, float64), it uses direct type assertion
 // to avoid the expensive JSON marshal/unmarshal fallback.
-// This significantly improves performance in MarshalGeneric[T].
+// Values that can't be converted result in the zero value of T.
 func convertToType[T any](value interface{}) T {
 	var zero T
 	if value == nil {
@@ -42,14 +43,22 @@ func convertToType[T any](value interface{}) T {
 	}
 
 	// Fallback only for complex types - use JSON marshal/unmarshal
+	converted, _ := convertViaJSON[T](value)
+	return converted
+}
+
+// convertViaJSON converts value to type T by a JSON round trip and returns
+// an error if value is not representable as T.
+func convertViaJSON[T any](value interface{}) (T, error) {
+	var zero T
 	valueJSON, err := json.Marshal(value)
 	if err != nil {
-		return zero
+		return zero, err
 	}
 	if err := json.Unmarshal(valueJSON, &zero); err != nil {
-		return zero
+		return zero, fmt.Errorf("converting %T to %T: %w", value, zero, err)
 	}
-	return zero
+	return zero, nil
 }
 
 // MarshalJSON returns the JSON encoding of DAG.
@@ -61,6 +70,17 @@ func convertToType[T any](value interface{}) T {
 func (d *DAG) MarshalJSON() ([]byte, error) {
 	mv := newMarshalVisitor(d)
 	d.DFSWalk(mv)
+	mv.EdgeValues = d.getEdgeValueList()
+	if d.options.Codecs != nil {
+		for i, v := range mv.StorableVertices {
+			id, value := v.Vertex()
+			encoded, err := encodeValue(d.options.Codecs, value)
+			if err != nil {
+				return nil, fmt.Errorf("vertex '%s': %w", id, err)
+			}
+			mv.StorableVertices[i] = storableVertex{WrappedID: id, Value: encoded}
+		}
+	}
 	return json.Marshal(mv.storableDAG)
 }
 
@@ -77,10 +97,34 @@ func (d *DAG) MarshalJSON() ([]byte, error) {
 //   // Complex custom type
 //   type Person struct { Name string; Age int }
 //   data, err := dag.MarshalGeneric[Person](d)
+//
+// Values that are not of type T are converted by a JSON round trip.
+// MarshalGeneric returns an error if such a conversion fails.
 func MarshalGeneric[T any](d *DAG) ([]byte, error) {
 	mv := newGenericMarshalVisitor[T](d)
 	d.DFSWalk(mv)
-	return json.Marshal(mv.storableDAGGeneric)
+	if mv.err != nil {
+		return nil, mv.err
+	}
+	mv.storableDAGGeneric.EdgeValues = d.getEdgeValueList()
+	if d.options.Codecs == nil {
+		return json.Marshal(mv.storableDAGGeneric)
+	}
+
+	// replace values that have a codec by their encoding
+	encoded := storableDAGGeneric[interface{}]{
+		StorableVertices: make([]storableVertexGeneric[interface{}], len(mv.storableDAGGeneric.StorableVertices)),
+		StorableEdges:    mv.storableDAGGeneric.StorableEdges,
+		EdgeValues:       mv.storableDAGGeneric.EdgeValues,
+	}
+	for i, v := range mv.storableDAGGeneric.StorableVertices {
+		value, err := encodeValue(d.options.Codecs, v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("vertex '%s': %w", v.WrappedID, err)
+		}
+		encoded.StorableVertices[i] = storableVertexGeneric[interface{}]{WrappedID: v.WrappedID, Value: value}
+	}
+	return json.Marshal(encoded)
 }
 
 // UnmarshalJSON is an informative method. See the UnmarshalJSON function below.
@@ -110,52 +154,47 @@ func (d *DAG) UnmarshalJSON(_ []byte) error {
 //
 //   // Pointer to struct type
 //   dag, err := dag.UnmarshalJSONGeneric[*Person](data, opts)
+//
+// Duplicate edges are handled according to options.DuplicateEdges like by
+// UnmarshalGenericJSON.
 func UnmarshalJSONGeneric[T any](data []byte, options Options) (*DAG, error) {
-	var sd storableDAGGeneric[T]
-	if err := json.Unmarshal(data, &sd); err != nil {
+	sd, err := decodeStorableDAGGeneric[T](data, options.Codecs)
+	if err != nil {
 		return nil, err
 	}
-
-	dag := NewDAG()
-
-	// Set options only if VertexHashFunc is provided
-	if options.VertexHashFunc != nil {
-		dag.Options(options)
+	vertices := sd.VerticesGeneric()
+	if err := validateVertices(len(vertices), func(i int) (string, interface{}) {
+		return vertices[i].WrappedID, vertices[i].Value
+	}, options.VertexValidator); err != nil {
+		return nil, err
 	}
 
-	// Batch add vertices - direct access to avoid interface boxing
-	dag.muDAG.Lock()
-	for _, v := range sd.VerticesGeneric() {
-		id := v.WrappedID
-		value := v.Value
+	dag := NewDAG(WithOptions(options))
 
-		vHash := dag.hashVertex(value)
-
-		// Check for duplicate vertex
-		if _, exists := dag.vertices[vHash]; exists {
-			dag.muDAG.Unlock()
-			return nil, VertexDuplicateError{value}
-		}
-
-		if _, exists := dag.vertexIds[id]; exists {
+	// Batch add vertices
+	dag.muDAG.Lock()
+	for _, v := range vertices {
+		if err := dag.addVertexByID(v.WrappedID, v.Value); err != nil {
 			dag.muDAG.Unlock()
-			return nil, IDDuplicateError{id}
+			return nil, err
 		}
-
-		// Add vertex directly without interface boxing
-		dag.vertices[vHash] = id
-		dag.vertexIds[id] = value
 	}
 	dag.muDAG.Unlock()
 
 	// Batch add edges using optimized method
-	if len(sd.StorableEdges) > 0 {
-		if err := dag.addEdgesBatch(sd.StorableEdges); err != nil {
-			return nil, err
-		}
+	edges := sd.StorableEdges
+	errEdges := dag.addEdgesBatch(len(edges), func(i int) (string, string) {
+		return edges[i].SrcID, edges[i].DstID
+	})
+	if _, warning := errEdges.(DuplicateEdgesWarning); errEdges != nil && !warning {
+		return nil, errEdges
+	}
+	if err := dag.setEdgeValues(sd.EdgeValues); err != nil {
+		return nil, err
 	}
 
-	return dag, nil
+	// errEdges is nil or a DuplicateEdgesWarning
+	return dag, errEdges
 }
 
 // UnmarshalJSONLegacy parses the JSON-encoded data that defined by StorableDAG.
@@ -182,14 +221,16 @@ func UnmarshalJSONGeneric[T any](data []byte, options Options) (*DAG, error) {
 //
 // For more specific information please read the test code.
 //
+// Duplicate edges are handled according to options.DuplicateEdges like by
+// UnmarshalGenericJSON.
+//
 // Deprecated: Use the generic UnmarshalJSON[T] function instead.
 func UnmarshalJSONLegacy(data []byte, wd StorableDAG, options Options) (*DAG, error) {
 	err := json.Unmarshal(data, &wd)
 	if err != nil {
 		return nil, err
 	}
-	dag := NewDAG()
-	dag.Options(options)
+	dag := NewDAG(WithOptions(options))
 
 	// Use batch vertex addition for better performance
 	vertices := wd.Vertices()
@@ -199,13 +240,60 @@ func UnmarshalJSONLegacy(data []byte, wd StorableDAG, options Options) (*DAG, er
 		}
 	}
 
+	var duplicates []GenericEdge
 	for _, e := range wd.Edges() {
-		errEdge := dag.AddEdge(e.Edge())
+		srcID, dstID := e.Edge()
+		errEdge := dag.AddEdge(srcID, dstID)
+		if _, duplicate := errEdge.(EdgeDuplicateError); duplicate && options.DuplicateEdges != DuplicateEdgesFail {
+			duplicates = append(duplicates, GenericEdge{SrcID: srcID, DstID: dstID})
+			continue
+		}
 		if errEdge != nil {
 			return nil, errEdge
 		}
 	}
-	return dag, nil
+	return dag, dag.duplicatesWarning(duplicates)
+}
+
+// decodeStorableDAGGeneric parses data, decoding vertex values with the
+// given codecs.
+func decodeStorableDAGGeneric[T any](data []byte, codecs *CodecRegistry) (storableDAGGeneric[T], error) {
+	var sd storableDAGGeneric[T]
+	if codecs == nil {
+		err := json.Unmarshal(data, &sd)
+		return sd, err
+	}
+	var raw storableDAGGeneric[json.RawMessage]
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return sd, err
+	}
+	sd.StorableVertices = make([]storableVertexGeneric[T], len(raw.StorableVertices))
+	for i, v := range raw.StorableVertices {
+		value, err := decodeValue[T](codecs, v.Value)
+		if err != nil {
+			return sd, fmt.Errorf("vertex '%s': %w", v.WrappedID, err)
+		}
+		sd.StorableVertices[i] = storableVertexGeneric[T]{WrappedID: v.WrappedID, Value: value}
+	}
+	sd.StorableEdges = raw.StorableEdges
+	sd.EdgeValues = raw.EdgeValues
+	return sd, nil
+}
+
+// addVerticesBatch adds multiple vertices in a single lock acquisition
+// This is an internal method used for performance optimization
+func (d *DAG) addVerticesBatch(vertices []Vertexer) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	for _, v := range vertices {
+		id, value := v.Vertex()
+		err := d.addVertexByID(id, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type marshalVisitor struct {
@@ -234,18 +322,19 @@ func (mv *marshalVisitor) Visit(v Vertexer) {
 	// Why not use Mutex here?
 	// Because at the time of Walk,
 	// the read lock has been used to protect the dag.
-	children, _ := mv.d.getChildren(srcID)
-	// Directly iterate over map keys - no need to sort for serialization
-	for dstID := range children {
-		e := storableEdge{SrcID: srcID, DstID: dstID}
+	// Directly iterate over the children - no need to sort for serialization,
+	// unless the children are to be ordered
+	mv.d.eachChild(mv.d.keyOf(srcID), func(child vertexHandle) {
+		e := storableEdge{SrcID: srcID, DstID: mv.d.ids.id(child)}
 		mv.StorableEdges = append(mv.StorableEdges, e)
-	}
+	})
 }
 
 // genericMarshalVisitor is a visitor that collects vertices and edges for generic serialization.
 type genericMarshalVisitor[T any] struct {
 	d                  *DAG
 	storableDAGGeneric storableDAGGeneric[T]
+	err                error
 }
 
 func newGenericMarshalVisitor[T any](d *DAG) *genericMarshalVisitor[T] {
@@ -266,8 +355,14 @@ func (mv *genericMarshalVisitor[T]) Visit(v Vertexer) {
 	// Extract vertex ID and value
 	id, value := v.Vertex()
 
-	// Convert value to type T using optimized conversion
-	typedValue := convertToType[T](value)
+	// Convert value to type T, using the fast path where possible
+	typedValue, ok := value.(T)
+	if !ok && value != nil {
+		var err error
+		if typedValue, err = convertViaJSON[T](value); err != nil && mv.err == nil {
+			mv.err = fmt.Errorf("vertex '%s': %w", id, err)
+		}
+	}
 
 	// Add vertex to storable DAG
 	mv.storableDAGGeneric.StorableVertices = append(mv.storableDAGGeneric.StorableVertices, storableVertexGeneric[T]{
@@ -279,10 +374,10 @@ func (mv *genericMarshalVisitor[T]) Visit(v Vertexer) {
 	// Why not use Mutex here?
 	// Because at the time of Walk,
 	// the read lock has been used to protect the dag.
-	children, _ := mv.d.getChildren(id)
-	// Directly iterate over map keys - no need to sort for serialization
-	for dstID := range children {
-		e := storableEdge{SrcID: id, DstID: dstID}
+	// Directly iterate over the children - no need to sort for serialization,
+	// unless the children are to be ordered
+	mv.d.eachChild(mv.d.keyOf(id), func(child vertexHandle) {
+		e := storableEdge{SrcID: id, DstID: mv.d.ids.id(child)}
 		mv.storableDAGGeneric.StorableEdges = append(mv.storableDAGGeneric.StorableEdges, e)
-	}
+	})
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package dag
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -468,3 +469,110 @@ func TestUnmarshalJSONCompatibility(t *testing.T) {
 	// Verify both methods produce equivalent graphs
 	testGraphsEqual(t, dag1, dag2)
 }
+
+func TestUnmarshalJSONValidation(t *testing.T) {
+	type step struct {
+		Cmd     string `json:"cmd"`
+		Retries int    `json:"retries"`
+	}
+	data := []byte(`{"vs":[` +
+		`{"i":"build","v":{"cmd":"make","retries":1}},` +
+		`{"i":"test","v":{"cmd":"","retries":1}},` +
+		`{"i":"deploy","v":{"cmd":"ship","retries":-1}}` +
+		`],"es":[{"s":"build","d":"test"},{"s":"test","d":"deploy"}]}`)
+
+	options := OptionsT[step]{
+		VertexValidator: func(id string, s step) error {
+			if s.Cmd == "" {
+				return fmt.Errorf("missing cmd")
+			}
+			if s.Retries < 0 {
+				return fmt.Errorf("negative retries %d", s.Retries)
+			}
+			return nil
+		},
+	}
+	_, err := UnmarshalGenericJSON[step](data, options)
+	verr, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("UnmarshalGenericJSON() = %v, want ValidationError", err)
+	}
+	want := "invalid vertices: 'test': missing cmd; 'deploy': negative retries -1"
+	if verr.Error() != want {
+		t.Errorf("Error() = %q, want %q", verr.Error(), want)
+	}
+	if errs := verr.VertexErrors(); len(errs) != 2 || errs["test"] == nil || errs["deploy"] == nil {
+		t.Errorf("VertexErrors() = %v, want errors of test and deploy", errs)
+	}
+
+	// untyped validators apply to DAG and TypedDAG as well
+	untyped := Options{VertexValidator: func(id string, v interface{}) error {
+		if id == "test" {
+			return fmt.Errorf("rejected")
+		}
+		return nil
+	}}
+	if _, err := UnmarshalJSON[step](data, untyped); err == nil {
+		t.Error("UnmarshalJSON() expected ValidationError, got nil")
+	}
+	if _, err := UnmarshalJSONGeneric[step](data, untyped); err == nil {
+		t.Error("UnmarshalJSONGeneric() expected ValidationError, got nil")
+	}
+
+	options.VertexValidator = func(id string, s step) error { return nil }
+	d, err := UnmarshalGenericJSON[step](data, options)
+	if err != nil {
+		t.Fatalf("UnmarshalGenericJSON() unexpected error: %v", err)
+	}
+	if d.GetOrder() != 3 || d.GetSize() != 2 {
+		t.Errorf("order = %d, size = %d, want 3, 2", d.GetOrder(), d.GetSize())
+	}
+}
+
+func TestUnmarshalJSONDuplicateEdges(t *testing.T) {
+	data := []byte(`{"vs":[{"i":"a","v":"a"},{"i":"b","v":"b"},{"i":"c","v":"c"}],` +
+		`"es":[{"s":"a","d":"b"},{"s":"b","d":"c"},{"s":"a","d":"b"},{"s":"a","d":"b"}]}`)
+
+	if _, err := UnmarshalGenericJSON[string](data, Options{}); err != (EdgeDuplicateError{"a", "b"}) {
+		t.Errorf("UnmarshalGenericJSON() = %v, want EdgeDuplicateError", err)
+	}
+
+	d, err := UnmarshalGenericJSON[string](data, Options{DuplicateEdges: DuplicateEdgesIgnore})
+	if err != nil {
+		t.Fatalf("UnmarshalGenericJSON() unexpected error: %v", err)
+	}
+	if d.GetSize() != 2 {
+		t.Errorf("GetSize() = %d, want 2", d.GetSize())
+	}
+
+	typed, err := UnmarshalJSON[string](data, Options{DuplicateEdges: DuplicateEdgesWarn})
+	warning, ok := err.(DuplicateEdgesWarning)
+	if !ok {
+		t.Fatalf("UnmarshalJSON() = %v, want DuplicateEdgesWarning", err)
+	}
+	if typed == nil || typed.GetSize() != 2 {
+		t.Fatalf("UnmarshalJSON() returned %v, want graph with 2 edges", typed)
+	}
+	if want := []GenericEdge{{"a", "b"}, {"a", "b"}}; !reflect.DeepEqual(warning.Edges(), want) {
+		t.Errorf("Edges() = %v, want %v", warning.Edges(), want)
+	}
+	if want := "skipped duplicate edges: 'a' -> 'b', 'a' -> 'b'"; warning.Error() != want {
+		t.Errorf("Error() = %q, want %q", warning.Error(), want)
+	}
+
+	// the legacy functions follow the same policy
+	if _, err := UnmarshalJSONGeneric[string](data, Options{DuplicateEdges: DuplicateEdgesWarn}); err == nil {
+		t.Error("UnmarshalJSONGeneric() expected DuplicateEdgesWarning, got nil")
+	}
+	legacyData := []byte(`{"vs":[{"i":"a","v":"a"},{"i":"b","v":"b"}],"es":[{"s":"a","d":"b"},{"s":"a","d":"b"}]}`)
+	var wd testStorableDAG
+	if _, err := UnmarshalJSONLegacy(legacyData, &wd, defaultOptions()); err != (EdgeDuplicateError{"a", "b"}) {
+		t.Errorf("UnmarshalJSONLegacy() = %v, want EdgeDuplicateError", err)
+	}
+	options := defaultOptions()
+	options.DuplicateEdges = DuplicateEdgesIgnore
+	legacy, err := UnmarshalJSONLegacy(legacyData, &wd, options)
+	if err != nil || legacy.GetSize() != 1 {
+		t.Errorf("UnmarshalJSONLegacy() = %v, %v, want graph with 1 edge", legacy, err)
+	}
+}
@@ -0,0 +1,106 @@
+package dag
+
+import "unsafe"
+
+// MemStatsReport describes the approximate memory used by a DAG in bytes.
+//
+// The numbers are estimates based on the sizes of the internal maps. They
+// include the bytes of the vertex ids and the shallow size of the vertex
+// values, but do not follow pointers held by vertex values.
+type MemStatsReport struct {
+	// Vertices is the memory used to index vertices and store their values.
+	Vertices int64
+
+	// Adjacency is the memory used by the in- and outbound edge maps and the
+	// sets of roots and leaves.
+	Adjacency int64
+
+	// AncestorsCache is the memory used by the ancestors-cache.
+	AncestorsCache int64
+
+	// DescendantsCache is the memory used by the descendants-cache.
+	DescendantsCache int64
+
+	// Total is the sum of all of the above.
+	Total int64
+}
+
+const (
+	// mapHeaderSize approximates the fixed cost of a (small) map.
+	mapHeaderSize = 48
+
+	// mapEntryOverhead approximates the per entry bookkeeping of a map
+	// (tophash, overflow pointers and unused slots at average load).
+	mapEntryOverhead = 4
+
+	interfaceSize = int64(unsafe.Sizeof(interface{}(nil)))
+	stringSize    = int64(unsafe.Sizeof(""))
+	pointerSize   = int64(unsafe.Sizeof(uintptr(0)))
+	handleSize    = int64(unsafe.Sizeof(vertexHandle(0)))
+)
+
+// mapBytes estimates the size of a map with the given number of entries and
+// key and value sizes.
+func mapBytes(entries int, keySize, valueSize int64) int64 {
+	return mapHeaderSize + int64(entries)*(keySize+valueSize+mapEntryOverhead)
+}
+
+// setMapBytes estimates the size of a map of sets as used for edges and caches.
+func setMapBytes(m map[vertexHandle]map[vertexHandle]struct{}) int64 {
+	total := mapBytes(len(m), handleSize, pointerSize)
+	for _, set := range m {
+		total += mapBytes(len(set), handleSize, 0)
+	}
+	return total
+}
+
+func (r *MemStatsReport) sum() {
+	r.Total = r.Vertices + r.Adjacency + r.AncestorsCache + r.DescendantsCache
+}
+
+// EstimateMemory returns the approximate memory used by the GenericDAG. It may
+// be used to decide when to call FlushCaches on large graphs.
+func (d *GenericDAG[T]) EstimateMemory() MemStatsReport {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	var zero T
+	var report MemStatsReport
+	idBytes := int64(0)
+	for id := range d.ids.handles {
+		idBytes += int64(len(id))
+	}
+	report.Vertices = idBytes +
+		mapBytes(d.ids.len(), stringSize, handleSize) +
+		int64(cap(d.ids.ids))*stringSize +
+		int64(cap(d.values))*int64(unsafe.Sizeof(zero)) +
+		mapBytes(len(d.hashes), interfaceSize, handleSize)
+	if d.collisions != nil {
+		report.Vertices += mapBytes(len(d.collisions), interfaceSize, 3*pointerSize)
+		for _, others := range d.collisions {
+			report.Vertices += int64(cap(others)) * handleSize
+		}
+	}
+	report.Adjacency = setMapBytes(d.inboundEdge) + setMapBytes(d.outboundEdge) +
+		mapBytes(len(d.roots), handleSize, 0) + mapBytes(len(d.leaves), handleSize, 0)
+	if d.childLists != nil {
+		report.Adjacency += mapBytes(len(d.childLists), handleSize, 3*pointerSize)
+		for _, list := range d.childLists {
+			report.Adjacency += int64(cap(list)) * handleSize
+		}
+	}
+
+	d.muCache.RLock()
+	report.AncestorsCache = setMapBytes(d.ancestorsCache)
+	report.DescendantsCache = setMapBytes(d.descendantsCache)
+	d.muCache.RUnlock()
+
+	report.sum()
+	return report
+}
+
+// EstimateMemory returns the approximate memory used by the TypedDAG. It may
+// be used to decide when to call FlushCaches on large graphs.
+func (d *TypedDAG[T]) EstimateMemory() MemStatsReport {
+	return d.inner.EstimateMemory()
+}
@@ -0,0 +1,47 @@
+package dag
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestEstimateMemory(t *testing.T) {
+	d := NewGenericDAG[int]()
+	empty := d.EstimateMemory()
+
+	for i := 0; i < 100; i++ {
+		d.MustAddVertexByID(strconv.Itoa(i), i)
+		if i > 0 {
+			d.MustAddEdge(strconv.Itoa(i-1), strconv.Itoa(i))
+		}
+	}
+	// AddEdge populates the caches to maintain them, so start from scratch
+	d.FlushCaches()
+	built := d.EstimateMemory()
+	if built.Vertices <= empty.Vertices || built.Adjacency <= empty.Adjacency {
+		t.Errorf("estimate should grow with the graph: %+v vs. %+v", built, empty)
+	}
+	if built.DescendantsCache != empty.DescendantsCache {
+		t.Errorf("descendants cache should be empty before any query: %+v", built)
+	}
+
+	_, _ = d.GetDescendants("0")
+	cached := d.EstimateMemory()
+	if cached.DescendantsCache <= built.DescendantsCache {
+		t.Errorf("descendants cache estimate should grow after GetDescendants: %+v", cached)
+	}
+	if cached.Total != cached.Vertices+cached.Adjacency+cached.AncestorsCache+cached.DescendantsCache {
+		t.Errorf("Total should be the sum of all parts: %+v", cached)
+	}
+
+	d.FlushCaches()
+	if flushed := d.EstimateMemory(); flushed.DescendantsCache != empty.DescendantsCache {
+		t.Errorf("descendants cache estimate should drop after FlushCaches: %+v", flushed)
+	}
+
+	legacy := NewDAG()
+	legacy.MustAddVertexByID("a", 1)
+	if report := legacy.EstimateMemory(); report.Vertices == 0 || report.Total == 0 {
+		t.Errorf("EstimateMemory() = %+v, want non-zero estimates", report)
+	}
+}
@@ -0,0 +1,109 @@
+package dag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+)
+
+// ComputeMerkleHashes computes the Merkle hash of each vertex, which is a
+// SHA-256 digest of the value of the vertex and of the Merkle hashes of its
+// parents along with the values of the edges from them. Thus, the Merkle hash
+// of a vertex changes iff its value or the value of any of its ancestors, or
+// the edges between them or their values change, which allows to invalidate
+// artifacts derived from a vertex and its inputs like build systems do. As a
+// vertex is identified by its content, its id doesn't contribute to its
+// Merkle hash.
+//
+// Values are encoded like by Hash. The hashes are computed by the first call
+// to GetMerkleHash after a modification of the graph anyway;
+// ComputeMerkleHashes allows to compute them in advance.
+func (d *GenericDAG[T]) ComputeMerkleHashes() {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	d.getMerkleHashes()
+}
+
+// GetMerkleHash returns the Merkle hash of the vertex with id, see
+// ComputeMerkleHashes. Modifications of values through pointers are only
+// noticed after calling Rehash for the modified vertex. GetMerkleHash returns
+// an error if id is empty or unknown.
+func (d *GenericDAG[T]) GetMerkleHash(id string) ([32]byte, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return [32]byte{}, err
+	}
+	return d.getMerkleHashes()[d.keyOf(id)], nil
+}
+
+// getMerkleHashes returns the Merkle hashes of all vertices, which are
+// computed if the graph has been modified since they have been computed last.
+func (d *GenericDAG[T]) getMerkleHashes() map[vertexHandle][32]byte {
+	d.muCache.RLock()
+	hashes, mutations := d.merkleHashes, d.merkleMutations
+	d.muCache.RUnlock()
+	if hashes != nil && mutations == d.mutations {
+		return hashes
+	}
+
+	// visit the vertices from the roots downwards, so that the hashes of all
+	// parents of a vertex are known when the vertex is visited
+	hashes = make(map[vertexHandle][32]byte, d.ids.len())
+	pending := make(map[vertexHandle]int, d.ids.len())
+	ready := d.rootHandles()
+	for h := range d.inboundEdge {
+		pending[h] = len(d.inboundEdge[h])
+	}
+	for len(ready) > 0 {
+		h := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+
+		// each parent contributes its hash followed by the value of the edge
+		// to h, if it has one
+		parents := make([][]byte, 0, len(d.inboundEdge[h]))
+		for parent := range d.inboundEdge[h] {
+			hash := hashes[parent]
+			entry := hash[:]
+			if value, ok := d.edgeValues[[2]vertexHandle{parent, h}]; ok {
+				entry = append(entry, hashedEdgeValue(value)...)
+			}
+			parents = append(parents, entry)
+		}
+		// sort the entries of the parents, so that their order doesn't matter
+		sort.Slice(parents, func(i, j int) bool {
+			return bytes.Compare(parents[i], parents[j]) < 0
+		})
+		sum := sha256.New()
+		writeHashField(sum, d.hashedValue(d.values[h]))
+		for _, parent := range parents {
+			writeHashField(sum, parent)
+		}
+		var hash [32]byte
+		copy(hash[:], sum.Sum(nil))
+		hashes[h] = hash
+
+		for child := range d.outboundEdge[h] {
+			if pending[child]--; pending[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+
+	d.muCache.Lock()
+	d.merkleHashes, d.merkleMutations = hashes, d.mutations
+	d.muCache.Unlock()
+	return hashes
+}
+
+// ComputeMerkleHashes computes the Merkle hash of each vertex. See
+// GenericDAG.ComputeMerkleHashes for details.
+func (d *TypedDAG[T]) ComputeMerkleHashes() {
+	d.inner.ComputeMerkleHashes()
+}
+
+// GetMerkleHash returns the Merkle hash of the vertex with id. See
+// GenericDAG.GetMerkleHash for details.
+func (d *TypedDAG[T]) GetMerkleHash(id string) ([32]byte, error) {
+	return d.inner.GetMerkleHash(id)
+}
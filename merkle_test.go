@@ -0,0 +1,71 @@
+package dag
+
+import "testing"
+
+func TestMerkleHashes(t *testing.T) {
+	/*   a   b
+	 *   |\ /
+	 *   c d
+	 */
+	type file struct{ Content string }
+	d := New[*file](WithIdentityHash())
+	files := map[string]*file{"a": {"a"}, "b": {"b"}, "c": {"c"}, "d": {"d"}}
+	for id, f := range files {
+		d.MustAddVertexByID(id, f)
+	}
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("a", "d")
+	d.MustAddEdge("b", "d")
+
+	hashes := func() map[string][32]byte {
+		out := make(map[string][32]byte)
+		for id := range files {
+			hash, err := d.GetMerkleHash(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out[id] = hash
+		}
+		return out
+	}
+	d.ComputeMerkleHashes()
+	before := hashes()
+	changed := func(want ...string) {
+		t.Helper()
+		after := hashes()
+		for id := range files {
+			wantChanged := false
+			for _, w := range want {
+				wantChanged = wantChanged || w == id
+			}
+			if (after[id] != before[id]) != wantChanged {
+				t.Errorf("Merkle hash of %s changed = %v, want %v", id, !wantChanged, wantChanged)
+			}
+		}
+		before = after
+	}
+
+	// modifications through pointers are noticed after rehashing
+	files["b"].Content = "b2"
+	changed()
+	if err := d.Rehash("b"); err != nil {
+		t.Fatal(err)
+	}
+	changed("b", "d")
+
+	d.MustAddEdge("c", "d")
+	changed("d")
+	if err := d.DeleteEdge("a", "c"); err != nil {
+		t.Fatal(err)
+	}
+	changed("c", "d")
+
+	// the id doesn't contribute to the hash
+	d.MustAddVertexByID("e", &file{"a"})
+	if e, _ := d.GetMerkleHash("e"); e != before["a"] {
+		t.Error("Merkle hash of e differs from a with equal content")
+	}
+	if _, err := d.GetMerkleHash("x"); err != (IDUnknownError{"x"}) {
+		t.Errorf("GetMerkleHash(x) = %v, want IDUnknownError", err)
+	}
+}
@@ -0,0 +1,117 @@
+package dag
+
+// Density returns the ratio of the number of edges to the number of edges a
+// directed graph of the same order can have at most, i.e. a value between 0
+// for a graph without edges and 0.5 for a DAG with all possible edges. The
+// density of a graph with fewer than two vertices is 0.
+func (d *GenericDAG[T]) Density() float64 {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	order := d.getOrder()
+	if order < 2 {
+		return 0
+	}
+	return float64(d.getSize()) / float64(order*(order-1))
+}
+
+// AverageDegree returns the average number of edges (in- and outbound) per
+// vertex, or 0 for an empty graph.
+func (d *GenericDAG[T]) AverageDegree() float64 {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	order := d.getOrder()
+	if order == 0 {
+		return 0
+	}
+	return 2 * float64(d.getSize()) / float64(order)
+}
+
+// MaxLevelWidth returns the largest number of vertices on the same level,
+// where the roots are on level 0 and each other vertex is one level below
+// its lowest parent. This is the number of vertices a level-by-level
+// execution of the graph may run concurrently at most.
+func (d *GenericDAG[T]) MaxLevelWidth() int {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	width := 0
+	for _, level := range d.levels() {
+		if len(level) > width {
+			width = len(level)
+		}
+	}
+	return width
+}
+
+// LevelHistogram returns the number of vertices on each level, where the
+// roots are on level 0 and each other vertex is one level below its lowest
+// parent, i.e. the widths of the levels, whose maximum is MaxLevelWidth. The
+// histogram of an empty graph is empty.
+func (d *GenericDAG[T]) LevelHistogram() []int {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	levels := d.levels()
+	histogram := make([]int, len(levels))
+	for i, level := range levels {
+		histogram[i] = len(level)
+	}
+	return histogram
+}
+
+// levels returns the vertices of each level, where the roots are on level 0
+// and each other vertex is one level below its lowest parent. levels must be
+// called with the read lock held.
+func (d *GenericDAG[T]) levels() []map[vertexHandle]struct{} {
+	// process the vertices in topological order by counting down the
+	// number of unvisited parents of each vertex
+	pending := make(map[vertexHandle]int, len(d.inboundEdge))
+	for h, parents := range d.inboundEdge {
+		pending[h] = len(parents)
+	}
+	levelOf := make(map[vertexHandle]int, d.getOrder())
+	var levels []map[vertexHandle]struct{}
+	queue := d.rootHandles()
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		level := levelOf[h]
+		if level == len(levels) {
+			levels = append(levels, make(map[vertexHandle]struct{}))
+		}
+		levels[level][h] = struct{}{}
+		d.eachChild(h, func(child vertexHandle) {
+			if levelOf[child] < level+1 {
+				levelOf[child] = level + 1
+			}
+			pending[child]--
+			if pending[child] == 0 {
+				queue = append(queue, child)
+			}
+		})
+	}
+	return levels
+}
+
+// Density returns the ratio of the number of edges to the number of possible
+// edges. See GenericDAG.Density for details.
+func (d *TypedDAG[T]) Density() float64 {
+	return d.inner.Density()
+}
+
+// AverageDegree returns the average number of edges per vertex.
+func (d *TypedDAG[T]) AverageDegree() float64 {
+	return d.inner.AverageDegree()
+}
+
+// MaxLevelWidth returns the largest number of vertices on the same level.
+// See GenericDAG.MaxLevelWidth for details.
+func (d *TypedDAG[T]) MaxLevelWidth() int {
+	return d.inner.MaxLevelWidth()
+}
+
+// LevelHistogram returns the number of vertices on each level. See
+// GenericDAG.LevelHistogram for details.
+func (d *TypedDAG[T]) LevelHistogram() []int {
+	return d.inner.LevelHistogram()
+}
@@ -0,0 +1,51 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	d := New[string]()
+	if d.Density() != 0 || d.AverageDegree() != 0 || d.MaxLevelWidth() != 0 {
+		t.Errorf("metrics of empty graph = %v, %v, %v, want 0, 0, 0", d.Density(), d.AverageDegree(), d.MaxLevelWidth())
+	}
+	if got := d.LevelHistogram(); len(got) != 0 {
+		t.Errorf("LevelHistogram() of empty graph = %v, want []", got)
+	}
+
+	/*   a   b
+	 *   |\ /|
+	 *   c d |
+	 *    \| /
+	 *     e
+	 */
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "c"}, {"a", "d"}, {"b", "d"}, {"b", "e"}, {"c", "e"}, {"d", "e"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+	if got, want := d.Density(), 6.0/20; got != want {
+		t.Errorf("Density() = %v, want %v", got, want)
+	}
+	if got, want := d.AverageDegree(), 12.0/5; got != want {
+		t.Errorf("AverageDegree() = %v, want %v", got, want)
+	}
+	if got := d.MaxLevelWidth(); got != 2 {
+		t.Errorf("MaxLevelWidth() = %d, want 2", got)
+	}
+	if got, want := d.LevelHistogram(), []int{2, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LevelHistogram() = %v, want %v", got, want)
+	}
+
+	wide := New[string]()
+	wide.MustAddVertexByID("root", "root")
+	for _, id := range []string{"x", "y", "z"} {
+		wide.MustAddVertexByID(id, id)
+		wide.MustAddEdge("root", id)
+	}
+	if got := wide.MaxLevelWidth(); got != 3 {
+		t.Errorf("MaxLevelWidth() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,43 @@
+package dag
+
+// MustAddVertex is like AddVertex but panics if the vertex cannot be added.
+// It simplifies building known-good graphs in tests and initialization code.
+func (d *GenericDAG[T]) MustAddVertex(v T) string {
+	id, err := d.AddVertex(v)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// MustAddVertexByID is like AddVertexByID but panics if the vertex cannot be
+// added.
+func (d *GenericDAG[T]) MustAddVertexByID(id string, v T) {
+	if err := d.AddVertexByID(id, v); err != nil {
+		panic(err)
+	}
+}
+
+// MustAddEdge is like AddEdge but panics if the edge cannot be added.
+func (d *GenericDAG[T]) MustAddEdge(srcID, dstID string) {
+	if err := d.AddEdge(srcID, dstID); err != nil {
+		panic(err)
+	}
+}
+
+// MustAddVertex is like AddVertex but panics if the vertex cannot be added.
+// It simplifies building known-good graphs in tests and initialization code.
+func (d *TypedDAG[T]) MustAddVertex(v T) string {
+	return d.inner.MustAddVertex(v)
+}
+
+// MustAddVertexByID is like AddVertexByID but panics if the vertex cannot be
+// added.
+func (d *TypedDAG[T]) MustAddVertexByID(id string, v T) {
+	d.inner.MustAddVertexByID(id, v)
+}
+
+// MustAddEdge is like AddEdge but panics if the edge cannot be added.
+func (d *TypedDAG[T]) MustAddEdge(srcID, dstID string) {
+	d.inner.MustAddEdge(srcID, dstID)
+}
@@ -0,0 +1,34 @@
+package dag
+
+import "testing"
+
+func expectPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s should panic", name)
+		}
+	}()
+	f()
+}
+
+func TestMustAdd(t *testing.T) {
+	d := New[string]()
+	d.MustAddVertexByID("a", "A")
+	b := d.MustAddVertex("B")
+	d.MustAddEdge("a", b)
+	if d.GetOrder() != 2 || d.GetSize() != 1 {
+		t.Errorf("got %d vertices and %d edges, want 2 and 1", d.GetOrder(), d.GetSize())
+	}
+
+	expectPanic(t, "MustAddVertexByID with a known id", func() { d.MustAddVertexByID("a", "C") })
+	expectPanic(t, "MustAddVertex with a known value", func() { d.MustAddVertex("A") })
+	expectPanic(t, "MustAddEdge creating a loop", func() { d.MustAddEdge(b, "a") })
+
+	legacy := NewDAG()
+	v1 := legacy.MustAddVertex(1)
+	legacy.MustAddVertexByID("2", 2)
+	legacy.MustAddEdge(v1, "2")
+	expectPanic(t, "MustAddVertex with nil", func() { legacy.MustAddVertex(nil) })
+	expectPanic(t, "MustAddEdge with a known edge", func() { legacy.MustAddEdge(v1, "2") })
+}
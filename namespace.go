@@ -0,0 +1,196 @@
+package dag
+
+import (
+	"sort"
+	"strings"
+)
+
+// NamespaceSeparator separates the namespace of a vertex id from the id
+// local to the namespace, e.g. "teamA/build" is the vertex "build" of the
+// namespace "teamA". Ids without NamespaceSeparator belong to the global
+// namespace "".
+const NamespaceSeparator = "/"
+
+// NamespacedID returns the id of the vertex with the local id in namespace
+// ns. If ns is empty, NamespacedID returns id unchanged. ns must not contain
+// NamespaceSeparator.
+func NamespacedID(ns, id string) string {
+	if ns == "" {
+		return id
+	}
+	return ns + NamespaceSeparator + id
+}
+
+// SplitNamespace splits id at the first NamespaceSeparator into its namespace
+// and the id local to the namespace. SplitNamespace returns an empty
+// namespace and id for ids of the global namespace.
+func SplitNamespace(id string) (ns, local string) {
+	if i := strings.Index(id, NamespaceSeparator); i >= 0 {
+		return id[:i], id[i+len(NamespaceSeparator):]
+	}
+	return "", id
+}
+
+// saneNamespaces returns a CrossNamespaceEdgeError, if the GenericDAG
+// disallows edges between namespaces and srcID and dstID are in different
+// ones.
+func (d *GenericDAG[T]) saneNamespaces(srcID, dstID string) error {
+	if !d.options.DisallowCrossNamespaceEdges {
+		return nil
+	}
+	if namespaceOf(srcID) != namespaceOf(dstID) {
+		return CrossNamespaceEdgeError{srcID, dstID}
+	}
+	return nil
+}
+
+// namespaceOf returns the namespace of id.
+func namespaceOf(id string) string {
+	ns, _ := SplitNamespace(id)
+	return ns
+}
+
+// GetNamespace returns all vertices of namespace ns by their (full) ids.
+func (d *GenericDAG[T]) GetNamespace(ns string) map[string]T {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	vertices := make(map[string]T)
+	d.ids.each(func(id string, h vertexHandle) bool {
+		if namespaceOf(id) == ns {
+			vertices[id] = d.values[h]
+		}
+		return true
+	})
+	return vertices
+}
+
+// GetNamespaces returns the namespaces of all vertices in sorted order,
+// including the global namespace "" if it has any vertices.
+func (d *GenericDAG[T]) GetNamespaces() []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	seen := make(map[string]struct{})
+	d.ids.each(func(id string, _ vertexHandle) bool {
+		seen[namespaceOf(id)] = struct{}{}
+		return true
+	})
+	namespaces := make([]string, 0, len(seen))
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// NamespacedDAG is a view of a GenericDAG scoped to a namespace: the ids
+// passed to and returned by its methods are local to the namespace. Errors
+// refer to the full ids of the vertices. All changes go to the GenericDAG, so
+// several views of different namespaces can share a graph.
+type NamespacedDAG[T any] struct {
+	d  *GenericDAG[T]
+	ns string
+}
+
+// WithNamespace returns a view of the GenericDAG scoped to namespace ns. ns
+// must not contain NamespaceSeparator.
+func (d *GenericDAG[T]) WithNamespace(ns string) *NamespacedDAG[T] {
+	return &NamespacedDAG[T]{d: d, ns: ns}
+}
+
+// Namespace returns the namespace of the view.
+func (n *NamespacedDAG[T]) Namespace() string {
+	return n.ns
+}
+
+// ID returns the full id of the vertex with the local id.
+func (n *NamespacedDAG[T]) ID(id string) string {
+	return NamespacedID(n.ns, id)
+}
+
+// Graph returns the underlying GenericDAG.
+func (n *NamespacedDAG[T]) Graph() *GenericDAG[T] {
+	return n.d
+}
+
+// AddVertexByID adds the vertex with the local id and value v to the
+// namespace. See GenericDAG.AddVertexByID for details.
+func (n *NamespacedDAG[T]) AddVertexByID(id string, v T) error {
+	if id == "" {
+		return IDEmptyError{}
+	}
+	return n.d.AddVertexByID(n.ID(id), v)
+}
+
+// GetVertex returns the value of the vertex with the local id.
+func (n *NamespacedDAG[T]) GetVertex(id string) (T, error) {
+	if id == "" {
+		var zero T
+		return zero, IDEmptyError{}
+	}
+	return n.d.GetVertex(n.ID(id))
+}
+
+// GetVertices returns all vertices of the namespace by their local ids.
+func (n *NamespacedDAG[T]) GetVertices() map[string]T {
+	vertices := n.d.GetNamespace(n.ns)
+	local := make(map[string]T, len(vertices))
+	for id, v := range vertices {
+		_, localID := SplitNamespace(id)
+		local[localID] = v
+	}
+	return local
+}
+
+// DeleteVertex deletes the vertex with the local id.
+func (n *NamespacedDAG[T]) DeleteVertex(id string) error {
+	if id == "" {
+		return IDEmptyError{}
+	}
+	return n.d.DeleteVertex(n.ID(id))
+}
+
+// AddEdge adds an edge between the vertices with the local ids srcID and
+// dstID. See GenericDAG.AddEdge for details.
+func (n *NamespacedDAG[T]) AddEdge(srcID, dstID string) error {
+	if srcID == "" || dstID == "" {
+		return IDEmptyError{}
+	}
+	return n.d.AddEdge(n.ID(srcID), n.ID(dstID))
+}
+
+// IsEdge returns true if there is an edge between the vertices with the
+// local ids srcID and dstID.
+func (n *NamespacedDAG[T]) IsEdge(srcID, dstID string) (bool, error) {
+	if srcID == "" || dstID == "" {
+		return false, IDEmptyError{}
+	}
+	return n.d.IsEdge(n.ID(srcID), n.ID(dstID))
+}
+
+// DeleteEdge deletes the edge between the vertices with the local ids srcID
+// and dstID.
+func (n *NamespacedDAG[T]) DeleteEdge(srcID, dstID string) error {
+	if srcID == "" || dstID == "" {
+		return IDEmptyError{}
+	}
+	return n.d.DeleteEdge(n.ID(srcID), n.ID(dstID))
+}
+
+// GetNamespace returns all vertices of namespace ns by their (full) ids.
+func (d *TypedDAG[T]) GetNamespace(ns string) map[string]T {
+	return d.inner.GetNamespace(ns)
+}
+
+// GetNamespaces returns the namespaces of all vertices in sorted order. See
+// GenericDAG.GetNamespaces for details.
+func (d *TypedDAG[T]) GetNamespaces() []string {
+	return d.inner.GetNamespaces()
+}
+
+// WithNamespace returns a view of the TypedDAG scoped to namespace ns. See
+// GenericDAG.WithNamespace for details.
+func (d *TypedDAG[T]) WithNamespace(ns string) *NamespacedDAG[T] {
+	return d.inner.WithNamespace(ns)
+}
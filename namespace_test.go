@@ -0,0 +1,73 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitNamespace(t *testing.T) {
+	for _, tc := range []struct{ id, ns, local string }{
+		{"teamA/build", "teamA", "build"},
+		{"teamA/build/x", "teamA", "build/x"},
+		{"build", "", "build"},
+	} {
+		if ns, local := SplitNamespace(tc.id); ns != tc.ns || local != tc.local {
+			t.Errorf("SplitNamespace(%q) = %q, %q, want %q, %q", tc.id, ns, local, tc.ns, tc.local)
+		}
+	}
+	if id := NamespacedID("teamA", "build"); id != "teamA/build" {
+		t.Errorf("NamespacedID() = %q, want teamA/build", id)
+	}
+	if id := NamespacedID("", "build"); id != "build" {
+		t.Errorf("NamespacedID() = %q, want build", id)
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	d := New[int](WithoutCrossNamespaceEdges())
+	a, b := d.WithNamespace("teamA"), d.WithNamespace("teamB")
+	_ = a.AddVertexByID("build", 1)
+	_ = a.AddVertexByID("test", 2)
+	_ = b.AddVertexByID("build", 3)
+	d.MustAddVertexByID("shared", 4)
+
+	if err := a.AddEdge("build", "test"); err != nil {
+		t.Fatalf("AddEdge() unexpected error: %v", err)
+	}
+	if isEdge, _ := d.IsEdge("teamA/build", "teamA/test"); !isEdge {
+		t.Error("edge teamA/build -> teamA/test missing")
+	}
+	if err := d.AddEdge("teamA/build", "teamB/build"); err != (CrossNamespaceEdgeError{"teamA/build", "teamB/build"}) {
+		t.Errorf("AddEdge() = %v, want CrossNamespaceEdgeError", err)
+	}
+	if err := d.AddEdge("shared", "teamB/build"); err == nil {
+		t.Error("AddEdge() from the global namespace expected CrossNamespaceEdgeError")
+	}
+
+	if got, want := a.GetVertices(), map[string]int{"build": 1, "test": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetVertices() = %v, want %v", got, want)
+	}
+	if got, want := d.GetNamespace("teamB"), map[string]int{"teamB/build": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetNamespace(teamB) = %v, want %v", got, want)
+	}
+	if got, want := d.GetNamespaces(), []string{"", "teamA", "teamB"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetNamespaces() = %v, want %v", got, want)
+	}
+	if v, err := b.GetVertex("build"); err != nil || v != 3 {
+		t.Errorf("GetVertex(build) = %v, %v, want 3", v, err)
+	}
+	if _, err := a.GetVertex(""); err != (IDEmptyError{}) {
+		t.Errorf("GetVertex(\"\") = %v, want IDEmptyError", err)
+	}
+	if err := a.DeleteVertex("test"); err != nil || d.GetOrder() != 3 {
+		t.Errorf("DeleteVertex(test) = %v, order %d, want nil, 3", err, d.GetOrder())
+	}
+
+	// without the option, edges may cross namespaces
+	open := New[int]()
+	open.MustAddVertexByID("teamA/x", 1)
+	open.MustAddVertexByID("teamB/y", 2)
+	if err := open.AddEdge("teamA/x", "teamB/y"); err != nil {
+		t.Errorf("AddEdge() unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package dag
+
+// NestedIDSeparator separates the id of a nested vertex from the ids of the
+// vertices of its graph in a flattened graph, e.g. "build/compile" is the
+// vertex "compile" of the graph nested in the vertex "build".
+//
+// NestedIDSeparator equals NamespaceSeparator, so the vertices of a graph
+// nested in a vertex of the global namespace form a namespace named by that
+// vertex, while those nested in a namespaced vertex stay in its namespace.
+const NestedIDSeparator = "/"
+
+// nestedGraph returns the graph nested in a vertex with the value v, if v is a
+// *GenericDAG[T] or, for DAG, a *DAG.
+func nestedGraph[T any](v T) (*GenericDAG[T], bool) {
+	switch g := any(v).(type) {
+	case *GenericDAG[T]:
+		return g, g != nil
+	case *DAG:
+		if g == nil {
+			return nil, false
+		}
+		nested, ok := any(g.dagCore).(*GenericDAG[T])
+		return nested, ok
+	}
+	return nil, false
+}
+
+// FlattenNested returns a new GenericDAG, in which each vertex whose value is
+// a non-empty *GenericDAG[T] (a nested graph, e.g. a reusable sub-pipeline)
+// is replaced by the vertices and edges of that graph. Nested graphs are
+// flattened recursively, and the ids of their vertices are prefixed with the
+// id of the nested vertex and NestedIDSeparator. The edges of a nested
+// vertex are attached to the roots (inbound edges) and leaves (outbound
+// edges) of its graph, and keep their values. Vertices with an empty nested
+// graph are kept as they are.
+//
+// As the same graph may be nested in several vertices (which requires
+// WithDuplicateValues for the graph nesting them), the new GenericDAG allows
+// duplicate values. FlattenNested returns a NestedLoopError if a nested
+// graph contains itself, and an IDDuplicateError if a prefixed id collides
+// with another id. With Options.DisallowCrossNamespaceEdges, it returns a
+// CrossNamespaceEdgeError if a flattened edge crosses namespaces, e.g. the
+// edge from "a" to the vertex "build/compile" nested in "build"; namespaced
+// ids like "ci/build" keep their nested vertices in their namespace.
+func (d *GenericDAG[T]) FlattenNested() (*GenericDAG[T], error) {
+	d.muDAG.RLock()
+	options := d.options
+	d.muDAG.RUnlock()
+	options.AllowDuplicateValues = true
+
+	flat := NewGenericDAG[T](WithOptions(options))
+	active := map[*GenericDAG[T]]struct{}{d: {}}
+	if _, _, err := d.flattenInto(flat, "", active); err != nil {
+		return nil, err
+	}
+	return flat, nil
+}
+
+// flattenInto adds the vertices and edges of the GenericDAG to flat, with ids
+// prefixed by prefix, and returns the ids in flat of its roots and leaves.
+// active holds the graphs currently being flattened.
+func (d *GenericDAG[T]) flattenInto(flat *GenericDAG[T], prefix string, active map[*GenericDAG[T]]struct{}) (roots, leaves []string, err error) {
+	d.muDAG.RLock()
+	s := d.snapshot()
+	d.muDAG.RUnlock()
+
+	// entries and exits hold for each vertex the ids in flat its inbound and
+	// outbound edges are attached to, which differ for nested vertices only
+	entries := make(map[string][]string, len(s.ids))
+	exits := make(map[string][]string, len(s.ids))
+	for i, id := range s.ids {
+		flatID := prefix + id
+		if nested, ok := nestedGraph(s.values[i]); ok {
+			if _, exists := active[nested]; exists {
+				return nil, nil, NestedLoopError{flatID}
+			}
+			active[nested] = struct{}{}
+			entries[id], exits[id], err = nested.flattenInto(flat, flatID+NestedIDSeparator, active)
+			delete(active, nested)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(entries[id]) > 0 {
+				continue
+			}
+		}
+		if err = flat.addVertexByID(flatID, s.values[i]); err != nil {
+			return nil, nil, err
+		}
+		entries[id], exits[id] = []string{flatID}, []string{flatID}
+	}
+
+	// the edges attached to a nested vertex all get the value of its edge
+	values := make(map[[2]string]interface{}, len(s.edgeValues))
+	for _, v := range s.edgeValues {
+		values[[2]string{v.SrcID, v.DstID}] = v.Value
+	}
+	for _, e := range s.edges {
+		value, hasValue := values[e]
+		for _, src := range exits[e[0]] {
+			for _, dst := range entries[e[1]] {
+				if err = flat.saneNamespaces(src, dst); err != nil {
+					return nil, nil, err
+				}
+				srcHash, dstHash := flat.keyOf(src), flat.keyOf(dst)
+				flat.linkEdge(srcHash, dstHash)
+				if hasValue {
+					flat.setEdgeValue(srcHash, dstHash, value)
+				}
+			}
+		}
+	}
+	rootIDs, leafIDs := s.rootsAndLeaves()
+	for _, id := range rootIDs {
+		roots = append(roots, entries[id]...)
+	}
+	for _, id := range leafIDs {
+		leaves = append(leaves, exits[id]...)
+	}
+	return roots, leaves, nil
+}
+
+// FlattenNested returns a new DAG, in which each vertex whose value is a
+// non-empty *DAG is replaced by the vertices and edges of that DAG. See
+// GenericDAG.FlattenNested for details.
+func (d *DAG) FlattenNested() (*DAG, error) {
+	core, err := d.dagCore.FlattenNested()
+	if err != nil {
+		return nil, err
+	}
+	return &DAG{core}, nil
+}
+
+// FlattenNested returns a new TypedDAG, in which each vertex whose value is a
+// non-empty *GenericDAG[T] is replaced by the vertices and edges of that
+// graph. See GenericDAG.FlattenNested for details.
+func (d *TypedDAG[T]) FlattenNested() (*TypedDAG[T], error) {
+	inner, err := d.inner.FlattenNested()
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDAG[T]{inner: inner}, nil
+}
+
+// NestedDescendantsFlow is like DescendantsFlow, but executes the flows of
+// nested DAGs recursively: it runs on the flattened DAG (see FlattenNested),
+// so callback is called for each vertex of a nested DAG with its prefixed id,
+// once all vertices its nested vertex depends on have finished their work.
+// NestedDescendantsFlow returns an error if the DAG can't be flattened or
+// startID is not a vertex of the flattened DAG, e.g. because it has a
+// nested DAG itself.
+func (d *DAG) NestedDescendantsFlow(startID string, inputs []FlowResult, callback FlowCallback) ([]FlowResult, error) {
+	flat, err := d.FlattenNested()
+	if err != nil {
+		return []FlowResult{}, err
+	}
+	return flat.DescendantsFlow(startID, inputs, callback)
+}
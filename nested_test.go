@@ -0,0 +1,171 @@
+package dag
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// newNestedTestDAG returns the DAG
+//
+//	start -> build -> deploy
+//
+// where build nests compile -> {test, lint}, and deploy nests an empty DAG.
+func newNestedTestDAG() (d, build *DAG) {
+	build = NewDAG()
+	_ = build.AddVertexByID("compile", "compile")
+	_ = build.AddVertexByID("test", "test")
+	_ = build.AddVertexByID("lint", "lint")
+	_ = build.AddEdge("compile", "test")
+	_ = build.AddEdge("compile", "lint")
+
+	d = NewDAG()
+	_ = d.AddVertexByID("start", "start")
+	_ = d.AddVertexByID("build", build)
+	_ = d.AddVertexByID("deploy", NewDAG())
+	_ = d.AddEdge("start", "build")
+	_ = d.AddEdge("build", "deploy")
+	return d, build
+}
+
+func TestFlattenNested(t *testing.T) {
+	d, _ := newNestedTestDAG()
+
+	flat, err := d.FlattenNested()
+	if err != nil {
+		t.Fatalf("FlattenNested() unexpected error: %v", err)
+	}
+	var edges []string
+	for _, e := range flat.GetEdges().Edges {
+		edges = append(edges, e.SrcID+"->"+e.DstID)
+	}
+	sort.Strings(edges)
+	want := []string{
+		"build/compile->build/lint",
+		"build/compile->build/test",
+		"build/lint->deploy",
+		"build/test->deploy",
+		"start->build/compile",
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("FlattenNested() edges = %v, want %v", edges, want)
+	}
+	if flat.GetOrder() != 5 {
+		t.Errorf("FlattenNested() order = %d, want 5", flat.GetOrder())
+	}
+	if v, _ := flat.GetVertex("build/test"); v != "test" {
+		t.Errorf("GetVertex(build/test) = %v, want test", v)
+	}
+}
+
+func TestFlattenNested_Reused(t *testing.T) {
+	sub := NewGenericDAG[interface{}]()
+	_ = sub.AddVertexByID("a", 1)
+	_ = sub.AddVertexByID("b", 2)
+	_ = sub.AddEdge("a", "b")
+
+	outer := NewGenericDAG[interface{}](WithDuplicateValues())
+	_ = outer.AddVertexByID("x", sub)
+	_ = outer.AddVertexByID("y", sub)
+	_ = outer.AddEdge("x", "y")
+
+	// outer nested twice, so sub appears four times
+	top := NewGenericDAG[interface{}](WithDuplicateValues())
+	_ = top.AddVertexByID("p", outer)
+	_ = top.AddVertexByID("q", outer)
+
+	flat, err := top.FlattenNested()
+	if err != nil {
+		t.Fatalf("FlattenNested() unexpected error: %v", err)
+	}
+	if flat.GetOrder() != 8 || flat.GetSize() != 6 {
+		t.Errorf("FlattenNested() order = %d, size = %d, want 8, 6", flat.GetOrder(), flat.GetSize())
+	}
+	if ok, _ := flat.IsEdge("q/x/b", "q/y/a"); !ok {
+		t.Error("IsEdge(q/x/b, q/y/a) = false, want true")
+	}
+}
+
+func TestFlattenNested_Loop(t *testing.T) {
+	d := NewDAG()
+	sub := NewDAG()
+	_ = d.AddVertexByID("a", sub)
+	_ = sub.AddVertexByID("b", d)
+
+	_, err := d.FlattenNested()
+	if _, ok := err.(NestedLoopError); !ok {
+		t.Errorf("FlattenNested() = %v, want NestedLoopError", err)
+	}
+}
+
+func TestNestedDescendantsFlow(t *testing.T) {
+	d, _ := newNestedTestDAG()
+
+	var mu sync.Mutex
+	var visited []string
+	results, err := d.NestedDescendantsFlow("start", nil, func(d *DAG, id string, parentResults []FlowResult) (interface{}, error) {
+		mu.Lock()
+		visited = append(visited, id)
+		mu.Unlock()
+		return len(parentResults), nil
+	})
+	if err != nil {
+		t.Fatalf("NestedDescendantsFlow() unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "deploy" || results[0].Result.(int) != 2 {
+		t.Errorf("NestedDescendantsFlow() = %+v, want a single result of deploy with 2 parents", results)
+	}
+	if len(visited) != 5 {
+		t.Errorf("NestedDescendantsFlow() visited %v, want 5 vertices", visited)
+	}
+
+	if _, err := d.NestedDescendantsFlow("build", nil, nil); err == nil {
+		t.Error("NestedDescendantsFlow() from a nested vertex expected error")
+	}
+}
+
+func TestFlattenNested_Namespaces(t *testing.T) {
+	build := NewDAG()
+	_ = build.AddVertexByID("compile", "compile")
+
+	// the vertices nested in build form the namespace "build"
+	d := NewDAG(WithoutCrossNamespaceEdges())
+	_ = d.AddVertexByID("start", "start")
+	_ = d.AddVertexByID("build", build)
+	_ = d.AddEdge("start", "build")
+	if _, err := d.FlattenNested(); err != (CrossNamespaceEdgeError{"start", "build/compile"}) {
+		t.Errorf("FlattenNested() = %v, want CrossNamespaceEdgeError", err)
+	}
+
+	// the vertices nested in ci/build stay in the namespace "ci"
+	d = NewDAG(WithoutCrossNamespaceEdges())
+	_ = d.AddVertexByID("ci/start", "start")
+	_ = d.AddVertexByID("ci/build", build)
+	_ = d.AddEdge("ci/start", "ci/build")
+	flat, err := d.FlattenNested()
+	if err != nil {
+		t.Fatalf("FlattenNested() unexpected error: %v", err)
+	}
+	if ok, _ := flat.IsEdge("ci/start", "ci/build/compile"); !ok {
+		t.Error("IsEdge(ci/start, ci/build/compile) = false, want true")
+	}
+}
+
+func TestFlattenNested_EdgeValues(t *testing.T) {
+	d, _ := newNestedTestDAG()
+	if err := d.SetEdgeValue("start", "build", 2.5); err != nil {
+		t.Fatal(err)
+	}
+
+	flat, err := d.FlattenNested()
+	if err != nil {
+		t.Fatalf("FlattenNested() unexpected error: %v", err)
+	}
+	if v, _ := flat.GetEdgeValue("start", "build/compile"); v != 2.5 {
+		t.Errorf("GetEdgeValue(start, build/compile) = %v, want 2.5", v)
+	}
+	if v, _ := flat.GetEdgeValue("build/test", "deploy"); v != nil {
+		t.Errorf("GetEdgeValue(build/test, deploy) = %v, want nil", v)
+	}
+}
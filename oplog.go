@@ -0,0 +1,268 @@
+package dag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// OpKind identifies the kind of a recorded operation.
+type OpKind string
+
+const (
+	// OpAddVertex adds the vertex ID with the JSON encoded Value.
+	OpAddVertex OpKind = "AddVertex"
+
+	// OpDeleteVertex deletes the vertex ID including all attached edges.
+	OpDeleteVertex OpKind = "DeleteVertex"
+
+	// OpAddEdge adds the edge from Src to Dst.
+	OpAddEdge OpKind = "AddEdge"
+
+	// OpDeleteEdge deletes the edge from Src to Dst.
+	OpDeleteEdge OpKind = "DeleteEdge"
+
+	// OpSetEdgeValue sets the JSON encoded Value of the edge from Src to
+	// Dst, or removes the value of the edge if Value is empty.
+	OpSetEdgeValue OpKind = "SetEdgeValue"
+)
+
+// Op is a single recorded modification of a DAG. Which fields are set depends
+// on Kind.
+type Op struct {
+	Kind  OpKind          `json:"op"`
+	ID    string          `json:"id,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Src   string          `json:"src,omitempty"`
+	Dst   string          `json:"dst,omitempty"`
+}
+
+// OpSink receives the operations captured by an OpRecorder.
+type OpSink interface {
+	RecordOp(op Op) error
+}
+
+// OpLog is an OpSink that collects operations in memory.
+type OpLog []Op
+
+// RecordOp appends op to the log.
+func (l *OpLog) RecordOp(op Op) error {
+	*l = append(*l, op)
+	return nil
+}
+
+// OpWriter is an OpSink that writes operations as JSON lines to an
+// io.Writer. Use ReadOps to read them back.
+type OpWriter struct {
+	enc *json.Encoder
+}
+
+// NewOpWriter creates an OpWriter that writes to w.
+func NewOpWriter(w io.Writer) *OpWriter {
+	return &OpWriter{enc: json.NewEncoder(w)}
+}
+
+// RecordOp writes op as a single line of JSON.
+func (w *OpWriter) RecordOp(op Op) error {
+	return w.enc.Encode(op)
+}
+
+// RecordEvent writes e as a single line of JSON, which makes an OpWriter a
+// ChangeEventSink. Use ReadChangeEvents to read them back.
+func (w *OpWriter) RecordEvent(e ChangeEvent) error {
+	return w.enc.Encode(e)
+}
+
+// ReadOps reads operations written by an OpWriter until the end of r.
+func ReadOps(r io.Reader) ([]Op, error) {
+	dec := json.NewDecoder(r)
+	var ops []Op
+	for {
+		var op Op
+		if err := dec.Decode(&op); err == io.EOF {
+			return ops, nil
+		} else if err != nil {
+			return ops, err
+		}
+		ops = append(ops, op)
+	}
+}
+
+// OpRecorder captures the modifications of a DAG. It is created by Record.
+type OpRecorder struct {
+	sink     OpSink
+	err      error
+	stop     func()
+	stopOnce sync.Once
+}
+
+// Stop stops recording. Stop returns the first error returned by the sink or
+// while encoding a vertex value. Operations after such an error are not
+// recorded anymore. Subsequent calls to Stop return the same error.
+func (r *OpRecorder) Stop() error {
+	r.stopOnce.Do(r.stop)
+	return r.err
+}
+
+// Record starts capturing every modification of the GenericDAG as an Op and
+// passes it to sink. Vertex values are encoded with encoding/json, or with
+// the codecs of the GenericDAG, and edge values with encoding/json. The sink
+// is called while the GenericDAG is locked, so it must not access the
+// GenericDAG.
+//
+// Transitive reductions are recorded as the individual edge deletions.
+func (d *GenericDAG[T]) Record(sink OpSink) *OpRecorder {
+	r := d.newRecorder(sink)
+	d.muDAG.Lock()
+	d.recorders = append(d.recorders, r)
+	d.muDAG.Unlock()
+	return r
+}
+
+// newRecorder returns an OpRecorder passing operations to sink, which isn't
+// registered yet.
+func (d *GenericDAG[T]) newRecorder(sink OpSink) *OpRecorder {
+	r := &OpRecorder{sink: sink}
+	r.stop = func() {
+		d.muDAG.Lock()
+		defer d.muDAG.Unlock()
+		for i, recorder := range d.recorders {
+			if recorder == r {
+				d.recorders = append(d.recorders[:i], d.recorders[i+1:]...)
+				return
+			}
+		}
+	}
+	return r
+}
+
+// Record starts capturing every modification of the TypedDAG. See
+// GenericDAG.Record for details.
+func (d *TypedDAG[T]) Record(sink OpSink) *OpRecorder {
+	return d.inner.Record(sink)
+}
+
+// recordVertexOp passes a vertex operation to all recorders. It must be
+// called with the write lock held.
+func (d *GenericDAG[T]) recordVertexOp(kind OpKind, id string, v *T) {
+	if len(d.recorders) == 0 {
+		return
+	}
+	op := Op{Kind: kind, ID: id}
+	if v != nil {
+		value, err := encodeValue(d.options.Codecs, *v)
+		var data []byte
+		if err == nil {
+			data, err = json.Marshal(value)
+		}
+		if err != nil {
+			for _, r := range d.recorders {
+				if r.err == nil {
+					r.err = fmt.Errorf("encoding the value of vertex %s: %w", id, err)
+				}
+			}
+			return
+		}
+		op.Value = data
+	}
+	d.recordOp(op)
+}
+
+// recordEdgeOp passes an edge operation to all recorders. It must be called
+// with the write lock held.
+func (d *GenericDAG[T]) recordEdgeOp(kind OpKind, srcID, dstID string) {
+	if len(d.recorders) == 0 {
+		return
+	}
+	d.recordOp(Op{Kind: kind, Src: srcID, Dst: dstID})
+}
+
+// recordEdgeValueOp passes the modification of the value of an edge to all
+// recorders. It must be called with the write lock held.
+func (d *GenericDAG[T]) recordEdgeValueOp(srcID, dstID string, value interface{}) {
+	if len(d.recorders) == 0 {
+		return
+	}
+	op := Op{Kind: OpSetEdgeValue, Src: srcID, Dst: dstID}
+	if value != nil {
+		data, err := json.Marshal(value)
+		if err != nil {
+			for _, r := range d.recorders {
+				if r.err == nil {
+					r.err = fmt.Errorf("encoding the value of edge %s -> %s: %w", srcID, dstID, err)
+				}
+			}
+			return
+		}
+		op.Value = data
+	}
+	d.recordOp(op)
+}
+
+func (d *GenericDAG[T]) recordOp(op Op) {
+	for _, r := range d.recorders {
+		if r.err == nil {
+			r.err = r.sink.RecordOp(op)
+		}
+	}
+}
+
+// Replay applies the given operations to the GenericDAG in order. Replaying
+// the operations recorded from an empty GenericDAG into another empty
+// GenericDAG with the same options rebuilds a graph with the same ids and
+// edges. Values may differ in their types though: edge values are decoded by
+// encoding/json, e.g. numbers as float64, and so are vertex values of
+// interface types without a codec (see Options.Codecs), e.g. those of a DAG,
+// whose structs are decoded as map[string]interface{}. Replay stops at the
+// first operation that fails and returns its error.
+func (d *GenericDAG[T]) Replay(ops []Op) error {
+	for i, op := range ops {
+		d.muDAG.Lock()
+		err := d.applyOp(op)
+		d.muDAG.Unlock()
+		if err != nil {
+			return fmt.Errorf("replaying op %d (%s): %w", i, op.Kind, err)
+		}
+	}
+	return nil
+}
+
+// applyOp applies a single operation. It must be called with the write lock
+// held.
+func (d *GenericDAG[T]) applyOp(op Op) error {
+	switch op.Kind {
+	case OpAddVertex:
+		v, err := decodeValue[T](d.options.Codecs, op.Value)
+		if err != nil {
+			return err
+		}
+		return d.addVertexByID(op.ID, v)
+	case OpDeleteVertex:
+		return d.deleteVertex(op.ID)
+	case OpAddEdge:
+		return d.addEdge(op.Src, op.Dst)
+	case OpDeleteEdge:
+		return d.deleteEdge(op.Src, op.Dst)
+	case OpSetEdgeValue:
+		if _, _, err := d.saneEdge(op.Src, op.Dst); err != nil {
+			return err
+		}
+		var value interface{}
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return err
+			}
+		}
+		d.changeEdgeValue(op.Src, op.Dst, value)
+		return nil
+	default:
+		return fmt.Errorf("unknown operation %q", op.Kind)
+	}
+}
+
+// Replay applies the given operations to the TypedDAG in order. See
+// GenericDAG.Replay for details.
+func (d *TypedDAG[T]) Replay(ops []Op) error {
+	return d.inner.Replay(ops)
+}
@@ -0,0 +1,172 @@
+package dag
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	d := NewGenericDAG[string]()
+	var log OpLog
+	var buf bytes.Buffer
+	r1 := d.Record(&log)
+	r2 := d.Record(NewOpWriter(&buf))
+
+	d.MustAddVertexByID("a", "A")
+	d.MustAddVertexByID("b", "B")
+	d.MustAddVertexByID("c", "C")
+	d.MustAddVertexByID("x", "X")
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("b", "c")
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("c", "x")
+	if err := d.AddEdge("c", "a"); err == nil {
+		t.Fatalf("expected a loop error")
+	}
+	d.ReduceTransitively()
+	if err := d.DeleteEdge("c", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DeleteVertex("x"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r1.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if err := r2.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	d.MustAddVertexByID("y", "Y")
+
+	want := []Op{
+		{Kind: OpAddVertex, ID: "a", Value: []byte(`"A"`)},
+		{Kind: OpAddVertex, ID: "b", Value: []byte(`"B"`)},
+		{Kind: OpAddVertex, ID: "c", Value: []byte(`"C"`)},
+		{Kind: OpAddVertex, ID: "x", Value: []byte(`"X"`)},
+		{Kind: OpAddEdge, Src: "a", Dst: "b"},
+		{Kind: OpAddEdge, Src: "b", Dst: "c"},
+		{Kind: OpAddEdge, Src: "a", Dst: "c"},
+		{Kind: OpAddEdge, Src: "c", Dst: "x"},
+		{Kind: OpDeleteEdge, Src: "a", Dst: "c"},
+		{Kind: OpDeleteEdge, Src: "c", Dst: "x"},
+		{Kind: OpDeleteVertex, ID: "x"},
+	}
+	if !reflect.DeepEqual([]Op(log), want) {
+		t.Errorf("recorded %v, want %v", log, want)
+	}
+	read, err := ReadOps(&buf)
+	if err != nil {
+		t.Fatalf("ReadOps failed: %v", err)
+	}
+	if !reflect.DeepEqual(read, want) {
+		t.Errorf("ReadOps() = %v, want %v", read, want)
+	}
+
+	replayed := New[string]()
+	if err := replayed.Replay(read); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if replayed.GetOrder() != 3 || replayed.GetSize() != 2 {
+		t.Errorf("replayed %d vertices and %d edges, want 3 and 2", replayed.GetOrder(), replayed.GetSize())
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"b", "c"}} {
+		if ok, _ := replayed.IsEdge(e[0], e[1]); !ok {
+			t.Errorf("replayed graph lacks edge %s -> %s", e[0], e[1])
+		}
+	}
+	if v, _ := replayed.GetVertex("c"); v != "C" {
+		t.Errorf("replayed value of c = %q, want C", v)
+	}
+}
+
+func TestRecordAndReplayEdgeValues(t *testing.T) {
+	d := NewGenericDAG[string]()
+	var log OpLog
+	r := d.Record(&log)
+	for _, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, id)
+	}
+	if err := d.AddWeightedEdge("a", "b", 2.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddEdgeWithValue("b", "c", "label"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetEdgeValue("a", "b", 7.0); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetEdgeValue("b", "c", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Op{
+		{Kind: OpSetEdgeValue, Src: "a", Dst: "b", Value: []byte(`2.5`)},
+		{Kind: OpSetEdgeValue, Src: "b", Dst: "c", Value: []byte(`"label"`)},
+		{Kind: OpSetEdgeValue, Src: "a", Dst: "b", Value: []byte(`7`)},
+		{Kind: OpSetEdgeValue, Src: "b", Dst: "c"},
+	}
+	var got []Op
+	for _, op := range log {
+		if op.Kind == OpSetEdgeValue {
+			got = append(got, op)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded %v, want %v", got, want)
+	}
+
+	replayed := NewGenericDAG[string]()
+	if err := replayed.Replay(log); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := replayed.GetEdgeValue("a", "b"); v != 7.0 {
+		t.Errorf("replayed GetEdgeValue(a, b) = %v, want 7", v)
+	}
+	if v, _ := replayed.GetEdgeValue("b", "c"); v != nil {
+		t.Errorf("replayed GetEdgeValue(b, c) = %v, want nil", v)
+	}
+	if replayed.Hash() != d.Hash() {
+		t.Error("replayed graph differs from the recorded one")
+	}
+	if err := replayed.Replay([]Op{{Kind: OpSetEdgeValue, Src: "a", Dst: "c", Value: []byte(`1`)}}); err == nil {
+		t.Error("Replay of the value of an unknown edge = nil, want error")
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	d := NewGenericDAG[int]()
+	err := d.Replay([]Op{
+		{Kind: OpAddVertex, ID: "a", Value: []byte(`1`)},
+		{Kind: OpAddEdge, Src: "a", Dst: "b"},
+	})
+	var unknown IDUnknownError
+	if !errors.As(err, &unknown) {
+		t.Errorf("Replay() = %v, want IDUnknownError", err)
+	}
+	if err := d.Replay([]Op{{Kind: "Rename"}}); err == nil {
+		t.Errorf("expected an error for an unknown operation")
+	}
+	if err := d.Replay([]Op{{Kind: OpAddVertex, ID: "b", Value: []byte(`"b"`)}}); err == nil {
+		t.Errorf("expected an error for a value of the wrong type")
+	}
+}
+
+type failingSink struct{ err error }
+
+func (s failingSink) RecordOp(Op) error { return s.err }
+
+func TestRecordSinkError(t *testing.T) {
+	d := NewGenericDAG[int]()
+	errSink := errors.New("sink failed")
+	r := d.Record(failingSink{errSink})
+	d.MustAddVertexByID("a", 1)
+	if err := r.Stop(); !errors.Is(err, errSink) {
+		t.Errorf("Stop() = %v, want %v", err, errSink)
+	}
+}
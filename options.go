@@ -1,27 +1,537 @@
 package dag
 
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
 // Options is the configuration for the DAG.
 type Options struct {
 	// VertexHashFunc is the function that calculates the hash value of a vertex.
 	// This can be useful when the vertex contains not comparable types such as maps.
 	// If VertexHashFunc is nil, the defaultVertexHashFunc is used.
 	VertexHashFunc func(v interface{}) interface{}
+
+	// VertexEqualFunc reports whether two vertex values with the same hash
+	// value are equal. If VertexEqualFunc is set, values are only duplicates
+	// if their hashes are equal and VertexEqualFunc returns true, which allows
+	// a fast VertexHashFunc with collisions. If VertexEqualFunc is nil, values
+	// with equal hashes are duplicates.
+	VertexEqualFunc func(a, b interface{}) bool
+
+	// IDGenerator is the function that generates ids for vertices added
+	// without an explicit id (and not implementing IDInterface).
+	// If IDGenerator is nil, random UUIDs are used. See
+	// SequentialIDGenerator and SeededIDGenerator for deterministic ids.
+	IDGenerator func() string
+
+	// IdentityHash hashes vertex values by their identity instead of using
+	// VertexHashFunc and VertexEqualFunc: pointers, maps, channels and slices
+	// are only duplicates if they are the very same, while all other values,
+	// e.g. structs, are never duplicates. This allows adding distinct but
+	// equal values without a custom VertexHashFunc.
+	IdentityHash bool
+
+	// AllowDuplicateValues keys vertices purely by their id. Distinct vertices
+	// may then hold equal values, and VertexHashFunc is not used at all.
+	AllowDuplicateValues bool
+
+	// PreserveInsertionOrder makes iteration, walks and marshaling visit
+	// vertices in the order they have been added instead of in random or id
+	// order. Note, the storage of deleted vertices is then not reused.
+	PreserveInsertionOrder bool
+
+	// SortedChildren keeps the children of each vertex in a list sorted by
+	// id, so that walks, flows and marshaling visit children in id order
+	// without sorting them on every call. SortedChildren takes precedence
+	// over PreserveInsertionOrder for the order of children.
+	SortedChildren bool
+
+	// DisableCache turns off the ancestors- and descendants-cache. Closures
+	// are then computed on every call, trading speed for memory.
+	DisableCache bool
+
+	// TrackStatus tracks the VertexStatus of each vertex, which flows update
+	// while they run, see GetStatus and GetVerticesByStatus.
+	TrackStatus bool
+
+	// VertexCapacity and EdgeCapacity are size hints used to pre-allocate
+	// the internal maps.
+	VertexCapacity int
+	EdgeCapacity   int
+
+	// Codecs holds the codecs used to serialize vertex values. If Codecs is
+	// nil, values are serialized with encoding/json.
+	Codecs *CodecRegistry
+
+	// VertexValidator is called for each vertex decoded when unmarshaling a
+	// graph, which fails with a ValidationError listing all vertices for
+	// which VertexValidator returned an error.
+	VertexValidator func(id string, v interface{}) error
+
+	// DisallowCrossNamespaceEdges makes adding an edge between vertices of
+	// different namespaces fail with a CrossNamespaceEdgeError. See
+	// NamespacedID for how ids are namespaced, and NestedIDSeparator for how
+	// the ids of FlattenNested and ExpandTemplate are.
+	DisallowCrossNamespaceEdges bool
+
+	// DuplicateEdges controls how unmarshaling handles edges listed more
+	// than once in the input, e.g. when inputs have been concatenated. See
+	// DuplicateEdgePolicy.
+	DuplicateEdges DuplicateEdgePolicy
+
+	// ExistingIDs controls what adding a vertex with the id of an existing
+	// vertex does. See ExistingIDPolicy.
+	ExistingIDs ExistingIDPolicy
+
+	// NilValues controls whether adding nil or zero vertex values fails with
+	// a VertexNilError. See NilValuePolicy.
+	NilValues NilValuePolicy
+
+	// FlowParentOrder sorts the parent results passed to the callbacks of
+	// flows, including the inputs of the start vertex, which otherwise arrive
+	// in the order the parents finish, e.g. for callbacks combining their
+	// inputs positionally. FlowParentOrder reports whether a sorts before b;
+	// see FlowResultsByID. If FlowParentOrder is nil, results aren't sorted.
+	FlowParentOrder func(a, b FlowResult) bool
+
+	// rejectNilValues makes adding nil vertex values fail with a
+	// VertexNilError. It is always set for DAG.
+	rejectNilValues bool
+}
+
+// DuplicateEdgePolicy is the handling of edges listed more than once in the
+// input of the unmarshal functions.
+type DuplicateEdgePolicy int
+
+const (
+	// DuplicateEdgesFail makes unmarshaling fail with an EdgeDuplicateError.
+	// It is the default.
+	DuplicateEdgesFail DuplicateEdgePolicy = iota
+
+	// DuplicateEdgesIgnore adds duplicate edges once and silently skips their
+	// repetitions.
+	DuplicateEdgesIgnore
+
+	// DuplicateEdgesWarn adds duplicate edges once like DuplicateEdgesIgnore,
+	// but unmarshaling returns the graph along with a DuplicateEdgesWarning
+	// listing the skipped repetitions.
+	DuplicateEdgesWarn
+)
+
+// ExistingIDPolicy is the handling of vertices added by AddVertexByID,
+// AddVertex or Replay with the id of an existing vertex.
+type ExistingIDPolicy int
+
+const (
+	// ExistingIDFail makes adding the vertex fail with an IDDuplicateError.
+	// It is the default.
+	ExistingIDFail ExistingIDPolicy = iota
+
+	// ExistingIDReplace replaces the value of the existing vertex, keeping
+	// its edges. Adding fails with a VertexDuplicateError, if another vertex
+	// already has the value and the options don't allow duplicate values.
+	ExistingIDReplace
+
+	// ExistingIDIgnore keeps the existing vertex along with its value and
+	// silently skips the new one.
+	ExistingIDIgnore
+)
+
+// NilValuePolicy is the handling of nil and zero vertex values added to a
+// GenericDAG or TypedDAG. DAG always rejects nil, whatever the policy.
+type NilValuePolicy int
+
+const (
+	// NilValuesAllow adds nil and zero values like any other value. It is
+	// the default.
+	NilValuesAllow NilValuePolicy = iota
+
+	// NilValuesReject makes adding nil pointers, maps, slices, channels,
+	// functions and interfaces fail with a VertexNilError, like DAG does for
+	// nil.
+	NilValuesReject
+
+	// ZeroValuesReject makes adding any zero value, e.g. 0, "" or an empty
+	// struct, fail with a VertexNilError in addition to the nil values
+	// rejected by NilValuesReject.
+	ZeroValuesReject
+)
+
+// rejects returns true, if the options reject adding the vertex value v.
+func (o Options) rejects(v interface{}) bool {
+	if v == nil {
+		return o.rejectNilValues || o.NilValues != NilValuesAllow
+	}
+	switch o.NilValues {
+	case NilValuesReject:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+			return rv.IsNil()
+		}
+	case ZeroValuesReject:
+		return reflect.ValueOf(v).IsZero()
+	}
+	return false
+}
+
+// OptionsT is the configuration for a GenericDAG or TypedDAG with vertex
+// values of type T. It has all fields of Options, but its hooks receive typed
+// vertex values, e.g.:
+//
+//	opts := OptionsT[Person]{
+//		VertexHashFunc: func(p Person) interface{} { return p.Name },
+//	}
+//	d := NewWithOptions[Person](opts)
+type OptionsT[T any] struct {
+	Options
+
+	// VertexHashFunc is the function that calculates the hash value of a
+	// vertex. If set, it replaces Options.VertexHashFunc.
+	VertexHashFunc func(v T) interface{}
+
+	// VertexEqualFunc reports whether two vertex values with the same hash
+	// value are equal. If set, it replaces Options.VertexEqualFunc.
+	VertexEqualFunc func(a, b T) bool
+
+	// VertexValidator validates each vertex decoded when unmarshaling a
+	// graph. If set, it replaces Options.VertexValidator.
+	VertexValidator func(id string, v T) error
+}
+
+// untyped returns the Options equivalent to o.
+func (o OptionsT[T]) untyped() Options {
+	options := o.Options
+	if o.VertexHashFunc != nil {
+		options.VertexHashFunc = typedHashFunc(o.VertexHashFunc)
+	}
+	if o.VertexEqualFunc != nil {
+		options.VertexEqualFunc = typedEqualFunc(o.VertexEqualFunc)
+	}
+	if validate := o.VertexValidator; validate != nil {
+		options.VertexValidator = func(id string, v interface{}) error {
+			return validate(id, v.(T))
+		}
+	}
+	return options
+}
+
+// optionsOf returns options as Options.
+func optionsOf[T any, O Options | OptionsT[T]](options O) Options {
+	if o, ok := any(options).(OptionsT[T]); ok {
+		return o.untyped()
+	}
+	return any(options).(Options)
+}
+
+// validateVertices calls validate for the vertex with the i-th id and value
+// for all n vertices and returns a ValidationError listing all failures, or
+// nil if there were none or validate is nil.
+func validateVertices(n int, vertex func(i int) (string, interface{}), validate func(id string, v interface{}) error) error {
+	if validate == nil {
+		return nil
+	}
+	var failed ValidationError
+	for i := 0; i < n; i++ {
+		id, v := vertex(i)
+		if err := validate(id, v); err != nil {
+			failed.ids = append(failed.ids, id)
+			failed.add(err)
+		}
+	}
+	if len(failed.ids) > 0 {
+		return failed
+	}
+	return nil
+}
+
+// typedHashFunc adapts a hash function of typed values to Options.
+func typedHashFunc[T any](f func(v T) interface{}) func(v interface{}) interface{} {
+	return func(v interface{}) interface{} {
+		return f(v.(T))
+	}
+}
+
+// typedEqualFunc adapts an equality function of typed values to Options.
+func typedEqualFunc[T any](f func(a, b T) bool) func(a, b interface{}) bool {
+	return func(a, b interface{}) bool {
+		return f(a.(T), b.(T))
+	}
+}
+
+// Option configures a DAG at construction time. Options are applied in order,
+// so later options override earlier ones.
+type Option func(*Options)
+
+// WithOptions applies all fields of the given Options at once. It is mainly
+// meant to bridge code that still builds an Options struct.
+func WithOptions(options Options) Option {
+	return func(o *Options) {
+		*o = options
+	}
+}
+
+// WithOptionsT applies all fields of the given OptionsT at once.
+func WithOptionsT[T any](options OptionsT[T]) Option {
+	return WithOptions(options.untyped())
+}
+
+// WithHashFunc sets the function that calculates the hash value of a vertex.
+func WithHashFunc(f func(v interface{}) interface{}) Option {
+	return func(o *Options) {
+		o.VertexHashFunc = f
+	}
+}
+
+// WithTypedHashFunc sets the function that calculates the hash value of a
+// vertex of type T, saving type switches in the hash function. Use it only
+// for DAGs with vertex values of type T.
+func WithTypedHashFunc[T any](f func(v T) interface{}) Option {
+	return WithHashFunc(typedHashFunc(f))
+}
+
+// WithEqualFunc sets the function that reports whether two vertex values with
+// the same hash value are equal.
+func WithEqualFunc(f func(a, b interface{}) bool) Option {
+	return func(o *Options) {
+		o.VertexEqualFunc = f
+	}
+}
+
+// WithTypedEqualFunc sets the function that reports whether two vertex values
+// of type T with the same hash value are equal. Use it only for DAGs with
+// vertex values of type T.
+func WithTypedEqualFunc[T any](f func(a, b T) bool) Option {
+	return WithEqualFunc(typedEqualFunc(f))
+}
+
+// WithIDGenerator sets the function that generates ids for vertices added
+// without an explicit id.
+func WithIDGenerator(g func() string) Option {
+	return func(o *Options) {
+		o.IDGenerator = g
+	}
+}
+
+// WithSequentialIDs generates the ids of vertices added without an explicit
+// id as prefix followed by 1, 2, 3, ... in the order the vertices are added,
+// e.g. to keep golden files of marshaled graphs stable across runs. Each DAG
+// constructed with the Option counts on its own.
+func WithSequentialIDs(prefix string) Option {
+	return func(o *Options) {
+		o.IDGenerator = SequentialIDGenerator(prefix)
+	}
+}
+
+// WithSeededIDs generates the ids of vertices added without an explicit id as
+// UUIDs drawn from a pseudo-random source with the given seed. The ids look
+// like those generated by default, but are the same on every run adding the
+// vertices in the same order. Each DAG constructed with the Option draws from
+// its own source.
+func WithSeededIDs(seed int64) Option {
+	return func(o *Options) {
+		o.IDGenerator = SeededIDGenerator(seed)
+	}
+}
+
+// WithDuplicateValues allows distinct vertices to hold equal values by keying
+// vertices purely by their id.
+func WithDuplicateValues() Option {
+	return func(o *Options) {
+		o.AllowDuplicateValues = true
+	}
+}
+
+// WithIdentityHash hashes vertex values by their identity, so that distinct
+// but equal values may be added. See Options.IdentityHash for details.
+func WithIdentityHash() Option {
+	return func(o *Options) {
+		o.IdentityHash = true
+	}
+}
+
+// WithInsertionOrder makes iteration, walks and marshaling visit vertices in
+// the order they have been added.
+func WithInsertionOrder() Option {
+	return func(o *Options) {
+		o.PreserveInsertionOrder = true
+	}
+}
+
+// WithSortedChildren keeps the children of each vertex sorted by id.
+func WithSortedChildren() Option {
+	return func(o *Options) {
+		o.SortedChildren = true
+	}
+}
+
+// WithoutCache disables the ancestors- and descendants-cache.
+func WithoutCache() Option {
+	return func(o *Options) {
+		o.DisableCache = true
+	}
+}
+
+// WithStatusTracking tracks the VertexStatus of each vertex.
+func WithStatusTracking() Option {
+	return func(o *Options) {
+		o.TrackStatus = true
+	}
+}
+
+// WithCodecs sets the codecs used to serialize vertex values.
+func WithCodecs(r *CodecRegistry) Option {
+	return func(o *Options) {
+		o.Codecs = r
+	}
+}
+
+// WithoutCrossNamespaceEdges disallows edges between vertices of different
+// namespaces.
+func WithoutCrossNamespaceEdges() Option {
+	return func(o *Options) {
+		o.DisallowCrossNamespaceEdges = true
+	}
+}
+
+// WithDuplicateEdges sets how unmarshaling handles edges listed more than
+// once in the input.
+func WithDuplicateEdges(p DuplicateEdgePolicy) Option {
+	return func(o *Options) {
+		o.DuplicateEdges = p
+	}
+}
+
+// WithExistingIDs sets what adding a vertex with the id of an existing vertex
+// does, e.g. to make re-delivered vertices replace or keep their previous
+// value without a racy read-modify-write sequence.
+func WithExistingIDs(p ExistingIDPolicy) Option {
+	return func(o *Options) {
+		o.ExistingIDs = p
+	}
+}
+
+// WithNilValues sets whether adding nil or zero vertex values fails, e.g. to
+// make a GenericDAG of pointers reject nil like DAG does.
+func WithNilValues(p NilValuePolicy) Option {
+	return func(o *Options) {
+		o.NilValues = p
+	}
+}
+
+// WithFlowParentOrder sorts the parent results passed to the callbacks of
+// flows by less.
+func WithFlowParentOrder(less func(a, b FlowResult) bool) Option {
+	return func(o *Options) {
+		o.FlowParentOrder = less
+	}
+}
+
+// WithSortedFlowParents sorts the parent results passed to the callbacks of
+// flows by the ids of the parents.
+func WithSortedFlowParents() Option {
+	return WithFlowParentOrder(FlowResultsByID)
+}
+
+// FlowResultsByID reports whether the id of a sorts before the id of b. It
+// can be used as Options.FlowParentOrder.
+func FlowResultsByID(a, b FlowResult) bool {
+	return a.ID < b.ID
+}
+
+// WithCapacity pre-allocates the internal maps for the given number of
+// vertices and edges.
+func WithCapacity(vertices, edges int) Option {
+	return func(o *Options) {
+		o.VertexCapacity = vertices
+		o.EdgeCapacity = edges
+	}
 }
 
 // Options sets the options for the DAG.
 // Options must be called before any other method of the DAG is called.
+//
+// Deprecated: Pass Option values to NewDAG instead, which makes the
+// configuration immutable after construction.
 func (d *DAG) Options(options Options) {
-	d.muDAG.Lock()
-	defer d.muDAG.Unlock()
-	d.options = options
+	options.rejectNilValues = true
+	d.dagCore.Options(options)
 }
 
 func defaultOptions() Options {
 	return Options{
 		VertexHashFunc: defaultVertexHashFunc,
+		IDGenerator:    defaultIDGenerator,
 	}
 }
 
+// buildOptions applies opts on top of the default options.
+func buildOptions(opts []Option) Options {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options.normalize()
+}
+
+// normalize replaces unset function fields with their defaults.
+func (o Options) normalize() Options {
+	if o.VertexHashFunc == nil {
+		o.VertexHashFunc = defaultVertexHashFunc
+	}
+	if o.IDGenerator == nil {
+		o.IDGenerator = defaultIDGenerator
+	}
+	return o
+}
+
+// adjacencyCapacity returns the size hint for the in- and outbound edge maps,
+// which hold at most one entry per vertex and per edge.
+func (o Options) adjacencyCapacity() int {
+	if o.EdgeCapacity < o.VertexCapacity {
+		return o.EdgeCapacity
+	}
+	return o.VertexCapacity
+}
+
 func defaultVertexHashFunc(v interface{}) interface{} {
 	return v
 }
+
+func defaultIDGenerator() string {
+	return uuid.New().String()
+}
+
+// SequentialIDGenerator returns an id generator for Options.IDGenerator, that
+// returns prefix followed by 1, 2, 3, ... on successive calls. It is safe for
+// concurrent use.
+func SequentialIDGenerator(prefix string) func() string {
+	var mu sync.Mutex
+	var n uint64
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return prefix + strconv.FormatUint(n, 10)
+	}
+}
+
+// SeededIDGenerator returns an id generator for Options.IDGenerator, that
+// returns version 4 UUIDs read from a pseudo-random source with the given
+// seed. Generators with the same seed return the same sequence of ids on all
+// platforms. It is safe for concurrent use.
+func SeededIDGenerator(seed int64) func() string {
+	var mu sync.Mutex
+	r := rand.New(rand.NewSource(seed))
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		// reading from a *rand.Rand never fails
+		return uuid.Must(uuid.NewRandomFromReader(r)).String()
+	}
+}
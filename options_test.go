@@ -2,6 +2,8 @@ package dag
 
 import (
 	"encoding/json"
+	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -205,3 +207,432 @@ func TestOverrideVertexHashFunOption(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestFunctionalOptions(t *testing.T) {
+	next := 0
+	gen := func() string {
+		next++
+		return "gen-" + strconv.Itoa(next)
+	}
+	hash := func(v interface{}) interface{} {
+		return v.(testNonComparableVertexType).ID
+	}
+
+	d := NewGenericDAG[testNonComparableVertexType](WithHashFunc(hash), WithIDGenerator(gen), WithCapacity(10, 20))
+	id1, err := d.AddVertex(testNonComparableVertexType{ID: "1", NotComparableField: map[string]string{"a": "b"}})
+	if err != nil {
+		t.Fatalf("AddVertex failed: %v", err)
+	}
+	id2, err := d.AddVertex(testNonComparableVertexType{ID: "2", NotComparableField: map[string]string{"a": "b"}})
+	if err != nil {
+		t.Fatalf("AddVertex failed: %v", err)
+	}
+	if id1 != "gen-1" || id2 != "gen-2" {
+		t.Errorf("generated ids = %s, %s, want gen-1, gen-2", id1, id2)
+	}
+	if err := d.AddEdge(id1, id2); err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+
+	legacy := NewDAG(WithIDGenerator(gen))
+	id3, err := legacy.AddVertex(3)
+	if err != nil {
+		t.Fatalf("AddVertex failed: %v", err)
+	}
+	if id3 != "gen-3" {
+		t.Errorf("generated id = %s, want gen-3", id3)
+	}
+}
+
+func TestDeterministicIDs(t *testing.T) {
+	addAll := func(opt Option) []string {
+		d := New[int](opt)
+		var ids []string
+		for i := 0; i < 3; i++ {
+			id, err := d.AddVertex(i)
+			if err != nil {
+				t.Fatalf("AddVertex failed: %v", err)
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	opt := WithSequentialIDs("v")
+	for run := 0; run < 2; run++ {
+		if ids := addAll(opt); !reflect.DeepEqual(ids, []string{"v1", "v2", "v3"}) {
+			t.Errorf("run %d: sequential ids = %v, want [v1 v2 v3]", run, ids)
+		}
+	}
+
+	seeded := addAll(WithSeededIDs(42))
+	if again := addAll(WithSeededIDs(42)); !reflect.DeepEqual(seeded, again) {
+		t.Errorf("seeded ids differ between runs: %v, %v", seeded, again)
+	}
+	if other := addAll(WithSeededIDs(43)); reflect.DeepEqual(seeded, other) {
+		t.Errorf("ids seeded with 42 and 43 are equal: %v", seeded)
+	}
+	if seeded[0] == seeded[1] || len(seeded[0]) != 36 {
+		t.Errorf("seeded ids = %v, want distinct UUIDs", seeded)
+	}
+}
+
+func TestWithoutCache(t *testing.T) {
+	d := New[int](WithoutCache())
+	for i := 0; i < 5; i++ {
+		if err := d.AddVertexByID(strconv.Itoa(i), i); err != nil {
+			t.Fatalf("AddVertexByID failed: %v", err)
+		}
+	}
+	// 0 -> 1 -> 2 -> 3, 0 -> 4
+	for _, e := range [][2]string{{"0", "1"}, {"1", "2"}, {"2", "3"}, {"0", "4"}} {
+		if err := d.AddEdge(e[0], e[1]); err != nil {
+			t.Fatalf("AddEdge failed: %v", err)
+		}
+	}
+
+	descendants, _ := d.GetDescendants("0")
+	if len(descendants) != 4 {
+		t.Errorf("GetDescendants(0) = %d vertices, want 4", len(descendants))
+	}
+	ancestors, _ := d.GetAncestors("3")
+	if len(ancestors) != 3 {
+		t.Errorf("GetAncestors(3) = %d vertices, want 3", len(ancestors))
+	}
+	if len(d.inner.descendantsCache) != 0 || len(d.inner.ancestorsCache) != 0 {
+		t.Error("caches should stay empty when caching is disabled")
+	}
+
+	if err := d.DeleteEdge("1", "2"); err != nil {
+		t.Fatalf("DeleteEdge failed: %v", err)
+	}
+	descendants, _ = d.GetDescendants("0")
+	if len(descendants) != 2 {
+		t.Errorf("GetDescendants(0) = %d vertices, want 2", len(descendants))
+	}
+}
+
+func TestAllowDuplicateValues(t *testing.T) {
+	d := NewGenericDAG[string](WithOptions(Options{AllowDuplicateValues: true}))
+	for _, id := range []string{"a", "b", "c"} {
+		if err := d.AddVertexByID(id, "same"); err != nil {
+			t.Fatalf("AddVertexByID(%s) failed: %v", id, err)
+		}
+	}
+	if err := d.AddVertexByID("a", "other"); err == nil {
+		t.Error("AddVertexByID with a known id should fail")
+	} else if _, ok := err.(IDDuplicateError); !ok {
+		t.Errorf("expected IDDuplicateError, got %T", err)
+	}
+	if err := d.AddEdge("a", "b"); err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+	if err := d.AddEdge("b", "c"); err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+	if err := d.AddEdge("c", "a"); err == nil {
+		t.Error("AddEdge should detect the loop")
+	}
+	descendants, _ := d.GetDescendants("a")
+	if len(descendants) != 2 || descendants["c"] != "same" {
+		t.Errorf("GetDescendants(a) = %v", descendants)
+	}
+
+	legacy := NewDAG(WithDuplicateValues())
+	if err := legacy.AddVertexByID("a", 1); err != nil {
+		t.Fatalf("AddVertexByID failed: %v", err)
+	}
+	if err := legacy.AddVertexByID("b", 1); err != nil {
+		t.Fatalf("AddVertexByID failed: %v", err)
+	}
+	_ = legacy.AddEdge("a", "b")
+	children, _ := legacy.GetChildren("a")
+	if children["b"] != 1 {
+		t.Errorf("GetChildren(a) = %v, want map[b:1]", children)
+	}
+	cp, err := legacy.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if cp.GetOrder() != 2 || cp.GetSize() != 1 {
+		t.Errorf("copy has %d vertices and %d edges, want 2 and 1", cp.GetOrder(), cp.GetSize())
+	}
+}
+
+func TestWithIdentityHash(t *testing.T) {
+	type task struct {
+		Name string
+		Tags map[string]string
+	}
+	d := NewDAG(WithIdentityHash())
+	first := &task{Name: "build", Tags: map[string]string{"a": "b"}}
+	if err := d.AddVertexByID("1", first); err != nil {
+		t.Fatalf("AddVertexByID() unexpected error: %v", err)
+	}
+	// distinct pointers to equal (and not comparable) structs
+	if err := d.AddVertexByID("2", &task{Name: "build", Tags: map[string]string{"a": "b"}}); err != nil {
+		t.Errorf("AddVertexByID() of an equal value unexpected error: %v", err)
+	}
+	if _, ok := d.AddVertexByID("3", first).(VertexDuplicateError); !ok {
+		t.Errorf("AddVertexByID() of the same pointer didn't fail")
+	}
+	tags := map[string]string{}
+	if err := d.AddVertexByID("4", tags); err != nil {
+		t.Errorf("AddVertexByID() of a map unexpected error: %v", err)
+	}
+	if _, ok := d.AddVertexByID("5", tags).(VertexDuplicateError); !ok {
+		t.Errorf("AddVertexByID() of the same map didn't fail")
+	}
+
+	// values without identity are never duplicates
+	for _, id := range []string{"6", "7"} {
+		if err := d.AddVertexByID(id, task{Name: "test"}); err != nil {
+			t.Errorf("AddVertexByID(%s) unexpected error: %v", id, err)
+		}
+	}
+
+	// deleting a vertex releases its identity
+	if err := d.DeleteVertex("1"); err != nil {
+		t.Fatalf("DeleteVertex() unexpected error: %v", err)
+	}
+	if err := d.AddVertexByID("3", first); err != nil {
+		t.Errorf("AddVertexByID() of a deleted pointer unexpected error: %v", err)
+	}
+}
+
+func TestWithInsertionOrder(t *testing.T) {
+	d := NewGenericDAG[int](WithInsertionOrder())
+	ids := []string{"z", "m", "a", "k", "b"}
+	for i, id := range ids {
+		d.MustAddVertexByID(id, i)
+	}
+	d.MustAddEdge("z", "k")
+	d.MustAddEdge("z", "b")
+	d.MustAddEdge("m", "a")
+
+	if got := d.VertexIDs(); !reflect.DeepEqual(got, ids) {
+		t.Errorf("VertexIDs() = %v, want %v", got, ids)
+	}
+
+	var walked []string
+	d.GenericDFSWalk(visitorFunc[int](func(_ int, id string) {
+		walked = append(walked, id)
+	}))
+	if want := []string{"z", "k", "b", "m", "a"}; !reflect.DeepEqual(walked, want) {
+		t.Errorf("GenericDFSWalk visited %v, want %v", walked, want)
+	}
+
+	descendants, _ := d.GetOrderedDescendants("z")
+	if want := []string{"k", "b"}; !reflect.DeepEqual(descendants, want) {
+		t.Errorf("GetOrderedDescendants(z) = %v, want %v", descendants, want)
+	}
+
+	data, err := d.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"vs":[{"i":"z","v":0},{"i":"k","v":3},{"i":"b","v":4},{"i":"m","v":1},{"i":"a","v":2}],` +
+		`"es":[{"s":"z","d":"k"},{"s":"z","d":"b"},{"s":"m","d":"a"}]}`
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", data, want)
+	}
+
+	// deleting and re-adding a vertex moves it to the end
+	if err := d.DeleteVertex("m"); err != nil {
+		t.Fatal(err)
+	}
+	d.MustAddVertexByID("m", 1)
+	if want := []string{"z", "a", "k", "b", "m"}; !reflect.DeepEqual(d.VertexIDs(), want) {
+		t.Errorf("VertexIDs() = %v, want %v", d.VertexIDs(), want)
+	}
+	c, _ := d.Copy()
+	if !reflect.DeepEqual(c.VertexIDs(), d.VertexIDs()) {
+		t.Errorf("Copy() changed the order to %v", c.VertexIDs())
+	}
+}
+
+func TestWithSortedChildren(t *testing.T) {
+	d := NewGenericDAG[string](WithSortedChildren(), WithInsertionOrder())
+	for _, id := range []string{"root", "c", "a", "d", "b"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, child := range []string{"c", "a", "d", "b"} {
+		d.MustAddEdge("root", child)
+	}
+	d.MustAddEdge("c", "b")
+	if err := d.DeleteEdge("root", "d"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := d.childIDs("root"), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("childIDs(root) = %v, want %v", got, want)
+	}
+	descendants, _ := d.GetOrderedDescendants("root")
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(descendants, want) {
+		t.Errorf("GetOrderedDescendants(root) = %v, want %v", descendants, want)
+	}
+
+	d.ReduceTransitively()
+	if err := d.DeleteVertex("a"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.childIDs("root"), []string{"c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("childIDs(root) = %v, want %v", got, want)
+	}
+
+	data1, _ := d.MarshalJSON()
+	c, _ := d.Copy()
+	data2, _ := c.MarshalJSON()
+	if string(data1) != string(data2) {
+		t.Errorf("copy marshals to %s, want %s", data2, data1)
+	}
+	if got, want := c.childIDs("c"), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("childIDs(c) of the copy = %v, want %v", got, want)
+	}
+}
+
+func TestOptionsT(t *testing.T) {
+	type person struct {
+		Name string
+		Tags map[string]string
+	}
+	options := OptionsT[person]{
+		VertexHashFunc: func(p person) interface{} { return p.Name },
+	}
+
+	for name, d := range map[string]*TypedDAG[person]{
+		"NewWithOptions":    NewWithOptions[person](options),
+		"WithOptionsT":      New[person](WithOptionsT(options)),
+		"WithTypedHashFunc": New[person](WithTypedHashFunc(options.VertexHashFunc)),
+	} {
+		if err := d.AddVertexByID("1", person{Name: "Alice", Tags: map[string]string{"a": "b"}}); err != nil {
+			t.Fatalf("%s: AddVertexByID() unexpected error: %v", name, err)
+		}
+		err := d.AddVertexByID("2", person{Name: "Alice"})
+		if _, ok := err.(VertexDuplicateError); !ok {
+			t.Errorf("%s: AddVertexByID() = %v, want %T", name, err, VertexDuplicateError{})
+		}
+		if err := d.AddVertexByID("3", person{Name: "Bob"}); err != nil {
+			t.Errorf("%s: AddVertexByID() unexpected error: %v", name, err)
+		}
+	}
+}
+
+func TestVertexEqualFunc(t *testing.T) {
+	// hash by the first letter only, so that distinct names collide
+	hash := func(s string) interface{} { return s[0] }
+	equal := func(a, b string) bool { return a == b }
+
+	for name, d := range map[string]*TypedDAG[string]{
+		"WithTypedEqualFunc": New[string](WithTypedHashFunc(hash), WithTypedEqualFunc(equal)),
+		"OptionsT":           NewWithOptions[string](OptionsT[string]{VertexHashFunc: hash, VertexEqualFunc: equal}),
+	} {
+		for _, v := range []string{"alice", "anna", "amy"} {
+			if err := d.AddVertexByID(v, v); err != nil {
+				t.Fatalf("%s: AddVertexByID(%s) unexpected error: %v", name, v, err)
+			}
+		}
+		if _, ok := d.AddVertexByID("x", "anna").(VertexDuplicateError); !ok {
+			t.Errorf("%s: AddVertexByID() of a duplicate value didn't fail", name)
+		}
+
+		// deleting any of the colliding vertices keeps the others indexed
+		for _, id := range []string{"alice", "anna"} {
+			if err := d.DeleteVertex(id); err != nil {
+				t.Fatalf("%s: DeleteVertex(%s) unexpected error: %v", name, id, err)
+			}
+		}
+		if _, ok := d.AddVertexByID("x", "amy").(VertexDuplicateError); !ok {
+			t.Errorf("%s: AddVertexByID() of a duplicate value didn't fail", name)
+		}
+		if err := d.AddVertexByID("anna", "anna"); err != nil {
+			t.Errorf("%s: AddVertexByID() of a deleted value failed: %v", name, err)
+		}
+	}
+
+	without := New[string](WithTypedHashFunc(hash))
+	without.MustAddVertexByID("alice", "alice")
+	if _, ok := without.AddVertexByID("anna", "anna").(VertexDuplicateError); !ok {
+		t.Errorf("AddVertexByID() without VertexEqualFunc didn't fail on a hash collision")
+	}
+}
+
+func TestWithExistingIDs(t *testing.T) {
+	d := NewGenericDAG[string](WithExistingIDs(ExistingIDReplace))
+	for _, id := range []string{"a", "b"} {
+		if err := d.AddVertexByID(id, id); err != nil {
+			t.Fatalf("AddVertexByID(%s) failed: %v", id, err)
+		}
+	}
+	if err := d.AddEdge("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	modCount := d.ModCount()
+	if err := d.AddVertexByID("a", "A"); err != nil {
+		t.Fatalf("AddVertexByID(a) replacing failed: %v", err)
+	}
+	if v, _ := d.GetVertex("a"); v != "A" {
+		t.Errorf("GetVertex(a) = %q, want A", v)
+	}
+	if d.ModCount() == modCount {
+		t.Error("ModCount() unchanged after replacing a value")
+	}
+	if ok, _ := d.IsEdge("a", "b"); !ok {
+		t.Error("replacing a value deleted its edges")
+	}
+	// re-delivering the same value succeeds, while values stay unique
+	if err := d.AddVertexByID("a", "A"); err != nil {
+		t.Errorf("AddVertexByID(a) re-delivering failed: %v", err)
+	}
+	if _, ok := d.AddVertexByID("b", "A").(VertexDuplicateError); !ok {
+		t.Error("AddVertexByID(b) with the value of a should fail with VertexDuplicateError")
+	}
+	// the replaced value is free for other vertices
+	if err := d.AddVertexByID("c", "a"); err != nil {
+		t.Errorf("AddVertexByID(c) with the replaced value failed: %v", err)
+	}
+
+	d = NewGenericDAG[string](WithExistingIDs(ExistingIDIgnore))
+	if err := d.AddVertexByID("a", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddVertexByID("a", "A"); err != nil {
+		t.Errorf("AddVertexByID(a) ignoring failed: %v", err)
+	}
+	if v, _ := d.GetVertex("a"); v != "a" {
+		t.Errorf("GetVertex(a) = %q, want a", v)
+	}
+	if d.GetOrder() != 1 {
+		t.Errorf("GetOrder() = %d, want 1", d.GetOrder())
+	}
+}
+
+func TestNilValues(t *testing.T) {
+	type node struct{ name string }
+
+	d := NewGenericDAG[*node]()
+	if err := d.AddVertexByID("nil", nil); err != nil {
+		t.Errorf("AddVertexByID(nil) unexpected error by default: %v", err)
+	}
+
+	d = NewGenericDAG[*node](WithNilValues(NilValuesReject))
+	if _, err := d.AddVertex(nil); err != (VertexNilError{}) {
+		t.Errorf("AddVertex(nil) = %v, want VertexNilError", err)
+	}
+	if err := d.AddVertexByID("zero", &node{}); err != nil {
+		t.Errorf("AddVertexByID(&node{}) unexpected error: %v", err)
+	}
+
+	typed := New[node](WithNilValues(ZeroValuesReject))
+	if err := typed.AddVertexByID("zero", node{}); err != (VertexNilError{}) {
+		t.Errorf("AddVertexByID(node{}) = %v, want VertexNilError", err)
+	}
+	if err := typed.AddVertexByID("a", node{"a"}); err != nil {
+		t.Errorf("AddVertexByID(node{a}) unexpected error: %v", err)
+	}
+
+	legacy := NewDAG(WithNilValues(NilValuesReject))
+	if err := legacy.AddVertexByID("nil", (*node)(nil)); err != (VertexNilError{}) {
+		t.Errorf("DAG AddVertexByID((*node)(nil)) = %v, want VertexNilError", err)
+	}
+}
@@ -0,0 +1,226 @@
+package dag
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultParallelThreshold is the order below which parallel walks fall back
+// to their sequential counterparts by default.
+const defaultParallelThreshold = 10000
+
+// ParallelOptions configures ParallelBFSWalk and ParallelDFSWalk.
+type ParallelOptions struct {
+	// Workers is the number of goroutines visiting vertices. If Workers is
+	// zero or negative, runtime.GOMAXPROCS(0) workers are used.
+	Workers int
+
+	// Threshold is the number of vertices below which the walk runs
+	// sequentially, as the overhead of coordinating workers outweighs the
+	// gain on small graphs. If Threshold is zero, a default of 10000 is used;
+	// a negative Threshold always walks in parallel.
+	Threshold int
+}
+
+func (o ParallelOptions) workers() int {
+	if o.Workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.Workers
+}
+
+func (o ParallelOptions) sequential(order int) bool {
+	threshold := o.Threshold
+	if threshold == 0 {
+		threshold = defaultParallelThreshold
+	}
+	return order < threshold || o.workers() == 1
+}
+
+// ParallelBFSWalk visits each vertex of the GenericDAG once, level by level
+// starting at the roots. The vertices of a level are partitioned across
+// workers, so visitor.Visit is called concurrently and must be safe for
+// concurrent use. All vertices of a level (i.e. with the same distance from
+// the closest root) are visited before any vertex of the next level, but
+// within a level the order is undefined.
+//
+// Below opts.Threshold vertices, ParallelBFSWalk falls back to GenericBFSWalk.
+// The GenericDAG is read-locked during the walk, so visitor must not modify it.
+func (d *GenericDAG[T]) ParallelBFSWalk(visitor GenericVisitor[T], opts ParallelOptions) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if opts.sequential(d.getOrder()) {
+		d.genericBFSWalk(visitor, nil)
+		return
+	}
+
+	workers := opts.workers()
+	visited := make([]uint32, len(d.values))
+	frontier := d.rootHandles()
+	for _, h := range frontier {
+		visited[h] = 1
+	}
+	for len(frontier) > 0 {
+		chunk := (len(frontier) + workers - 1) / workers
+		next := make([][]vertexHandle, workers)
+		var wg sync.WaitGroup
+		for w := 0; w*chunk < len(frontier); w++ {
+			part := frontier[w*chunk : minInt((w+1)*chunk, len(frontier))]
+			wg.Add(1)
+			go func(w int, part []vertexHandle) {
+				defer wg.Done()
+				for _, h := range part {
+					visitor.Visit(d.values[h], d.ids.id(h))
+					for child := range d.outboundEdge[h] {
+						if atomic.CompareAndSwapUint32(&visited[child], 0, 1) {
+							next[w] = append(next[w], child)
+						}
+					}
+				}
+			}(w, part)
+		}
+		wg.Wait()
+
+		frontier = frontier[:0]
+		for _, part := range next {
+			frontier = append(frontier, part...)
+		}
+	}
+}
+
+// ParallelDFSWalk visits each vertex of the GenericDAG once. Workers walk
+// depth-first from the roots and hand over parts of their stacks to idle
+// workers, so visitor.Visit is called concurrently and must be safe for
+// concurrent use. The order of the visits is undefined; in particular, a
+// vertex may be visited before its parents.
+//
+// Below opts.Threshold vertices, ParallelDFSWalk falls back to GenericDFSWalk.
+// The GenericDAG is read-locked during the walk, so visitor must not modify it.
+func (d *GenericDAG[T]) ParallelDFSWalk(visitor GenericVisitor[T], opts ParallelOptions) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if opts.sequential(d.getOrder()) {
+		d.genericDFSWalk(visitor, nil)
+		return
+	}
+
+	workers := opts.workers()
+	visited := make([]uint32, len(d.values))
+	pool := newWorkPool(workers, d.rootHandles())
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var stack []vertexHandle
+			for {
+				if len(stack) == 0 {
+					if stack = pool.take(); stack == nil {
+						return
+					}
+				}
+				h := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if !atomic.CompareAndSwapUint32(&visited[h], 0, 1) {
+					continue
+				}
+				visitor.Visit(d.values[h], d.ids.id(h))
+				for child := range d.outboundEdge[h] {
+					if atomic.LoadUint32(&visited[child]) == 0 {
+						stack = append(stack, child)
+					}
+				}
+				stack = pool.share(stack)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// rootHandles returns the handles of all vertices without parents.
+func (d *GenericDAG[T]) rootHandles() []vertexHandle {
+	roots := make([]vertexHandle, 0, len(d.roots))
+	for h := range d.roots {
+		roots = append(roots, h)
+	}
+	return roots
+}
+
+// workPool distributes the pending vertices of a parallel depth-first walk
+// among its workers.
+type workPool struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	items   []vertexHandle
+	waiting int32
+	idle    int
+	workers int
+}
+
+func newWorkPool(workers int, items []vertexHandle) *workPool {
+	p := &workPool{items: items, workers: workers}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// take blocks until there are items in the pool and returns some of them.
+// take returns nil once all workers are idle and the pool is empty, i.e. the
+// walk is complete.
+func (p *workPool) take() []vertexHandle {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idle++
+	for len(p.items) == 0 {
+		if p.idle == p.workers {
+			p.cond.Broadcast()
+			return nil
+		}
+		atomic.AddInt32(&p.waiting, 1)
+		p.cond.Wait()
+		atomic.AddInt32(&p.waiting, -1)
+	}
+	p.idle--
+
+	// leave items for the other waiting workers
+	n := len(p.items)/(int(p.waiting)+1) + 1
+	if n > len(p.items) {
+		n = len(p.items)
+	}
+	items := append([]vertexHandle(nil), p.items[len(p.items)-n:]...)
+	p.items = p.items[:len(p.items)-n]
+	return items
+}
+
+// share hands over the bottom half of stack to waiting workers, if there are
+// any, and returns the rest.
+func (p *workPool) share(stack []vertexHandle) []vertexHandle {
+	if len(stack) < 2 || atomic.LoadInt32(&p.waiting) == 0 {
+		return stack
+	}
+	half := len(stack) / 2
+	p.mu.Lock()
+	p.items = append(p.items, stack[:half]...)
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	return append(stack[:0], stack[half:]...)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ParallelBFSWalk visits each vertex of the TypedDAG once, level by level, by
+// several workers. See GenericDAG.ParallelBFSWalk for details.
+func (d *TypedDAG[T]) ParallelBFSWalk(visitor GenericVisitor[T], opts ParallelOptions) {
+	d.inner.ParallelBFSWalk(visitor, opts)
+}
+
+// ParallelDFSWalk visits each vertex of the TypedDAG once, depth-first, by
+// several workers. See GenericDAG.ParallelDFSWalk for details.
+func (d *TypedDAG[T]) ParallelDFSWalk(visitor GenericVisitor[T], opts ParallelOptions) {
+	d.inner.ParallelDFSWalk(visitor, opts)
+}
@@ -0,0 +1,74 @@
+package dag
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestParallelWalks(t *testing.T) {
+	d, err := GenerateRandomDAG(7, 2000, 6000, func(i int) int { return i })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// level is the distance of a vertex from its closest root
+	level := make(map[string]int)
+	d.GenericBFSWalk(visitorFunc[int](func(_ int, id string) {
+		parents, _ := d.GetParents(id)
+		l := -1
+		for parent := range parents {
+			if pl, ok := level[parent]; ok && (l < 0 || pl+1 < l) {
+				l = pl + 1
+			}
+		}
+		if l < 0 {
+			l = 0
+		}
+		level[id] = l
+	}))
+
+	for _, opts := range []ParallelOptions{
+		{Workers: 4, Threshold: -1},
+		{Workers: 1, Threshold: -1},
+		{}, // sequential fallback
+	} {
+		var mu sync.Mutex
+		var visits []string
+		visitor := visitorFunc[int](func(v int, id string) {
+			if id != "node_"+strconv.Itoa(v) {
+				t.Errorf("visited %s with value %d", id, v)
+			}
+			mu.Lock()
+			visits = append(visits, id)
+			mu.Unlock()
+		})
+
+		d.ParallelBFSWalk(visitor, opts)
+		checkVisitedOnce(t, d, visits)
+		for i := 1; i < len(visits); i++ {
+			if level[visits[i]] < level[visits[i-1]] {
+				t.Fatalf("%+v: ParallelBFSWalk visited %s (level %d) after %s (level %d)", opts,
+					visits[i], level[visits[i]], visits[i-1], level[visits[i-1]])
+			}
+		}
+
+		visits = nil
+		d.ParallelDFSWalk(visitor, opts)
+		checkVisitedOnce(t, d, visits)
+	}
+}
+
+func checkVisitedOnce(t *testing.T, d *GenericDAG[int], visits []string) {
+	t.Helper()
+	seen := make(map[string]bool, len(visits))
+	for _, id := range visits {
+		if seen[id] {
+			t.Fatalf("%s visited twice", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != d.GetOrder() {
+		t.Fatalf("visited %d vertices, want %d", len(seen), d.GetOrder())
+	}
+}
@@ -0,0 +1,84 @@
+package dag
+
+import "sort"
+
+// FromParentMap returns a new GenericDAG from the common representation
+// where each vertex lists its parents (i.e. its prerequisites): there is a
+// vertex for each key of parents, each id listed as parent and each key of
+// values, and an edge from each parent to the vertex listing it. The vertices
+// hold the values of the same id in values, or the zero value of T if values
+// has none. Parents listed repeatedly are added once.
+//
+// FromParentMap returns an error if an id is empty, a vertex lists itself as
+// parent, the parents form a cycle, or the vertices can't be added, e.g. as
+// their values are duplicates and the options don't allow duplicate values.
+// Vertices and edges are added in id order, so the error is deterministic.
+func FromParentMap[T any](parents map[string][]string, values map[string]T, opts ...Option) (*GenericDAG[T], error) {
+	idSet := make(map[string]struct{}, len(parents)+len(values))
+	for id, ps := range parents {
+		idSet[id] = struct{}{}
+		for _, parent := range ps {
+			idSet[parent] = struct{}{}
+		}
+	}
+	for id := range values {
+		idSet[id] = struct{}{}
+	}
+	ids := make([]string, 0, len(idSet))
+	for id := range idSet {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var edges []GenericEdge
+	for _, id := range ids {
+		ps := append([]string(nil), parents[id]...)
+		sort.Strings(ps)
+		for i, parent := range ps {
+			if i == 0 || parent != ps[i-1] {
+				edges = append(edges, GenericEdge{SrcID: parent, DstID: id})
+			}
+		}
+	}
+
+	options := buildOptions(opts)
+	options.VertexCapacity, options.EdgeCapacity = len(ids), len(edges)
+	d := NewGenericDAG[T](WithOptions(options))
+	d.muDAG.Lock()
+	for _, id := range ids {
+		if err := d.addVertexByID(id, values[id]); err != nil {
+			d.muDAG.Unlock()
+			return nil, err
+		}
+	}
+	d.muDAG.Unlock()
+	if err := d.addEdgesBatch(len(edges), func(i int) (string, string) {
+		return edges[i].SrcID, edges[i].DstID
+	}); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ToParentMap returns the structure of the GenericDAG as map from the id of
+// each vertex to the ids of its parents, which is empty for roots. The
+// parents are listed in insertion order, if the GenericDAG preserves it, and
+// in sorted order otherwise. FromParentMap turns the map back into a graph.
+func (d *GenericDAG[T]) ToParentMap() map[string][]string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	parents := make(map[string][]string, d.ids.len())
+	d.ids.each(func(id string, h vertexHandle) bool {
+		parents[id] = d.orderedRelativeIDs(d.inboundEdge[h])
+		return true
+	})
+	return parents
+}
+
+// ToParentMap returns the structure of the TypedDAG as map from the id of
+// each vertex to the ids of its parents. See GenericDAG.ToParentMap for
+// details.
+func (d *TypedDAG[T]) ToParentMap() map[string][]string {
+	return d.inner.ToParentMap()
+}
@@ -0,0 +1,54 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromParentMap(t *testing.T) {
+	parents := map[string][]string{
+		"test":   {"build"},
+		"deploy": {"test", "build", "test"},
+		"build":  nil,
+	}
+	values := map[string]int{"build": 1, "test": 2, "deploy": 3, "docs": 4}
+	d, err := FromParentMap(parents, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.GetOrder() != 4 || d.GetSize() != 3 {
+		t.Errorf("order, size = %d, %d, want 4, 3", d.GetOrder(), d.GetSize())
+	}
+	if v, _ := d.GetVertex("docs"); v != 4 {
+		t.Errorf("GetVertex(docs) = %d, want 4", v)
+	}
+
+	want := map[string][]string{
+		"build":  {},
+		"test":   {"build"},
+		"deploy": {"build", "test"},
+		"docs":   {},
+	}
+	if got := d.ToParentMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToParentMap() = %v, want %v", got, want)
+	}
+
+	// parents without entry of their own become vertices with zero values
+	d, err = FromParentMap[int](map[string][]string{"b": {"a"}}, nil, WithDuplicateValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isEdge, _ := d.IsEdge("a", "b"); !isEdge {
+		t.Error("edge a -> b missing")
+	}
+
+	if _, err := FromParentMap[int](map[string][]string{"a": {"b"}, "b": {"a"}}, nil, WithDuplicateValues()); err == nil {
+		t.Error("FromParentMap() with a cycle expected error")
+	}
+	if _, err := FromParentMap[int](map[string][]string{"a": {"a"}}, nil); err == nil {
+		t.Error("FromParentMap() with a self-parent expected error")
+	}
+	if _, err := FromParentMap[int](map[string][]string{"a": {""}}, nil, WithDuplicateValues()); err == nil {
+		t.Error("FromParentMap() with an empty id expected error")
+	}
+}
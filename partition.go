@@ -0,0 +1,126 @@
+package dag
+
+import "fmt"
+
+// CrossEdge is an edge between two parts of a partitioned graph, see
+// Partition.
+type CrossEdge struct {
+	SrcID, DstID     string
+	SrcPart, DstPart int
+}
+
+// Partition splits the graph into k parts of about equal cost, e.g. to shard
+// a big pipeline across k pools of workers, and returns the parts along with
+// the edges between them. The cost of a vertex is cost(id, v), or 1 if cost
+// is nil. The parts keep the ids, values and options of the graph.
+//
+// Partition cuts a depth-first topological order of the vertices into k
+// consecutive ranges of about equal cost. Chains of vertices thus tend to end
+// up in the same part, which keeps the number of cross-partition edges low,
+// though not necessarily minimal. All cross-partition edges lead from a part
+// to a later one, so the parts can be executed one after the other. Parts
+// are empty if there are less than k vertices.
+//
+// Partition returns an error if k is less than 1 or cost returns a negative
+// cost.
+func (d *GenericDAG[T]) Partition(k int, cost func(id string, v T) float64) ([]*GenericDAG[T], []CrossEdge, error) {
+	if k < 1 {
+		return nil, nil, fmt.Errorf("the number of parts must be >= 1, got %d", k)
+	}
+
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	order := d.depthFirstTopologicalOrder()
+	costs := make([]float64, len(order))
+	total := 0.0
+	for i, h := range order {
+		costs[i] = 1
+		if cost != nil {
+			costs[i] = cost(d.ids.id(h), d.values[h])
+		}
+		if costs[i] < 0 {
+			return nil, nil, fmt.Errorf("vertex '%s' has negative cost %v", d.ids.id(h), costs[i])
+		}
+		total += costs[i]
+	}
+
+	// assign each vertex to the part its cost is centered in
+	parts := make([]*GenericDAG[T], k)
+	for i := range parts {
+		parts[i] = NewGenericDAG[T](WithOptions(d.options))
+	}
+	partOf := make(map[vertexHandle]int, len(order))
+	done := 0.0
+	for i, h := range order {
+		p := i * k / len(order)
+		if total > 0 {
+			p = int((done + costs[i]/2) / total * float64(k))
+		}
+		if p >= k {
+			p = k - 1
+		}
+		done += costs[i]
+		partOf[h] = p
+		if err := parts[p].addVertexByID(d.ids.id(h), d.values[h]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var cross []CrossEdge
+	for _, h := range order {
+		srcID, srcPart := d.ids.id(h), partOf[h]
+		d.eachChild(h, func(child vertexHandle) {
+			dstID, dstPart := d.ids.id(child), partOf[child]
+			if srcPart == dstPart {
+				parts[srcPart].linkEdge(parts[srcPart].keyOf(srcID), parts[srcPart].keyOf(dstID))
+				return
+			}
+			cross = append(cross, CrossEdge{SrcID: srcID, DstID: dstID, SrcPart: srcPart, DstPart: dstPart})
+		})
+	}
+	return parts, cross, nil
+}
+
+// depthFirstTopologicalOrder returns the handles of all vertices in a
+// topological order, which visits a child as soon as all its parents have
+// been visited, so that chains of vertices are consecutive.
+func (d *GenericDAG[T]) depthFirstTopologicalOrder() []vertexHandle {
+	order := make([]vertexHandle, 0, d.ids.len())
+	pending := make(map[vertexHandle]int, len(d.inboundEdge))
+	for h, parents := range d.inboundEdge {
+		pending[h] = len(parents)
+	}
+	roots := d.orderedRelativeIDs(d.roots)
+	stack := make([]vertexHandle, 0, len(roots))
+	for i := len(roots) - 1; i >= 0; i-- {
+		stack = append(stack, d.keyOf(roots[i]))
+	}
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		order = append(order, h)
+		children := d.childIDs(d.ids.id(h))
+		for i := len(children) - 1; i >= 0; i-- {
+			child := d.keyOf(children[i])
+			if pending[child]--; pending[child] == 0 {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return order
+}
+
+// Partition splits the graph into k parts of about equal cost. See
+// GenericDAG.Partition for details.
+func (d *TypedDAG[T]) Partition(k int, cost func(id string, v T) float64) ([]*TypedDAG[T], []CrossEdge, error) {
+	inner, cross, err := d.inner.Partition(k, cost)
+	if err != nil {
+		return nil, nil, err
+	}
+	parts := make([]*TypedDAG[T], len(inner))
+	for i, part := range inner {
+		parts[i] = &TypedDAG[T]{inner: part}
+	}
+	return parts, cross, nil
+}
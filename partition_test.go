@@ -0,0 +1,70 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	// two independent chains end up in separate parts
+	d := New[string]()
+	for _, id := range []string{"a1", "a2", "a3", "b1", "b2", "b3"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a1", "a2"}, {"a2", "a3"}, {"b1", "b2"}, {"b2", "b3"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+	parts, cross, err := d.Partition(2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cross) != 0 {
+		t.Errorf("Partition(2) cross edges = %v, want none", cross)
+	}
+	for i, want := range []string{"a1", "b1"} {
+		if parts[i].GetOrder() != 3 || parts[i].GetSize() != 2 {
+			t.Errorf("part %d has %d vertices and %d edges, want 3 and 2", i, parts[i].GetOrder(), parts[i].GetSize())
+		}
+		if _, err := parts[i].GetVertex(want); err != nil {
+			t.Errorf("part %d misses %s", i, want)
+		}
+	}
+
+	// costs shift the boundary, and edges between parts are reported
+	d.MustAddEdge("a3", "b1")
+	cost := func(id string, _ string) float64 {
+		if id == "a1" {
+			return 4
+		}
+		return 1
+	}
+	parts, cross, err = d.Partition(2, cost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parts[0].GetVertices(); !reflect.DeepEqual(got, map[string]string{"a1": "a1"}) {
+		t.Errorf("part 0 = %v, want a1 only", got)
+	}
+	if want := []CrossEdge{{"a1", "a2", 0, 1}}; !reflect.DeepEqual(cross, want) {
+		t.Errorf("cross edges = %v, want %v", cross, want)
+	}
+
+	parts, _, err = d.Partition(10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := 0
+	for _, part := range parts {
+		order += part.GetOrder()
+	}
+	if len(parts) != 10 || order != 6 {
+		t.Errorf("Partition(10) = %d parts with %d vertices, want 10 with 6", len(parts), order)
+	}
+
+	if _, _, err := d.Partition(0, nil); err == nil {
+		t.Error("Partition(0) = nil, want error")
+	}
+	if _, _, err := d.Partition(2, func(string, string) float64 { return -1 }); err == nil {
+		t.Error("Partition() with negative costs = nil, want error")
+	}
+}
@@ -0,0 +1,166 @@
+package dag
+
+import "container/heap"
+
+// GetShortestPath returns the ids of the vertices on a path from srcID to
+// dstID with the fewest edges, including both ends. GetShortestPath returns
+// nil if dstID isn't a descendant of srcID, and [srcID] if both are the same.
+// GetShortestPath returns an error if srcID or dstID are empty or unknown.
+func (d *GenericDAG[T]) GetShortestPath(srcID, dstID string) ([]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(srcID); err != nil {
+		return nil, err
+	}
+	if err := d.saneID(dstID); err != nil {
+		return nil, err
+	}
+	if srcID == dstID {
+		return []string{srcID}, nil
+	}
+	return d.shortestPath(d.keyOf(srcID), d.keyOf(dstID)), nil
+}
+
+// GetLongestPath returns the ids of the vertices on a path from srcID to
+// dstID with the most edges, including both ends, e.g. the critical path of
+// a pipeline of steps taking equal time. See GetShortestPath for details.
+func (d *GenericDAG[T]) GetLongestPath(srcID, dstID string) ([]string, error) {
+	path, _, err := d.GetLongestWeightedPath(srcID, dstID, func(string, string) float64 { return 1 })
+	return path, err
+}
+
+// GetShortestWeightedPath returns the ids of the vertices on the path from
+// srcID to dstID with the lowest sum of the weights of its edges, and this
+// sum. weight returns the weight of the edge between srcID and dstID, which
+// may be negative. If weight is nil, the weights are the values of the edges
+// added by AddWeightedEdge, and edges without a float64 value weigh 1.
+// Among paths of equal weight, the same one is returned on every call.
+//
+// GetShortestWeightedPath returns nil and 0 if dstID isn't a descendant of
+// srcID, and [srcID] and 0 if both are the same. It returns an error if srcID
+// or dstID are empty or unknown. The GenericDAG is read-locked while weight is
+// called, so weight must not modify it.
+func (d *GenericDAG[T]) GetShortestWeightedPath(srcID, dstID string, weight func(srcID, dstID string) float64) ([]string, float64, error) {
+	return d.weightedPath(srcID, dstID, weight, false)
+}
+
+// GetLongestWeightedPath returns the ids of the vertices on the path from
+// srcID to dstID with the highest sum of the weights of its edges, and this
+// sum, e.g. the critical path of a pipeline weighted by the durations of its
+// steps. See GetShortestWeightedPath for details.
+func (d *GenericDAG[T]) GetLongestWeightedPath(srcID, dstID string, weight func(srcID, dstID string) float64) ([]string, float64, error) {
+	return d.weightedPath(srcID, dstID, weight, true)
+}
+
+// weightedPath returns the path from srcID to dstID with the lowest or, if
+// longest is set, highest weight, and its weight.
+func (d *GenericDAG[T]) weightedPath(srcID, dstID string, weight func(srcID, dstID string) float64, longest bool) ([]string, float64, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(srcID); err != nil {
+		return nil, 0, err
+	}
+	if err := d.saneID(dstID); err != nil {
+		return nil, 0, err
+	}
+	if srcID == dstID {
+		return []string{srcID}, 0, nil
+	}
+	srcHash, dstHash := d.keyOf(srcID), d.keyOf(dstID)
+	if _, reachable := d.getDescendants(srcHash)[dstHash]; !reachable {
+		return nil, 0, nil
+	}
+	if weight == nil {
+		weight = d.edgeValueWeight
+	}
+
+	// relax the edges of the vertices on paths from srcID to dstID in
+	// topological order, visiting the smallest id first among the vertices
+	// ready, so that ties are broken deterministically
+	descendants, ancestors := d.getDescendants(srcHash), d.getAncestors(dstHash)
+	onPath := func(h vertexHandle) bool {
+		_, isDescendant := descendants[h]
+		_, isAncestor := ancestors[h]
+		return h == srcHash || h == dstHash || isDescendant && isAncestor
+	}
+	pending := make(map[vertexHandle]int)
+	countParents := func(h vertexHandle) {
+		for parent := range d.inboundEdge[h] {
+			if onPath(parent) {
+				pending[h]++
+			}
+		}
+	}
+	for h := range ancestors {
+		if _, isDescendant := descendants[h]; isDescendant {
+			countParents(h)
+		}
+	}
+	countParents(dstHash)
+
+	distance := map[vertexHandle]float64{srcHash: 0}
+	previous := make(map[vertexHandle]vertexHandle)
+	ready := idHeap{srcID}
+	for ready.Len() > 0 {
+		id := heap.Pop(&ready).(string)
+		h := d.keyOf(id)
+		for _, childID := range d.childIDs(id) {
+			child := d.keyOf(childID)
+			if !onPath(child) {
+				continue
+			}
+			candidate := distance[h] + weight(id, childID)
+			current, reached := distance[child]
+			if !reached || longest && candidate > current || !longest && candidate < current {
+				distance[child] = candidate
+				previous[child] = h
+			}
+			if pending[child]--; pending[child] == 0 && child != dstHash {
+				heap.Push(&ready, childID)
+			}
+		}
+	}
+
+	var path []string
+	for h := dstHash; h != srcHash; h = previous[h] {
+		path = append(path, d.ids.id(h))
+	}
+	path = append(path, srcID)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, distance[dstHash], nil
+}
+
+// edgeValueWeight returns the value of the edge between srcID and dstID, if
+// it is a float64, or else 1.
+func (d *GenericDAG[T]) edgeValueWeight(srcID, dstID string) float64 {
+	if w, ok := d.edgeValues[[2]vertexHandle{d.keyOf(srcID), d.keyOf(dstID)}].(float64); ok {
+		return w
+	}
+	return 1
+}
+
+// GetShortestPath returns the ids of the vertices on a path from srcID to
+// dstID with the fewest edges. See GenericDAG.GetShortestPath for details.
+func (d *TypedDAG[T]) GetShortestPath(srcID, dstID string) ([]string, error) {
+	return d.inner.GetShortestPath(srcID, dstID)
+}
+
+// GetLongestPath returns the ids of the vertices on a path from srcID to
+// dstID with the most edges. See GenericDAG.GetLongestPath for details.
+func (d *TypedDAG[T]) GetLongestPath(srcID, dstID string) ([]string, error) {
+	return d.inner.GetLongestPath(srcID, dstID)
+}
+
+// GetShortestWeightedPath returns the path from srcID to dstID with the lowest
+// weight. See GenericDAG.GetShortestWeightedPath for details.
+func (d *TypedDAG[T]) GetShortestWeightedPath(srcID, dstID string, weight func(srcID, dstID string) float64) ([]string, float64, error) {
+	return d.inner.GetShortestWeightedPath(srcID, dstID, weight)
+}
+
+// GetLongestWeightedPath returns the path from srcID to dstID with the highest
+// weight. See GenericDAG.GetLongestWeightedPath for details.
+func (d *TypedDAG[T]) GetLongestWeightedPath(srcID, dstID string, weight func(srcID, dstID string) float64) ([]string, float64, error) {
+	return d.inner.GetLongestWeightedPath(srcID, dstID, weight)
+}
@@ -0,0 +1,94 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenericDAG_GetShortestAndLongestPath(t *testing.T) {
+	// a -> b -> d -> e, a -> c -> e, a -> e, x -> d is outside the paths
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c", "d", "e", "x"} {
+		d.MustAddVertexByID(id, i)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("b", "d")
+	d.MustAddEdge("d", "e")
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("c", "e")
+	d.MustAddEdge("a", "e")
+	d.MustAddEdge("x", "d")
+
+	tests := []struct {
+		name     string
+		got      func() ([]string, error)
+		expected []string
+	}{
+		{"shortest", func() ([]string, error) { return d.GetShortestPath("a", "e") }, []string{"a", "e"}},
+		{"longest", func() ([]string, error) { return d.GetLongestPath("a", "e") }, []string{"a", "b", "d", "e"}},
+		{"longest to d", func() ([]string, error) { return d.GetLongestPath("a", "d") }, []string{"a", "b", "d"}},
+		{"same", func() ([]string, error) { return d.GetLongestPath("c", "c") }, []string{"c"}},
+		{"unreachable", func() ([]string, error) { return d.GetLongestPath("x", "c") }, nil},
+		{"unreachable shortest", func() ([]string, error) { return d.GetShortestPath("e", "a") }, nil},
+	}
+	for _, tt := range tests {
+		path, err := tt.got()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(path, tt.expected) {
+			t.Errorf("%s: path = %v, want %v", tt.name, path, tt.expected)
+		}
+	}
+
+	if _, err := d.GetShortestPath("", "e"); err == nil {
+		t.Error("GetShortestPath(\"\", \"e\") returned no error")
+	}
+	if _, err := d.GetLongestPath("a", "z"); err == nil {
+		t.Error("GetLongestPath(\"a\", \"z\") returned no error")
+	}
+}
+
+func TestGenericDAG_GetWeightedPath(t *testing.T) {
+	// a -2-> b -2-> d, a -1-> c -5-> d, a -> d weighs 1 without a value
+	d := New[string]()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range []struct {
+		src, dst string
+		weight   float64
+	}{{"a", "b", 2}, {"b", "d", 2}, {"a", "c", 1}, {"c", "d", 5}} {
+		if err := d.AddWeightedEdge(e.src, e.dst, e.weight); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.MustAddEdge("a", "d")
+
+	path, weight, err := d.GetShortestWeightedPath("a", "d", nil)
+	if err != nil || !reflect.DeepEqual(path, []string{"a", "d"}) || weight != 1 {
+		t.Errorf("GetShortestWeightedPath() = %v, %v, %v, want [a d], 1", path, weight, err)
+	}
+	path, weight, err = d.GetLongestWeightedPath("a", "d", nil)
+	if err != nil || !reflect.DeepEqual(path, []string{"a", "c", "d"}) || weight != 6 {
+		t.Errorf("GetLongestWeightedPath() = %v, %v, %v, want [a c d], 6", path, weight, err)
+	}
+
+	// negative weights turn the shortest path into the longest one
+	negative := func(srcID, dstID string) float64 { return -d.inner.edgeValueWeight(srcID, dstID) }
+	path, weight, err = d.GetShortestWeightedPath("a", "d", negative)
+	if err != nil || !reflect.DeepEqual(path, []string{"a", "c", "d"}) || weight != -6 {
+		t.Errorf("GetShortestWeightedPath(negative) = %v, %v, %v, want [a c d], -6", path, weight, err)
+	}
+
+	// ties are broken in favor of the path relaxed first
+	path, weight, err = d.GetLongestWeightedPath("a", "d", func(srcID, dstID string) float64 {
+		if srcID == "a" && dstID == "d" {
+			return 2
+		}
+		return 1
+	})
+	if err != nil || !reflect.DeepEqual(path, []string{"a", "d"}) || weight != 2 {
+		t.Errorf("GetLongestWeightedPath(ties) = %v, %v, %v, want [a d], 2", path, weight, err)
+	}
+}
@@ -0,0 +1,190 @@
+package dag
+
+// TagInterface describes the interface a vertex value must implement to be
+// selected by tag, see Query.WithTag.
+type TagInterface interface {
+	Tags() []string
+}
+
+// Query selects vertices of a GenericDAG. A Query is built by chaining its
+// methods and evaluated by IDs or Vertices in a single traversal, e.g.:
+//
+//	ids, err := d.Select().Descendants("a").WithTag("prod").MaxDepth(3).IDs()
+//
+// Without a call to Descendants or Ancestors, a Query selects from all
+// vertices. A Query must not be modified while it is evaluated.
+type Query[T any] struct {
+	graph    *GenericDAG[T]
+	starts   []queryStart
+	maxDepth int
+	filters  []func(id string, v T) bool
+}
+
+type queryStart struct {
+	id  string
+	asc bool
+}
+
+// Select returns a new Query selecting vertices of the GenericDAG.
+func (d *GenericDAG[T]) Select() *Query[T] {
+	return &Query[T]{graph: d, maxDepth: -1}
+}
+
+// Descendants selects the descendants of the vertex with id. If called
+// repeatedly, or along with Ancestors, the union of the relatives is selected.
+func (q *Query[T]) Descendants(id string) *Query[T] {
+	q.starts = append(q.starts, queryStart{id: id})
+	return q
+}
+
+// Ancestors selects the ancestors of the vertex with id. If called
+// repeatedly, or along with Descendants, the union of the relatives is
+// selected.
+func (q *Query[T]) Ancestors(id string) *Query[T] {
+	q.starts = append(q.starts, queryStart{id: id, asc: true})
+	return q
+}
+
+// MaxDepth limits the relatives selected by Descendants and Ancestors to
+// those at most depth edges away, e.g. 1 for the children or parents only.
+// A negative depth (the default) selects all relatives.
+func (q *Query[T]) MaxDepth(depth int) *Query[T] {
+	q.maxDepth = depth
+	return q
+}
+
+// Where selects only the vertices for which keep returns true. Filters only
+// restrict the result, relatives of vertices filtered out are still selected.
+func (q *Query[T]) Where(keep func(id string, v T) bool) *Query[T] {
+	q.filters = append(q.filters, keep)
+	return q
+}
+
+// WithTag selects only the vertices whose value implements TagInterface and
+// has the given tag.
+func (q *Query[T]) WithTag(tag string) *Query[T] {
+	return q.Where(func(_ string, v T) bool {
+		tagged, ok := any(v).(TagInterface)
+		if !ok {
+			return false
+		}
+		for _, t := range tagged.Tags() {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// IDs returns the ids of the selected vertices, sorted by id or in insertion
+// order if the GenericDAG preserves it. IDs returns an error if any id passed
+// to Descendants or Ancestors is empty or unknown.
+func (q *Query[T]) IDs() ([]string, error) {
+	d := q.graph
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	selected, err := q.evaluate()
+	if err != nil {
+		return nil, err
+	}
+	return d.orderedRelativeIDs(selected), nil
+}
+
+// Vertices returns the ids and values of the selected vertices. Vertices
+// returns an error if any id passed to Descendants or Ancestors is empty or
+// unknown.
+func (q *Query[T]) Vertices() (map[string]T, error) {
+	d := q.graph
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	selected, err := q.evaluate()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]T, len(selected))
+	for h := range selected {
+		out[d.ids.id(h)] = d.values[h]
+	}
+	return out, nil
+}
+
+// evaluate returns the handles of the selected vertices. It must be called
+// under the read lock of the graph.
+func (q *Query[T]) evaluate() (map[vertexHandle]struct{}, error) {
+	d := q.graph
+	selected := make(map[vertexHandle]struct{})
+	if len(q.starts) == 0 {
+		d.ids.each(func(id string, h vertexHandle) bool {
+			if q.keep(id, h) {
+				selected[h] = struct{}{}
+			}
+			return true
+		})
+		return selected, nil
+	}
+
+	for _, start := range q.starts {
+		if err := d.saneID(start.id); err != nil {
+			return nil, err
+		}
+		var relatives map[vertexHandle]struct{}
+		switch {
+		case q.maxDepth >= 0:
+			relatives = q.relativesByDepth(d.keyOf(start.id), start.asc)
+		case start.asc:
+			relatives = d.getAncestors(d.keyOf(start.id))
+		default:
+			relatives = d.getDescendants(d.keyOf(start.id))
+		}
+		for h := range relatives {
+			if q.keep(d.ids.id(h), h) {
+				selected[h] = struct{}{}
+			}
+		}
+	}
+	return selected, nil
+}
+
+// keep reports whether the vertex with id and the handle h passes all filters.
+func (q *Query[T]) keep(id string, h vertexHandle) bool {
+	for _, f := range q.filters {
+		if !f(id, q.graph.values[h]) {
+			return false
+		}
+	}
+	return true
+}
+
+// relativesByDepth returns the relatives of the vertex with the handle h up
+// to the maximum depth of the query.
+func (q *Query[T]) relativesByDepth(h vertexHandle, asc bool) map[vertexHandle]struct{} {
+	d := q.graph
+	edges := d.outboundEdge
+	if asc {
+		edges = d.inboundEdge
+	}
+	relatives := make(map[vertexHandle]struct{})
+	level := []vertexHandle{h}
+	for depth := 0; depth < q.maxDepth && len(level) > 0; depth++ {
+		var next []vertexHandle
+		for _, v := range level {
+			for relative := range edges[v] {
+				if _, seen := relatives[relative]; !seen {
+					relatives[relative] = struct{}{}
+					next = append(next, relative)
+				}
+			}
+		}
+		level = next
+	}
+	return relatives
+}
+
+// Select returns a new Query selecting vertices of the TypedDAG. See Query
+// for details.
+func (d *TypedDAG[T]) Select() *Query[T] {
+	return d.inner.Select()
+}
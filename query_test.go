@@ -0,0 +1,71 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+type taggedVertex struct {
+	name string
+	tags []string
+}
+
+func (v taggedVertex) Tags() []string {
+	return v.tags
+}
+
+func TestQuery(t *testing.T) {
+	/*     a
+	 *    / \
+	 *   b   c
+	 *   |   |
+	 *   d   e
+	 *    \ /
+	 *     f
+	 */
+	d := New[taggedVertex](WithIdentityHash())
+	tags := map[string][]string{"b": {"prod"}, "d": {"dev", "prod"}, "f": {"prod"}}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		d.MustAddVertexByID(id, taggedVertex{name: id, tags: tags[id]})
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "e"}, {"d", "f"}, {"e", "f"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	tests := []struct {
+		name  string
+		query *Query[taggedVertex]
+		want  []string
+	}{
+		{"all", d.Select(), []string{"a", "b", "c", "d", "e", "f"}},
+		{"descendants", d.Select().Descendants("b"), []string{"d", "f"}},
+		{"ancestors", d.Select().Ancestors("f"), []string{"a", "b", "c", "d", "e"}},
+		{"union", d.Select().Descendants("c").Ancestors("d"), []string{"a", "b", "e", "f"}},
+		{"max depth", d.Select().Descendants("a").MaxDepth(2), []string{"b", "c", "d", "e"}},
+		{"max depth 0", d.Select().Descendants("a").MaxDepth(0), []string{}},
+		{"tag", d.Select().Descendants("a").WithTag("prod").MaxDepth(2), []string{"b", "d"}},
+		{"tags", d.Select().WithTag("prod").WithTag("dev"), []string{"d"}},
+		{"where", d.Select().Ancestors("f").Where(func(id string, _ taggedVertex) bool { return id != "a" }), []string{"b", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		got, err := tt.query.IDs()
+		if err != nil {
+			t.Errorf("%s: IDs() unexpected error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: IDs() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	vertices, err := d.Select().Descendants("d").Vertices()
+	if err != nil || len(vertices) != 1 || vertices["f"].name != "f" {
+		t.Errorf("Vertices() = %v, %v, want map[f:...]", vertices, err)
+	}
+	if _, err := d.Select().Descendants("x").IDs(); err == nil {
+		t.Error("IDs() with an unknown id should fail")
+	}
+	if _, err := d.Select().Ancestors("").Vertices(); err == nil {
+		t.Error("Vertices() with an empty id should fail")
+	}
+}
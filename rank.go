@@ -0,0 +1,64 @@
+package dag
+
+import "sort"
+
+// RankedVertex is a vertex id along with the size of one of its closures, as
+// returned by TopByDescendants and TopByAncestors.
+type RankedVertex struct {
+	ID    string
+	Count int
+}
+
+// TopByDescendants returns the n vertices with the most descendants, e.g. the
+// vertices whose failure affects most of a pipeline, ordered by the number of
+// descendants (descending) and id. If the graph has fewer than n vertices,
+// all of them are returned. The descendants are counted using (and filling)
+// the descendants-cache.
+func (d *GenericDAG[T]) TopByDescendants(n int) []RankedVertex {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.topBy(n, d.getDescendants)
+}
+
+// TopByAncestors returns the n vertices with the most ancestors, ordered by
+// the number of ancestors (descending) and id. If the graph has fewer than n
+// vertices, all of them are returned. The ancestors are counted using (and
+// filling) the ancestors-cache.
+func (d *GenericDAG[T]) TopByAncestors(n int) []RankedVertex {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.topBy(n, d.getAncestors)
+}
+
+func (d *GenericDAG[T]) topBy(n int, closure func(h vertexHandle) map[vertexHandle]struct{}) []RankedVertex {
+	if n <= 0 {
+		return []RankedVertex{}
+	}
+	ranked := make([]RankedVertex, 0, d.ids.len())
+	d.ids.each(func(id string, h vertexHandle) bool {
+		ranked = append(ranked, RankedVertex{ID: id, Count: len(closure(h))})
+		return true
+	})
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// TopByDescendants returns the n vertices with the most descendants. See
+// GenericDAG.TopByDescendants for details.
+func (d *TypedDAG[T]) TopByDescendants(n int) []RankedVertex {
+	return d.inner.TopByDescendants(n)
+}
+
+// TopByAncestors returns the n vertices with the most ancestors. See
+// GenericDAG.TopByAncestors for details.
+func (d *TypedDAG[T]) TopByAncestors(n int) []RankedVertex {
+	return d.inner.TopByAncestors(n)
+}
@@ -0,0 +1,36 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopByDescendantsAndAncestors(t *testing.T) {
+	/*   a   b
+	 *   |\ /
+	 *   c d
+	 *   |/
+	 *   e
+	 */
+	for _, opts := range [][]Option{nil, {WithoutCache()}} {
+		d := New[string](opts...)
+		for _, id := range []string{"a", "b", "c", "d", "e"} {
+			d.MustAddVertexByID(id, id)
+		}
+		for _, e := range [][2]string{{"a", "c"}, {"a", "d"}, {"b", "d"}, {"c", "e"}, {"d", "e"}} {
+			d.MustAddEdge(e[0], e[1])
+		}
+
+		want := []RankedVertex{{"a", 3}, {"b", 2}, {"c", 1}}
+		if got := d.TopByDescendants(3); !reflect.DeepEqual(got, want) {
+			t.Errorf("TopByDescendants(3) = %v, want %v", got, want)
+		}
+		want = []RankedVertex{{"e", 4}, {"d", 2}, {"c", 1}, {"a", 0}, {"b", 0}}
+		if got := d.TopByAncestors(10); !reflect.DeepEqual(got, want) {
+			t.Errorf("TopByAncestors(10) = %v, want %v", got, want)
+		}
+		if got := d.TopByAncestors(0); len(got) != 0 {
+			t.Errorf("TopByAncestors(0) = %v, want none", got)
+		}
+	}
+}
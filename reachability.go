@@ -0,0 +1,150 @@
+package dag
+
+import "sort"
+
+// ReachabilityIndex answers reachability queries on a graph in O(log k) time,
+// where k is the (typically small) number of intervals of the source vertex.
+// It is built by BuildReachabilityIndex and reflects the graph at that time,
+// i.e. it isn't updated when the graph is modified later on.
+//
+// The index labels the vertices with the post-order numbers of a spanning
+// forest of the graph, so that the descendants of each vertex form a short
+// list of intervals of these numbers (tree cover). This uses far less memory
+// than the descendants-cache for most graphs.
+type ReachabilityIndex struct {
+	positions map[string]int
+	post      []int32
+	intervals [][]reachInterval
+}
+
+// reachInterval is the closed interval of post-order numbers [low, high].
+type reachInterval struct {
+	low, high int32
+}
+
+// BuildReachabilityIndex returns a ReachabilityIndex for the current graph,
+// which is meant for graphs that aren't modified anymore and are queried very
+// often.
+func (d *GenericDAG[T]) BuildReachabilityIndex() *ReachabilityIndex {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	// number the vertices in the post-order of a depth-first search, which
+	// visits the children of each vertex before the vertex itself
+	n := d.ids.len()
+	index := &ReachabilityIndex{
+		positions: make(map[string]int, n),
+		post:      make([]int32, 0, n),
+		intervals: make([][]reachInterval, 0, n),
+	}
+	order := make([]vertexHandle, 0, n)
+	low := make(map[vertexHandle]int32, n)
+	post := make(map[vertexHandle]int32, n)
+	type frame struct {
+		h        vertexHandle
+		children []vertexHandle
+	}
+	for _, root := range d.rootHandles() {
+		low[root] = int32(len(order))
+		stack := []frame{{root, d.childHandles(root)}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if len(top.children) == 0 {
+				post[top.h] = int32(len(order))
+				order = append(order, top.h)
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			child := top.children[0]
+			top.children = top.children[1:]
+			if _, seen := low[child]; !seen {
+				low[child] = int32(len(order))
+				stack = append(stack, frame{child, d.childHandles(child)})
+			}
+		}
+	}
+
+	// the descendants of a vertex are its subtree in the spanning forest and
+	// the descendants of its other children, which are labeled before it
+	intervalsOf := make(map[vertexHandle][]reachInterval, n)
+	for _, h := range order {
+		intervals := []reachInterval{{low[h], post[h]}}
+		for child := range d.outboundEdge[h] {
+			intervals = append(intervals, intervalsOf[child]...)
+		}
+		intervals = mergeIntervals(intervals)
+		intervalsOf[h] = intervals
+
+		index.positions[d.ids.id(h)] = len(index.post)
+		index.post = append(index.post, post[h])
+		index.intervals = append(index.intervals, intervals)
+	}
+	return index
+}
+
+// childHandles returns the handles of the children of the vertex with the
+// handle h.
+func (d *GenericDAG[T]) childHandles(h vertexHandle) []vertexHandle {
+	children := make([]vertexHandle, 0, len(d.outboundEdge[h]))
+	d.eachChild(h, func(child vertexHandle) {
+		children = append(children, child)
+	})
+	return children
+}
+
+// mergeIntervals sorts the intervals and merges overlapping and adjacent ones.
+func mergeIntervals(intervals []reachInterval) []reachInterval {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].low < intervals[j].low
+	})
+	merged := intervals[:1]
+	for _, next := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if next.low > last.high+1 {
+			merged = append(merged, next)
+		} else if next.high > last.high {
+			last.high = next.high
+		}
+	}
+	return merged[:len(merged):len(merged)]
+}
+
+// IsReachable reports whether the vertex with dstID is a descendant of the
+// vertex with srcID, i.e. whether there is a path from srcID to dstID.
+// IsReachable returns an error if srcID or dstID is empty or unknown.
+func (r *ReachabilityIndex) IsReachable(srcID, dstID string) (bool, error) {
+	src, err := r.position(srcID)
+	if err != nil {
+		return false, err
+	}
+	dst, err := r.position(dstID)
+	if err != nil {
+		return false, err
+	}
+	if src == dst {
+		return false, nil
+	}
+	intervals := r.intervals[src]
+	p := r.post[dst]
+	i := sort.Search(len(intervals), func(i int) bool {
+		return intervals[i].high >= p
+	})
+	return i < len(intervals) && intervals[i].low <= p, nil
+}
+
+func (r *ReachabilityIndex) position(id string) (int, error) {
+	if id == "" {
+		return 0, IDEmptyError{}
+	}
+	i, exists := r.positions[id]
+	if !exists {
+		return 0, IDUnknownError{id}
+	}
+	return i, nil
+}
+
+// BuildReachabilityIndex returns a ReachabilityIndex for the current graph.
+// See GenericDAG.BuildReachabilityIndex for details.
+func (d *TypedDAG[T]) BuildReachabilityIndex() *ReachabilityIndex {
+	return d.inner.BuildReachabilityIndex()
+}
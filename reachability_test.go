@@ -0,0 +1,36 @@
+package dag
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestReachabilityIndex(t *testing.T) {
+	for _, size := range [][2]int{{0, 0}, {1, 0}, {30, 60}, {200, 600}, {200, 5000}} {
+		d, err := GenerateRandomDAG(7, size[0], size[1], strconv.Itoa)
+		if err != nil {
+			t.Fatalf("GenerateRandomDAG() unexpected error: %v", err)
+		}
+		index := d.BuildReachabilityIndex()
+		for srcID := range d.GetVertices() {
+			descendants, _ := d.GetDescendants(srcID)
+			for dstID := range d.GetVertices() {
+				_, want := descendants[dstID]
+				got, err := index.IsReachable(srcID, dstID)
+				if err != nil || got != want {
+					t.Fatalf("%v: IsReachable(%s, %s) = %v, %v, want %v", size, srcID, dstID, got, err, want)
+				}
+			}
+		}
+	}
+
+	d := New[string]()
+	d.MustAddVertexByID("a", "a")
+	index := d.BuildReachabilityIndex()
+	if _, err := index.IsReachable("", "a"); err == nil {
+		t.Error("IsReachable() with an empty id should fail")
+	}
+	if _, err := index.IsReachable("a", "b"); err == nil {
+		t.Error("IsReachable() with an unknown id should fail")
+	}
+}
@@ -0,0 +1,352 @@
+package dag
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+var (
+	_ GraphReader = (*DAG)(nil)
+	_ GraphReader = (*GenericDAG[int])(nil)
+	_ GraphReader = (*TypedDAG[int])(nil)
+)
+
+// GraphReader is the read-only view on the structure of a graph that the
+// algorithms of this package work on. It is implemented by DAG, GenericDAG
+// and TypedDAG, and may be implemented by adapters for other graph
+// representations.
+type GraphReader interface {
+	// Order returns the number of vertices.
+	Order() int
+
+	// Size returns the number of edges.
+	Size() int
+
+	// VertexIDs returns the ids of all vertices in no particular order.
+	VertexIDs() []string
+
+	// ChildrenIDs returns the ids of the children of the vertex with id in
+	// no particular order. It returns an error if id is empty or unknown.
+	ChildrenIDs(id string) ([]string, error)
+
+	// ParentsIDs returns the ids of the parents of the vertex with id in no
+	// particular order. It returns an error if id is empty or unknown.
+	ParentsIDs(id string) ([]string, error)
+}
+
+// Order returns the number of vertices in the graph.
+func (d *GenericDAG[T]) Order() int {
+	return d.GetOrder()
+}
+
+// Size returns the number of edges in the graph.
+func (d *GenericDAG[T]) Size() int {
+	return d.GetSize()
+}
+
+// VertexIDs returns the ids of all vertices in no particular order, or in
+// insertion order if the GenericDAG preserves it.
+func (d *GenericDAG[T]) VertexIDs() []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	ids := make([]string, 0, d.ids.len())
+	d.ids.each(func(id string, _ vertexHandle) bool {
+		ids = append(ids, id)
+		return true
+	})
+	return ids
+}
+
+// ChildrenIDs returns the ids of the children of the vertex with id, without
+// copying their values, in the order walks visit them: in insertion order, if
+// the GenericDAG preserves it and doesn't keep sorted children, and sorted by
+// id otherwise. ChildrenIDs returns an error if id is empty or unknown.
+func (d *GenericDAG[T]) ChildrenIDs(id string) ([]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	return d.childIDs(id), nil
+}
+
+// ParentsIDs returns the ids of the parents of the vertex with id, without
+// copying their values, sorted by id, unless the GenericDAG preserves the
+// insertion order. ParentsIDs returns an error if id is empty or unknown.
+func (d *GenericDAG[T]) ParentsIDs(id string) ([]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	return d.orderedRelativeIDs(d.inboundEdge[d.keyOf(id)]), nil
+}
+
+// Order returns the number of vertices in the graph.
+func (d *TypedDAG[T]) Order() int {
+	return d.inner.Order()
+}
+
+// Size returns the number of edges in the graph.
+func (d *TypedDAG[T]) Size() int {
+	return d.inner.Size()
+}
+
+// VertexIDs returns the ids of all vertices in no particular order.
+func (d *TypedDAG[T]) VertexIDs() []string {
+	return d.inner.VertexIDs()
+}
+
+// ChildrenIDs returns the ids of the children of the vertex with id. See
+// GenericDAG.ChildrenIDs for their order.
+func (d *TypedDAG[T]) ChildrenIDs(id string) ([]string, error) {
+	return d.inner.ChildrenIDs(id)
+}
+
+// ParentsIDs returns the ids of the parents of the vertex with id. See
+// GenericDAG.ParentsIDs for their order.
+func (d *TypedDAG[T]) ParentsIDs(id string) ([]string, error) {
+	return d.inner.ParentsIDs(id)
+}
+
+// TopologicalOrder returns the ids of all vertices of g such that for any
+// edge a -> b, a comes before b. Among the vertices that may come next, the
+// one with the smallest id is chosen, so the order is deterministic.
+// TopologicalOrder returns a CycleError if g (e.g. a user-provided adapter)
+// contains a cycle.
+//
+// As g is queried vertex by vertex, g must not be modified concurrently.
+func TopologicalOrder(g GraphReader) ([]string, error) {
+	order, remaining, err := kahnOrder(g.VertexIDs(), func(id string) (int, error) {
+		parents, err := g.ParentsIDs(id)
+		return len(parents), err
+	}, g.ChildrenIDs, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(remaining) > 0 {
+		ids := make([]string, 0, len(remaining))
+		for id := range remaining {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return nil, CycleError{ids}
+	}
+	return order, nil
+}
+
+// kahnOrder orders ids by Kahn's algorithm, such that for any edge a -> b, a
+// comes before b. parentCount and children return the number of parents and
+// the ids of the children of the vertex with id. If stable, the smallest id
+// among the vertices that may come next is chosen, otherwise the one that
+// became ready last. kahnOrder also returns the number of parents left of the
+// vertices it couldn't order, as they are on or behind a cycle.
+func kahnOrder(ids []string, parentCount func(id string) (int, error), children func(id string) ([]string, error), stable bool) ([]string, map[string]int, error) {
+	inDegree := make(map[string]int, len(ids))
+	ready := make(idHeap, 0)
+	for _, id := range ids {
+		n, err := parentCount(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		inDegree[id] = n
+		if n == 0 {
+			ready = append(ready, id)
+		}
+	}
+	if stable {
+		heap.Init(&ready)
+	}
+
+	order := make([]string, 0, len(ids))
+	for len(ready) > 0 {
+		var id string
+		if stable {
+			id = heap.Pop(&ready).(string)
+		} else {
+			id, ready = ready[len(ready)-1], ready[:len(ready)-1]
+		}
+		order = append(order, id)
+		delete(inDegree, id)
+		childIDs, err := children(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, child := range childIDs {
+			if inDegree[child]--; inDegree[child] == 0 {
+				if stable {
+					heap.Push(&ready, child)
+				} else {
+					ready = append(ready, child)
+				}
+			}
+		}
+	}
+	return order, inDegree, nil
+}
+
+// AllPaths returns all paths from the vertex with srcID to the vertex with
+// dstID in g, each as the list of vertex ids it visits including both ends.
+// Paths are returned in lexicographic order of their ids. AllPaths returns
+// an error if srcID or dstID is empty or unknown, and a CycleError if g (e.g.
+// a user-provided adapter) contains a cycle on a path from srcID to dstID.
+//
+// Note, the number of paths may grow exponentially with the size of g.
+func AllPaths(g GraphReader, srcID, dstID string) ([][]string, error) {
+	return allPaths(g, srcID, dstID, nil)
+}
+
+// allPaths is AllPaths, which returns a LimitExceededError once the search
+// exceeds budget.
+func allPaths(g GraphReader, srcID, dstID string, budget *traversalBudget) ([][]string, error) {
+	if _, err := g.ChildrenIDs(srcID); err != nil {
+		return nil, err
+	}
+	// only vertices reaching dstID lie on a path to it
+	reaching, err := reachingIDs(g, dstID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := reaching[srcID]; !ok {
+		return nil, nil
+	}
+
+	var paths [][]string
+	onPath := make(map[string]int)
+	var walk func(path []string) error
+	walk = func(path []string) error {
+		if err := budget.visit(); err != nil {
+			return err
+		}
+		id := path[len(path)-1]
+		if id == dstID {
+			if err := budget.result(); err != nil {
+				return err
+			}
+			paths = append(paths, append([]string(nil), path...))
+			return nil
+		}
+		children, err := g.ChildrenIDs(id)
+		if err != nil {
+			return err
+		}
+		sort.Strings(children)
+		onPath[id] = len(path) - 1
+		defer delete(onPath, id)
+		for _, child := range children {
+			if _, ok := reaching[child]; !ok {
+				continue
+			}
+			if i, ok := onPath[child]; ok {
+				ids := append([]string(nil), path[i:]...)
+				sort.Strings(ids)
+				return CycleError{ids}
+			}
+			if err := walk(append(path, child)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk([]string{srcID}); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// reachingIDs returns the ids of the vertices of g, from which the vertex
+// with id can be reached, including id.
+func reachingIDs(g GraphReader, id string) (map[string]struct{}, error) {
+	reaching := map[string]struct{}{id: {}}
+	queue := []string{id}
+	for len(queue) > 0 {
+		parents, err := g.ParentsIDs(queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+		for _, parent := range parents {
+			if _, ok := reaching[parent]; !ok {
+				reaching[parent] = struct{}{}
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return reaching, nil
+}
+
+// GraphStats describes the shape of a graph.
+type GraphStats struct {
+	Order  int // number of vertices
+	Size   int // number of edges
+	Roots  int // number of vertices without parents
+	Leaves int // number of vertices without children
+	Depth  int // number of edges on the longest path
+}
+
+// Stats returns the GraphStats of g. Stats returns a CycleError if g
+// contains a cycle.
+func Stats(g GraphReader) (GraphStats, error) {
+	order, err := TopologicalOrder(g)
+	if err != nil {
+		return GraphStats{}, err
+	}
+	stats := GraphStats{Order: g.Order(), Size: g.Size()}
+	depth := make(map[string]int, len(order))
+	for _, id := range order {
+		parents, err := g.ParentsIDs(id)
+		if err != nil {
+			return GraphStats{}, err
+		}
+		if len(parents) == 0 {
+			stats.Roots++
+		}
+		children, err := g.ChildrenIDs(id)
+		if err != nil {
+			return GraphStats{}, err
+		}
+		if len(children) == 0 {
+			stats.Leaves++
+		}
+		for _, child := range children {
+			if depth[id]+1 > depth[child] {
+				depth[child] = depth[id] + 1
+			}
+		}
+		if depth[id] > stats.Depth {
+			stats.Depth = depth[id]
+		}
+	}
+	return stats, nil
+}
+
+// CycleError is the error type to describe the situation, that a graph read
+// through a GraphReader or audited by AuditAcyclicity contains a cycle.
+type CycleError struct {
+	ids []string
+}
+
+// IDs returns the ids of the vertices involved in the cycle.
+func (e CycleError) IDs() []string {
+	return e.ids
+}
+
+// Implements the error interface.
+func (e CycleError) Error() string {
+	return fmt.Sprintf("graph contains a cycle among %v", e.ids)
+}
+
+// idHeap is a min-heap of vertex ids.
+type idHeap []string
+
+func (h idHeap) Len() int            { return len(h) }
+func (h idHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h idHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *idHeap) Push(x interface{}) { *h = append(*h, x.(string)) }
+func (h *idHeap) Pop() interface{} {
+	old := *h
+	id := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return id
+}
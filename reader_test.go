@@ -0,0 +1,158 @@
+package dag
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// mapGraph adapts an adjacency map to GraphReader.
+type mapGraph map[string][]string
+
+func (g mapGraph) Order() int { return len(g) }
+
+func (g mapGraph) Size() int {
+	size := 0
+	for _, children := range g {
+		size += len(children)
+	}
+	return size
+}
+
+func (g mapGraph) VertexIDs() []string {
+	ids := make([]string, 0, len(g))
+	for id := range g {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (g mapGraph) ChildrenIDs(id string) ([]string, error) {
+	children, ok := g[id]
+	if !ok {
+		return nil, IDUnknownError{id}
+	}
+	return children, nil
+}
+
+func (g mapGraph) ParentsIDs(id string) ([]string, error) {
+	if _, ok := g[id]; !ok {
+		return nil, IDUnknownError{id}
+	}
+	var parents []string
+	for parent, children := range g {
+		for _, child := range children {
+			if child == id {
+				parents = append(parents, parent)
+			}
+		}
+	}
+	return parents, nil
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	edges := [][2]string{{"b", "d"}, {"a", "d"}, {"d", "e"}, {"c", "e"}}
+	d := NewDAG()
+	g := NewGenericDAG[string]()
+	td := New[string]()
+	for _, id := range []string{"e", "d", "c", "b", "a"} {
+		d.MustAddVertexByID(id, id)
+		g.MustAddVertexByID(id, id)
+		td.MustAddVertexByID(id, id)
+	}
+	adapter := mapGraph{"a": nil, "b": nil, "c": nil, "d": nil, "e": nil}
+	for _, e := range edges {
+		d.MustAddEdge(e[0], e[1])
+		g.MustAddEdge(e[0], e[1])
+		td.MustAddEdge(e[0], e[1])
+		adapter[e[0]] = append(adapter[e[0]], e[1])
+	}
+
+	want := []string{"a", "b", "c", "d", "e"}
+	for name, reader := range map[string]GraphReader{"DAG": d, "GenericDAG": g, "TypedDAG": td, "adapter": adapter} {
+		if reader.Order() != 5 || reader.Size() != 4 {
+			t.Errorf("%s: Order() = %d, Size() = %d, want 5 and 4", name, reader.Order(), reader.Size())
+		}
+		order, err := TopologicalOrder(reader)
+		if err != nil {
+			t.Fatalf("%s: TopologicalOrder failed: %v", name, err)
+		}
+		if !reflect.DeepEqual(order, want) {
+			t.Errorf("%s: TopologicalOrder() = %v, want %v", name, order, want)
+		}
+	}
+
+	parents, err := g.ParentsIDs("d")
+	if err != nil || len(parents) != 2 {
+		t.Errorf("ParentsIDs(d) = %v, %v, want a and b", parents, err)
+	}
+	if _, err := g.ChildrenIDs("x"); !errors.As(err, new(IDUnknownError)) {
+		t.Errorf("ChildrenIDs(x) = %v, want IDUnknownError", err)
+	}
+
+	adapter["e"] = []string{"b"}
+	var cycle CycleError
+	if _, err := TopologicalOrder(adapter); !errors.As(err, &cycle) {
+		t.Fatalf("TopologicalOrder() = %v, want CycleError", err)
+	}
+	if !reflect.DeepEqual(cycle.ids, []string{"b", "d", "e"}) {
+		t.Errorf("CycleError lists %v, want b, d and e", cycle.ids)
+	}
+}
+
+func TestAllPathsAndStats(t *testing.T) {
+	d := NewDAG()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"a", "d"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	paths, err := AllPaths(d, "a", "d")
+	if err != nil {
+		t.Fatalf("AllPaths failed: %v", err)
+	}
+	want := [][]string{{"a", "b", "d"}, {"a", "c", "d"}, {"a", "d"}}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("AllPaths(a, d) = %v, want %v", paths, want)
+	}
+	if paths, _ := AllPaths(d, "d", "a"); len(paths) != 0 {
+		t.Errorf("AllPaths(d, a) = %v, want none", paths)
+	}
+	if _, err := AllPaths(d, "a", "x"); !errors.As(err, new(IDUnknownError)) {
+		t.Errorf("AllPaths(a, x) = %v, want IDUnknownError", err)
+	}
+
+	stats, err := Stats(d)
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if want := (GraphStats{Order: 5, Size: 5, Roots: 2, Leaves: 2, Depth: 2}); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestAllPaths_Cycle(t *testing.T) {
+	// b -> c -> b is a cycle on the way to d, x -> y -> x isn't
+	adapter := mapGraph{"a": {"b", "x"}, "b": {"c"}, "c": {"b", "d"}, "d": nil, "x": {"y"}, "y": {"x"}}
+	var cycle CycleError
+	if _, err := AllPaths(adapter, "a", "d"); !errors.As(err, &cycle) {
+		t.Fatalf("AllPaths(a, d) = %v, want CycleError", err)
+	}
+	if !reflect.DeepEqual(cycle.ids, []string{"b", "c"}) {
+		t.Errorf("CycleError lists %v, want b and c", cycle.ids)
+	}
+
+	adapter["c"] = []string{"d"}
+	paths, err := AllPaths(adapter, "a", "d")
+	if err != nil {
+		t.Fatalf("AllPaths(a, d) unexpected error: %v", err)
+	}
+	if want := [][]string{{"a", "b", "c", "d"}}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("AllPaths(a, d) = %v, want %v", paths, want)
+	}
+	if paths, err := AllPaths(adapter, "x", "d"); err != nil || len(paths) != 0 {
+		t.Errorf("AllPaths(x, d) = %v, %v, want none", paths, err)
+	}
+}
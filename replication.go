@@ -0,0 +1,126 @@
+package dag
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ChangeEvent is an operation numbered by its position in the stream of
+// modifications of a replicated GenericDAG, see Replicate. Sequence numbers
+// start at 1 and have no gaps.
+type ChangeEvent struct {
+	Seq uint64 `json:"seq"`
+	Op
+}
+
+// ChangeEventSink receives the ChangeEvents of a replicated GenericDAG.
+type ChangeEventSink interface {
+	RecordEvent(e ChangeEvent) error
+}
+
+// eventSequencer is an OpSink numbering the operations as ChangeEvents.
+type eventSequencer struct {
+	sink ChangeEventSink
+	seq  uint64
+}
+
+func (s *eventSequencer) RecordOp(op Op) error {
+	s.seq++
+	return s.sink.RecordEvent(ChangeEvent{Seq: s.seq, Op: op})
+}
+
+// Replicate starts the replication of the GenericDAG to followers, e.g. read
+// replicas in other processes. It returns a copy of the graph as it is now,
+// from which followers start, and passes every later modification as a
+// ChangeEvent to sink, like Record does. Followers apply the events in order
+// with ApplyEvent. The returned OpRecorder stops the replication.
+//
+// Replicate returns an error if the copy can't be created.
+func (d *GenericDAG[T]) Replicate(sink ChangeEventSink) (*GenericDAG[T], *OpRecorder, error) {
+	r := d.newRecorder(&eventSequencer{sink: sink})
+
+	// take the snapshot along with registering the recorder, so that no
+	// modification is missed or applied twice
+	d.muDAG.Lock()
+	d.recorders = append(d.recorders, r)
+	s := d.snapshot()
+	d.muDAG.Unlock()
+
+	initial, err := materializeGeneric(s)
+	if err != nil {
+		_ = r.Stop()
+		return nil, nil, err
+	}
+	return initial, r, nil
+}
+
+// ApplyEvent applies a ChangeEvent of a replicated GenericDAG to this
+// GenericDAG, which must be a follower started from the copy returned by
+// Replicate. Events already applied are ignored, so events may be delivered
+// more than once.
+//
+// ApplyEvent returns a ReplicationGapError if preceding events haven't been
+// applied yet, and a ReplicationConflictError if the event can't be applied,
+// e.g. as the follower has been modified otherwise. Either way the follower
+// is left unchanged.
+func (d *GenericDAG[T]) ApplyEvent(e ChangeEvent) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+	if e.Seq <= d.appliedSeq {
+		return nil
+	}
+	if e.Seq != d.appliedSeq+1 {
+		return ReplicationGapError{d.appliedSeq + 1, e.Seq}
+	}
+	if err := d.applyOp(e.Op); err != nil {
+		return ReplicationConflictError{e.Seq, err}
+	}
+	d.appliedSeq = e.Seq
+	return nil
+}
+
+// AppliedSeq returns the sequence number of the last ChangeEvent applied by
+// ApplyEvent, e.g. to request the events after it from the leader.
+func (d *GenericDAG[T]) AppliedSeq() uint64 {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.appliedSeq
+}
+
+// ReadChangeEvents reads ChangeEvents written by an OpWriter until the end of
+// r.
+func ReadChangeEvents(r io.Reader) ([]ChangeEvent, error) {
+	dec := json.NewDecoder(r)
+	var events []ChangeEvent
+	for {
+		var e ChangeEvent
+		if err := dec.Decode(&e); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return events, err
+		}
+		events = append(events, e)
+	}
+}
+
+// Replicate starts the replication of the TypedDAG to followers. See
+// GenericDAG.Replicate for details.
+func (d *TypedDAG[T]) Replicate(sink ChangeEventSink) (*TypedDAG[T], *OpRecorder, error) {
+	initial, r, err := d.inner.Replicate(sink)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &TypedDAG[T]{inner: initial}, r, nil
+}
+
+// ApplyEvent applies a ChangeEvent of a replicated graph to this TypedDAG.
+// See GenericDAG.ApplyEvent for details.
+func (d *TypedDAG[T]) ApplyEvent(e ChangeEvent) error {
+	return d.inner.ApplyEvent(e)
+}
+
+// AppliedSeq returns the sequence number of the last ChangeEvent applied by
+// ApplyEvent.
+func (d *TypedDAG[T]) AppliedSeq() uint64 {
+	return d.inner.AppliedSeq()
+}
@@ -0,0 +1,103 @@
+package dag
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestReplication(t *testing.T) {
+	leader := New[string]()
+	leader.MustAddVertexByID("a", "a")
+	leader.MustAddVertexByID("b", "b")
+	leader.MustAddEdge("a", "b")
+
+	var buf bytes.Buffer
+	follower, r, err := leader.Replicate(NewOpWriter(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leader.MustAddVertexByID("c", "c")
+	leader.MustAddEdge("b", "c")
+	leader.MustAddEdge("a", "c")
+	leader.ReduceTransitively()
+	if err := leader.DeleteVertex("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := ReadChangeEvents(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 || events[0].Seq != 1 || events[4].Seq != 5 {
+		t.Fatalf("got events %v, want 5 numbered from 1", events)
+	}
+
+	var gap ReplicationGapError
+	if err := follower.ApplyEvent(events[1]); !errors.As(err, &gap) {
+		t.Errorf("ApplyEvent(2) = %v, want ReplicationGapError", err)
+	}
+	for _, e := range events[:3] {
+		if err := follower.ApplyEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// events may be delivered more than once
+	for _, e := range events {
+		if err := follower.ApplyEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if follower.AppliedSeq() != 5 || follower.Hash() != leader.Hash() {
+		t.Errorf("follower at %d is %v, want 5 and %v", follower.AppliedSeq(), follower, leader)
+	}
+
+	// events conflicting with local modifications are rejected
+	follower.MustAddVertexByID("d", "d")
+	var conflict ReplicationConflictError
+	err = follower.ApplyEvent(ChangeEvent{Seq: 6, Op: Op{Kind: OpAddVertex, ID: "d", Value: []byte(`"d"`)}})
+	if !errors.As(err, &conflict) || !errors.As(err, new(VertexDuplicateError)) {
+		t.Errorf("conflicting ApplyEvent() = %v, want ReplicationConflictError", err)
+	}
+	if follower.AppliedSeq() != 5 {
+		t.Errorf("AppliedSeq() = %d after a conflict, want 5", follower.AppliedSeq())
+	}
+}
+
+func TestReplicationEdgeValues(t *testing.T) {
+	leader := New[string]()
+	leader.MustAddVertexByID("a", "a")
+	leader.MustAddVertexByID("b", "b")
+	leader.MustAddEdge("a", "b")
+
+	var buf bytes.Buffer
+	follower, r, err := leader.Replicate(NewOpWriter(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.SetEdgeValue("a", "b", "label"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := ReadChangeEvents(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range events {
+		if err := follower.ApplyEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, _ := follower.GetEdgeValue("a", "b"); v != "label" {
+		t.Errorf("follower GetEdgeValue(a, b) = %v, want label", v)
+	}
+	if follower.Hash() != leader.Hash() {
+		t.Errorf("follower is %v, want %v", follower, leader)
+	}
+}
@@ -0,0 +1,72 @@
+package dag
+
+import "sync"
+
+// maxPooledScratch bounds the size of the scratch buffers kept for reuse.
+// Buffers grown beyond it by a traversal of a huge graph are left to the
+// garbage collector instead of being pinned by the pools.
+const maxPooledScratch = 1 << 16
+
+// traversalScratch holds the temporaries of a traversal: the set of visited
+// vertices, a queue of vertices still to be expanded and a stack of ids.
+// Traversals take a traversalScratch from traversalPool by getScratch and
+// hand it back by release once they are done, so repeated traversals reuse
+// the memory of previous ones. A traversalScratch must not be retained or
+// used after release.
+type traversalScratch struct {
+	visited map[vertexHandle]struct{}
+	queue   []vertexHandle
+	head    int
+	ids     []string
+}
+
+var traversalPool = sync.Pool{
+	New: func() interface{} {
+		return &traversalScratch{visited: make(map[vertexHandle]struct{})}
+	},
+}
+
+// getScratch returns an empty traversalScratch.
+func getScratch() *traversalScratch {
+	return traversalPool.Get().(*traversalScratch)
+}
+
+// visit marks h as visited and appends it to the queue. visit returns false
+// and does nothing if h has been visited before.
+func (s *traversalScratch) visit(h vertexHandle) bool {
+	if _, exists := s.visited[h]; exists {
+		return false
+	}
+	s.visited[h] = struct{}{}
+	s.queue = append(s.queue, h)
+	return true
+}
+
+// seen returns true if h has been visited.
+func (s *traversalScratch) seen(h vertexHandle) bool {
+	_, exists := s.visited[h]
+	return exists
+}
+
+// next removes the first vertex from the queue and returns it. next returns
+// false if the queue is empty.
+func (s *traversalScratch) next() (vertexHandle, bool) {
+	if s.head == len(s.queue) {
+		return 0, false
+	}
+	h := s.queue[s.head]
+	s.head++
+	return h, true
+}
+
+// release resets s and puts it back into traversalPool.
+func (s *traversalScratch) release() {
+	if len(s.visited) > maxPooledScratch || cap(s.queue) > maxPooledScratch || cap(s.ids) > maxPooledScratch {
+		return
+	}
+	clear(s.visited)
+	s.queue, s.head = s.queue[:0], 0
+	clear(s.ids[:cap(s.ids)])
+	s.ids = s.ids[:0]
+	traversalPool.Put(s)
+}
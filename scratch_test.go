@@ -0,0 +1,32 @@
+package dag
+
+import "testing"
+
+func TestTraversalScratch(t *testing.T) {
+	s := getScratch()
+	for _, h := range []vertexHandle{3, 1, 3, 2} {
+		s.visit(h)
+	}
+	s.ids = append(s.ids, "a")
+	var order []vertexHandle
+	for h, ok := s.next(); ok; h, ok = s.next() {
+		order = append(order, h)
+	}
+	if len(order) != 3 || order[0] != 3 || order[1] != 1 || order[2] != 2 {
+		t.Errorf("next() returned %v, want [3 1 2]", order)
+	}
+	if !s.seen(1) || s.seen(4) {
+		t.Errorf("seen(1), seen(4) = %t, %t, want true, false", s.seen(1), s.seen(4))
+	}
+	s.release()
+
+	// whether or not the pool hands out the same scratch, it is empty
+	s = getScratch()
+	defer s.release()
+	if len(s.visited) != 0 || len(s.ids) != 0 {
+		t.Errorf("getScratch() = %+v, want empty scratch", s)
+	}
+	if _, ok := s.next(); ok {
+		t.Error("next() of empty scratch returned a vertex")
+	}
+}
@@ -0,0 +1,95 @@
+package dag
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sort"
+)
+
+// Hash returns a SHA-256 digest of the structure of the graph, its vertex
+// values and its edge values. The digest is canonical: it doesn't depend on
+// the order in which vertices and edges have been added, nor on the options
+// of the graph except for its codecs, so equal graphs have equal digests.
+// This allows to cheaply detect whether a graph has changed, e.g. to cache
+// artifacts derived from it by its content.
+//
+// Values are encoded like by MarshalJSON, i.e. with the codecs of the graph
+// if it has any, so the same values may hash differently under different
+// Options.Codecs. Edge values are encoded with encoding/json. Values which
+// can't be encoded are hashed by their Go syntax representation (%#v)
+// instead.
+func (d *GenericDAG[T]) Hash() [32]byte {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	ids := make([]string, 0, d.ids.len())
+	for id := range d.ids.handles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	h := sha256.New()
+	writeHashField(h, []byte{'v'})
+	for _, id := range ids {
+		writeHashField(h, []byte(id))
+		writeHashField(h, d.hashedValue(d.value(id)))
+	}
+	writeHashField(h, []byte{'e'})
+	for _, id := range ids {
+		children := relativeIDs(&d.ids, d.outboundEdge[d.keyOf(id)])
+		sort.Strings(children)
+		for _, child := range children {
+			writeHashField(h, []byte(id))
+			writeHashField(h, []byte(child))
+		}
+	}
+	if values := d.edgeValueList(); len(values) > 0 {
+		writeHashField(h, []byte{'w'})
+		for _, v := range values {
+			writeHashField(h, []byte(v.SrcID))
+			writeHashField(h, []byte(v.DstID))
+			writeHashField(h, hashedEdgeValue(v.Value))
+		}
+	}
+
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// hashedValue returns the encoding of v hashed by Hash.
+func (d *GenericDAG[T]) hashedValue(v T) []byte {
+	encoded, err := encodeValue(d.options.Codecs, v)
+	if err == nil {
+		var data []byte
+		if data, err = json.Marshal(encoded); err == nil {
+			return data
+		}
+	}
+	return []byte(fmt.Sprintf("%#v", v))
+}
+
+// hashedEdgeValue returns the encoding of the edge value v hashed by Hash.
+func hashedEdgeValue(v interface{}) []byte {
+	if data, err := json.Marshal(v); err == nil {
+		return data
+	}
+	return []byte(fmt.Sprintf("%#v", v))
+}
+
+// writeHashField writes data prefixed by its length to h, so that the
+// boundaries of fields are unambiguous.
+func writeHashField(h hash.Hash, data []byte) {
+	var n [binary.MaxVarintLen64]byte
+	h.Write(n[:binary.PutUvarint(n[:], uint64(len(data)))])
+	h.Write(data)
+}
+
+// Hash returns a SHA-256 digest of the structure of the graph, its vertex
+// values and its edge values. See GenericDAG.Hash for details.
+func (d *TypedDAG[T]) Hash() [32]byte {
+	return d.inner.Hash()
+}
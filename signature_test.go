@@ -0,0 +1,76 @@
+package dag
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	build := func(ids []string, edges [][2]string, opts ...Option) *TypedDAG[string] {
+		d := New[string](opts...)
+		for _, id := range ids {
+			d.MustAddVertexByID(id, "value of "+id)
+		}
+		for _, e := range edges {
+			d.MustAddEdge(e[0], e[1])
+		}
+		return d
+	}
+	d := build([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"a", "c"}})
+
+	// the order of additions and the options don't matter
+	same := build([]string{"c", "b", "a"}, [][2]string{{"a", "c"}, {"a", "b"}}, WithInsertionOrder())
+	if d.Hash() != same.Hash() {
+		t.Error("Hash() differs for equal graphs")
+	}
+
+	for name, other := range map[string]*TypedDAG[string]{
+		"edge":   build([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}}),
+		"vertex": build([]string{"a", "b", "c", "d"}, [][2]string{{"a", "b"}, {"a", "c"}}),
+		"empty":  New[string](),
+	} {
+		if d.Hash() == other.Hash() {
+			t.Errorf("Hash() equal for a graph with a different %s", name)
+		}
+	}
+
+	changed := build([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"a", "c"}}, WithDuplicateValues())
+	changed.MustAddVertexByID("x", "x")
+	_ = changed.DeleteVertex("x")
+	if d.Hash() != changed.Hash() {
+		t.Error("Hash() differs after adding and deleting a vertex")
+	}
+	_ = changed.DeleteVertex("c")
+	changed.MustAddVertexByID("c", "another value")
+	changed.MustAddEdge("a", "c")
+	if d.Hash() == changed.Hash() {
+		t.Error("Hash() equal for a graph with a different value")
+	}
+}
+
+func TestHashCodecs(t *testing.T) {
+	type secret struct{ s string }
+	codecs := NewCodecRegistry()
+	RegisterCodec(codecs,
+		func(s secret) (string, error) {
+			if s.s == "" {
+				return "", errors.New("empty")
+			}
+			return "redacted", nil
+		},
+		func(string) (secret, error) { return secret{}, nil })
+
+	a := New[secret](WithCodecs(codecs))
+	a.MustAddVertexByID("1", secret{"a"})
+	b := New[secret](WithCodecs(codecs))
+	b.MustAddVertexByID("1", secret{"b"})
+	if a.Hash() != b.Hash() {
+		t.Error("Hash() doesn't use the codecs")
+	}
+
+	c := New[secret](WithCodecs(codecs))
+	c.MustAddVertexByID("1", secret{})
+	if a.Hash() == c.Hash() {
+		t.Error("Hash() equal for a value that can't be encoded")
+	}
+}
@@ -0,0 +1,104 @@
+package dag
+
+import "sort"
+
+// SimulateDelete reports which vertices would lose all paths from the roots
+// of the graph, if the vertex with id was deleted, without deleting it. These
+// are the descendants of the vertex that are not reachable from any root
+// other than through it; they would become (or depend on) new roots. The
+// vertex itself is not part of the result. The ids are returned in sorted
+// order. SimulateDelete returns an error if id is empty or unknown.
+func (d *GenericDAG[T]) SimulateDelete(id string) ([]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return nil, err
+	}
+	vHash := d.keyOf(id)
+	return d.unreachableWithout(d.getDescendants(vHash), func(src, dst vertexHandle) bool {
+		return src == vHash
+	}), nil
+}
+
+// SimulateDeleteEdge reports which vertices would lose all paths from the
+// roots of the graph, if the edge between srcID and dstID was deleted,
+// without deleting it. The ids are returned in sorted order.
+// SimulateDeleteEdge returns an error if srcID or dstID are empty or unknown,
+// or if there is no such edge.
+func (d *GenericDAG[T]) SimulateDeleteEdge(srcID, dstID string) ([]string, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(srcID); err != nil {
+		return nil, err
+	}
+	if err := d.saneID(dstID); err != nil {
+		return nil, err
+	}
+	srcHash, dstHash := d.keyOf(srcID), d.keyOf(dstID)
+	if !d.isEdge(srcHash, dstHash) {
+		return nil, EdgeUnknownError{srcID, dstID}
+	}
+	affected := copyMap(d.getDescendants(dstHash))
+	affected[dstHash] = struct{}{}
+	return d.unreachableWithout(affected, func(src, dst vertexHandle) bool {
+		return src == srcHash && dst == dstHash
+	}), nil
+}
+
+// unreachableWithout returns the sorted ids of the affected vertices that
+// can't be reached from the roots, if the edges for which removed returns
+// true were deleted. Vertices outside of affected must not lose any paths
+// by the removal.
+func (d *GenericDAG[T]) unreachableWithout(affected map[vertexHandle]struct{}, removed func(src, dst vertexHandle) bool) []string {
+	if len(affected) == 0 {
+		return []string{}
+	}
+
+	// only the affected vertices need to be reached, and only from their
+	// unaffected parents, which stay reachable
+	reached := make(map[vertexHandle]struct{}, len(affected))
+	var fifo []vertexHandle
+	for h := range affected {
+		for parent := range d.inboundEdge[h] {
+			if _, exists := affected[parent]; !exists && !removed(parent, h) {
+				reached[h] = struct{}{}
+				fifo = append(fifo, h)
+				break
+			}
+		}
+	}
+	for len(fifo) > 0 {
+		top := fifo[0]
+		fifo = fifo[1:]
+		for child := range d.outboundEdge[top] {
+			if _, exists := reached[child]; exists || removed(top, child) {
+				continue
+			}
+			reached[child] = struct{}{}
+			fifo = append(fifo, child)
+		}
+	}
+
+	unreachable := make([]string, 0, len(affected)-len(reached))
+	for h := range affected {
+		if _, exists := reached[h]; !exists {
+			unreachable = append(unreachable, d.ids.id(h))
+		}
+	}
+	sort.Strings(unreachable)
+	return unreachable
+}
+
+// SimulateDelete reports which vertices would lose all paths from the roots
+// of the graph, if the vertex with id was deleted. See
+// GenericDAG.SimulateDelete for details.
+func (d *TypedDAG[T]) SimulateDelete(id string) ([]string, error) {
+	return d.inner.SimulateDelete(id)
+}
+
+// SimulateDeleteEdge reports which vertices would lose all paths from the
+// roots of the graph, if the edge between srcID and dstID was deleted. See
+// GenericDAG.SimulateDeleteEdge for details.
+func (d *TypedDAG[T]) SimulateDeleteEdge(srcID, dstID string) ([]string, error) {
+	return d.inner.SimulateDeleteEdge(srcID, dstID)
+}
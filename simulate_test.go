@@ -0,0 +1,73 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimulateDelete(t *testing.T) {
+	/*   r1   r2
+	 *   |  \ |
+	 *   a    b
+	 *   |  / |
+	 *   c    d
+	 *   |
+	 *   e
+	 */
+	d := New[string]()
+	for _, id := range []string{"r1", "r2", "a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"r1", "a"}, {"r1", "b"}, {"r2", "b"}, {"a", "c"}, {"b", "c"}, {"b", "d"}, {"c", "e"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	vertexTests := []struct {
+		id   string
+		want []string
+	}{
+		{"r1", []string{"a"}},
+		{"a", []string{}},
+		{"b", []string{"d"}},
+		{"c", []string{"e"}},
+		{"e", []string{}},
+	}
+	for _, tt := range vertexTests {
+		got, err := d.SimulateDelete(tt.id)
+		if err != nil {
+			t.Errorf("SimulateDelete(%s) unexpected error: %v", tt.id, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SimulateDelete(%s) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+
+	edgeTests := []struct {
+		src, dst string
+		want     []string
+	}{
+		{"r1", "a", []string{"a"}},
+		{"r1", "b", []string{}},
+		{"b", "d", []string{"d"}},
+		{"c", "e", []string{"e"}},
+	}
+	for _, tt := range edgeTests {
+		got, err := d.SimulateDeleteEdge(tt.src, tt.dst)
+		if err != nil {
+			t.Errorf("SimulateDeleteEdge(%s, %s) unexpected error: %v", tt.src, tt.dst, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SimulateDeleteEdge(%s, %s) = %v, want %v", tt.src, tt.dst, got, tt.want)
+		}
+	}
+
+	if d.GetOrder() != 7 || d.GetSize() != 7 {
+		t.Errorf("order = %d, size = %d after simulations, want 7, 7", d.GetOrder(), d.GetSize())
+	}
+	if _, err := d.SimulateDelete("x"); err == nil {
+		t.Error("SimulateDelete with unknown id expected error")
+	}
+	if _, err := d.SimulateDeleteEdge("a", "b"); err == nil {
+		t.Error("SimulateDeleteEdge with unknown edge expected error")
+	}
+}
@@ -0,0 +1,147 @@
+package dag
+
+// graphSnapshot is a shallow capture of the structure of a graph. Taking a
+// snapshot only copies ids, values and edges, so the graph needs to be locked
+// only briefly. The (more expensive) indexing of the copy happens in
+// materialize, without holding any lock of the original graph.
+type graphSnapshot[T any] struct {
+	options    Options
+	ids        []string
+	values     []T
+	edges      [][2]string
+	edgeValues []GenericEdgeValue
+}
+
+// snapshot captures the structure of the GenericDAG. snapshot must be called
+// with the read lock held.
+func (d *GenericDAG[T]) snapshot() graphSnapshot[T] {
+	s := graphSnapshot[T]{
+		options: d.options,
+		ids:     make([]string, 0, d.ids.len()),
+		values:  make([]T, 0, d.ids.len()),
+		edges:   make([][2]string, 0, d.getSize()),
+	}
+	d.ids.each(func(id string, h vertexHandle) bool {
+		s.ids = append(s.ids, id)
+		s.values = append(s.values, d.values[h])
+		return true
+	})
+	for src, children := range d.outboundEdge {
+		srcID := d.ids.id(src)
+		for dst := range children {
+			s.edges = append(s.edges, [2]string{srcID, d.ids.id(dst)})
+		}
+	}
+	s.edgeValues = d.edgeValueList()
+	return s
+}
+
+// descendantsSnapshot captures the vertex vHash and all its descendants.
+// descendantsSnapshot must be called with the read lock held.
+func (d *GenericDAG[T]) descendantsSnapshot(vHash vertexHandle) graphSnapshot[T] {
+	descendants := d.getDescendants(vHash)
+	s := graphSnapshot[T]{
+		options: d.options,
+		ids:     make([]string, 0, len(descendants)+1),
+		values:  make([]T, 0, len(descendants)+1),
+	}
+	capture := func(h vertexHandle) {
+		s.ids = append(s.ids, d.ids.id(h))
+		s.values = append(s.values, d.values[h])
+		for child := range d.outboundEdge[h] {
+			s.edges = append(s.edges, [2]string{d.ids.id(h), d.ids.id(child)})
+			if value, ok := d.edgeValues[[2]vertexHandle{h, child}]; ok {
+				s.edgeValues = append(s.edgeValues, GenericEdgeValue{SrcID: d.ids.id(h), DstID: d.ids.id(child), Value: value})
+			}
+		}
+	}
+	capture(vHash)
+	d.eachRelative(descendants, capture)
+	return s
+}
+
+// boxSnapshot returns the snapshot s with its values converted to
+// interface{}, to materialize it as DAG. The ids and edges are shared with s.
+func boxSnapshot[T any](s graphSnapshot[T]) graphSnapshot[interface{}] {
+	boxed := graphSnapshot[interface{}]{
+		options:    s.options,
+		ids:        s.ids,
+		values:     make([]interface{}, len(s.values)),
+		edges:      s.edges,
+		edgeValues: s.edgeValues,
+	}
+	for i, v := range s.values {
+		boxed.values[i] = v
+	}
+	return boxed
+}
+
+// materializeGeneric builds a new GenericDAG from the snapshot. As the
+// snapshot has been taken from a valid DAG, edges are linked without loop
+// detection.
+func materializeGeneric[T any](s graphSnapshot[T]) (*GenericDAG[T], error) {
+	return materializeChecked(s, nil)
+}
+
+// materializeChecked is materializeGeneric, which fails once c reports the
+// cancellation of its context. Values of edges missing from the snapshot
+// are dropped.
+func materializeChecked[T any](s graphSnapshot[T], c *cancelChecker) (*GenericDAG[T], error) {
+	options := s.options
+	options.VertexCapacity, options.EdgeCapacity = len(s.ids), len(s.edges)
+	newDAG := NewGenericDAG[T](WithOptions(options))
+	for i, id := range s.ids {
+		if err := c.err(); err != nil {
+			return nil, err
+		}
+		if err := newDAG.addVertexByID(id, s.values[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range s.edges {
+		if err := c.err(); err != nil {
+			return nil, err
+		}
+		newDAG.linkEdge(newDAG.keyOf(e[0]), newDAG.keyOf(e[1]))
+	}
+	for _, v := range s.edgeValues {
+		srcHash, srcKnown := newDAG.ids.handle(v.SrcID)
+		dstHash, dstKnown := newDAG.ids.handle(v.DstID)
+		if srcKnown && dstKnown && newDAG.isEdge(srcHash, dstHash) {
+			newDAG.setEdgeValue(srcHash, dstHash, v.Value)
+		}
+	}
+	return newDAG, nil
+}
+
+// materializeDAG builds a new DAG from the snapshot. Values are taken as they
+// are, even nil ones, but like any DAG the new one rejects adding nil values.
+func materializeDAG(s graphSnapshot[interface{}]) (*DAG, error) {
+	s.options.rejectNilValues = false
+	core, err := materializeGeneric(s)
+	if err != nil {
+		return nil, err
+	}
+	core.options.rejectNilValues = true
+	return &DAG{core}, nil
+}
+
+// rootsAndLeaves returns the ids of the roots and leaves of the snapshot, in
+// the order of its ids.
+func (s graphSnapshot[T]) rootsAndLeaves() (roots, leaves []string) {
+	hasParents := make(map[string]bool, len(s.ids))
+	hasChildren := make(map[string]bool, len(s.ids))
+	for _, e := range s.edges {
+		hasChildren[e[0]] = true
+		hasParents[e[1]] = true
+	}
+	for _, id := range s.ids {
+		if !hasParents[id] {
+			roots = append(roots, id)
+		}
+		if !hasChildren[id] {
+			leaves = append(leaves, id)
+		}
+	}
+	return roots, leaves
+}
@@ -0,0 +1,70 @@
+package dag
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestCopyDuringWrites(t *testing.T) {
+	d := NewGenericDAG[int]()
+	d.MustAddVertexByID("root", -1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			id := strconv.Itoa(i)
+			d.MustAddVertexByID(id, i)
+			d.MustAddEdge("root", id)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		c, err := d.Copy()
+		if err != nil {
+			t.Fatalf("Copy failed: %v", err)
+		}
+		// every copy is a consistent state: all vertices but the root hang
+		// below the root, except for a vertex whose edge was not yet added
+		if size, order := c.GetSize(), c.GetOrder(); size != order-1 && size != order-2 {
+			t.Errorf("inconsistent copy with %d vertices and %d edges", order, size)
+		}
+	}
+	wg.Wait()
+
+	c, err := d.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if c.GetOrder() != 201 || c.GetSize() != 200 {
+		t.Errorf("copy has %d vertices and %d edges, want 201 and 200", c.GetOrder(), c.GetSize())
+	}
+	if desc, _ := c.GetDescendants("root"); len(desc) != 200 {
+		t.Errorf("copy has %d descendants of root, want 200", len(desc))
+	}
+
+	// the copy is independent of the original
+	c.MustAddVertexByID("extra", 1000)
+	if d.GetOrder() != 201 {
+		t.Errorf("adding to the copy changed the original")
+	}
+}
+
+func TestCopyKeepsOptions(t *testing.T) {
+	d := NewDAG(WithDuplicateValues())
+	d.MustAddVertexByID("a", "same")
+	d.MustAddVertexByID("b", "same")
+	d.MustAddEdge("a", "b")
+
+	c, err := d.Copy()
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if c.GetOrder() != 2 || c.GetSize() != 1 {
+		t.Errorf("copy has %d vertices and %d edges, want 2 and 1", c.GetOrder(), c.GetSize())
+	}
+	if err := c.AddVertexByID("c", "same"); err != nil {
+		t.Errorf("copy does not allow duplicate values: %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package dag
+
+import "sort"
+
+// tombstone is what remains of a soft deleted vertex: its value, the ids of
+// the vertices it was connected to and the values of these edges by the id
+// of the other vertex.
+type tombstone[T any] struct {
+	value       T
+	parents     []string
+	children    []string
+	parentEdges map[string]interface{}
+	childEdges  map[string]interface{}
+}
+
+// SoftDeleteVertex removes the vertex with the given id from the DAG, but
+// keeps its value and edges, so RestoreVertex can bring it back. Until then,
+// the vertex is hidden from all queries, walks and flows as if it was deleted,
+// and its id can't be reused. Deleting the id with DeleteVertex drops the
+// vertex for good. Copies and serialized forms of the DAG don't include soft
+// deleted vertices.
+// SoftDeleteVertex returns an error if id is empty or unknown.
+func (d *GenericDAG[T]) SoftDeleteVertex(id string) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	if err := d.saneID(id); err != nil {
+		return err
+	}
+	h := d.keyOf(id)
+	t := &tombstone[T]{
+		value:    d.values[h],
+		parents:  d.relativeIDs(d.inboundEdge[h]),
+		children: d.relativeIDs(d.outboundEdge[h]),
+	}
+	for parent := range d.inboundEdge[h] {
+		if value, ok := d.edgeValues[[2]vertexHandle{parent, h}]; ok {
+			t.parentEdges = setEdgeValueOf(t.parentEdges, d.ids.id(parent), value)
+		}
+	}
+	for child := range d.outboundEdge[h] {
+		if value, ok := d.edgeValues[[2]vertexHandle{h, child}]; ok {
+			t.childEdges = setEdgeValueOf(t.childEdges, d.ids.id(child), value)
+		}
+	}
+	if err := d.deleteVertex(id); err != nil {
+		return err
+	}
+	if d.tombstones == nil {
+		d.tombstones = make(map[string]*tombstone[T])
+	}
+	d.tombstones[id] = t
+	return nil
+}
+
+// RestoreVertex brings back the soft deleted vertex with the given id along
+// with its edges to all vertices still in the DAG. Edges to soft deleted
+// vertices are restored along with these vertices later on.
+// RestoreVertex returns an error if id is empty or isn't soft deleted, if its
+// value was added to the DAG in the meantime, or if restoring its edges would
+// create a loop. The vertex stays soft deleted in this case.
+func (d *GenericDAG[T]) RestoreVertex(id string) error {
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	if id == "" {
+		return IDEmptyError{}
+	}
+	t, ok := d.tombstones[id]
+	if !ok {
+		return IDUnknownError{id}
+	}
+	delete(d.tombstones, id)
+	if err := d.restore(id, t); err != nil {
+		d.tombstones[id] = t
+		return err
+	}
+	return nil
+}
+
+// restore adds the vertex with the given id and its edges as recorded in t,
+// or nothing on error. The edges to soft deleted vertices are recorded in
+// their tombstones once all other edges have been added.
+func (d *GenericDAG[T]) restore(id string, t *tombstone[T]) error {
+	if err := d.addVertexByID(id, t.value); err != nil {
+		return err
+	}
+	var softParents, softChildren []string
+	for _, parent := range t.parents {
+		if _, exists := d.ids.handle(parent); !exists {
+			softParents = append(softParents, parent)
+			continue
+		}
+		if err := d.addEdge(parent, id); err != nil {
+			_ = d.deleteVertex(id)
+			return err
+		}
+		if value, ok := t.parentEdges[parent]; ok {
+			d.changeEdgeValue(parent, id, value)
+		}
+	}
+	for _, child := range t.children {
+		if _, exists := d.ids.handle(child); !exists {
+			softChildren = append(softChildren, child)
+			continue
+		}
+		if err := d.addEdge(id, child); err != nil {
+			_ = d.deleteVertex(id)
+			return err
+		}
+		if value, ok := t.childEdges[child]; ok {
+			d.changeEdgeValue(id, child, value)
+		}
+	}
+
+	for _, parent := range softParents {
+		if pt, ok := d.tombstones[parent]; ok {
+			pt.children = appendMissing(pt.children, id)
+			if value, ok := t.parentEdges[parent]; ok {
+				pt.childEdges = setEdgeValueOf(pt.childEdges, id, value)
+			}
+		}
+	}
+	for _, child := range softChildren {
+		if ct, ok := d.tombstones[child]; ok {
+			ct.parents = appendMissing(ct.parents, id)
+			if value, ok := t.childEdges[child]; ok {
+				ct.parentEdges = setEdgeValueOf(ct.parentEdges, id, value)
+			}
+		}
+	}
+	return nil
+}
+
+// forgetTombstoneEdges removes the edges to the vertex with the given id,
+// which has been deleted for good, from all tombstones.
+func (d *GenericDAG[T]) forgetTombstoneEdges(id string) {
+	for _, t := range d.tombstones {
+		t.parents = removeID(t.parents, id)
+		t.children = removeID(t.children, id)
+		delete(t.parentEdges, id)
+		delete(t.childEdges, id)
+	}
+}
+
+// removeID removes id from ids, keeping the order of the other ids.
+func removeID(ids []string, id string) []string {
+	for i, other := range ids {
+		if other == id {
+			return append(ids[:i:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+// appendMissing appends id to ids, unless ids already contains it.
+func appendMissing(ids []string, id string) []string {
+	for _, other := range ids {
+		if other == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+// setEdgeValueOf sets the value of the edge to the vertex with the given id
+// in edges, which is allocated if nil.
+func setEdgeValueOf(edges map[string]interface{}, id string, value interface{}) map[string]interface{} {
+	if edges == nil {
+		edges = make(map[string]interface{})
+	}
+	edges[id] = value
+	return edges
+}
+
+// IsSoftDeleted returns true, if the vertex with the given id is soft
+// deleted.
+func (d *GenericDAG[T]) IsSoftDeleted(id string) bool {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	_, ok := d.tombstones[id]
+	return ok
+}
+
+// GetSoftDeleted returns the soft deleted vertices by their ids.
+func (d *GenericDAG[T]) GetSoftDeleted() map[string]T {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	vertices := make(map[string]T, len(d.tombstones))
+	for id, t := range d.tombstones {
+		vertices[id] = t.value
+	}
+	return vertices
+}
+
+// relativeIDs returns the ids of the vertices in relatives in sorted order.
+func (d *GenericDAG[T]) relativeIDs(relatives map[vertexHandle]struct{}) []string {
+	ids := make([]string, 0, len(relatives))
+	for h := range relatives {
+		ids = append(ids, d.ids.id(h))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SoftDeleteVertex removes the vertex with the given id, but keeps it for
+// RestoreVertex. See GenericDAG.SoftDeleteVertex for details.
+func (d *TypedDAG[T]) SoftDeleteVertex(id string) error {
+	return d.inner.SoftDeleteVertex(id)
+}
+
+// RestoreVertex brings back the soft deleted vertex with the given id. See
+// GenericDAG.RestoreVertex for details.
+func (d *TypedDAG[T]) RestoreVertex(id string) error {
+	return d.inner.RestoreVertex(id)
+}
+
+// IsSoftDeleted returns true, if the vertex with the given id is soft
+// deleted.
+func (d *TypedDAG[T]) IsSoftDeleted(id string) bool {
+	return d.inner.IsSoftDeleted(id)
+}
+
+// GetSoftDeleted returns the soft deleted vertices by their ids.
+func (d *TypedDAG[T]) GetSoftDeleted() map[string]T {
+	return d.inner.GetSoftDeleted()
+}
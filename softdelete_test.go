@@ -0,0 +1,141 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSoftDeleteVertex(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c", "d"} {
+		d.MustAddVertexByID(id, i)
+	}
+	_ = d.AddEdge("a", "b")
+	_ = d.AddEdge("b", "c")
+	_ = d.AddEdge("b", "d")
+
+	if err := d.SoftDeleteVertex("b"); err != nil {
+		t.Fatalf("SoftDeleteVertex(b) unexpected error: %v", err)
+	}
+	if err := d.SoftDeleteVertex("c"); err != nil {
+		t.Fatalf("SoftDeleteVertex(c) unexpected error: %v", err)
+	}
+	if d.GetOrder() != 2 || d.GetSize() != 0 {
+		t.Errorf("GetOrder(), GetSize() = %d, %d, want 2, 0", d.GetOrder(), d.GetSize())
+	}
+	if _, err := d.GetVertex("b"); err != (IDUnknownError{"b"}) {
+		t.Errorf("GetVertex(b) = %v, want IDUnknownError", err)
+	}
+	if !d.IsSoftDeleted("b") || d.IsSoftDeleted("a") {
+		t.Error("IsSoftDeleted() reports the wrong vertices")
+	}
+	if got, want := d.GetSoftDeleted(), map[string]int{"b": 1, "c": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSoftDeleted() = %v, want %v", got, want)
+	}
+	if err := d.AddVertexByID("b", 9); err != (IDDuplicateError{"b"}) {
+		t.Errorf("AddVertexByID(b) = %v, want IDDuplicateError", err)
+	}
+
+	if err := d.RestoreVertex("b"); err != nil {
+		t.Fatalf("RestoreVertex(b) unexpected error: %v", err)
+	}
+	if v, _ := d.GetVertex("b"); v != 1 {
+		t.Errorf("GetVertex(b) = %d, want 1", v)
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"b", "d"}} {
+		if ok, _ := d.IsEdge(e[0], e[1]); !ok {
+			t.Errorf("edge %s -> %s not restored", e[0], e[1])
+		}
+	}
+	if err := d.RestoreVertex("c"); err != nil {
+		t.Fatalf("RestoreVertex(c) unexpected error: %v", err)
+	}
+	if ok, _ := d.IsEdge("b", "c"); !ok {
+		t.Error("edge b -> c between restored vertices not restored")
+	}
+	if err := d.RestoreVertex("c"); err != (IDUnknownError{"c"}) {
+		t.Errorf("RestoreVertex(c) twice = %v, want IDUnknownError", err)
+	}
+}
+
+func TestRestoreVertexLoop(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, i)
+	}
+	_ = d.AddEdge("a", "b")
+	_ = d.AddEdge("b", "c")
+	_ = d.SoftDeleteVertex("b")
+	_ = d.AddEdge("c", "a")
+
+	if _, ok := d.RestoreVertex("b").(EdgeLoopError); !ok {
+		t.Fatal("RestoreVertex(b) should fail with EdgeLoopError")
+	}
+	if !d.IsSoftDeleted("b") || d.GetOrder() != 2 || d.GetSize() != 1 {
+		t.Error("failed RestoreVertex(b) changed the DAG")
+	}
+
+	if err := d.DeleteVertex("b"); err != nil {
+		t.Fatalf("DeleteVertex(b) unexpected error: %v", err)
+	}
+	if d.IsSoftDeleted("b") {
+		t.Error("DeleteVertex(b) kept the soft deleted vertex")
+	}
+	if err := d.AddVertexByID("b", 1); err != nil {
+		t.Errorf("AddVertexByID(b) after DeleteVertex(b) unexpected error: %v", err)
+	}
+}
+
+func TestDeleteVertexForgetsTombstoneEdges(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c", "x"} {
+		d.MustAddVertexByID(id, i)
+	}
+	_ = d.AddEdge("a", "b")
+	_ = d.AddEdge("x", "b")
+	_ = d.AddEdge("b", "c")
+	_ = d.SetEdgeValue("a", "b", "old")
+	_ = d.SoftDeleteVertex("b")
+	_ = d.SoftDeleteVertex("a")
+
+	// deleting a soft deleted and a live neighbour for good
+	if err := d.DeleteVertex("a"); err != nil {
+		t.Fatalf("DeleteVertex(a) unexpected error: %v", err)
+	}
+	if err := d.DeleteVertex("x"); err != nil {
+		t.Fatalf("DeleteVertex(x) unexpected error: %v", err)
+	}
+	d.MustAddVertexByID("a", 10)
+	d.MustAddVertexByID("x", 11)
+	if err := d.RestoreVertex("b"); err != nil {
+		t.Fatalf("RestoreVertex(b) unexpected error: %v", err)
+	}
+	for _, parent := range []string{"a", "x"} {
+		if ok, _ := d.IsEdge(parent, "b"); ok {
+			t.Errorf("RestoreVertex(b) connected it to the new vertex %s", parent)
+		}
+	}
+	if ok, _ := d.IsEdge("b", "c"); !ok {
+		t.Error("RestoreVertex(b) didn't restore the edge b -> c")
+	}
+}
+
+func TestRestoreVertexLoopKeepsTombstones(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"p", "x", "b", "c"} {
+		d.MustAddVertexByID(id, i)
+	}
+	_ = d.AddEdge("p", "b")
+	_ = d.AddEdge("x", "b")
+	_ = d.AddEdge("b", "c")
+	_ = d.SoftDeleteVertex("b")
+	_ = d.SoftDeleteVertex("p")
+	_ = d.AddEdge("c", "x")
+
+	if _, ok := d.RestoreVertex("b").(EdgeLoopError); !ok {
+		t.Fatal("RestoreVertex(b) should fail with EdgeLoopError")
+	}
+	if children := d.inner.tombstones["p"].children; len(children) != 0 {
+		t.Errorf("failed RestoreVertex(b) left the children %v in the tombstone of p", children)
+	}
+}
@@ -0,0 +1,298 @@
+package dag
+
+import (
+	"fmt"
+	"sync"
+)
+
+// VertexStatus is the state of a vertex within a run of the graph, e.g. a
+// DescendantsFlow. Statuses are only tracked with Options.TrackStatus.
+//
+// A flow resets the statuses of its vertices when it starts, so they describe
+// the latest flow run on each vertex; flows running at the same time on
+// overlapping vertices share their statuses. Parents outside a flow which
+// aren't done become skipped when it starts, see StatusSkipped.
+type VertexStatus int
+
+const (
+	// StatusPending is the status of a vertex which waits for its parents.
+	// It is the status of all vertices which have no other status yet.
+	StatusPending VertexStatus = iota
+
+	// StatusReady is the status of a vertex whose parents are all done.
+	StatusReady
+
+	// StatusRunning is the status of a vertex which is being processed.
+	StatusRunning
+
+	// StatusSucceeded, StatusFailed and StatusSkipped are the statuses of a
+	// vertex which is done. Besides vertices skipped by a FlowCallback, a
+	// flow skips the parents of its vertices that aren't part of it.
+	StatusSucceeded
+	StatusFailed
+	StatusSkipped
+)
+
+var statusNames = [...]string{"Pending", "Ready", "Running", "Succeeded", "Failed", "Skipped"}
+
+// String returns the name of the status, e.g. "Running".
+func (s VertexStatus) String() string {
+	if s < 0 || int(s) >= len(statusNames) {
+		return fmt.Sprintf("VertexStatus(%d)", int(s))
+	}
+	return statusNames[s]
+}
+
+// done reports whether s is the status of a vertex which is done.
+func (s VertexStatus) done() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusSkipped
+}
+
+// statusTable holds the statuses of the vertices by their ids. It has its own
+// lock, as statuses change while flows hold the read lock of the graph.
+type statusTable struct {
+	mu       sync.Mutex
+	statuses map[string]VertexStatus
+}
+
+func (t *statusTable) get(id string) VertexStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.statuses[id]
+}
+
+func (t *statusTable) forget(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.statuses, id)
+}
+
+// GetStatus returns the status of the vertex with id. Without
+// Options.TrackStatus all vertices are pending. GetStatus returns an error if
+// id is empty or unknown.
+func (d *GenericDAG[T]) GetStatus(id string) (VertexStatus, error) {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if err := d.saneID(id); err != nil {
+		return StatusPending, err
+	}
+	if d.statuses == nil {
+		return StatusPending, nil
+	}
+	return d.statuses.get(id), nil
+}
+
+// SetStatus sets the status of the vertex with id, e.g. for vertices run by a
+// scheduler of the caller. A vertex may only become ready, running, succeeded
+// or failed if all its parents are done, i.e. succeeded, failed or skipped.
+// SetStatus may be called while a flow is running, but not from within a
+// FlowCallback, as the flow holds the read lock of the graph; use
+// SetStatusInFlow there instead.
+//
+// SetStatus returns an error if id is empty or unknown, if the status is
+// unknown, if status tracking is disabled, or a StatusInvariantError if the
+// status conflicts with the status of a parent.
+func (d *GenericDAG[T]) SetStatus(id string, status VertexStatus) error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	return d.setStatus(id, status)
+}
+
+// SetStatusInFlow is SetStatus for FlowCallbacks, e.g. to skip the vertex of
+// the callback: instead of locking the graph again, which may deadlock if a
+// writer is waiting, it relies on the read lock held by the flow. Thus,
+// SetStatusInFlow must only be called from within a FlowCallback of a flow of
+// the graph.
+func (d *GenericDAG[T]) SetStatusInFlow(id string, status VertexStatus) error {
+	return d.setStatus(id, status)
+}
+
+// setStatus is SetStatus, which must be called with the graph locked.
+func (d *GenericDAG[T]) setStatus(id string, status VertexStatus) error {
+	if err := d.saneID(id); err != nil {
+		return err
+	}
+	if status < StatusPending || status > StatusSkipped {
+		return fmt.Errorf("unknown vertex status %v", status)
+	}
+	if d.statuses == nil {
+		return fmt.Errorf("status tracking is disabled")
+	}
+	t := d.statuses
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := d.checkStatus(d.keyOf(id), status); err != nil {
+		return err
+	}
+	if t.statuses == nil {
+		t.statuses = make(map[string]VertexStatus)
+	}
+	t.statuses[id] = status
+	return nil
+}
+
+// checkStatus returns a StatusInvariantError if the vertex with the handle h
+// can't have the given status, as one of its parents isn't done. It must be
+// called under the lock of the status table.
+func (d *GenericDAG[T]) checkStatus(h vertexHandle, status VertexStatus) error {
+	if status == StatusPending || status == StatusSkipped {
+		return nil
+	}
+	for _, parent := range d.orderedRelativeIDs(d.inboundEdge[h]) {
+		if parentStatus := d.statuses.statuses[parent]; !parentStatus.done() {
+			return StatusInvariantError{d.ids.id(h), status, parent, parentStatus}
+		}
+	}
+	return nil
+}
+
+// GetVerticesByStatus returns the ids of all vertices with the given status,
+// sorted by id or in insertion order if the GenericDAG preserves it.
+func (d *GenericDAG[T]) GetVerticesByStatus(status VertexStatus) []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	selected := make(map[vertexHandle]struct{})
+	var t *statusTable
+	if d.statuses != nil {
+		t = d.statuses
+		t.mu.Lock()
+		defer t.mu.Unlock()
+	}
+	d.ids.each(func(id string, h vertexHandle) bool {
+		if t == nil && status == StatusPending || t != nil && t.statuses[id] == status {
+			selected[h] = struct{}{}
+		}
+		return true
+	})
+	return d.orderedRelativeIDs(selected)
+}
+
+// CheckStatuses checks that no vertex is ready, running, succeeded or failed
+// while one of its parents isn't done. It returns a MultiError of the
+// StatusInvariantErrors of all such vertices, or nil.
+func (d *GenericDAG[T]) CheckStatuses() error {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	if d.statuses == nil {
+		return nil
+	}
+	t := d.statuses
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var errs MultiError
+	for _, id := range d.orderedRelativeIDs(d.allHandles()) {
+		errs.add(d.checkStatus(d.keyOf(id), t.statuses[id]))
+	}
+	if len(errs.errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// allHandles returns the set of the handles of all vertices.
+func (d *GenericDAG[T]) allHandles() map[vertexHandle]struct{} {
+	all := make(map[vertexHandle]struct{}, d.ids.len())
+	d.ids.each(func(_ string, h vertexHandle) bool {
+		all[h] = struct{}{}
+		return true
+	})
+	return all
+}
+
+// startFlowStatuses resets the statuses of the vertices of a flow, of which
+// the vertex with startID is ready to run. A flow only runs its own vertices,
+// so their parents outside the flow, e.g. the parents of the start vertex,
+// count as done: unless they are done already, they are marked as skipped.
+func (d *GenericDAG[T]) startFlowStatuses(ids map[string]interface{}, startID string) {
+	t := d.statuses
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.statuses == nil {
+		t.statuses = make(map[string]VertexStatus, len(ids))
+	}
+	for id := range ids {
+		t.statuses[id] = StatusPending
+	}
+	for id := range ids {
+		for parent := range d.inboundEdge[d.keyOf(id)] {
+			parentID := d.ids.id(parent)
+			if _, inFlow := ids[parentID]; !inFlow && !t.statuses[parentID].done() {
+				t.statuses[parentID] = StatusSkipped
+			}
+		}
+	}
+	t.statuses[startID] = StatusReady
+}
+
+// setFlowStatus sets the status of the vertex with id within a flow.
+func (d *GenericDAG[T]) setFlowStatus(id string, status VertexStatus) {
+	t := d.statuses
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.statuses[id] = status
+}
+
+// finishFlowStatus marks the vertex with id as succeeded or failed, unless the
+// FlowCallback has set another status, and the children whose parents are all
+// done as ready.
+func (d *GenericDAG[T]) finishFlowStatus(id string, err error, children []string) {
+	t := d.statuses
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.statuses[id] == StatusRunning {
+		t.statuses[id] = StatusSucceeded
+		if err != nil {
+			t.statuses[id] = StatusFailed
+		}
+	}
+	for _, child := range children {
+		ready := true
+		for parent := range d.inboundEdge[d.keyOf(child)] {
+			if !t.statuses[d.ids.id(parent)].done() {
+				ready = false
+				break
+			}
+		}
+		if ready && t.statuses[child] == StatusPending {
+			t.statuses[child] = StatusReady
+		}
+	}
+}
+
+// GetStatus returns the status of the vertex with id. See
+// GenericDAG.GetStatus for details.
+func (d *TypedDAG[T]) GetStatus(id string) (VertexStatus, error) {
+	return d.inner.GetStatus(id)
+}
+
+// SetStatus sets the status of the vertex with id. See GenericDAG.SetStatus
+// for details.
+func (d *TypedDAG[T]) SetStatus(id string, status VertexStatus) error {
+	return d.inner.SetStatus(id, status)
+}
+
+// SetStatusInFlow is SetStatus for FlowCallbacks. See
+// GenericDAG.SetStatusInFlow for details.
+func (d *TypedDAG[T]) SetStatusInFlow(id string, status VertexStatus) error {
+	return d.inner.SetStatusInFlow(id, status)
+}
+
+// GetVerticesByStatus returns the ids of all vertices with the given status.
+func (d *TypedDAG[T]) GetVerticesByStatus(status VertexStatus) []string {
+	return d.inner.GetVerticesByStatus(status)
+}
+
+// CheckStatuses checks the statuses of all vertices against the statuses of
+// their parents. See GenericDAG.CheckStatuses for details.
+func (d *TypedDAG[T]) CheckStatuses() error {
+	return d.inner.CheckStatuses()
+}
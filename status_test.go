@@ -0,0 +1,182 @@
+package dag
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVertexStatus(t *testing.T) {
+	d := New[string](WithStatusTracking())
+	for _, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("b", "c")
+
+	if got, want := d.GetVerticesByStatus(StatusPending), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetVerticesByStatus(Pending) = %v, want %v", got, want)
+	}
+	if err := d.SetStatus("a", StatusRunning); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetStatus("b", StatusSkipped); err != nil {
+		t.Fatal(err)
+	}
+	var invariant StatusInvariantError
+	if err := d.SetStatus("c", StatusRunning); !errors.As(err, &invariant) {
+		t.Errorf("SetStatus(c, Running) = %v, want StatusInvariantError", err)
+	}
+	if err := d.SetStatus("a", StatusSucceeded); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetStatus("c", StatusReady); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := d.GetStatus("c"); status != StatusReady {
+		t.Errorf("GetStatus(c) = %v, want Ready", status)
+	}
+	if err := d.CheckStatuses(); err != nil {
+		t.Errorf("CheckStatuses() = %v, want nil", err)
+	}
+
+	// statuses may become inconsistent by resetting parents
+	if err := d.SetStatus("a", StatusPending); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CheckStatuses(); !errors.As(err, &invariant) {
+		t.Errorf("CheckStatuses() = %v, want StatusInvariantError", err)
+	}
+
+	if err := d.SetStatus("x", StatusReady); err != (IDUnknownError{"x"}) {
+		t.Errorf("SetStatus(x) = %v, want IDUnknownError", err)
+	}
+	if err := d.SetStatus("a", VertexStatus(42)); err == nil {
+		t.Error("SetStatus(a, 42) = nil, want error")
+	}
+	if err := New[string]().SetStatus("a", StatusReady); err == nil {
+		t.Error("SetStatus without status tracking = nil, want error")
+	}
+}
+
+func TestDescendantsFlowStatus(t *testing.T) {
+	d := NewDAG(WithStatusTracking())
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if err := d.AddVertexByID(id, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}} {
+		if err := d.AddEdge(e[0], e[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var violations []error
+	_, err := d.DescendantsFlow("a", nil, func(d *DAG, id string, _ []FlowResult) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if status, _ := d.GetStatus(id); status != StatusRunning {
+			violations = append(violations, errors.New(id+" is "+status.String()))
+		}
+		if err := d.CheckStatuses(); err != nil {
+			violations = append(violations, err)
+		}
+		switch id {
+		case "b":
+			return nil, errors.New("failed")
+		case "c":
+			return nil, d.SetStatusInFlow(id, StatusSkipped)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) > 0 {
+		t.Errorf("violations during flow: %v", violations)
+	}
+	for status, want := range map[VertexStatus][]string{
+		StatusSucceeded: {"a", "d"},
+		StatusFailed:    {"b"},
+		StatusSkipped:   {"c"},
+	} {
+		if got := d.GetVerticesByStatus(status); !reflect.DeepEqual(got, want) {
+			t.Errorf("GetVerticesByStatus(%v) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestFlowStatus_NonRootStart(t *testing.T) {
+	// a -> b -> c, x -> c; the flow from b doesn't run a and x
+	d := NewGenericDAG[string](WithStatusTracking())
+	for _, id := range []string{"a", "b", "c", "x"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("b", "c")
+	d.MustAddEdge("x", "c")
+
+	var mu sync.Mutex
+	var violations []error
+	_, err := GenericDescendantsFlow(d, "b", nil, func(d *GenericDAG[string], id string, _ []GenericFlowResult[int]) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := d.CheckStatuses(); err != nil {
+			violations = append(violations, err)
+		}
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) > 0 {
+		t.Errorf("violations during flow: %v", violations)
+	}
+	if err := d.CheckStatuses(); err != nil {
+		t.Errorf("CheckStatuses() = %v, want nil", err)
+	}
+	for status, want := range map[VertexStatus][]string{
+		StatusSucceeded: {"b", "c"},
+		StatusSkipped:   {"a", "x"},
+	} {
+		if got := d.GetVerticesByStatus(status); !reflect.DeepEqual(got, want) {
+			t.Errorf("GetVerticesByStatus(%v) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestSetStatusInFlow(t *testing.T) {
+	d := NewDAG(WithStatusTracking())
+	d.MustAddVertexByID("a", "a")
+	d.MustAddVertexByID("b", "b")
+	d.MustAddEdge("a", "b")
+
+	// a writer waiting for the lock of the graph while the callback of a
+	// sets its status must not deadlock the flow
+	writerDone := make(chan struct{})
+	_, err := d.DescendantsFlow("a", nil, func(d *DAG, id string, parents []FlowResult) (interface{}, error) {
+		if id == "a" {
+			go func() {
+				defer close(writerDone)
+				d.MustAddVertexByID("c", "c")
+			}()
+			time.Sleep(10 * time.Millisecond)
+			return nil, d.SetStatusInFlow(id, StatusSkipped)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-writerDone
+	if status, _ := d.GetStatus("a"); status != StatusSkipped {
+		t.Errorf("GetStatus(a) = %v, want %v", status, StatusSkipped)
+	}
+	if status, _ := d.GetStatus("b"); status != StatusSucceeded {
+		t.Errorf("GetStatus(b) = %v, want %v", status, StatusSucceeded)
+	}
+}
@@ -78,8 +78,9 @@ func (e storableEdge) Edge() (srcID, dstID string) {
 // It acts as a serializable operable structure.
 // And it uses short json tag to reduce the number of bytes after serialization.
 type storableDAG struct {
-	StorableVertices []Vertexer `json:"vs"`
-	StorableEdges    []Edger    `json:"es"`
+	StorableVertices []Vertexer         `json:"vs"`
+	StorableEdges    []Edger            `json:"es"`
+	EdgeValues       []GenericEdgeValue `json:"ev,omitempty"`
 }
 
 func (g storableDAG) Vertices() []Vertexer {
@@ -95,7 +96,8 @@ func (g storableDAG) Edges() []Edger {
 // And it uses short json tag to reduce the number of bytes after serialization.
 type storableDAGGeneric[T any] struct {
 	StorableVertices []storableVertexGeneric[T] `json:"vs"`
-	StorableEdges    []storableEdge              `json:"es"`
+	StorableEdges    []storableEdge             `json:"es"`
+	EdgeValues       []GenericEdgeValue         `json:"ev,omitempty"`
 }
 
 func (g storableDAGGeneric[T]) Vertices() []Vertexer {
@@ -131,6 +131,31 @@ func TestGetDescendantsGraphByDepth_DiamondPattern(t *testing.T) {
 	}
 }
 
+func TestGetRelativesGraphByDepth_KeepsOptions(t *testing.T) {
+	// a -> b -> c, where a and c hold the same value
+	dag := New[string](WithDuplicateValues())
+	dag.MustAddVertexByID("a", "x")
+	dag.MustAddVertexByID("b", "y")
+	dag.MustAddVertexByID("c", "x")
+	dag.MustAddEdge("a", "b")
+	dag.MustAddEdge("b", "c")
+
+	descendants, _, err := dag.GetDescendantsGraphByDepth("a", 3)
+	if err != nil {
+		t.Fatalf("GetDescendantsGraphByDepth() error = %v", err)
+	}
+	if order := descendants.GetOrder(); order != 3 {
+		t.Errorf("GetDescendantsGraphByDepth() order = %v, want %v", order, 3)
+	}
+	ancestors, _, err := dag.GetAncestorsGraphByDepth("c", 3)
+	if err != nil {
+		t.Fatalf("GetAncestorsGraphByDepth() error = %v", err)
+	}
+	if order := ancestors.GetOrder(); order != 3 {
+		t.Errorf("GetAncestorsGraphByDepth() order = %v, want %v", order, 3)
+	}
+}
+
 func TestGetAncestorsGraphByDepth(t *testing.T) {
 	// Create a DAG with multiple levels
 	dag := NewGenericDAG[string]()
@@ -0,0 +1,182 @@
+package dag
+
+import "sort"
+
+// SubgraphInfo describes how a subgraph has been copied, e.g. for diff or
+// visualization layers to correlate the copy with the original graph.
+type SubgraphInfo struct {
+	// RootID is the id of the copy of the vertex the subgraph has been
+	// extracted for.
+	RootID string
+
+	// Vertices maps the ids of the copied vertices to the ids of their
+	// copies.
+	Vertices map[string]string
+
+	// Edges maps the copied edges of the original graph to their copies.
+	Edges map[GenericEdge]GenericEdge
+
+	// SkippedVertices and SkippedEdges list the vertices and edges of the
+	// subgraph, which haven't been copied as the target graph already knew
+	// them, sorted by id. Only subgraphs copied into an existing graph skip
+	// any.
+	SkippedVertices []string
+	SkippedEdges    []GenericEdge
+}
+
+func newSubgraphInfo() *SubgraphInfo {
+	return &SubgraphInfo{
+		Vertices: make(map[string]string),
+		Edges:    make(map[GenericEdge]GenericEdge),
+	}
+}
+
+// addVertex records the copy of a vertex. Like the other recording methods,
+// addVertex does nothing if info is nil.
+func (info *SubgraphInfo) addVertex(id, newID string) {
+	if info != nil {
+		info.Vertices[id] = newID
+	}
+}
+
+// addEdge records the copy of an edge.
+func (info *SubgraphInfo) addEdge(original, copied GenericEdge) {
+	if info != nil {
+		info.Edges[original] = copied
+	}
+}
+
+// skipVertex records a vertex already known to the target graph.
+func (info *SubgraphInfo) skipVertex(id string) {
+	if info != nil {
+		info.SkippedVertices = append(info.SkippedVertices, id)
+	}
+}
+
+// skipEdge records an edge already known to the target graph.
+func (info *SubgraphInfo) skipEdge(e GenericEdge) {
+	if info != nil {
+		info.SkippedEdges = append(info.SkippedEdges, e)
+	}
+}
+
+// sortSkipped sorts the skipped vertices and edges by id.
+func (info *SubgraphInfo) sortSkipped() {
+	if info == nil {
+		return
+	}
+	sort.Strings(info.SkippedVertices)
+	sort.Slice(info.SkippedEdges, func(i, j int) bool {
+		a, b := info.SkippedEdges[i], info.SkippedEdges[j]
+		if a.SrcID != b.SrcID {
+			return a.SrcID < b.SrcID
+		}
+		return a.DstID < b.DstID
+	})
+}
+
+// GetDescendantsGraphInfo is like GetDescendantsGraphMapped, but returns a
+// SubgraphInfo mapping the vertices and edges of the original graph to their
+// copies instead of the id of the new root only. If mapID is nil, the copies
+// keep their ids like by GetDescendantsGraph.
+func (d *GenericDAG[T]) GetDescendantsGraphInfo(id string, mapID func(id string) string) (*GenericDAG[T], SubgraphInfo, error) {
+	info := newSubgraphInfo()
+	newDAG, _, err := d.getRelativesGraph(id, mapID, false, nil, info)
+	if err != nil {
+		return nil, SubgraphInfo{}, err
+	}
+	return newDAG, *info, nil
+}
+
+// GetAncestorsGraphInfo is like GetAncestorsGraphMapped, but returns a
+// SubgraphInfo. See GetDescendantsGraphInfo for details.
+func (d *GenericDAG[T]) GetAncestorsGraphInfo(id string, mapID func(id string) string) (*GenericDAG[T], SubgraphInfo, error) {
+	info := newSubgraphInfo()
+	newDAG, _, err := d.getRelativesGraph(id, mapID, true, nil, info)
+	if err != nil {
+		return nil, SubgraphInfo{}, err
+	}
+	return newDAG, *info, nil
+}
+
+// GetDescendantsGraphIntoInfo is like GetDescendantsGraphInto, but returns a
+// SubgraphInfo listing the vertices and edges copied into target as well as
+// those skipped, as target already knew them. If an error is returned, the
+// SubgraphInfo describes the partial modification of target.
+func (d *GenericDAG[T]) GetDescendantsGraphIntoInfo(id string, target *GenericDAG[T]) (SubgraphInfo, error) {
+	info := newSubgraphInfo()
+	err := d.getRelativesGraphInto(id, target, false, info)
+	return *info, err
+}
+
+// GetAncestorsGraphIntoInfo is like GetAncestorsGraphInto, but returns a
+// SubgraphInfo. See GetDescendantsGraphIntoInfo for details.
+func (d *GenericDAG[T]) GetAncestorsGraphIntoInfo(id string, target *GenericDAG[T]) (SubgraphInfo, error) {
+	info := newSubgraphInfo()
+	err := d.getRelativesGraphInto(id, target, true, info)
+	return *info, err
+}
+
+// GetDescendantsGraphInfo is like GetDescendantsGraphMapped, but returns a
+// SubgraphInfo. See GenericDAG.GetDescendantsGraphInfo for details.
+func (d *DAG) GetDescendantsGraphInfo(id string, mapID func(id string) string) (*DAG, SubgraphInfo, error) {
+	core, info, err := d.dagCore.GetDescendantsGraphInfo(id, mapID)
+	if err != nil {
+		return nil, info, err
+	}
+	return &DAG{core}, info, nil
+}
+
+// GetAncestorsGraphInfo is like GetAncestorsGraphMapped, but returns a
+// SubgraphInfo. See GenericDAG.GetDescendantsGraphInfo for details.
+func (d *DAG) GetAncestorsGraphInfo(id string, mapID func(id string) string) (*DAG, SubgraphInfo, error) {
+	core, info, err := d.dagCore.GetAncestorsGraphInfo(id, mapID)
+	if err != nil {
+		return nil, info, err
+	}
+	return &DAG{core}, info, nil
+}
+
+// GetDescendantsGraphIntoInfo is like GetDescendantsGraphInto, but returns a
+// SubgraphInfo. See GenericDAG.GetDescendantsGraphIntoInfo for details.
+func (d *DAG) GetDescendantsGraphIntoInfo(id string, target *DAG) (SubgraphInfo, error) {
+	return d.dagCore.GetDescendantsGraphIntoInfo(id, target.dagCore)
+}
+
+// GetAncestorsGraphIntoInfo is like GetAncestorsGraphInto, but returns a
+// SubgraphInfo. See GenericDAG.GetDescendantsGraphIntoInfo for details.
+func (d *DAG) GetAncestorsGraphIntoInfo(id string, target *DAG) (SubgraphInfo, error) {
+	return d.dagCore.GetAncestorsGraphIntoInfo(id, target.dagCore)
+}
+
+// GetDescendantsGraphInfo is like GetDescendantsGraphMapped, but returns a
+// SubgraphInfo. See GenericDAG.GetDescendantsGraphInfo for details.
+func (d *TypedDAG[T]) GetDescendantsGraphInfo(id string, mapID func(id string) string) (*TypedDAG[T], SubgraphInfo, error) {
+	inner, info, err := d.inner.GetDescendantsGraphInfo(id, mapID)
+	if err != nil {
+		return nil, info, err
+	}
+	return &TypedDAG[T]{inner: inner}, info, nil
+}
+
+// GetAncestorsGraphInfo is like GetAncestorsGraphMapped, but returns a
+// SubgraphInfo. See GenericDAG.GetDescendantsGraphInfo for details.
+func (d *TypedDAG[T]) GetAncestorsGraphInfo(id string, mapID func(id string) string) (*TypedDAG[T], SubgraphInfo, error) {
+	inner, info, err := d.inner.GetAncestorsGraphInfo(id, mapID)
+	if err != nil {
+		return nil, info, err
+	}
+	return &TypedDAG[T]{inner: inner}, info, nil
+}
+
+// GetDescendantsGraphIntoInfo is like GetDescendantsGraphInto, but returns a
+// SubgraphInfo. See GenericDAG.GetDescendantsGraphIntoInfo for details.
+func (d *TypedDAG[T]) GetDescendantsGraphIntoInfo(id string, target *TypedDAG[T]) (SubgraphInfo, error) {
+	return d.inner.GetDescendantsGraphIntoInfo(id, target.inner)
+}
+
+// GetAncestorsGraphIntoInfo is like GetAncestorsGraphInto, but returns a
+// SubgraphInfo. See GenericDAG.GetDescendantsGraphIntoInfo for details.
+func (d *TypedDAG[T]) GetAncestorsGraphIntoInfo(id string, target *TypedDAG[T]) (SubgraphInfo, error) {
+	return d.inner.GetAncestorsGraphIntoInfo(id, target.inner)
+}
@@ -0,0 +1,82 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetDescendantsGraphInfo(t *testing.T) {
+	d := New[string]()
+	for _, id := range []string{"a", "b", "c", "x"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("x", "a")
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("b", "c")
+
+	sub, info, err := d.GetDescendantsGraphInfo("a", func(id string) string { return "sub-" + id })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.RootID != "sub-a" || sub.GetOrder() != 3 {
+		t.Errorf("RootID = %q, GetOrder() = %d, want sub-a, 3", info.RootID, sub.GetOrder())
+	}
+	if want := map[string]string{"a": "sub-a", "b": "sub-b", "c": "sub-c"}; !reflect.DeepEqual(info.Vertices, want) {
+		t.Errorf("Vertices = %v, want %v", info.Vertices, want)
+	}
+	wantEdges := map[GenericEdge]GenericEdge{
+		{"a", "b"}: {"sub-a", "sub-b"},
+		{"a", "c"}: {"sub-a", "sub-c"},
+		{"b", "c"}: {"sub-b", "sub-c"},
+	}
+	if !reflect.DeepEqual(info.Edges, wantEdges) {
+		t.Errorf("Edges = %v, want %v", info.Edges, wantEdges)
+	}
+
+	_, info, err = d.GetAncestorsGraphInfo("b", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[GenericEdge]GenericEdge{{"x", "a"}: {"x", "a"}, {"a", "b"}: {"a", "b"}}; info.RootID != "b" || !reflect.DeepEqual(info.Edges, want) {
+		t.Errorf("RootID, Edges = %q, %v, want b, %v", info.RootID, info.Edges, want)
+	}
+
+	if _, _, err := d.GetDescendantsGraphInfo("unknown", nil); err == nil {
+		t.Error("GetDescendantsGraphInfo(unknown) expected error")
+	}
+}
+
+func TestGetDescendantsGraphIntoInfo(t *testing.T) {
+	d := New[string]()
+	for _, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("b", "c")
+
+	target := New[string]()
+	target.MustAddVertexByID("b", "b")
+	target.MustAddVertexByID("c", "c")
+	target.MustAddEdge("b", "c")
+
+	info, err := d.GetDescendantsGraphIntoInfo("a", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "a"}; !reflect.DeepEqual(info.Vertices, want) {
+		t.Errorf("Vertices = %v, want %v", info.Vertices, want)
+	}
+	if want := map[GenericEdge]GenericEdge{{"a", "b"}: {"a", "b"}}; !reflect.DeepEqual(info.Edges, want) {
+		t.Errorf("Edges = %v, want %v", info.Edges, want)
+	}
+	if want := []string{"b", "c"}; !reflect.DeepEqual(info.SkippedVertices, want) {
+		t.Errorf("SkippedVertices = %v, want %v", info.SkippedVertices, want)
+	}
+	if want := []GenericEdge{{"b", "c"}}; !reflect.DeepEqual(info.SkippedEdges, want) {
+		t.Errorf("SkippedEdges = %v, want %v", info.SkippedEdges, want)
+	}
+	if target.GetOrder() != 3 || target.GetSize() != 2 {
+		t.Errorf("target order, size = %d, %d, want 3, 2", target.GetOrder(), target.GetSize())
+	}
+}
@@ -0,0 +1,182 @@
+// Package taskdag declares Makefile style targets with dependencies, resolves
+// them into a DAG and runs them in dependency order, e.g.:
+//
+//	targets := taskdag.New(
+//		taskdag.Target("gen", taskdag.Do(generate)),
+//		taskdag.Target("lint", taskdag.Do(lint)),
+//		taskdag.Target("build", taskdag.DependsOn("gen", "lint"), taskdag.Do(build)),
+//	)
+//	err := targets.Run("build")
+//
+// Independent targets, e.g. gen and lint above, run concurrently.
+package taskdag
+
+import (
+	"fmt"
+
+	"github.com/JodeZer/dag"
+)
+
+// TargetSpec is a target declared by Target.
+type TargetSpec struct {
+	Name   string
+	Deps   []string
+	Action func() error
+}
+
+// Option configures a target.
+type Option func(*TargetSpec)
+
+// DependsOn adds the targets with the given names to the dependencies of a
+// target, which have to be done before the target runs.
+func DependsOn(names ...string) Option {
+	return func(t *TargetSpec) {
+		t.Deps = append(t.Deps, names...)
+	}
+}
+
+// Do sets the action of a target. Targets without an action only group
+// their dependencies.
+func Do(action func() error) Option {
+	return func(t *TargetSpec) {
+		t.Action = action
+	}
+}
+
+// Target declares the target with the given name.
+func Target(name string, opts ...Option) TargetSpec {
+	t := TargetSpec{Name: name}
+	for _, opt := range opts {
+		opt(&t)
+	}
+	return t
+}
+
+// Targets is a set of targets.
+type Targets struct {
+	targets []TargetSpec
+}
+
+// New returns the set of the given targets.
+func New(targets ...TargetSpec) *Targets {
+	return &Targets{targets: targets}
+}
+
+// Add adds the given targets to the set.
+func (s *Targets) Add(targets ...TargetSpec) {
+	s.targets = append(s.targets, targets...)
+}
+
+// MissingDependencyError is returned by Resolve and Run if a target depends on
+// a target that hasn't been declared.
+type MissingDependencyError struct {
+	Target     string
+	Dependency string
+}
+
+// Implements the error interface.
+func (e MissingDependencyError) Error() string {
+	return fmt.Sprintf("target '%s' depends on unknown target '%s'", e.Target, e.Dependency)
+}
+
+// TargetError is returned by Run if the action of a target fails.
+type TargetError struct {
+	Target string
+	Err    error
+}
+
+// Implements the error interface.
+func (e TargetError) Error() string {
+	return fmt.Sprintf("target '%s': %v", e.Target, e.Err)
+}
+
+// Unwrap returns the error of the action.
+func (e TargetError) Unwrap() error {
+	return e.Err
+}
+
+// Resolve returns the DAG of the targets: the id and value of each vertex is
+// the name of a target, and edges point from dependencies to the targets
+// depending on them. Resolve returns an error if a name is empty or declared
+// repeatedly, if a target depends on an unknown target (MissingDependencyError),
+// or if the dependencies form a cycle.
+func (s *Targets) Resolve() (*dag.DAG, error) {
+	d := dag.NewDAG()
+	for _, t := range s.targets {
+		if err := d.AddVertexByID(t.Name, t.Name); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range s.targets {
+		for _, dep := range t.Deps {
+			if _, err := d.GetVertex(dep); err != nil {
+				return nil, MissingDependencyError{Target: t.Name, Dependency: dep}
+			}
+			switch err := d.AddEdge(dep, t.Name); err.(type) {
+			case nil, dag.EdgeDuplicateError:
+			default:
+				return nil, err
+			}
+		}
+	}
+	return d, nil
+}
+
+// start is the id of the vertex the flow of Run starts at. It can't clash
+// with target names, as AddVertexByID rejects it if a target is named so.
+const start = "\x00start"
+
+// Run runs the target with the given name after all of its dependencies,
+// running independent targets concurrently. Each target runs at most once.
+// If an action fails, the targets depending on it don't run, and Run returns
+// a TargetError of the first failure it encounters. Run also returns the
+// errors of Resolve, and an error if the target is unknown.
+func (s *Targets) Run(name string) error {
+	d, err := s.Resolve()
+	if err != nil {
+		return err
+	}
+	actions := make(map[string]func() error, len(s.targets))
+	for _, t := range s.targets {
+		actions[t.Name] = t.Action
+	}
+
+	// run the target and its dependencies in a flow starting at a vertex
+	// preceding all dependencies without dependencies of their own
+	plan, _, err := d.GetAncestorsGraph(name)
+	if err != nil {
+		return err
+	}
+	roots := plan.GetRoots()
+	if err := plan.AddVertexByID(start, start); err != nil {
+		return err
+	}
+	for root := range roots {
+		if err := plan.AddEdge(start, root); err != nil {
+			return err
+		}
+	}
+
+	results, err := plan.DescendantsFlow(start, nil, func(_ *dag.DAG, id string, parents []dag.FlowResult) (interface{}, error) {
+		for _, parent := range parents {
+			if parent.Error != nil {
+				return nil, parent.Error
+			}
+		}
+		if action := actions[id]; action != nil {
+			if err := action(); err != nil {
+				return nil, TargetError{Target: id, Err: err}
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
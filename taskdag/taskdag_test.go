@@ -0,0 +1,84 @@
+package taskdag
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/JodeZer/dag"
+)
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	var done []string
+	action := func(name string, err error) Option {
+		return Do(func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			// all dependencies have been done before
+			done = append(done, name)
+			return err
+		})
+	}
+	failed := errors.New("failed")
+	targets := New(
+		Target("gen", action("gen", nil)),
+		Target("lint", action("lint", nil)),
+		Target("build", DependsOn("gen", "lint"), action("build", nil)),
+		Target("test", DependsOn("build"), action("test", nil)),
+		Target("docs", action("docs", nil)),
+		Target("all", DependsOn("test", "docs")),
+	)
+	targets.Add(
+		Target("broken", action("broken", failed)),
+		Target("release", DependsOn("all", "broken"), action("release", nil)),
+	)
+
+	if err := targets.Run("test"); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if len(done) != 4 || done[2] != "build" || done[3] != "test" {
+		t.Errorf("Run() ran %v, want gen and lint, then build and test", done)
+	}
+
+	done = nil
+	err := targets.Run("release")
+	var targetErr TargetError
+	if !errors.As(err, &targetErr) || targetErr.Target != "broken" || !errors.Is(err, failed) {
+		t.Errorf("Run() = %v, want the error of broken", err)
+	}
+	for _, name := range done {
+		if name == "release" {
+			t.Errorf("Run() ran release despite a failed dependency")
+		}
+	}
+
+	if err := targets.Run("unknown"); err == nil {
+		t.Error("Run() of an unknown target should fail")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	d, err := New(
+		Target("a"),
+		Target("b", DependsOn("a")),
+		Target("c", DependsOn("a", "b", "a")),
+	).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	if d.GetOrder() != 3 || d.GetSize() != 3 {
+		t.Errorf("Resolve() has %d vertices and %d edges, want 3 and 3", d.GetOrder(), d.GetSize())
+	}
+
+	_, err = New(Target("a", DependsOn("b"))).Resolve()
+	if err != (MissingDependencyError{Target: "a", Dependency: "b"}) {
+		t.Errorf("Resolve() = %v, want MissingDependencyError", err)
+	}
+	if _, err := New(Target("a", DependsOn("b")), Target("b", DependsOn("a"))).Resolve(); !errors.As(err, new(dag.EdgeLoopError)) {
+		t.Errorf("Resolve() of a cycle = %v, want EdgeLoopError", err)
+	}
+	if _, err := New(Target("a"), Target("a")).Resolve(); err == nil {
+		t.Error("Resolve() of a duplicate target should fail")
+	}
+}
@@ -0,0 +1,137 @@
+package dag
+
+import "strconv"
+
+// Template is a subgraph that ExpandTemplate instantiates once per parameter
+// of type P, e.g. the steps of a build that runs for each entry of a matrix
+// of platforms.
+type Template[T, P any] struct {
+	// Graph holds the vertices and edges of each instance.
+	Graph *GenericDAG[T]
+
+	// Name returns the name of the instance for the i-th parameter, which
+	// becomes part of the ids of its vertices. If Name is nil, instances are
+	// named by their index.
+	Name func(i int, param P) string
+
+	// Value returns the value of the vertex with the id and value v of Graph
+	// in the instance for param. If Value is nil, instances share the values
+	// of Graph, which requires WithDuplicateValues for the expanded graph.
+	Value func(id string, v T, param P) T
+}
+
+// TemplateInstance describes an instance added by ExpandTemplate.
+type TemplateInstance struct {
+	// Name is the name of the instance.
+	Name string
+
+	// Roots and Leaves are the ids of the roots and leaves of the instance,
+	// to which edges from and to the rest of the graph can be added.
+	Roots  []string
+	Leaves []string
+}
+
+// ExpandTemplate adds an instance of the template to d for each of params, in
+// that order. The vertex with id of the template gets the id
+// prefix + "[" + name + "]" + NestedIDSeparator + id in the instance with
+// name, e.g. "test[linux]/compile", so the ids are deterministic. Instances
+// are not connected to the rest of d; ExpandTemplate returns their roots and
+// leaves to do so.
+//
+// As NestedIDSeparator equals NamespaceSeparator, each instance forms its own
+// namespace, e.g. "test[linux]", unless prefix is namespaced itself. Under
+// Options.DisallowCrossNamespaceEdges, instances can thus only be connected to
+// the vertices of the namespace of a namespaced prefix, e.g. the instance
+// "ci/test[linux]" to "ci/checkout".
+//
+// Either all instances are added or, if any of their ids or values is already
+// known or not unique, none. ExpandTemplate returns an error in that case.
+func ExpandTemplate[T, P any](d *GenericDAG[T], prefix string, t Template[T, P], params []P) ([]TemplateInstance, error) {
+	t.Graph.muDAG.RLock()
+	s := t.Graph.snapshot()
+	t.Graph.muDAG.RUnlock()
+
+	// compute the ids and values of all instances in advance, to validate them
+	// before d is modified
+	instances := make([]TemplateInstance, len(params))
+	ids := make([][]string, len(params))
+	values := make([][]T, len(params))
+	for i, param := range params {
+		name := strconv.Itoa(i)
+		if t.Name != nil {
+			name = t.Name(i, param)
+		}
+		instances[i].Name = name
+		ids[i] = make([]string, len(s.ids))
+		values[i] = make([]T, len(s.ids))
+		for j, id := range s.ids {
+			ids[i][j] = prefix + "[" + name + "]" + NestedIDSeparator + id
+			values[i][j] = s.values[j]
+			if t.Value != nil {
+				values[i][j] = t.Value(id, s.values[j], param)
+			}
+		}
+	}
+
+	d.muDAG.Lock()
+	defer d.muDAG.Unlock()
+
+	newIDs := make(map[string]struct{}, len(params)*len(s.ids))
+	newValues := make(map[interface{}][]T)
+	for i := range params {
+		for j, id := range ids[i] {
+			if id == "" {
+				return nil, IDEmptyError{}
+			}
+			if _, exists := newIDs[id]; exists || d.idTaken(id) {
+				return nil, IDDuplicateError{id}
+			}
+			newIDs[id] = struct{}{}
+			if d.options.rejects(values[i][j]) {
+				return nil, VertexNilError{}
+			}
+			v := values[i][j]
+			hash, indexed := d.hashVertex(v)
+			if !indexed {
+				continue
+			}
+			if _, known := d.findValue(v, hash); known {
+				return nil, VertexDuplicateError{v}
+			}
+			for _, other := range newValues[hash] {
+				if d.equalValues(other, v) {
+					return nil, VertexDuplicateError{v}
+				}
+			}
+			newValues[hash] = append(newValues[hash], v)
+		}
+	}
+
+	// as the instances consist of new vertices only, neither adding the
+	// vertices nor the edges may fail anymore
+	roots, leaves := s.rootsAndLeaves()
+	for i := range params {
+		index := make(map[string]string, len(s.ids))
+		for j, id := range s.ids {
+			index[id] = ids[i][j]
+			if err := d.addVertexByID(ids[i][j], values[i][j]); err != nil {
+				return nil, err
+			}
+		}
+		for _, e := range s.edges {
+			if err := d.addEdge(index[e[0]], index[e[1]]); err != nil {
+				return nil, err
+			}
+		}
+		for _, v := range s.edgeValues {
+			d.changeEdgeValue(index[v.SrcID], index[v.DstID], v.Value)
+		}
+		for _, id := range roots {
+			instances[i].Roots = append(instances[i].Roots, index[id])
+		}
+		for _, id := range leaves {
+			instances[i].Leaves = append(instances[i].Leaves, index[id])
+		}
+	}
+	return instances, nil
+}
@@ -0,0 +1,140 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	tmpl := NewGenericDAG[string]()
+	_ = tmpl.AddVertexByID("compile", "compile")
+	_ = tmpl.AddVertexByID("test", "test")
+	_ = tmpl.AddEdge("compile", "test")
+
+	d := NewGenericDAG[string]()
+	_ = d.AddVertexByID("checkout", "checkout")
+
+	platforms := []string{"linux", "darwin"}
+	instances, err := ExpandTemplate(d, "build", Template[string, string]{
+		Graph: tmpl,
+		Name:  func(i int, platform string) string { return platform },
+		Value: func(id string, v string, platform string) string { return v + " on " + platform },
+	}, platforms)
+	if err != nil {
+		t.Fatalf("ExpandTemplate() unexpected error: %v", err)
+	}
+	want := []TemplateInstance{
+		{Name: "linux", Roots: []string{"build[linux]/compile"}, Leaves: []string{"build[linux]/test"}},
+		{Name: "darwin", Roots: []string{"build[darwin]/compile"}, Leaves: []string{"build[darwin]/test"}},
+	}
+	if !reflect.DeepEqual(instances, want) {
+		t.Errorf("ExpandTemplate() = %+v, want %+v", instances, want)
+	}
+	if d.GetOrder() != 5 || d.GetSize() != 2 {
+		t.Errorf("order = %d, size = %d, want 5, 2", d.GetOrder(), d.GetSize())
+	}
+	if v, _ := d.GetVertex("build[darwin]/test"); v != "test on darwin" {
+		t.Errorf("GetVertex(build[darwin]/test) = %q, want %q", v, "test on darwin")
+	}
+	if ok, _ := d.IsEdge("build[linux]/compile", "build[linux]/test"); !ok {
+		t.Error("IsEdge(build[linux]/compile, build[linux]/test) = false, want true")
+	}
+
+	// fan out from checkout
+	for _, instance := range instances {
+		for _, root := range instance.Roots {
+			if err := d.AddEdge("checkout", root); err != nil {
+				t.Errorf("AddEdge(checkout, %s) unexpected error: %v", root, err)
+			}
+		}
+	}
+}
+
+func TestExpandTemplate_Atomic(t *testing.T) {
+	tmpl := NewGenericDAG[int]()
+	_ = tmpl.AddVertexByID("a", 1)
+	_ = tmpl.AddVertexByID("b", 2)
+	_ = tmpl.AddEdge("a", "b")
+
+	d := NewGenericDAG[int]()
+	_ = d.AddVertexByID("m[1]/b", 0)
+
+	// the ids of the second instance are known
+	_, err := ExpandTemplate(d, "m", Template[int, int]{
+		Graph: tmpl,
+		Value: func(id string, v, param int) int { return v*10 + param },
+	}, []int{5, 6})
+	if _, ok := err.(IDDuplicateError); !ok {
+		t.Errorf("ExpandTemplate() = %v, want IDDuplicateError", err)
+	}
+
+	// the values of all instances are the same
+	_, err = ExpandTemplate(d, "n", Template[int, int]{Graph: tmpl}, []int{5, 6})
+	if _, ok := err.(VertexDuplicateError); !ok {
+		t.Errorf("ExpandTemplate() = %v, want VertexDuplicateError", err)
+	}
+
+	// the ids of the second instance are soft deleted
+	_ = d.AddVertexByID("t[1]/b", 1)
+	if err := d.SoftDeleteVertex("t[1]/b"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ExpandTemplate(d, "t", Template[int, int]{
+		Graph: tmpl,
+		Value: func(id string, v, param int) int { return v*10 + param },
+	}, []int{7, 8})
+	if _, ok := err.(IDDuplicateError); !ok {
+		t.Errorf("ExpandTemplate() = %v, want IDDuplicateError", err)
+	}
+
+	if d.GetOrder() != 1 {
+		t.Errorf("order = %d after failed expansions, want 1", d.GetOrder())
+	}
+}
+
+func TestExpandTemplate_Namespaces(t *testing.T) {
+	tmpl := NewGenericDAG[string]()
+	_ = tmpl.AddVertexByID("compile", "compile")
+
+	d := NewGenericDAG[string](WithDuplicateValues(), WithoutCrossNamespaceEdges())
+	_ = d.AddVertexByID("ci/checkout", "checkout")
+
+	// instances of a global prefix form their own namespace
+	instances, err := ExpandTemplate(d, "test", Template[string, string]{Graph: tmpl}, []string{"linux"})
+	if err != nil {
+		t.Fatalf("ExpandTemplate() unexpected error: %v", err)
+	}
+	if err := d.AddEdge("ci/checkout", instances[0].Roots[0]); err == nil {
+		t.Errorf("AddEdge(ci/checkout, %s) expected CrossNamespaceEdgeError", instances[0].Roots[0])
+	}
+
+	// instances of a namespaced prefix stay in its namespace
+	instances, err = ExpandTemplate(d, NamespacedID("ci", "test"), Template[string, string]{Graph: tmpl}, []string{"linux"})
+	if err != nil {
+		t.Fatalf("ExpandTemplate() unexpected error: %v", err)
+	}
+	if err := d.AddEdge("ci/checkout", instances[0].Roots[0]); err != nil {
+		t.Errorf("AddEdge(ci/checkout, %s) unexpected error: %v", instances[0].Roots[0], err)
+	}
+}
+
+func TestExpandTemplate_EdgeValues(t *testing.T) {
+	tmpl := NewGenericDAG[string]()
+	_ = tmpl.AddVertexByID("compile", "compile")
+	_ = tmpl.AddVertexByID("test", "test")
+	_ = tmpl.AddEdge("compile", "test")
+	if err := tmpl.SetEdgeValue("compile", "test", "artifact"); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewGenericDAG[string](WithDuplicateValues())
+	if _, err := ExpandTemplate(d, "build", Template[string, string]{Graph: tmpl}, []string{"linux", "darwin"}); err != nil {
+		t.Fatalf("ExpandTemplate() unexpected error: %v", err)
+	}
+	for _, name := range []string{"0", "1"} {
+		src, dst := "build["+name+"]/compile", "build["+name+"]/test"
+		if v, _ := d.GetEdgeValue(src, dst); v != "artifact" {
+			t.Errorf("GetEdgeValue(%s, %s) = %v, want artifact", src, dst, v)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package dag
+
+// GetTopologicalOrder returns the ids of all vertices such that for any edge
+// a -> b, a comes before b. Among vertices without a path between them, the
+// order is undefined and may differ between calls; use
+// GetTopologicalOrderStable for a deterministic order.
+func (d *GenericDAG[T]) GetTopologicalOrder() []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	order, _ := d.topologicalOrder(false)
+	return order
+}
+
+// GetTopologicalOrderStable returns the ids of all vertices such that for any
+// edge a -> b, a comes before b. Among the vertices that may come next, the
+// one with the smallest id is chosen, so the order is the same for equal
+// graphs.
+func (d *GenericDAG[T]) GetTopologicalOrderStable() []string {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+	order, _ := d.topologicalOrder(true)
+	return order
+}
+
+// GetTopologicalOrderValues returns the values of all vertices in the order
+// of GetTopologicalOrderStable.
+func (d *GenericDAG[T]) GetTopologicalOrderValues() []T {
+	d.muDAG.RLock()
+	defer d.muDAG.RUnlock()
+
+	ids, _ := d.topologicalOrder(true)
+	values := make([]T, len(ids))
+	for i, id := range ids {
+		values[i] = d.values[d.keyOf(id)]
+	}
+	return values
+}
+
+// topologicalOrder returns the ids of all vertices in topological order, in
+// a stable one if stable is set, and the number of parents left of those
+// which are on or behind a cycle, see kahnOrder. topologicalOrder must be
+// called with the read lock held.
+func (d *GenericDAG[T]) topologicalOrder(stable bool) ([]string, map[string]int) {
+	ids := make([]string, 0, d.ids.len())
+	d.ids.each(func(id string, _ vertexHandle) bool {
+		ids = append(ids, id)
+		return true
+	})
+	order, remaining, _ := kahnOrder(ids, func(id string) (int, error) {
+		return len(d.inboundEdge[d.keyOf(id)]), nil
+	}, func(id string) ([]string, error) {
+		return relativeIDs(&d.ids, d.outboundEdge[d.keyOf(id)]), nil
+	}, stable)
+	return order, remaining
+}
+
+// GetTopologicalOrder returns the ids of all vertices in topological order.
+// See GenericDAG.GetTopologicalOrder for details.
+func (d *TypedDAG[T]) GetTopologicalOrder() []string {
+	return d.inner.GetTopologicalOrder()
+}
+
+// GetTopologicalOrderStable returns the ids of all vertices in topological
+// order, choosing the smallest id among the vertices that may come next.
+func (d *TypedDAG[T]) GetTopologicalOrderStable() []string {
+	return d.inner.GetTopologicalOrderStable()
+}
+
+// GetTopologicalOrderValues returns the values of all vertices in the order
+// of GetTopologicalOrderStable.
+func (d *TypedDAG[T]) GetTopologicalOrderValues() []T {
+	return d.inner.GetTopologicalOrderValues()
+}
@@ -0,0 +1,41 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetTopologicalOrder(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"e", "d", "c", "b", "a"} {
+		d.MustAddVertexByID(id, i)
+	}
+	for _, e := range [][2]string{{"a", "d"}, {"c", "b"}, {"b", "d"}, {"d", "e"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	order := d.GetTopologicalOrder()
+	if len(order) != 5 {
+		t.Fatalf("GetTopologicalOrder() = %v, want 5 ids", order)
+	}
+	position := make(map[string]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+	for _, e := range [][2]string{{"a", "d"}, {"c", "b"}, {"b", "d"}, {"d", "e"}} {
+		if position[e[0]] > position[e[1]] {
+			t.Errorf("GetTopologicalOrder() = %v, want %s before %s", order, e[0], e[1])
+		}
+	}
+
+	want := []string{"a", "c", "b", "d", "e"}
+	if order := d.GetTopologicalOrderStable(); !reflect.DeepEqual(order, want) {
+		t.Errorf("GetTopologicalOrderStable() = %v, want %v", order, want)
+	}
+	if values := d.GetTopologicalOrderValues(); !reflect.DeepEqual(values, []int{4, 2, 3, 1, 0}) {
+		t.Errorf("GetTopologicalOrderValues() = %v, want [4 2 3 1 0]", values)
+	}
+	if order := NewDAG().GetTopologicalOrderStable(); len(order) != 0 {
+		t.Errorf("GetTopologicalOrderStable() of an empty graph = %v", order)
+	}
+}
@@ -1,5 +1,11 @@
 package dag
 
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
 // TypedDAG is a type-safe directed acyclic graph with vertex values of type T.
 // It provides compile-time type checking for vertex values and eliminates the need
 // for type assertions when working with vertices.
@@ -34,20 +40,54 @@ type TypedDAG[T any] struct {
 }
 
 // New creates a new type-safe DAG with vertex values of type T.
-func New[T any]() *TypedDAG[T] {
+// The given options configure the DAG for its whole lifetime.
+func New[T any](opts ...Option) *TypedDAG[T] {
 	return &TypedDAG[T]{
-		inner: NewGenericDAG[T](),
+		inner: NewGenericDAG[T](opts...),
 	}
 }
 
 // NewWithOptions creates a new type-safe DAG with vertex values of type T
-// and custom options.
-func NewWithOptions[T any](options Options) *TypedDAG[T] {
-	dag := &TypedDAG[T]{
-		inner: NewGenericDAG[T](),
+// and custom options, given either as Options or as OptionsT[T].
+func NewWithOptions[T any, O Options | OptionsT[T]](options O) *TypedDAG[T] {
+	return New[T](WithOptions(optionsOf[T](options)))
+}
+
+// FromDAG creates a new TypedDAG[T] holding a copy of the vertices and edges
+// of the legacy DAG d. FromDAG returns a TypeMismatchError listing all
+// vertices whose values are not of type T, instead of silently dropping them.
+func FromDAG[T any](d *DAG) (*TypedDAG[T], error) {
+	d.muDAG.RLock()
+	s := d.snapshot()
+	d.muDAG.RUnlock()
+
+	typed := graphSnapshot[T]{
+		options:    s.options,
+		ids:        s.ids,
+		values:     make([]T, len(s.values)),
+		edges:      s.edges,
+		edgeValues: s.edgeValues,
+	}
+	typed.options.rejectNilValues = false
+	var mismatches []string
+	for i, v := range s.values {
+		value, ok := v.(T)
+		if !ok {
+			mismatches = append(mismatches, s.ids[i])
+			continue
+		}
+		typed.values[i] = value
 	}
-	dag.inner.Options(options)
-	return dag
+	if len(mismatches) > 0 {
+		sort.Strings(mismatches)
+		return nil, TypeMismatchError{mismatches, reflect.TypeOf((*T)(nil)).Elem().String()}
+	}
+
+	inner, err := materializeGeneric(typed)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDAG[T]{inner: inner}, nil
 }
 
 // AddVertex adds the vertex v to the DAG.
@@ -57,23 +97,50 @@ func (d *TypedDAG[T]) AddVertex(v T) (string, error) {
 }
 
 // AddVertexByID adds the vertex v and the specified id to the DAG.
-// AddVertexByID returns an error if v is nil, v is already part of the graph,
-// or the specified id is already part of the graph.
+// AddVertexByID returns an error if the id is empty, v is nil, v is already
+// part of the graph, or the specified id is already part of the graph, unless
+// Options.ExistingIDs says otherwise.
 func (d *TypedDAG[T]) AddVertexByID(id string, v T) error {
 	return d.inner.AddVertexByID(id, v)
 }
 
+// AddVerticesBatch adds the vertices by their ids under a single acquisition
+// of the write lock. See GenericDAG.AddVerticesBatch for details.
+func (d *TypedDAG[T]) AddVerticesBatch(vertices map[string]T) error {
+	return d.inner.AddVerticesBatch(vertices)
+}
+
 // GetVertex returns a vertex by its id.
 // GetVertex returns an error if id is empty or unknown.
 func (d *TypedDAG[T]) GetVertex(id string) (T, error) {
 	return d.inner.GetVertex(id)
 }
 
+// GetVerticesByIDs returns the values of the vertices with the given ids and
+// the ids that are empty or unknown. See GenericDAG.GetVerticesByIDs for
+// details.
+func (d *TypedDAG[T]) GetVerticesByIDs(ids []string) (map[string]T, []string) {
+	return d.inner.GetVerticesByIDs(ids)
+}
+
+// Rehash recomputes the hash of the value of the vertex with id after the data
+// it points to has been modified. See GenericDAG.Rehash for details.
+func (d *TypedDAG[T]) Rehash(id string) error {
+	return d.inner.Rehash(id)
+}
+
 // GetVertices returns all vertices as a map of id to value.
 func (d *TypedDAG[T]) GetVertices() map[string]T {
 	return d.inner.GetVertices()
 }
 
+// GetVerticesPage returns the ids and values of at most limit vertices after
+// skipping the first offset vertices. See GenericDAG.GetVerticesPage for
+// details.
+func (d *TypedDAG[T]) GetVerticesPage(offset, limit int, sortByID bool) []GenericStorableVertex[T] {
+	return d.inner.GetVerticesPage(offset, limit, sortByID)
+}
+
 // DeleteVertex deletes the vertex with the given id.
 // DeleteVertex also deletes all attached edges (inbound and outbound).
 // DeleteVertex returns an error if id is empty or unknown.
@@ -88,6 +155,14 @@ func (d *TypedDAG[T]) AddEdge(srcID, dstID string) error {
 	return d.inner.AddEdge(srcID, dstID)
 }
 
+// EnsureEdge adds an edge between srcID and dstID, unless it already exists.
+// EnsureEdge returns true if the edge has been added, and false if it already
+// existed. EnsureEdge returns an error if srcID or dstID are empty strings or
+// unknown, or if the new edge would create a loop.
+func (d *TypedDAG[T]) EnsureEdge(srcID, dstID string) (bool, error) {
+	return d.inner.EnsureEdge(srcID, dstID)
+}
+
 // IsEdge returns true if there exists an edge between srcID and dstID.
 // IsEdge returns false if there is no such edge.
 // IsEdge returns an error if srcID or dstID are empty, unknown, or the same.
@@ -122,6 +197,18 @@ func (d *TypedDAG[T]) GetLeaves() map[string]T {
 	return d.inner.GetLeaves()
 }
 
+// LeavesIter returns an iterator over the ids and values of all vertices
+// without children. See GenericDAG.RootsIter for details.
+func (d *TypedDAG[T]) LeavesIter() func(yield func(id string, value T) bool) {
+	return d.inner.LeavesIter()
+}
+
+// LeavesVersion returns a counter that changes whenever the set of leaves
+// changes.
+func (d *TypedDAG[T]) LeavesVersion() uint64 {
+	return d.inner.LeavesVersion()
+}
+
 // IsLeaf returns true if the vertex with the given id has no children.
 // IsLeaf returns an error if id is empty or unknown.
 func (d *TypedDAG[T]) IsLeaf(id string) (bool, error) {
@@ -133,6 +220,18 @@ func (d *TypedDAG[T]) GetRoots() map[string]T {
 	return d.inner.GetRoots()
 }
 
+// RootsIter returns an iterator over the ids and values of all vertices
+// without parents. See GenericDAG.RootsIter for details.
+func (d *TypedDAG[T]) RootsIter() func(yield func(id string, value T) bool) {
+	return d.inner.RootsIter()
+}
+
+// RootsVersion returns a counter that changes whenever the set of roots
+// changes.
+func (d *TypedDAG[T]) RootsVersion() uint64 {
+	return d.inner.RootsVersion()
+}
+
 // IsRoot returns true if the vertex with the given id has no parents.
 // IsRoot returns an error if id is empty or unknown.
 func (d *TypedDAG[T]) IsRoot(id string) (bool, error) {
@@ -164,12 +263,26 @@ func (d *TypedDAG[T]) GetOrderedAncestors(id string) ([]string, error) {
 	return d.inner.GetOrderedAncestors(id)
 }
 
+// GetOrderedAncestorsWithValues returns the ids and values of all ancestors of
+// the vertex with id in the breath-first order of GetOrderedAncestors.
+// GetOrderedAncestorsWithValues returns an error if id is empty or unknown.
+func (d *TypedDAG[T]) GetOrderedAncestorsWithValues(id string) ([]GenericStorableVertex[T], error) {
+	return d.inner.GetOrderedAncestorsWithValues(id)
+}
+
 // GetDescendants returns all descendants of the vertex with the id.
 // GetDescendants returns an error if id is empty or unknown.
 func (d *TypedDAG[T]) GetDescendants(id string) (map[string]T, error) {
 	return d.inner.GetDescendants(id)
 }
 
+// GetDescendantsVia returns all descendants of the vertex with rootID whose
+// every path from rootID passes through the vertex with viaID. See
+// GenericDAG.GetDescendantsVia for details.
+func (d *TypedDAG[T]) GetDescendantsVia(rootID, viaID string) (map[string]T, error) {
+	return d.inner.GetDescendantsVia(rootID, viaID)
+}
+
 // GetOrderedDescendants returns all descendants of the vertex with id
 // in a breath-first order. Only the first occurrence of each vertex is returned.
 // GetOrderedDescendants returns an error if id is empty or unknown.
@@ -177,11 +290,19 @@ func (d *TypedDAG[T]) GetOrderedDescendants(id string) ([]string, error) {
 	return d.inner.GetOrderedDescendants(id)
 }
 
+// GetOrderedDescendantsWithValues returns the ids and values of all
+// descendants of the vertex with id in the breath-first order of
+// GetOrderedDescendants. GetOrderedDescendantsWithValues returns an error if
+// id is empty or unknown.
+func (d *TypedDAG[T]) GetOrderedDescendantsWithValues(id string) ([]GenericStorableVertex[T], error) {
+	return d.inner.GetOrderedDescendantsWithValues(id)
+}
+
 // GetDescendantsGraph returns a new TypedDAG consisting of the vertex with id
 // and all its descendants (i.e. the subgraph). GetDescendantsGraph also returns
 // the id of the (copy of the) given vertex within the new graph (i.e. the id of
 // the single root of the new graph). GetDescendantsGraph returns an error if id
-// is empty or unknown.
+// is empty or unknown. The vertices of the new graph keep their ids.
 func (d *TypedDAG[T]) GetDescendantsGraph(id string) (*TypedDAG[T], string, error) {
 	inner, newId, err := d.inner.GetDescendantsGraph(id)
 	if err != nil {
@@ -194,7 +315,7 @@ func (d *TypedDAG[T]) GetDescendantsGraph(id string) (*TypedDAG[T], string, erro
 // and all its ancestors (i.e. the subgraph). GetAncestorsGraph also returns the id
 // of the (copy of the) given vertex within the new graph (i.e. the id of the
 // single leaf of the new graph). GetAncestorsGraph returns an error if id is
-// empty or unknown.
+// empty or unknown. The vertices of the new graph keep their ids.
 func (d *TypedDAG[T]) GetAncestorsGraph(id string) (*TypedDAG[T], string, error) {
 	inner, newId, err := d.inner.GetAncestorsGraph(id)
 	if err != nil {
@@ -203,6 +324,42 @@ func (d *TypedDAG[T]) GetAncestorsGraph(id string) (*TypedDAG[T], string, error)
 	return &TypedDAG[T]{inner: inner}, newId, nil
 }
 
+// GetDescendantsGraphMapped is like GetDescendantsGraph, but the copy of each
+// vertex gets the id mapID returns for its original id. See
+// GenericDAG.GetDescendantsGraphMapped for details.
+func (d *TypedDAG[T]) GetDescendantsGraphMapped(id string, mapID func(id string) string) (*TypedDAG[T], string, error) {
+	inner, newId, err := d.inner.GetDescendantsGraphMapped(id, mapID)
+	if err != nil {
+		return nil, "", err
+	}
+	return &TypedDAG[T]{inner: inner}, newId, nil
+}
+
+// GetAncestorsGraphMapped is like GetAncestorsGraph, but the copy of each
+// vertex gets the id mapID returns for its original id. See
+// GenericDAG.GetDescendantsGraphMapped for details.
+func (d *TypedDAG[T]) GetAncestorsGraphMapped(id string, mapID func(id string) string) (*TypedDAG[T], string, error) {
+	inner, newId, err := d.inner.GetAncestorsGraphMapped(id, mapID)
+	if err != nil {
+		return nil, "", err
+	}
+	return &TypedDAG[T]{inner: inner}, newId, nil
+}
+
+// GetDescendantsGraphInto copies the vertex with id and all its descendants
+// into target, merging them with the vertices already there. See
+// GenericDAG.GetDescendantsGraphInto for details.
+func (d *TypedDAG[T]) GetDescendantsGraphInto(id string, target *TypedDAG[T]) error {
+	return d.inner.GetDescendantsGraphInto(id, target.inner)
+}
+
+// GetAncestorsGraphInto copies the vertex with id and all its ancestors into
+// target, merging them with the vertices already there. See
+// GenericDAG.GetDescendantsGraphInto for details.
+func (d *TypedDAG[T]) GetAncestorsGraphInto(id string, target *TypedDAG[T]) error {
+	return d.inner.GetAncestorsGraphInto(id, target.inner)
+}
+
 // AncestorsWalker returns a channel and subsequently walks all ancestors of
 // the vertex with id in a breath first order. The second channel returned may
 // be used to stop further walking. AncestorsWalker returns an error if id is
@@ -222,11 +379,47 @@ func (d *TypedDAG[T]) DescendantsWalker(id string) (chan string, chan bool, erro
 // DescendantsFlow traverses descendants of the vertex with the ID startID.
 // For the vertex itself and each of its descendant it executes the given
 // callback function providing it the results of its respective parents.
-// The callback function is only executed after all parents have finished their work.
+// The callback function is only executed after all parents within the flow
+// have finished their work. See DAG.DescendantsFlow for details.
+//
+// The flow runs on the TypedDAG itself. callback is provided it as DAG: a
+// TypedDAG[interface{}] is wrapped as is, other TypedDAGs are copied once the
+// first callback runs. The copy shares the vertex statuses and locks of the
+// TypedDAG.
 func (d *TypedDAG[T]) DescendantsFlow(startID string, inputs []FlowResult, callback FlowCallback) ([]FlowResult, error) {
-	// Note: DescendantsFlow still works with the old DAG interface due to interface{} in FlowResult
-	// This is a compatibility layer - in future versions we might add a generic version
-	return d.toDAG().DescendantsFlow(startID, inputs, callback)
+	return d.DescendantsFlowWithOptions(startID, inputs, callback, FlowOptions{})
+}
+
+// DescendantsFlowWithOptions is like DescendantsFlow, but configured by opts.
+// See DAG.DescendantsFlowWithOptions for details.
+func (d *TypedDAG[T]) DescendantsFlowWithOptions(startID string, inputs []FlowResult, callback FlowCallback, opts FlowOptions) ([]FlowResult, error) {
+	results, err := legacyFlow(d.inner, d.flowDAG(), startID, inputs, callback, boundedQueue(opts.MaxConcurrency))
+	if err != nil {
+		return []FlowResult{}, err
+	}
+	return results, nil
+}
+
+// flowDAG returns a function returning the DAG provided to the callbacks of
+// the flows of the TypedDAG. The function must only be called while the flow
+// holds the read lock of the graph.
+func (d *TypedDAG[T]) flowDAG() func() *DAG {
+	if core, ok := any(d.inner).(*dagCore); ok {
+		legacy := &DAG{core}
+		return func() *DAG { return legacy }
+	}
+	var once sync.Once
+	var legacy *DAG
+	return func() *DAG {
+		once.Do(func() {
+			// as the snapshot is taken from a valid graph, materializing
+			// can't fail
+			legacy, _ = materializeDAG(boxSnapshot(d.inner.snapshot()))
+			legacy.statuses = d.inner.statuses
+			legacy.vertexLocks = d.inner.vertexLocks
+		})
+		return legacy
+	}
 }
 
 // ReduceTransitively transitively reduces the graph.
@@ -257,6 +450,9 @@ func (d *TypedDAG[T]) MarshalJSON() ([]byte, error) {
 
 // Options sets the options for the TypedDAG.
 // Options must be called before any other method of the TypedDAG is called.
+//
+// Deprecated: Pass Option values to New instead, which makes the
+// configuration immutable after construction.
 func (d *TypedDAG[T]) Options(options Options) {
 	d.inner.Options(options)
 }
@@ -278,45 +474,37 @@ func (d *TypedDAG[T]) Options(options Options) {
 //	    Age  int    `json:"age"`
 //	}
 //	dag, err := dag.UnmarshalJSON[Person](data, dag.Options{})
-func UnmarshalJSON[T any](data []byte, options Options) (*TypedDAG[T], error) {
+//
+// Duplicate edges are handled according to Options.DuplicateEdges like by
+// UnmarshalGenericJSON.
+func UnmarshalJSON[T any, O Options | OptionsT[T]](data []byte, options O) (*TypedDAG[T], error) {
 	inner, err := UnmarshalGenericJSON[T](data, options)
-	if err != nil {
+	if inner == nil {
 		return nil, err
 	}
-	return &TypedDAG[T]{inner: inner}, nil
+	// err is nil or a DuplicateEdgesWarning
+	return &TypedDAG[T]{inner: inner}, err
 }
 
 // toDAG converts the TypedDAG to a *DAG for backward compatibility.
 // This is used for features like DescendantsFlow that haven't been genericized yet.
+// The values are boxed once while the copy is built from a snapshot, without
+// the checks of adding vertices and edges one by one.
 func (d *TypedDAG[T]) toDAG() *DAG {
-	legacy := NewDAG()
-	legacy.Options(d.getOptions())
-
-	// Copy all vertices
-	for id, value := range d.inner.GetVertices() {
-		_ = legacy.AddVertexByID(id, value)
-	}
-
-	// Copy all edges
-	// We need to use the internal structure for efficiency
 	d.inner.muDAG.RLock()
-	for vHash, children := range d.inner.outboundEdge {
-		srcID := d.inner.vertices[vHash]
-		for childHash := range children {
-			dstID := d.inner.vertices[childHash]
-			_ = legacy.AddEdge(srcID, dstID)
-		}
-	}
+	s := d.inner.snapshot()
 	d.inner.muDAG.RUnlock()
 
+	// as the snapshot is taken from a valid graph, materializing can't fail
+	legacy, _ := materializeDAG(boxSnapshot(s))
 	return legacy
 }
 
 // getOptions returns the current options of the TypedDAG.
 func (d *TypedDAG[T]) getOptions() Options {
-	// Return empty options for now - in a full implementation
-	// we might want to expose the options directly
-	return defaultOptions()
+	d.inner.muDAG.RLock()
+	defer d.inner.muDAG.RUnlock()
+	return d.inner.options
 }
 
 // ToDAG returns the underlying *GenericDAG for advanced usage.
@@ -369,6 +557,12 @@ func (d *TypedDAG[T]) GetEdgesWithOption(option CopyOption) EdgeList {
 	return d.inner.GetEdgesWithOption(option)
 }
 
+// Edges returns an iterator over all edges, which doesn't collect them into a
+// list. See GenericDAG.Edges for details.
+func (d *TypedDAG[T]) Edges() func(yield func(e GenericEdge) bool) {
+	return d.inner.Edges()
+}
+
 // GetVerticesList returns a list of all vertices in the DAG.
 // The returned node list shares data with the DAG for better performance.
 // Use GetVerticesListWithOption(CopyData) for a safe, independent copy.
@@ -2,6 +2,8 @@ package dag
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -887,4 +889,29 @@ func TestTypedDAGMarshalToDAGUnmarshal(t *testing.T) {
 	if !isEdge {
 		t.Error("Expected edge p1 -> p2 to exist")
 	}
-}
\ No newline at end of file
+}
+func TestFromDAG(t *testing.T) {
+	legacy := NewDAG()
+	legacy.MustAddVertexByID("a", "x")
+	legacy.MustAddVertexByID("b", "y")
+	legacy.MustAddEdge("a", "b")
+
+	typed, err := FromDAG[string](legacy)
+	if err != nil {
+		t.Fatalf("FromDAG failed: %v", err)
+	}
+	if v, _ := typed.GetVertex("b"); v != "y" || typed.GetSize() != 1 {
+		t.Errorf("FromDAG() copied b = %q and %d edges, want y and 1", v, typed.GetSize())
+	}
+
+	legacy.MustAddVertexByID("d", 4)
+	legacy.MustAddVertexByID("c", 3)
+	_, err = FromDAG[string](legacy)
+	var mismatch TypeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("FromDAG() = %v, want TypeMismatchError", err)
+	}
+	if !reflect.DeepEqual(mismatch.ids, []string{"c", "d"}) || mismatch.typeName != "string" {
+		t.Errorf("TypeMismatchError = %v, want c and d not of type string", mismatch)
+	}
+}
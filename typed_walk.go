@@ -0,0 +1,56 @@
+package dag
+
+// WalkAncestors returns an iterator over the ids and values of all ancestors
+// of the vertex with id in breadth-first order. Like AncestorsWalker, but
+// without a goroutine and with typed values; with Go 1.23 or later it can be
+// used in a range loop:
+//
+//	for id, v := range seq {
+//		...
+//	}
+//
+// The iterator walks a snapshot of the ancestors taken when the loop starts,
+// so the loop body may modify the TypedDAG, which doesn't affect the loop.
+// Breaking out of the loop stops the walk.
+// WalkAncestors returns an error if id is empty or unknown.
+func (d *TypedDAG[T]) WalkAncestors(id string) (func(yield func(id string, value T) bool), error) {
+	return d.walk(id, true)
+}
+
+// WalkDescendants returns an iterator over the ids and values of all
+// descendants of the vertex with id in breadth-first order. See WalkAncestors
+// for details. WalkDescendants returns an error if id is empty or unknown.
+func (d *TypedDAG[T]) WalkDescendants(id string) (func(yield func(id string, value T) bool), error) {
+	return d.walk(id, false)
+}
+
+func (d *TypedDAG[T]) walk(id string, asc bool) (func(yield func(string, T) bool), error) {
+	g := d.inner
+	g.muDAG.RLock()
+	err := g.saneID(id)
+	g.muDAG.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(string, T) bool) {
+		g.muDAG.RLock()
+		// the vertex may have been deleted since the iterator was created
+		var relatives []GenericStorableVertex[T]
+		if h, exists := g.ids.handle(id); exists {
+			eachRelative := g.eachChild
+			if asc {
+				eachRelative = func(h vertexHandle, f func(parent vertexHandle)) {
+					g.eachRelative(g.inboundEdge[h], f)
+				}
+			}
+			relatives = g.orderedRelatives(h, eachRelative)
+		}
+		g.muDAG.RUnlock()
+
+		for _, relative := range relatives {
+			if !yield(relative.ID, relative.Value) {
+				return
+			}
+		}
+	}, nil
+}
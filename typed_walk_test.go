@@ -0,0 +1,65 @@
+package dag
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTypedDAG_Walk(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		d.MustAddVertexByID(id, i)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("b", "d")
+	d.MustAddEdge("c", "d")
+	d.MustAddEdge("d", "e")
+
+	seq, err := d.WalkDescendants("a")
+	if err != nil {
+		t.Fatalf("WalkDescendants failed: %v", err)
+	}
+	var order []string
+	sum := 0
+	seq(func(id string, v int) bool {
+		order = append(order, id)
+		sum += v
+		return true
+	})
+	if len(order) != 4 || sum != 1+2+3+4 {
+		t.Errorf("WalkDescendants() = %v (sum %d), want b, c, d, e", order, sum)
+	}
+	// breadth-first: the children come before their descendants
+	if order[2] != "d" || order[3] != "e" {
+		t.Errorf("WalkDescendants() = %v, not in breadth-first order", order)
+	}
+
+	seq, err = d.WalkAncestors("e")
+	if err != nil {
+		t.Fatalf("WalkAncestors failed: %v", err)
+	}
+	var first []string
+	seq(func(id string, v int) bool {
+		first = append(first, id)
+		return len(first) < 2
+	})
+	if len(first) != 2 || first[0] != "d" {
+		t.Errorf("stopped WalkAncestors() = %v, want d and one of b, c", first)
+	}
+
+	// the iterator may be used again, e.g. after the graph has changed
+	if err := d.DeleteVertex("d"); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	seq(func(string, int) bool { count++; return true })
+	if count != 0 {
+		t.Errorf("WalkAncestors() after deleting the parent yielded %d vertices", count)
+	}
+
+	var unknown IDUnknownError
+	if _, err := d.WalkDescendants("x"); !errors.As(err, &unknown) {
+		t.Errorf("WalkDescendants(x) = %v, want IDUnknownError", err)
+	}
+}
@@ -0,0 +1,115 @@
+package dag
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UnmarshalSubgraphJSON parses JSON-encoded data like UnmarshalGenericJSON,
+// but returns only the vertex with the id rootID and its descendants up to
+// depth edges below it (all descendants, if depth is negative), along with
+// the edges between them. The values of all other vertices are skipped
+// without decoding them, so a single pipeline can be loaded from a snapshot
+// holding many.
+//
+// UnmarshalSubgraphJSON returns an error if data can't be parsed, if rootID
+// is empty or not a vertex in data, or if the subgraph can't be built, e.g.
+// because its vertices fail validation.
+func UnmarshalSubgraphJSON[T any, O Options | OptionsT[T]](data []byte, rootID string, depth int, opts O) (*GenericDAG[T], error) {
+	if rootID == "" {
+		return nil, IDEmptyError{}
+	}
+	options := optionsOf[T](opts)
+
+	// keep the values as they are until it is known which are needed
+	var raw GenericStorableDAG[json.RawMessage]
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	known := false
+	for _, v := range raw.Vertices {
+		if v.ID == rootID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, IDUnknownError{rootID}
+	}
+
+	children := make(map[string][]string)
+	for _, e := range raw.Edges {
+		children[e.SrcID] = append(children[e.SrcID], e.DstID)
+	}
+	selected := map[string]struct{}{rootID: {}}
+	level := []string{rootID}
+	for distance := 0; len(level) > 0 && (depth < 0 || distance < depth); distance++ {
+		var next []string
+		for _, id := range level {
+			for _, child := range children[id] {
+				if _, exists := selected[child]; !exists {
+					selected[child] = struct{}{}
+					next = append(next, child)
+				}
+			}
+		}
+		level = next
+	}
+
+	vertices := make([]GenericStorableVertex[T], 0, len(selected))
+	for _, v := range raw.Vertices {
+		if _, exists := selected[v.ID]; !exists {
+			continue
+		}
+		value, err := decodeValue[T](options.Codecs, v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("vertex '%s': %w", v.ID, err)
+		}
+		vertices = append(vertices, GenericStorableVertex[T]{ID: v.ID, Value: value})
+	}
+	var edges []GenericEdge
+	for _, e := range raw.Edges {
+		_, src := selected[e.SrcID]
+		_, dst := selected[e.DstID]
+		if src && dst {
+			edges = append(edges, e)
+		}
+	}
+	var edgeValues []GenericEdgeValue
+	for _, v := range raw.EdgeValues {
+		_, src := selected[v.SrcID]
+		_, dst := selected[v.DstID]
+		if src && dst {
+			edgeValues = append(edgeValues, v)
+		}
+	}
+
+	if err := validateVertices(len(vertices), func(i int) (string, interface{}) {
+		return vertices[i].ID, vertices[i].Value
+	}, options.VertexValidator); err != nil {
+		return nil, err
+	}
+
+	g := NewGenericDAG[T](WithOptions(options))
+	g.muDAG.Lock()
+	for _, v := range vertices {
+		if err := g.addVertexByID(v.ID, v.Value); err != nil {
+			g.muDAG.Unlock()
+			return nil, err
+		}
+	}
+	g.muDAG.Unlock()
+
+	errEdges := g.addEdgesBatch(len(edges), func(i int) (string, string) {
+		return edges[i].SrcID, edges[i].DstID
+	})
+	if _, warning := errEdges.(DuplicateEdgesWarning); errEdges != nil && !warning {
+		return nil, errEdges
+	}
+	if err := g.setEdgeValues(edgeValues); err != nil {
+		return nil, err
+	}
+
+	// errEdges is nil or a DuplicateEdgesWarning
+	return g, errEdges
+}
@@ -0,0 +1,55 @@
+package dag
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestUnmarshalSubgraphJSON(t *testing.T) {
+	// a -> b -> c -> d, a -> c, and a second pipeline x -> y, whose values
+	// are no ints and must not be decoded
+	data := []byte(`{"vs":[{"i":"a","v":1},{"i":"b","v":2},{"i":"c","v":3},{"i":"d","v":4},{"i":"x","v":"no int"},{"i":"y","v":"no int"}],` +
+		`"es":[{"s":"a","d":"b"},{"s":"b","d":"c"},{"s":"c","d":"d"},{"s":"a","d":"c"},{"s":"x","d":"y"}]}`)
+
+	idsOf := func(g *GenericDAG[int]) []string {
+		var ids []string
+		for id := range g.GetVertices() {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	g, err := UnmarshalSubgraphJSON[int](data, "b", -1, Options{})
+	if err != nil {
+		t.Fatalf("UnmarshalSubgraphJSON(b) unexpected error: %v", err)
+	}
+	if got, want := idsOf(g), []string{"b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("vertices = %v, want %v", got, want)
+	}
+	if g.GetSize() != 2 {
+		t.Errorf("GetSize() = %d, want 2", g.GetSize())
+	}
+
+	g, err = UnmarshalSubgraphJSON[int](data, "a", 1, Options{})
+	if err != nil {
+		t.Fatalf("UnmarshalSubgraphJSON(a, 1) unexpected error: %v", err)
+	}
+	if got, want := idsOf(g), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("vertices within depth 1 = %v, want %v", got, want)
+	}
+	if ok, _ := g.IsEdge("b", "c"); !ok || g.GetSize() != 3 {
+		t.Errorf("edges within depth 1 = %d, want 3 including b -> c", g.GetSize())
+	}
+	if v, _ := g.GetVertex("c"); v != 3 {
+		t.Errorf("GetVertex(c) = %d, want 3", v)
+	}
+
+	if _, err := UnmarshalSubgraphJSON[int](data, "z", -1, Options{}); err != (IDUnknownError{"z"}) {
+		t.Errorf("UnmarshalSubgraphJSON(z) = %v, want IDUnknownError", err)
+	}
+	if _, err := UnmarshalSubgraphJSON[int](data, "x", -1, Options{}); err == nil {
+		t.Error("UnmarshalSubgraphJSON(x) of values no ints: want error, got nil")
+	}
+}
@@ -0,0 +1,57 @@
+package dag
+
+// LockVertex locks the mutex of the vertex with id, e.g. so that flow
+// callbacks of different vertices mutating an external resource keyed by
+// vertex can coordinate without a global lock. If the mutex is already
+// locked, LockVertex blocks until it is available.
+//
+// Vertex mutexes are independent of the lock of the graph: they don't block
+// methods of the graph, and methods of the graph don't lock them, so a locked
+// vertex may be modified or deleted. LockVertex returns an error if id is
+// empty or unknown.
+func (d *GenericDAG[T]) LockVertex(id string) error {
+	d.muDAG.RLock()
+	err := d.saneID(id)
+	d.muDAG.RUnlock()
+	if err != nil {
+		return err
+	}
+	d.vertexLocks.lock(id)
+	return nil
+}
+
+// UnlockVertex unlocks the mutex of the vertex with id locked by LockVertex,
+// even if the vertex has been deleted in the meantime. Like for sync.Mutex,
+// it is a run-time error if the mutex isn't locked.
+func (d *GenericDAG[T]) UnlockVertex(id string) {
+	d.vertexLocks.unlock(id)
+}
+
+// WithVertexLock calls f while holding the mutex of the vertex with id and
+// returns the error of f. See LockVertex for details. WithVertexLock returns
+// an error without calling f if id is empty or unknown.
+func (d *GenericDAG[T]) WithVertexLock(id string, f func() error) error {
+	if err := d.LockVertex(id); err != nil {
+		return err
+	}
+	defer d.UnlockVertex(id)
+	return f()
+}
+
+// LockVertex locks the mutex of the vertex with id. The DAG passed to the
+// callbacks of DescendantsFlow and WorkStealingFlow shares the mutexes of the
+// TypedDAG. See GenericDAG.LockVertex for details.
+func (d *TypedDAG[T]) LockVertex(id string) error {
+	return d.inner.LockVertex(id)
+}
+
+// UnlockVertex unlocks the mutex of the vertex with id locked by LockVertex.
+func (d *TypedDAG[T]) UnlockVertex(id string) {
+	d.inner.UnlockVertex(id)
+}
+
+// WithVertexLock calls f while holding the mutex of the vertex with id. See
+// GenericDAG.WithVertexLock for details.
+func (d *TypedDAG[T]) WithVertexLock(id string, f func() error) error {
+	return d.inner.WithVertexLock(id, f)
+}
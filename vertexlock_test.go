@@ -0,0 +1,65 @@
+package dag
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockVertex(t *testing.T) {
+	d := New[int]()
+	for i, id := range []string{"a", "b", "c", "d"} {
+		d.MustAddVertexByID(id, i)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("b", "d")
+	d.MustAddEdge("c", "d")
+
+	if err := d.LockVertex("x"); !errors.As(err, new(IDUnknownError)) {
+		t.Errorf("LockVertex(x) = %v, want IDUnknownError", err)
+	}
+	if err := d.WithVertexLock("", func() error { return nil }); !errors.As(err, new(IDEmptyError)) {
+		t.Errorf("WithVertexLock('') = %v, want IDEmptyError", err)
+	}
+
+	// the callbacks of b and c wait for the lock of a held by the test
+	if err := d.LockVertex("a"); err != nil {
+		t.Fatalf("LockVertex(a) failed: %v", err)
+	}
+	var mu sync.Mutex
+	var order []string
+	callback := func(g *DAG, id string, parents []FlowResult) (interface{}, error) {
+		if id == "b" || id == "c" {
+			err := g.WithVertexLock("a", func() error {
+				mu.Lock()
+				order = append(order, id)
+				mu.Unlock()
+				return nil
+			})
+			return nil, err
+		}
+		return nil, nil
+	}
+	done := make(chan error)
+	go func() {
+		_, err := d.DescendantsFlow("a", nil, callback)
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	locked := len(order)
+	mu.Unlock()
+	if locked != 0 {
+		t.Errorf("%d callbacks ran while a was locked", locked)
+	}
+	d.UnlockVertex("a")
+	if err := <-done; err != nil {
+		t.Fatalf("DescendantsFlow failed: %v", err)
+	}
+	if len(order) != 2 {
+		t.Errorf("callbacks of %v ran, want b and c", order)
+	}
+}
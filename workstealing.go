@@ -0,0 +1,161 @@
+package dag
+
+import (
+	"runtime"
+	"sync"
+)
+
+// WorkStealingOptions configures WorkStealingFlow.
+type WorkStealingOptions struct {
+	// Workers is the number of goroutines executing the FlowCallback. If
+	// Workers is zero or negative, runtime.GOMAXPROCS(0) workers are used.
+	Workers int
+
+	// Cost returns the estimated cost (e.g. the expected duration) of the
+	// vertex with the given id. Ready vertices are queued at the worker with
+	// the lowest estimated cost queued. If Cost is nil, all vertices have a
+	// cost of 1. Cost may be called by several workers at the same time.
+	Cost func(id string) float64
+}
+
+func (o WorkStealingOptions) workers() int {
+	if o.Workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.Workers
+}
+
+// scheduler returns a stealingScheduler for a flow of the given number of
+// vertices.
+func (o WorkStealingOptions) scheduler(vertices int) flowScheduler {
+	return newStealingScheduler(o.workers(), vertices, o.cost)
+}
+
+func (o WorkStealingOptions) cost(id string) float64 {
+	if o.Cost == nil {
+		return 1
+	}
+	return o.Cost(id)
+}
+
+// WorkStealingFlow is like DescendantsFlow, but runs the FlowCallback on a
+// fixed number of workers instead of one goroutine per vertex. Each worker
+// has a queue of ready vertices; a vertex becomes ready once all its parents
+// within the flow have finished, and is queued at the worker with the lowest
+// estimated cost queued. Idle workers steal from the worker with the highest
+// estimated cost queued. On graphs with skewed costs, this keeps all workers
+// busy without the overhead of a goroutine per vertex.
+//
+// Like DescendantsFlow, WorkStealingFlow returns the results of the leaves
+// of the flow, and doesn't wait for parents outside the flow, i.e. parents of
+// descendants that aren't descendants of startID themselves. The DAG is
+// read-locked during the flow, so callback must not modify it.
+// WorkStealingFlow returns an error if startID is empty or unknown.
+func (d *DAG) WorkStealingFlow(startID string, inputs []FlowResult, callback FlowCallback, opts WorkStealingOptions) ([]FlowResult, error) {
+	return legacyFlow(d.dagCore, func() *DAG { return d }, startID, inputs, callback, opts.scheduler)
+}
+
+// stealingScheduler holds the queues of ready vertices of the workers of a
+// WorkStealingFlow.
+type stealingScheduler struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queues  [][]stealingItem
+	loads   []float64
+	pending int
+	cost    func(id string) float64
+}
+
+// stealingItem is a ready vertex along with its estimated cost.
+type stealingItem struct {
+	id   string
+	cost float64
+}
+
+// newStealingScheduler returns a scheduler for a flow of the given number of
+// vertices, which estimates their costs with cost. No more workers than
+// vertices are started.
+func newStealingScheduler(workers, vertices int, cost func(id string) float64) *stealingScheduler {
+	if workers > vertices {
+		workers = vertices
+	}
+	s := &stealingScheduler{
+		queues:  make([][]stealingItem, workers),
+		loads:   make([]float64, workers),
+		pending: vertices,
+		cost:    cost,
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+func (s *stealingScheduler) workers() int {
+	return len(s.queues)
+}
+
+// push queues the ready vertex with id at the least loaded worker. Its cost
+// is estimated before s.mu is locked, as it may call user code.
+func (s *stealingScheduler) push(id string) {
+	cost := s.cost(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target := 0
+	for w, load := range s.loads {
+		if load < s.loads[target] {
+			target = w
+		}
+	}
+	s.queues[target] = append(s.queues[target], stealingItem{id, cost})
+	s.loads[target] += cost
+	s.cond.Broadcast()
+}
+
+// next blocks until there is a vertex for worker w to run and returns its id:
+// the latest vertex queued at w, or else the earliest vertex queued at the
+// most loaded worker. next returns false once all vertices are done.
+func (s *stealingScheduler) next(w int) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		if q := s.queues[w]; len(q) > 0 {
+			item := q[len(q)-1]
+			s.queues[w] = q[:len(q)-1]
+			s.loads[w] -= item.cost
+			return item.id, true
+		}
+		victim := -1
+		for v, q := range s.queues {
+			if len(q) > 0 && (victim < 0 || s.loads[v] > s.loads[victim]) {
+				victim = v
+			}
+		}
+		if victim >= 0 {
+			item := s.queues[victim][0]
+			s.queues[victim] = s.queues[victim][1:]
+			s.loads[victim] -= item.cost
+			return item.id, true
+		}
+		if s.pending == 0 {
+			return "", false
+		}
+		s.cond.Wait()
+	}
+}
+
+// done counts a finished vertex and wakes all workers once the flow is
+// complete.
+func (s *stealingScheduler) done() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending--
+	if s.pending == 0 {
+		s.cond.Broadcast()
+	}
+}
+
+// WorkStealingFlow is like DescendantsFlow, but runs the FlowCallback on a
+// fixed number of workers. See DAG.WorkStealingFlow for details, and
+// TypedDAG.DescendantsFlow for the DAG provided to callback.
+func (d *TypedDAG[T]) WorkStealingFlow(startID string, inputs []FlowResult, callback FlowCallback, opts WorkStealingOptions) ([]FlowResult, error) {
+	return legacyFlow(d.inner, d.flowDAG(), startID, inputs, callback, opts.scheduler)
+}
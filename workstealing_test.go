@@ -0,0 +1,209 @@
+package dag
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDAG_WorkStealingFlow(t *testing.T) {
+	// a -> b -> d, a -> c -> d, d -> e, d -> f; x -> e is outside the flow
+	d := NewDAG()
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "x"} {
+		d.MustAddVertexByID(id, id)
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}, {"d", "f"}, {"x", "e"}} {
+		d.MustAddEdge(e[0], e[1])
+	}
+
+	// each vertex returns the number of paths from a to it
+	var running, maxRunning int32
+	callback := func(d *DAG, id string, parents []FlowResult) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&running, -1)
+		time.Sleep(time.Millisecond)
+
+		paths := 0
+		for _, p := range parents {
+			paths += p.Result.(int)
+		}
+		if id == "a" {
+			paths = 1
+		}
+		return paths, nil
+	}
+	costs := map[string]float64{"b": 10, "c": 1}
+	cost := func(id string) float64 { return costs[id] + 1 }
+
+	for _, opts := range []WorkStealingOptions{
+		{Workers: 1},
+		{Workers: 2, Cost: cost},
+		{}, // GOMAXPROCS workers
+	} {
+		maxRunning = 0
+		results, err := d.WorkStealingFlow("a", nil, callback, opts)
+		if err != nil {
+			t.Fatalf("%+v: unexpected error %v", opts, err)
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+		if len(results) != 2 || results[0].ID != "e" || results[1].ID != "f" {
+			t.Fatalf("%+v: WorkStealingFlow() = %+v, want the results of e and f", opts, results)
+		}
+		for _, r := range results {
+			if r.Result != 2 || len(r.ParentIDs) != 1 || r.ParentIDs[0] != "d" {
+				t.Errorf("%+v: result of %s = %v based on %v, want 2 based on [d]", opts, r.ID, r.Result, r.ParentIDs)
+			}
+		}
+		if opts.Workers > 0 && int(maxRunning) > opts.Workers {
+			t.Errorf("%+v: %d callbacks ran concurrently", opts, maxRunning)
+		}
+	}
+
+	if _, err := d.WorkStealingFlow("", nil, callback, WorkStealingOptions{}); err == nil {
+		t.Error("WorkStealingFlow(\"\") returned no error")
+	}
+	if _, err := d.WorkStealingFlow("z", nil, callback, WorkStealingOptions{}); err == nil {
+		t.Error("WorkStealingFlow(\"z\") returned no error")
+	}
+}
+
+func TestDAG_DescendantsFlowWithOptions(t *testing.T) {
+	d := NewDAG()
+	d.MustAddVertexByID("root", "root")
+	for i := 0; i < 100; i++ {
+		id := "v" + strconv.Itoa(i)
+		d.MustAddVertexByID(id, id)
+		d.MustAddEdge("root", id)
+	}
+
+	var running, maxRunning int32
+	callback := func(d *DAG, id string, parents []FlowResult) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&running, -1)
+		time.Sleep(100 * time.Microsecond)
+		return id, nil
+	}
+
+	results, err := d.DescendantsFlowWithOptions("root", nil, callback, FlowOptions{MaxConcurrency: 3})
+	if err != nil {
+		t.Fatalf("DescendantsFlowWithOptions failed: %v", err)
+	}
+	if len(results) != 100 {
+		t.Errorf("DescendantsFlowWithOptions() returned %d results, want 100", len(results))
+	}
+	if maxRunning > 3 {
+		t.Errorf("%d callbacks ran concurrently, want at most 3", maxRunning)
+	}
+
+	results, err = d.DescendantsFlowWithOptions("root", nil, callback, FlowOptions{})
+	if err != nil || len(results) != 100 {
+		t.Errorf("DescendantsFlowWithOptions() = %d results, %v, want 100", len(results), err)
+	}
+}
+
+func TestDAG_DescendantsFlowWithOptions_SameSemantics(t *testing.T) {
+	// a -> b -> d, a -> c -> d, x -> d; x is a parent of d outside the flow
+	d := NewDAG(WithSortedFlowParents(), WithStatusTracking())
+	for _, id := range []string{"a", "b", "c", "d", "x"} {
+		d.MustAddVertexByID(id, id)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("a", "c")
+	d.MustAddEdge("b", "d")
+	d.MustAddEdge("c", "d")
+	d.MustAddEdge("x", "d")
+
+	callback := func(d *DAG, id string, parents []FlowResult) (interface{}, error) {
+		return id, nil
+	}
+	for _, maxConcurrency := range []int{0, 1, 2} {
+		results, err := d.DescendantsFlowWithOptions("a", []FlowResult{{ID: "input"}}, callback, FlowOptions{MaxConcurrency: maxConcurrency})
+		if err != nil {
+			t.Fatalf("MaxConcurrency %d: %v", maxConcurrency, err)
+		}
+		if len(results) != 1 || results[0].ID != "d" || !reflect.DeepEqual(results[0].ParentIDs, []string{"b", "c"}) {
+			t.Errorf("MaxConcurrency %d: DescendantsFlowWithOptions() = %+v, want d based on b and c", maxConcurrency, results)
+		}
+		if status, _ := d.GetStatus("x"); status != StatusSkipped {
+			t.Errorf("MaxConcurrency %d: GetStatus(x) = %v, want %v", maxConcurrency, status, StatusSkipped)
+		}
+		if err := d.CheckStatuses(); err != nil {
+			t.Errorf("MaxConcurrency %d: CheckStatuses() = %v", maxConcurrency, err)
+		}
+	}
+}
+
+func TestTypedDAG_Flows(t *testing.T) {
+	d := New[int](WithStatusTracking())
+	for i, id := range []string{"a", "b", "c"} {
+		d.MustAddVertexByID(id, i)
+	}
+	d.MustAddEdge("a", "b")
+	d.MustAddEdge("a", "c")
+
+	var mu sync.Mutex
+	graphs := make(map[*DAG]struct{})
+	callback := func(legacy *DAG, id string, parents []FlowResult) (interface{}, error) {
+		mu.Lock()
+		graphs[legacy] = struct{}{}
+		mu.Unlock()
+		return legacy.GetVertex(id)
+	}
+	flows := map[string]func() ([]FlowResult, error){
+		"DescendantsFlow": func() ([]FlowResult, error) {
+			return d.DescendantsFlow("a", nil, callback)
+		},
+		"DescendantsFlowWithOptions": func() ([]FlowResult, error) {
+			return d.DescendantsFlowWithOptions("a", nil, callback, FlowOptions{MaxConcurrency: 1})
+		},
+		"WorkStealingFlow": func() ([]FlowResult, error) {
+			return d.WorkStealingFlow("a", nil, callback, WorkStealingOptions{Workers: 2})
+		},
+	}
+	for name, flow := range flows {
+		graphs = make(map[*DAG]struct{})
+		results, err := flow()
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+		if len(results) != 2 || results[0].Result != 1 || results[1].Result != 2 {
+			t.Errorf("%s() = %+v, want the values of b and c", name, results)
+		}
+		if len(graphs) != 1 {
+			t.Errorf("%s provided %d DAGs to the callbacks, want 1", name, len(graphs))
+		}
+		if status, _ := d.GetStatus("c"); status != StatusSucceeded {
+			t.Errorf("%s: GetStatus(c) = %v, want %v", name, status, StatusSucceeded)
+		}
+	}
+
+	// a TypedDAG[interface{}] is provided to the callbacks as is
+	untyped := New[interface{}]()
+	untyped.MustAddVertexByID("a", 1)
+	_, err := untyped.DescendantsFlow("a", nil, func(legacy *DAG, id string, parents []FlowResult) (interface{}, error) {
+		if legacy.dagCore != untyped.inner {
+			t.Error("DescendantsFlow() provided a copy of a TypedDAG[interface{}]")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}